@@ -30,14 +30,18 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/controller/backupschedule"
 	"github.com/pingcap/tidb-operator/pkg/controller/dmcluster"
 	"github.com/pingcap/tidb-operator/pkg/controller/periodicity"
+	"github.com/pingcap/tidb-operator/pkg/controller/placementrule"
 	"github.com/pingcap/tidb-operator/pkg/controller/restore"
+	"github.com/pingcap/tidb-operator/pkg/controller/ticdcchangefeed"
 	"github.com/pingcap/tidb-operator/pkg/controller/tidbcluster"
 	"github.com/pingcap/tidb-operator/pkg/controller/tidbinitializer"
 	"github.com/pingcap/tidb-operator/pkg/controller/tidbmonitor"
 	"github.com/pingcap/tidb-operator/pkg/features"
+	"github.com/pingcap/tidb-operator/pkg/metrics"
 	"github.com/pingcap/tidb-operator/pkg/scheme"
 	"github.com/pingcap/tidb-operator/pkg/upgrader"
 	"github.com/pingcap/tidb-operator/pkg/version"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
@@ -69,6 +73,10 @@ func main() {
 		klog.V(1).Infof("FLAG: --%s=%q", flag.Name, flag.Value)
 	})
 
+	// register the metrics before the controllers are created to collect the
+	// metrics of their workqueues
+	metrics.RegisterMetrics()
+
 	hostName, err := os.Hostname()
 	if err != nil {
 		klog.Fatalf("failed to get hostname: %v", err)
@@ -151,6 +159,8 @@ func main() {
 			backupschedule.NewController(deps),
 			tidbinitializer.NewController(deps),
 			tidbmonitor.NewController(deps),
+			ticdcchangefeed.NewController(deps),
+			placementrule.NewController(deps),
 		}
 		if cliCfg.PodWebhookEnabled {
 			controllers = append(controllers, periodicity.NewController(deps))
@@ -213,5 +223,6 @@ func main() {
 		})
 	}, cliCfg.WaitDuration)
 
+	http.Handle("/metrics", promhttp.Handler())
 	klog.Fatal(http.ListenAndServe(":6060", nil))
 }
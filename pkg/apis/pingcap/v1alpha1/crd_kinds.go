@@ -53,6 +53,14 @@ const (
 	TidbClusterAutoScalerKind    = "TidbClusterAutoScaler"
 	TidbClusterAutoScalerKindKey = "tidbclusterautoscaler"
 
+	TiCDCChangefeedName    = "ticdcchangefeeds"
+	TiCDCChangefeedKind    = "TiCDCChangefeed"
+	TiCDCChangefeedKindKey = "ticdcchangefeed"
+
+	PlacementRuleName    = "placementrules"
+	PlacementRuleKind    = "PlacementRule"
+	PlacementRuleKindKey = "placementrule"
+
 	SpecPath = "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1."
 )
 
@@ -74,6 +82,8 @@ type CrdKinds struct {
 	TiDBMonitor           CrdKind
 	TiDBInitializer       CrdKind
 	TidbClusterAutoScaler CrdKind
+	TiCDCChangefeed       CrdKind
+	PlacementRule         CrdKind
 }
 
 var DefaultCrdKinds = CrdKinds{
@@ -86,4 +96,6 @@ var DefaultCrdKinds = CrdKinds{
 	TiDBMonitor:           CrdKind{Plural: TiDBMonitorName, Kind: TiDBMonitorKind, ShortNames: []string{"tm"}, SpecName: SpecPath + TiDBMonitorKind},
 	TiDBInitializer:       CrdKind{Plural: TiDBInitializerName, Kind: TiDBInitializerKind, ShortNames: []string{"ti"}, SpecName: SpecPath + TiDBInitializerKind},
 	TidbClusterAutoScaler: CrdKind{Plural: TidbClusterAutoScalerName, Kind: TidbClusterAutoScalerKind, ShortNames: []string{"ta"}, SpecName: SpecPath + TidbClusterAutoScalerKind},
+	TiCDCChangefeed:       CrdKind{Plural: TiCDCChangefeedName, Kind: TiCDCChangefeedKind, ShortNames: []string{"cf"}, SpecName: SpecPath + TiCDCChangefeedKind},
+	PlacementRule:         CrdKind{Plural: PlacementRuleName, Kind: PlacementRuleKind, ShortNames: []string{"pr"}, SpecName: SpecPath + PlacementRuleKind},
 }
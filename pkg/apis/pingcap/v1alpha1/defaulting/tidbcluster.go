@@ -40,12 +40,21 @@ func SetTidbClusterDefault(tc *v1alpha1.TidbCluster) {
 	if tc.Spec.TiKV != nil {
 		setTikvSpecDefault(tc)
 	}
+	for i := range tc.Spec.TiKVGroups {
+		setTiKVGroupSpecDefault(tc, &tc.Spec.TiKVGroups[i])
+	}
 	if tc.Spec.TiDB != nil {
 		setTidbSpecDefault(tc)
 	}
+	for i := range tc.Spec.TiDBGroups {
+		setTiDBGroupSpecDefault(tc, &tc.Spec.TiDBGroups[i])
+	}
 	if tc.Spec.Pump != nil {
 		setPumpSpecDefault(tc)
 	}
+	if tc.Spec.Drainer != nil {
+		setDrainerSpecDefault(tc)
+	}
 	if tc.Spec.TiFlash != nil {
 		setTiFlashSpecDefault(tc)
 	}
@@ -93,6 +102,27 @@ func setTidbSpecDefault(tc *v1alpha1.TidbCluster) {
 	}
 }
 
+func setTiDBGroupSpecDefault(tc *v1alpha1.TidbCluster, group *v1alpha1.TiDBGroupSpec) {
+	if len(tc.Spec.Version) > 0 || group.Version != nil {
+		if group.BaseImage == "" {
+			group.BaseImage = defaultTiDBImage
+		}
+	}
+	if group.MaxFailoverCount == nil {
+		group.MaxFailoverCount = pointer.Int32Ptr(3)
+	}
+
+	// Start set config if need.
+	if group.Config == nil {
+		return
+	}
+	// we only set default log
+	backupKey := "log.file.max-backups"
+	if v := group.Config.Get(backupKey); v == nil {
+		group.Config.Set(backupKey, tidbLogMaxBackups)
+	}
+}
+
 func setTikvSpecDefault(tc *v1alpha1.TidbCluster) {
 	if len(tc.Spec.Version) > 0 || tc.Spec.TiKV.Version != nil {
 		if tc.Spec.TiKV.BaseImage == "" {
@@ -104,6 +134,17 @@ func setTikvSpecDefault(tc *v1alpha1.TidbCluster) {
 	}
 }
 
+func setTiKVGroupSpecDefault(tc *v1alpha1.TidbCluster, group *v1alpha1.TiKVGroupSpec) {
+	if len(tc.Spec.Version) > 0 || group.Version != nil {
+		if group.BaseImage == "" {
+			group.BaseImage = defaultTiKVImage
+		}
+	}
+	if group.MaxFailoverCount == nil {
+		group.MaxFailoverCount = pointer.Int32Ptr(3)
+	}
+}
+
 func setPdSpecDefault(tc *v1alpha1.TidbCluster) {
 	if len(tc.Spec.Version) > 0 || tc.Spec.PD.Version != nil {
 		if tc.Spec.PD.BaseImage == "" {
@@ -123,6 +164,14 @@ func setPumpSpecDefault(tc *v1alpha1.TidbCluster) {
 	}
 }
 
+func setDrainerSpecDefault(tc *v1alpha1.TidbCluster) {
+	if len(tc.Spec.Version) > 0 || tc.Spec.Drainer.Version != nil {
+		if tc.Spec.Drainer.BaseImage == "" {
+			tc.Spec.Drainer.BaseImage = defaultBinlogImage
+		}
+	}
+}
+
 func setTiFlashSpecDefault(tc *v1alpha1.TidbCluster) {
 	if len(tc.Spec.Version) > 0 || tc.Spec.TiFlash.Version != nil {
 		if tc.Spec.TiFlash.BaseImage == "" {
@@ -0,0 +1,35 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+// GetGroupID returns the ID of the rule group in PD
+func (pr *PlacementRule) GetGroupID() string {
+	if pr.Spec.GroupID != "" {
+		return pr.Spec.GroupID
+	}
+	return pr.Name
+}
+
+// GetClusterNamespace returns the namespace of the TidbCluster the rules belong to
+func (pr *PlacementRule) GetClusterNamespace() string {
+	if pr.Spec.Cluster.Namespace != "" {
+		return pr.Spec.Cluster.Namespace
+	}
+	return pr.Namespace
+}
+
+// IsSpecApplied returns whether the latest spec has been applied to PD
+func (pr *PlacementRule) IsSpecApplied() bool {
+	return pr.Status.GroupID == pr.GetGroupID() && pr.Status.ObservedGeneration == pr.Generation
+}
@@ -0,0 +1,200 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PlacementRuleRole is the role of the replicas placed by a rule
+type PlacementRuleRole string
+
+const (
+	// PlacementRuleRoleVoter means the replicas can vote and be elected as leader
+	PlacementRuleRoleVoter PlacementRuleRole = "voter"
+	// PlacementRuleRoleLeader means the replica is the leader
+	PlacementRuleRoleLeader PlacementRuleRole = "leader"
+	// PlacementRuleRoleFollower means the replicas can vote but can not be elected as leader
+	PlacementRuleRoleFollower PlacementRuleRole = "follower"
+	// PlacementRuleRoleLearner means the replicas can neither vote nor be elected as leader
+	PlacementRuleRoleLearner PlacementRuleRole = "learner"
+)
+
+// PlacementRuleDriftType is the type of a difference between PD and the spec
+type PlacementRuleDriftType string
+
+const (
+	// PlacementRuleDriftMissing means the rule in spec is missing in PD
+	PlacementRuleDriftMissing PlacementRuleDriftType = "Missing"
+	// PlacementRuleDriftModified means the rule in PD differs from the spec
+	PlacementRuleDriftModified PlacementRuleDriftType = "Modified"
+	// PlacementRuleDriftUnexpected means the rule in PD does not exist in spec
+	PlacementRuleDriftUnexpected PlacementRuleDriftType = "Unexpected"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// +k8s:openapi-gen=true
+// PlacementRule is a group of PD placement rules of a TidbCluster,
+// the operator owns all the rules of the group in PD
+type PlacementRule struct {
+	metav1.TypeMeta `json:",inline"`
+	// +k8s:openapi-gen=false
+	metav1.ObjectMeta `json:"metadata"`
+
+	// Spec defines the desired state of PlacementRule
+	Spec PlacementRuleSpec `json:"spec"`
+
+	// +k8s:openapi-gen=false
+	// Most recently observed status of the PlacementRule
+	Status PlacementRuleStatus `json:"status"`
+}
+
+// +k8s:openapi-gen=true
+// PlacementRuleSpec describes the desired rule group in PD
+type PlacementRuleSpec struct {
+	// Cluster is the TidbCluster whose PD the rules are applied to
+	Cluster TidbClusterRef `json:"cluster"`
+
+	// GroupID is the ID of the rule group in PD, the groups "pd" and
+	// "tiflash" are reserved and can not be managed
+	// Optional: Defaults to the name of the PlacementRule
+	// +optional
+	GroupID string `json:"groupID,omitempty"`
+
+	// GroupIndex decides the order the rule groups are applied in,
+	// groups with larger index are applied later
+	// +optional
+	GroupIndex int `json:"groupIndex,omitempty"`
+
+	// GroupOverride indicates whether the rule group overrides the groups with smaller index
+	// +optional
+	GroupOverride bool `json:"groupOverride,omitempty"`
+
+	// Rules are the placement rules of the group
+	Rules []PlacementRuleItem `json:"rules"`
+}
+
+// +k8s:openapi-gen=true
+// PlacementRuleItem is a single placement rule of a rule group
+type PlacementRuleItem struct {
+	// ID is the ID of the rule, unique in the group
+	ID string `json:"id"`
+
+	// Index decides the order the rules in the group are applied in
+	// +optional
+	Index int `json:"index,omitempty"`
+
+	// Override indicates whether the rule overrides the rules with smaller index in the group
+	// +optional
+	Override bool `json:"override,omitempty"`
+
+	// StartKeyHex is the hex encoded start key of the key range the rule applies to
+	// Optional: Defaults to the start of the key space
+	// +optional
+	StartKeyHex string `json:"startKeyHex,omitempty"`
+
+	// EndKeyHex is the hex encoded end key of the key range the rule applies to
+	// Optional: Defaults to the end of the key space
+	// +optional
+	EndKeyHex string `json:"endKeyHex,omitempty"`
+
+	// Role is the role of the replicas placed by the rule
+	// +kubebuilder:validation:Enum=voter;leader;follower;learner
+	Role PlacementRuleRole `json:"role"`
+
+	// Count is the number of the replicas placed by the rule
+	Count int `json:"count"`
+
+	// LabelConstraints filter the stores the replicas can be placed on
+	// +optional
+	LabelConstraints []PlacementLabelConstraint `json:"labelConstraints,omitempty"`
+
+	// LocationLabels are the labels used to spread the replicas across topology domains
+	// +optional
+	LocationLabels []string `json:"locationLabels,omitempty"`
+
+	// IsolationLevel is the location label the replicas must be isolated at
+	// +optional
+	IsolationLevel string `json:"isolationLevel,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// PlacementLabelConstraint filters the stores by their labels
+type PlacementLabelConstraint struct {
+	// Key is the key of the store label
+	Key string `json:"key"`
+
+	// Op is the operator applied to the label values
+	// +kubebuilder:validation:Enum=in;notIn;exists;notExists
+	Op string `json:"op"`
+
+	// Values are the values of the store label
+	// +optional
+	Values []string `json:"values,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// PlacementRuleStatus is the observed state of a PlacementRule
+type PlacementRuleStatus struct {
+	// GroupID is the ID of the rule group applied to PD
+	GroupID string `json:"groupID,omitempty"`
+
+	// ObservedGeneration is the most recent generation of the spec applied to PD
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Synced indicates whether the rules in PD match the spec
+	Synced bool `json:"synced,omitempty"`
+
+	// Drifts are the differences between PD and the applied spec found in
+	// the last sync, they are reverted by the operator
+	// +optional
+	Drifts []PlacementRuleDrift `json:"drifts,omitempty"`
+
+	// LastDriftTime is the last time a drift was found
+	// +optional
+	LastDriftTime *metav1.Time `json:"lastDriftTime,omitempty"`
+
+	// LastSyncTime is the last time the rules were synced to PD
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// Message is the reason the rules can not be synced
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// PlacementRuleDrift is a difference between PD and the applied spec
+type PlacementRuleDrift struct {
+	// RuleID is the ID of the rule, empty for the rule group itself
+	// +optional
+	RuleID string `json:"ruleID,omitempty"`
+
+	// Type is the type of the drift
+	Type PlacementRuleDriftType `json:"type"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// +k8s:openapi-gen=true
+// PlacementRuleList is PlacementRule list
+type PlacementRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +k8s:openapi-gen=false
+	metav1.ListMeta `json:"metadata"`
+
+	Items []PlacementRule `json:"items"`
+}
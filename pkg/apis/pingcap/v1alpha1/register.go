@@ -68,6 +68,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&TidbClusterAutoScalerList{},
 		&DMCluster{},
 		&DMClusterList{},
+		&TiCDCChangefeed{},
+		&TiCDCChangefeedList{},
+		&PlacementRule{},
+		&PlacementRuleList{},
 	)
 
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
@@ -101,6 +101,12 @@ func IsRestoreComplete(restore *Restore) bool {
 	return condition != nil && condition.Status == corev1.ConditionTrue
 }
 
+// IsRestoreFailed returns true if a Restore is failed
+func IsRestoreFailed(restore *Restore) bool {
+	_, condition := GetRestoreCondition(&restore.Status, RestoreFailed)
+	return condition != nil && condition.Status == corev1.ConditionTrue
+}
+
 // IsRestoreScheduled returns true if a Restore has successfully scheduled
 func IsRestoreScheduled(restore *Restore) bool {
 	_, condition := GetRestoreCondition(&restore.Status, RestoreScheduled)
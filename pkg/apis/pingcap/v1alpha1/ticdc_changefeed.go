@@ -0,0 +1,35 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+// GetChangefeedID returns the ID of the changefeed in TiCDC
+func (cf *TiCDCChangefeed) GetChangefeedID() string {
+	if cf.Spec.ChangefeedID != "" {
+		return cf.Spec.ChangefeedID
+	}
+	return cf.Name
+}
+
+// GetClusterNamespace returns the namespace of the TidbCluster the changefeed belongs to
+func (cf *TiCDCChangefeed) GetClusterNamespace() string {
+	if cf.Spec.Cluster.Namespace != "" {
+		return cf.Spec.Cluster.Namespace
+	}
+	return cf.Namespace
+}
+
+// IsSpecApplied returns whether the latest spec has been applied to TiCDC
+func (cf *TiCDCChangefeed) IsSpecApplied() bool {
+	return cf.Status.ChangefeedID != "" && cf.Status.ObservedGeneration == cf.Generation
+}
@@ -0,0 +1,172 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TiCDCChangefeedState is the state of a changefeed reported by TiCDC
+type TiCDCChangefeedState string
+
+const (
+	// TiCDCChangefeedStateNormal means the changefeed is replicating
+	TiCDCChangefeedStateNormal TiCDCChangefeedState = "normal"
+	// TiCDCChangefeedStateStopped means the changefeed is paused
+	TiCDCChangefeedStateStopped TiCDCChangefeedState = "stopped"
+	// TiCDCChangefeedStateError means the changefeed hit an error and TiCDC is retrying it
+	TiCDCChangefeedStateError TiCDCChangefeedState = "error"
+	// TiCDCChangefeedStateFailed means the changefeed hit an unrecoverable error
+	TiCDCChangefeedStateFailed TiCDCChangefeedState = "failed"
+	// TiCDCChangefeedStateFinished means the changefeed reached its target ts
+	TiCDCChangefeedStateFinished TiCDCChangefeedState = "finished"
+	// TiCDCChangefeedStateRemoved means the changefeed has been removed from TiCDC
+	TiCDCChangefeedStateRemoved TiCDCChangefeedState = "removed"
+	// TiCDCChangefeedStatePending means the changefeed has not been created in TiCDC yet
+	TiCDCChangefeedStatePending TiCDCChangefeedState = "pending"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// +k8s:openapi-gen=true
+// TiCDCChangefeed is a replication task of TiCDC
+type TiCDCChangefeed struct {
+	metav1.TypeMeta `json:",inline"`
+	// +k8s:openapi-gen=false
+	metav1.ObjectMeta `json:"metadata"`
+
+	// Spec defines the desired state of TiCDCChangefeed
+	Spec TiCDCChangefeedSpec `json:"spec"`
+
+	// +k8s:openapi-gen=false
+	// Most recently observed status of the TiCDCChangefeed
+	Status TiCDCChangefeedStatus `json:"status"`
+}
+
+// +k8s:openapi-gen=true
+// TiCDCChangefeedSpec describes the desired state of a TiCDC changefeed
+type TiCDCChangefeedSpec struct {
+	// Cluster is the TidbCluster whose TiCDC captures run the changefeed
+	Cluster TidbClusterRef `json:"cluster"`
+
+	// ChangefeedID is the ID of the changefeed in TiCDC
+	// Optional: Defaults to the name of the TiCDCChangefeed
+	// +optional
+	ChangefeedID string `json:"changefeedID,omitempty"`
+
+	// SinkURI is the downstream of the changefeed, e.g. mysql://root@127.0.0.1:3306/
+	// +optional
+	SinkURI string `json:"sinkURI,omitempty"`
+
+	// SinkURISecret references a key of a secret in the same namespace
+	// which contains the sink URI, take high precedence than sinkURI if set.
+	// It is used to keep the credentials of the downstream out of the spec.
+	// +optional
+	SinkURISecret *corev1.SecretKeySelector `json:"sinkURISecret,omitempty"`
+
+	// StartTs is the TSO the changefeed starts replicating from, in the string form of uint64
+	// Optional: Defaults to the current TSO when the changefeed is created
+	// +optional
+	StartTs string `json:"startTs,omitempty"`
+
+	// TargetTs is the TSO the changefeed stops replicating at, in the string form of uint64
+	// Optional: Defaults to no limit
+	// +optional
+	TargetTs string `json:"targetTs,omitempty"`
+
+	// Paused indicates that the changefeed should be paused
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// Filter defines which tables and events are replicated
+	// +optional
+	Filter *TiCDCChangefeedFilter `json:"filter,omitempty"`
+
+	// ForceReplicate indicates whether to replicate tables without a valid index
+	// +optional
+	ForceReplicate bool `json:"forceReplicate,omitempty"`
+
+	// IgnoreIneligibleTable indicates whether to ignore tables that cannot be replicated
+	// +optional
+	IgnoreIneligibleTable bool `json:"ignoreIneligibleTable,omitempty"`
+
+	// Protocol is the message protocol of MQ sinks, e.g. default, canal, avro, maxwell
+	// +optional
+	Protocol string `json:"protocol,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// TiCDCChangefeedFilter is the table filter of a changefeed
+type TiCDCChangefeedFilter struct {
+	// Rules are the table filter rules, e.g. "test.*", "!test.t1"
+	// +optional
+	Rules []string `json:"rules,omitempty"`
+
+	// IgnoreTxnStartTs are the start ts of transactions which are skipped
+	// +optional
+	IgnoreTxnStartTs []string `json:"ignoreTxnStartTs,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// TiCDCChangefeedStatus is the observed state of a TiCDC changefeed
+type TiCDCChangefeedStatus struct {
+	// ChangefeedID is the ID of the changefeed created in TiCDC
+	ChangefeedID string `json:"changefeedID,omitempty"`
+
+	// ObservedGeneration is the most recent generation of the spec applied to TiCDC
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// State is the state of the changefeed reported by TiCDC
+	State TiCDCChangefeedState `json:"state,omitempty"`
+
+	// CheckpointTs is the checkpoint TSO of the changefeed
+	CheckpointTs string `json:"checkpointTs,omitempty"`
+
+	// CheckpointTime is the physical time of the checkpoint TSO
+	CheckpointTime *metav1.Time `json:"checkpointTime,omitempty"`
+
+	// LagSeconds is the replication lag computed from the checkpoint TSO
+	LagSeconds int64 `json:"lagSeconds,omitempty"`
+
+	// Error is the last error reported by TiCDC for the changefeed
+	Error *TiCDCChangefeedError `json:"error,omitempty"`
+
+	// LastSyncTime is the last time the status was synced from TiCDC
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// TiCDCChangefeedError is an error reported by TiCDC for a changefeed
+type TiCDCChangefeedError struct {
+	// Addr is the address of the capture which reported the error
+	Addr string `json:"addr,omitempty"`
+	// Code is the error code
+	Code string `json:"code,omitempty"`
+	// Message is the error message
+	Message string `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// +k8s:openapi-gen=true
+// TiCDCChangefeedList is TiCDCChangefeed list
+type TiCDCChangefeedList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +k8s:openapi-gen=false
+	metav1.ListMeta `json:"metadata"`
+
+	Items []TiCDCChangefeed `json:"items"`
+}
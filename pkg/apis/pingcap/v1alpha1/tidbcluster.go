@@ -34,6 +34,14 @@ const (
 	defaultEnablePVReclaim = false
 	// defaultEvictLeaderTimeout is the timeout limit of evict leader
 	defaultEvictLeaderTimeout = 3 * time.Minute
+	// defaultTiCDCGracefulShutdownTimeout is the timeout limit of graceful
+	// shutdown of a TiCDC capture
+	defaultTiCDCGracefulShutdownTimeout = 10 * time.Minute
+	// defaultTiDBDrainTimeout is the timeout limit of draining the
+	// connections of a TiDB pod
+	defaultTiDBDrainTimeout = 5 * time.Minute
+	// defaultCanaryBakeTime is the time to watch the health of the canary pods
+	defaultCanaryBakeTime = 10 * time.Minute
 )
 
 var (
@@ -179,6 +187,27 @@ func (tc *TidbCluster) TiDBImage() string {
 	return image
 }
 
+func (tc *TidbCluster) DrainerImage() *string {
+	if tc.Spec.Drainer == nil {
+		return nil
+	}
+	image := tc.Spec.Drainer.Image
+	baseImage := tc.Spec.Drainer.BaseImage
+	// base image takes higher priority
+	if baseImage != "" {
+		version := tc.Spec.Drainer.Version
+		if version == nil {
+			version = &tc.Spec.Version
+		}
+		if *version == "" {
+			image = baseImage
+		} else {
+			image = fmt.Sprintf("%s:%s", baseImage, *version)
+		}
+	}
+	return &image
+}
+
 func (tc *TidbCluster) PumpImage() *string {
 	if tc.Spec.Pump == nil {
 		return nil
@@ -243,12 +272,26 @@ func (tc *TidbCluster) PDScaling() bool {
 	return tc.Status.PD.Phase == ScalePhase
 }
 
+// TiKVUpgrading returns whether the default TiKV or any TiKV group is upgrading
 func (tc *TidbCluster) TiKVUpgrading() bool {
-	return tc.Status.TiKV.Phase == UpgradePhase
+	return tc.tikvInPhase(UpgradePhase)
 }
 
+// TiKVScaling returns whether the default TiKV or any TiKV group is scaling
 func (tc *TidbCluster) TiKVScaling() bool {
-	return tc.Status.TiKV.Phase == ScalePhase
+	return tc.tikvInPhase(ScalePhase)
+}
+
+func (tc *TidbCluster) tikvInPhase(phase MemberPhase) bool {
+	if tc.Status.TiKV.Phase == phase {
+		return true
+	}
+	for _, group := range tc.Status.TiKV.Groups {
+		if group.Phase == phase {
+			return true
+		}
+	}
+	return false
 }
 
 func (tc *TidbCluster) TiDBUpgrading() bool {
@@ -263,6 +306,14 @@ func (tc *TidbCluster) TiFlashUpgrading() bool {
 	return tc.Status.TiFlash.Phase == UpgradePhase
 }
 
+func (tc *TidbCluster) TiCDCUpgrading() bool {
+	return tc.Status.TiCDC.Phase == UpgradePhase
+}
+
+func (tc *TidbCluster) TiCDCScaling() bool {
+	return tc.Status.TiCDC.Phase == ScalePhase
+}
+
 func (tc *TidbCluster) getDeleteSlots(component string) (deleteSlots sets.Int32) {
 	deleteSlots = sets.NewInt32()
 	annotations := tc.GetAnnotations()
@@ -372,7 +423,19 @@ func (tc *TidbCluster) TiKVAllStoresReady() bool {
 	return true
 }
 
+// TiKVFailoverMode returns the failover mode of TiKV, defaults to AddReplica
+func (tc *TidbCluster) TiKVFailoverMode() TiKVFailoverMode {
+	if tc.Spec.TiKV.FailoverMode == "" {
+		return TiKVFailoverModeAddReplica
+	}
+	return tc.Spec.TiKV.FailoverMode
+}
+
 func (tc *TidbCluster) TiKVStsDesiredReplicas() int32 {
+	if tc.TiKVFailoverMode() == TiKVFailoverModeReplaceInPlace {
+		// failed stores are replaced with the same ordinals
+		return tc.Spec.TiKV.Replicas
+	}
 	return tc.Spec.TiKV.Replicas + int32(len(tc.Status.TiKV.FailureStores))
 }
 
@@ -392,6 +455,71 @@ func (tc *TidbCluster) TiKVStsDesiredOrdinals(excludeFailover bool) sets.Int32 {
 	return helper.GetPodOrdinalsFromReplicasAndDeleteSlots(replicas, tc.getDeleteSlots(label.TiKVLabelVal))
 }
 
+// PlanOnly returns whether the spec should not be applied, only the actions
+// expected to be taken are published in the status
+func (tc *TidbCluster) PlanOnly() bool {
+	return tc.GetAnnotations()[label.AnnPlanKey] == label.AnnPlanVal
+}
+
+// Bootstrapping returns whether the tidb cluster is being bootstrapped from
+// a backup, the TiDB service is not created until the restore completes
+func (tc *TidbCluster) Bootstrapping() bool {
+	return tc.Spec.BootstrapFrom != nil && tc.Status.Bootstrap != nil && tc.Status.Bootstrap.Phase != RestoreComplete
+}
+
+// TiKVGroupName returns the name of the TiKV group this TidbCluster is synced
+// for, it is empty for the default TiKV
+func (tc *TidbCluster) TiKVGroupName() string {
+	return tc.GetLabels()[label.TiKVGroupLabelKey]
+}
+
+// TiKVGroupCluster returns a shallow copy of the TidbCluster whose TiKV spec
+// and status are the ones of the given group, so that the group can be synced
+// like the default TiKV. The status of the group must be read back from the
+// returned TidbCluster after syncing.
+func (tc *TidbCluster) TiKVGroupCluster(group *TiKVGroupSpec) *TidbCluster {
+	gtc := *tc
+	gtc.ObjectMeta = *tc.ObjectMeta.DeepCopy()
+	if gtc.Labels == nil {
+		gtc.Labels = map[string]string{}
+	}
+	gtc.Labels[label.TiKVGroupLabelKey] = group.Name
+	// delete slots and auto-scaling of TiKV only apply to the default TiKV
+	delete(gtc.Annotations, label.AnnTiKVDeleteSlots)
+	delete(gtc.Annotations, label.AnnTiKVAutoScalingOutOrdinals)
+	gtc.Spec.TiKV = &group.TiKVSpec
+	gtc.Status.TiKV = tc.Status.TiKV.Groups[group.Name]
+	gtc.Status.TiKV.Groups = nil
+	return &gtc
+}
+
+// TiDBGroupName returns the name of the TiDB group this TidbCluster is synced
+// for, it is empty for the default TiDB
+func (tc *TidbCluster) TiDBGroupName() string {
+	return tc.GetLabels()[label.TiDBGroupLabelKey]
+}
+
+// TiDBGroupCluster returns a shallow copy of the TidbCluster whose TiDB spec
+// and status are the ones of the given group, so that the group can be synced
+// like the default TiDB. The status of the group must be read back from the
+// returned TidbCluster after syncing.
+func (tc *TidbCluster) TiDBGroupCluster(group *TiDBGroupSpec) *TidbCluster {
+	gtc := *tc
+	gtc.ObjectMeta = *tc.ObjectMeta.DeepCopy()
+	if gtc.Labels == nil {
+		gtc.Labels = map[string]string{}
+	}
+	gtc.Labels[label.TiDBGroupLabelKey] = group.Name
+	// delete slots and auto-scaling out ordinals of TiDB only apply to the
+	// default TiDB, the groups are auto-scaled in place by their replicas
+	delete(gtc.Annotations, label.AnnTiDBDeleteSlots)
+	delete(gtc.Annotations, label.AnnTiDBAutoScalingOutOrdinals)
+	gtc.Spec.TiDB = &group.TiDBSpec
+	gtc.Status.TiDB = tc.Status.TiDB.Groups[group.Name]
+	gtc.Status.TiDB.Groups = nil
+	return &gtc
+}
+
 func (tc *TidbCluster) TiFlashAllPodsStarted() bool {
 	return tc.TiFlashStsDesiredReplicas() == tc.TiFlashStsActualReplicas()
 }
@@ -669,6 +797,54 @@ func (tc *TidbCluster) TiCDCTimezone() string {
 	return tc.Timezone()
 }
 
+func (tc *TidbCluster) TiCDCGracefulShutdownTimeout() time.Duration {
+	if tc.Spec.TiCDC != nil && tc.Spec.TiCDC.GracefulShutdownTimeout != nil {
+		d, err := time.ParseDuration(*tc.Spec.TiCDC.GracefulShutdownTimeout)
+		if err == nil {
+			return d
+		}
+	}
+	return defaultTiCDCGracefulShutdownTimeout
+}
+
+func (tc *TidbCluster) TiDBDrainEnabled() bool {
+	return tc.Spec.TiDB != nil && tc.Spec.TiDB.Drain != nil && tc.Spec.TiDB.Drain.Enabled
+}
+
+func (tc *TidbCluster) TiDBDrainTimeout() time.Duration {
+	if tc.TiDBDrainEnabled() && tc.Spec.TiDB.Drain.Timeout != nil {
+		d, err := time.ParseDuration(*tc.Spec.TiDB.Drain.Timeout)
+		if err == nil {
+			return d
+		}
+	}
+	return defaultTiDBDrainTimeout
+}
+
+// CanaryPods returns the count of the canary pods
+func (s *CanaryUpgradeStrategy) CanaryPods() int32 {
+	if s.Pods != nil {
+		return *s.Pods
+	}
+	return 1
+}
+
+// CanaryBakeTime returns the time to watch the health of the canary pods
+func (s *CanaryUpgradeStrategy) CanaryBakeTime() time.Duration {
+	if s.BakeTime != nil {
+		d, err := time.ParseDuration(*s.BakeTime)
+		if err == nil {
+			return d
+		}
+	}
+	return defaultCanaryBakeTime
+}
+
+// AutoRollbackEnabled returns whether to roll back the unhealthy canary pods
+func (s *CanaryUpgradeStrategy) AutoRollbackEnabled() bool {
+	return s.AutoRollback == nil || *s.AutoRollback
+}
+
 func (tc *TidbCluster) TiCDCGCTTL() int32 {
 	if tc.Spec.TiCDC != nil && tc.Spec.TiCDC.Config != nil && tc.Spec.TiCDC.Config.GCTTL != nil {
 		return *tc.Spec.TiCDC.Config.GCTTL
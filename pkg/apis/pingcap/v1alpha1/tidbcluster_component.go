@@ -44,6 +44,7 @@ type ComponentAccessor interface {
 	AdditionalVolumeMounts() []corev1.VolumeMount
 	TerminationGracePeriodSeconds() *int64
 	StatefulSetUpdateStrategy() apps.StatefulSetUpdateStrategyType
+	CanaryUpgradeStrategy() *CanaryUpgradeStrategy
 }
 
 type componentAccessorImpl struct {
@@ -225,6 +226,13 @@ func (a *componentAccessorImpl) TerminationGracePeriodSeconds() *int64 {
 	return a.ComponentSpec.TerminationGracePeriodSeconds
 }
 
+func (a *componentAccessorImpl) CanaryUpgradeStrategy() *CanaryUpgradeStrategy {
+	if a.ComponentSpec.UpgradeStrategy == nil {
+		return nil
+	}
+	return a.ComponentSpec.UpgradeStrategy.Canary
+}
+
 func buildTidbClusterComponentAccessor(spec *TidbClusterSpec, componentSpec *ComponentSpec) ComponentAccessor {
 	return &componentAccessorImpl{
 		imagePullPolicy:           spec.ImagePullPolicy,
@@ -295,6 +303,16 @@ func (tc *TidbCluster) BasePumpSpec() (ComponentAccessor, bool) {
 	return buildTidbClusterComponentAccessor(&tc.Spec, &tc.Spec.Pump.ComponentSpec), true
 }
 
+// BaseDrainerSpec returns two results:
+// 1. the base drainer spec, if exists.
+// 2. whether the base drainer spec exists.
+func (tc *TidbCluster) BaseDrainerSpec() (ComponentAccessor, bool) {
+	if tc.Spec.Drainer == nil {
+		return nil, false
+	}
+	return buildTidbClusterComponentAccessor(&tc.Spec, &tc.Spec.Drainer.ComponentSpec), true
+}
+
 func (dc *DMCluster) BaseMasterSpec() ComponentAccessor {
 	return buildDMClusterComponentAccessor(&dc.Spec, &dc.Spec.Master.ComponentSpec)
 }
@@ -249,6 +249,22 @@ func TestTiKVIsAvailable(t *testing.T) {
 	}
 }
 
+func TestTiKVPhaseOfGroups(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbCluster()
+	tc.Status.TiKV.Phase = NormalPhase
+	g.Expect(tc.TiKVUpgrading()).To(BeFalse())
+	g.Expect(tc.TiKVScaling()).To(BeFalse())
+
+	tc.Status.TiKV.Groups = map[string]TiKVStatus{
+		"hot":  {Phase: UpgradePhase},
+		"cold": {Phase: ScalePhase},
+	}
+	g.Expect(tc.TiKVUpgrading()).To(BeTrue())
+	g.Expect(tc.TiKVScaling()).To(BeTrue())
+}
+
 func TestComponentAccessor(t *testing.T) {
 	g := NewGomegaWithT(t)
 
@@ -61,6 +61,12 @@ type TidbClusterAutoScalerSpec struct {
 	// TiDB represents the auto-scaling spec for tidb
 	// +optional
 	TiDB *TidbAutoScalerSpec `json:"tidb,omitempty"`
+
+	// TiDBGroups represents the auto-scaling spec for the tidb groups of the
+	// target TidbCluster, the key is the name of the group. Only the external
+	// service is supported and the replicas of the group are updated in place.
+	// +optional
+	TiDBGroups map[string]TidbAutoScalerSpec `json:"tidbGroups,omitempty"`
 }
 
 // +k8s:openapi-gen=true
@@ -18,6 +18,7 @@ import (
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -38,6 +39,26 @@ const (
 	DMWorkerStateBound string = "bound"
 	// DMWorkerStateOffline represents status of offline of dm-worker
 	DMWorkerStateOffline string = "offline"
+
+	// PumpStateOnline represents status of online of pump
+	PumpStateOnline string = "online"
+	// PumpStatePausing represents status of pausing of pump
+	PumpStatePausing string = "pausing"
+	// PumpStatePaused represents status of paused of pump
+	PumpStatePaused string = "paused"
+	// PumpStateClosing represents status of closing of pump
+	PumpStateClosing string = "closing"
+	// PumpStateOffline represents status of offline of pump
+	PumpStateOffline string = "offline"
+
+	// DrainerStateOnline represents status of online of drainer
+	DrainerStateOnline string = "online"
+	// DrainerStatePaused represents status of paused of drainer
+	DrainerStatePaused string = "paused"
+	// DrainerStateClosing represents status of closing of drainer
+	DrainerStateClosing string = "closing"
+	// DrainerStateOffline represents status of offline of drainer
+	DrainerStateOffline string = "offline"
 )
 
 // MemberType represents member type
@@ -54,6 +75,10 @@ const (
 	TiFlashMemberType MemberType = "tiflash"
 	// TiCDCMemberType is ticdc container type
 	TiCDCMemberType MemberType = "ticdc"
+	// PumpMemberType is pump container type
+	PumpMemberType MemberType = "pump"
+	// DrainerMemberType is drainer container type
+	DrainerMemberType MemberType = "drainer"
 	// DMMasterMemberType is dm-master container type
 	DMMasterMemberType MemberType = "dm-master"
 	// DMWorkerMemberType is dm-worker container type
@@ -89,6 +114,19 @@ const (
 	ConfigUpdateStrategyRollingUpdate ConfigUpdateStrategy = "RollingUpdate"
 )
 
+// TiKVFailoverMode represents how the operator replaces a failed TiKV store
+type TiKVFailoverMode string
+
+const (
+	// TiKVFailoverModeAddReplica adds an extra replica for every failed store
+	// and keeps the failed pod until the failover is recovered
+	TiKVFailoverModeAddReplica TiKVFailoverMode = "AddReplica"
+	// TiKVFailoverModeReplaceInPlace deletes the failed store through PD and
+	// recreates the pod with the same ordinal on fresh storage once the store
+	// becomes Tombstone, so the number of replicas never changes
+	TiKVFailoverModeReplaceInPlace TiKVFailoverMode = "ReplaceInPlace"
+)
+
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
@@ -136,10 +174,22 @@ type TidbClusterSpec struct {
 	// +optional
 	TiDB *TiDBSpec `json:"tidb,omitempty"`
 
+	// TiDBGroups are additional groups of TiDB, each group is managed by its
+	// own StatefulSet and Service and can have different config and resources.
+	// The Service of the default TiDB selects only the default TiDB servers
+	// after they are rolled with the default group label.
+	// +optional
+	TiDBGroups []TiDBGroupSpec `json:"tidbGroups,omitempty"`
+
 	// TiKV cluster spec
 	// +optional
 	TiKV *TiKVSpec `json:"tikv,omitempty"`
 
+	// TiKVGroups are additional groups of TiKV, each group is managed by its
+	// own StatefulSet and can have different storage, resources and store labels
+	// +optional
+	TiKVGroups []TiKVGroupSpec `json:"tikvGroups,omitempty"`
+
 	// TiFlash cluster spec
 	// +optional
 	TiFlash *TiFlashSpec `json:"tiflash,omitempty"`
@@ -152,6 +202,10 @@ type TidbClusterSpec struct {
 	// +optional
 	Pump *PumpSpec `json:"pump,omitempty"`
 
+	// Drainer cluster spec
+	// +optional
+	Drainer *DrainerSpec `json:"drainer,omitempty"`
+
 	// Helper spec
 	// +optional
 	Helper *HelperSpec `json:"helper,omitempty"`
@@ -161,6 +215,26 @@ type TidbClusterSpec struct {
 	// +optional
 	Paused bool `json:"paused,omitempty"`
 
+	// Suspend indicates that the components of the tidb cluster are scaled
+	// to zero in order while their PVCs are kept, the components are scaled
+	// back in dependency order when it is unset. Failover is disabled until
+	// the components are resumed.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// BootstrapFrom is the backup data restored to the tidb cluster after it is
+	// created, the TiDB service is not created and the cluster is not Ready
+	// until the restore completes. It is ignored for an existing cluster.
+	// +optional
+	BootstrapFrom *BootstrapFromSpec `json:"bootstrapFrom,omitempty"`
+
+	// MaintenanceWindows are the windows in which the disruptive operations,
+	// e.g. rolling upgrades and PVC resizes, are allowed. The operations wait
+	// for the next window if they are started out of the windows, failover is
+	// not affected. The operations are allowed at any time if it is empty.
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
 	// TiDB cluster version
 	// +optional
 	Version string `json:"version"`
@@ -183,8 +257,9 @@ type TidbClusterSpec struct {
 	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
 
 	// ConfigUpdateStrategy determines how the configuration change is applied to the cluster.
-	// UpdateStrategyInPlace will update the ConfigMap of configuration in-place and an extra rolling-update of the
-	// cluster component is needed to reload the configuration change.
+	// UpdateStrategyInPlace will update the ConfigMap of configuration in-place, for PD, TiKV and TiDB the changed
+	// items that can be changed online are applied through their HTTP APIs, and the pods are rolled only when
+	// other items are changed. For the other components an extra rolling-update is needed to reload the configuration change.
 	// UpdateStrategyRollingUpdate will create a new ConfigMap with the new configuration and rolling-update the
 	// related components to use the new ConfigMap, that is, the new configuration will be applied automatically.
 	// +kubebuilder:validation:Enum=InPlace,RollingUpdate
@@ -268,6 +343,7 @@ type TidbClusterStatus struct {
 	TiKV       TiKVStatus                `json:"tikv,omitempty"`
 	TiDB       TiDBStatus                `json:"tidb,omitempty"`
 	Pump       PumpStatus                `json:"pump,omitempty"`
+	Drainer    DrainerStatus             `json:"drainer,omitempty"`
 	TiFlash    TiFlashStatus             `json:"tiflash,omitempty"`
 	TiCDC      TiCDCStatus               `json:"ticdc,omitempty"`
 	Monitor    *TidbMonitorRef           `json:"monitor,omitempty"`
@@ -275,6 +351,19 @@ type TidbClusterStatus struct {
 	// Represents the latest available observations of a tidb cluster's state.
 	// +optional
 	Conditions []TidbClusterCondition `json:"conditions,omitempty"`
+	// Plan is the actions expected to be taken for the spec, it is only
+	// made when the plan annotation is set and the spec is not applied
+	// +optional
+	Plan *TidbClusterPlan `json:"plan,omitempty"`
+	// Suspend is the status of the hibernation, it is nil when the cluster is not suspended
+	// +optional
+	Suspend *SuspendStatus `json:"suspend,omitempty"`
+	// Bootstrap is the status of the restore bootstrapping the tidb cluster
+	// +optional
+	Bootstrap *BootstrapStatus `json:"bootstrap,omitempty"`
+	// ResizingPVCs are the PVCs being resized, keyed by PVC name
+	// +optional
+	ResizingPVCs map[string]PVCResizeStatus `json:"resizingPVCs,omitempty"`
 }
 
 // TidbClusterCondition describes the state of a tidb cluster at a certain point.
@@ -308,8 +397,152 @@ const (
 	// - All TiKV stores are up.
 	// - All TiFlash stores are up.
 	TidbClusterReady TidbClusterConditionType = "Ready"
+	// TidbClusterMaintenancePending indicates that there are disruptive
+	// operations waiting for the maintenance window.
+	TidbClusterMaintenancePending TidbClusterConditionType = "MaintenancePending"
+	// TidbClusterNodeDraining indicates that there are TiKV or TiFlash stores
+	// on the nodes being drained.
+	TidbClusterNodeDraining TidbClusterConditionType = "NodeDraining"
+	// TidbClusterVolumeResizing indicates that there are PVCs whose capacity
+	// is less than the storage request.
+	TidbClusterVolumeResizing TidbClusterConditionType = "VolumeResizing"
+	// TidbClusterPDAvailable indicates that the quorum of the PD members is healthy.
+	TidbClusterPDAvailable TidbClusterConditionType = "PDAvailable"
+	// TidbClusterTiKVStoresHealthy indicates that all the TiKV stores, including
+	// the ones of the TiKV groups, are up.
+	TidbClusterTiKVStoresHealthy TidbClusterConditionType = "TiKVStoresHealthy"
+	// TidbClusterTiDBAvailable indicates that at least one TiDB member of the
+	// default TiDB or the TiDB groups is healthy.
+	TidbClusterTiDBAvailable TidbClusterConditionType = "TiDBAvailable"
+	// TidbClusterUpgrading indicates that there are components being upgraded.
+	TidbClusterUpgrading TidbClusterConditionType = "Upgrading"
+	// TidbClusterScaling indicates that there are components being scaled.
+	TidbClusterScaling TidbClusterConditionType = "Scaling"
+	// TidbClusterFailoverActive indicates that there are components with
+	// failure members or stores which are failed over.
+	TidbClusterFailoverActive TidbClusterConditionType = "FailoverActive"
+	// TidbClusterConfigOutOfSync indicates that there are components whose
+	// pods are not updated to the latest statefulset revision or whose config
+	// applied in place waits for the pods to be rolled, i.e. the latest config
+	// and spec are not applied to all the pods yet.
+	TidbClusterConfigOutOfSync TidbClusterConditionType = "ConfigOutOfSync"
+)
+
+// MaintenanceWindow is a recurring window in which the disruptive operations are allowed
+type MaintenanceWindow struct {
+	// Schedule is the cron expression of the start of the window, e.g. "0 2 * * 6"
+	Schedule string `json:"schedule"`
+	// Duration is the length of the window, in the format of Go Duration, e.g. "4h"
+	Duration string `json:"duration"`
+	// TimeZone is the IANA time zone name of the schedule, e.g. "Asia/Shanghai"
+	// Optional: Defaults to UTC
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// BootstrapFromSpec describes the backup data a tidb cluster is bootstrapped from
+type BootstrapFromSpec struct {
+	// Backup is the name of a completed Backup in the namespace of the tidb cluster,
+	// the storage location and the tool of the Backup are used for the restore
+	// +optional
+	Backup string `json:"backup,omitempty"`
+	// Restore is the template of the Restore created to bootstrap the tidb cluster,
+	// the storage location in it is used when Backup is not set. The restore
+	// always targets the tidb cluster itself, restore.to.secretName is required.
+	// +optional
+	Restore *RestoreSpec `json:"restore,omitempty"`
+}
+
+// BootstrapStatus is the status of the restore bootstrapping a tidb cluster
+type BootstrapStatus struct {
+	// Restore is the name of the Restore created to bootstrap the tidb cluster,
+	// it is empty until the cluster is ready to be restored
+	// +optional
+	Restore string `json:"restore,omitempty"`
+	// Phase is the phase of the Restore
+	// +optional
+	Phase RestoreConditionType `json:"phase,omitempty"`
+	// TimeCompleted is the time at which the restore was completed
+	// +optional
+	TimeCompleted metav1.Time `json:"timeCompleted,omitempty"`
+	// Message is the reason of the failure of the Restore, the failed Restore
+	// is recreated after it is deleted or spec.bootstrapFrom is changed
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// SuspendPhase is the phase of the hibernation of a tidb cluster
+type SuspendPhase string
+
+const (
+	// SuspendPhaseSuspending means the components are being scaled to zero
+	SuspendPhaseSuspending SuspendPhase = "Suspending"
+	// SuspendPhaseSuspended means all the components are scaled to zero
+	SuspendPhaseSuspended SuspendPhase = "Suspended"
+	// SuspendPhaseResuming means the components are being scaled back
+	SuspendPhaseResuming SuspendPhase = "Resuming"
 )
 
+// SuspendStatus is the status of the hibernation of a tidb cluster
+type SuspendStatus struct {
+	Phase SuspendPhase `json:"phase"`
+	// Component is the component being suspended or resumed
+	// +optional
+	Component MemberType `json:"component,omitempty"`
+	// LastTransitionTime is the last time the phase changed
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// PlanActionType is the type of an action expected to be taken on a component
+type PlanActionType string
+
+const (
+	// PlanActionCreate means the statefulset of the component will be created
+	PlanActionCreate PlanActionType = "Create"
+	// PlanActionRestart means the pods will be rolled
+	PlanActionRestart PlanActionType = "Restart"
+	// PlanActionScaleOut means the pods will be added
+	PlanActionScaleOut PlanActionType = "ScaleOut"
+	// PlanActionScaleIn means the pods will be removed
+	PlanActionScaleIn PlanActionType = "ScaleIn"
+	// PlanActionReconfigure means the config items will be applied online without restarting the pods
+	PlanActionReconfigure PlanActionType = "Reconfigure"
+)
+
+// TidbClusterPlan is the actions expected to be taken for the spec of a TidbCluster
+type TidbClusterPlan struct {
+	// ObservedGeneration is the generation of the spec the plan is made for
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// GeneratedTime is the time the plan is made
+	GeneratedTime metav1.Time `json:"generatedTime,omitempty"`
+	// Components are the plans of the components which have actions to take,
+	// it is empty if nothing will be changed
+	// +optional
+	Components []ComponentPlan `json:"components,omitempty"`
+}
+
+// ComponentPlan is the actions expected to be taken on a component
+type ComponentPlan struct {
+	// Component is the member type of the component
+	Component MemberType `json:"component"`
+	// StatefulSet is the name of the statefulset of the component
+	StatefulSet string `json:"statefulSet"`
+	// Actions are the actions expected to be taken on the component
+	Actions []PlanAction `json:"actions"`
+}
+
+// PlanAction is an action expected to be taken on a component
+type PlanAction struct {
+	// Type is the type of the action
+	Type PlanActionType `json:"type"`
+	// Pods are the pods the action is taken on
+	// +optional
+	Pods []string `json:"pods,omitempty"`
+	// ConfigKeys are the changed config items which cause the action
+	// +optional
+	ConfigKeys []string `json:"configKeys,omitempty"`
+}
+
 // +k8s:openapi-gen=true
 // DiscoverySpec contains details of Discovery members
 type DiscoverySpec struct {
@@ -382,6 +615,43 @@ type PDSpec struct {
 	// MountClusterClientSecret indicates whether to mount `cluster-client-secret` to the Pod
 	// +optional
 	MountClusterClientSecret *bool `json:"mountClusterClientSecret,omitempty"`
+
+	// Schedulers are the PD schedulers declared to run, the operator adds the
+	// missing ones and removes the ones it added which are no longer declared.
+	// The evict-leader schedulers are owned by the operator and can not be declared.
+	// +optional
+	Schedulers []PDSchedulerSpec `json:"schedulers,omitempty"`
+}
+
+// PDSchedulerSpec is a PD scheduler declared in the spec
+// +k8s:openapi-gen=true
+type PDSchedulerSpec struct {
+	// Name of the scheduler as PD lists it, e.g. balance-hot-region-scheduler,
+	// shuffle-leader-scheduler, grant-leader-scheduler
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// StoreID is the store the scheduler is created for, it is required by
+	// the grant-leader-scheduler and only used when the scheduler is added
+	// +optional
+	StoreID *uint64 `json:"storeID,omitempty"`
+
+	// Config of the scheduler, it is set through the scheduler config API of
+	// PD and only the declared items are managed
+	// +optional
+	Config *config.GenericConfig `json:"config,omitempty"`
+}
+
+// TiKVGroupSpec contains details of a group of TiKV members
+// Delete slots and auto-scaling annotations of TiKV do not apply to the groups
+// +k8s:openapi-gen=true
+type TiKVGroupSpec struct {
+	// Name of the group, the StatefulSet of the group is named
+	// ${clusterName}-tikv-${name}
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	TiKVSpec `json:",inline"`
 }
 
 // TiKVSpec contains details of TiKV members
@@ -414,6 +684,12 @@ type TiKVSpec struct {
 	// +optional
 	MaxFailoverCount *int32 `json:"maxFailoverCount,omitempty"`
 
+	// FailoverMode is the way to replace a failed store, AddReplica or ReplaceInPlace
+	// Optional: Defaults to AddReplica
+	// +kubebuilder:validation:Enum=AddReplica;ReplaceInPlace
+	// +optional
+	FailoverMode TiKVFailoverMode `json:"failoverMode,omitempty"`
+
 	// The storageClassName of the persistent volume for TiKV data storage.
 	// Defaults to Kubernetes default storage class.
 	// +optional
@@ -520,6 +796,12 @@ type TiCDCSpec struct {
 	// Config is the Configuration of tidbcdc servers
 	// +optional
 	Config *TiCDCConfig `json:"config,omitempty"`
+
+	// GracefulShutdownTimeout is the timeout of draining the tables and resigning
+	// the ownership of a capture before its pod is deleted, in the format of Go Duration.
+	// Defaults to 10m
+	// +optional
+	GracefulShutdownTimeout *string `json:"gracefulShutdownTimeout,omitempty"`
 }
 
 // TiCDCConfig is the configuration of tidbcdc
@@ -565,6 +847,19 @@ type StorageClaim struct {
 	StorageClassName *string `json:"storageClassName,omitempty"`
 }
 
+// TiDBGroupSpec contains details of a group of TiDB members
+// Delete slots and auto-scaling annotations of TiDB do not apply to the groups,
+// the groups are auto-scaled by TidbClusterAutoScaler.Spec.TiDBGroups
+// +k8s:openapi-gen=true
+type TiDBGroupSpec struct {
+	// Name of the group, the StatefulSet and Service of the group are named
+	// ${clusterName}-tidb-${name}, so it can not be peer or initializer
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	TiDBSpec `json:",inline"`
+}
+
 // TiDBSpec contains details of TiDB members
 // +k8s:openapi-gen=true
 type TiDBSpec struct {
@@ -588,6 +883,12 @@ type TiDBSpec struct {
 	// +optional
 	Service *TiDBServiceSpec `json:"service,omitempty"`
 
+	// Drain defines how to drain the client connections of a TiDB pod before
+	// it is deleted during upgrading or scaling in.
+	// Optional: The pod is deleted without draining by default.
+	// +optional
+	Drain *TiDBDrainSpec `json:"drain,omitempty"`
+
 	// Whether enable TiDB Binlog, it is encouraged to not set this field and rely on the default behavior
 	// Optional: Defaults to true if PumpSpec is non-nil, otherwise false
 	// +optional
@@ -696,6 +997,79 @@ type PumpSpec struct {
 	SetTimeZone *bool `json:"setTimeZone,omitempty"`
 }
 
+// DrainerSpec contains details of Drainer members
+// +k8s:openapi-gen=true
+type DrainerSpec struct {
+	ComponentSpec               `json:",inline"`
+	corev1.ResourceRequirements `json:",inline"`
+
+	// Specify a Service Account for drainer
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+
+	// The desired ready replicas
+	// +kubebuilder:validation:Minimum=0
+	Replicas int32 `json:"replicas"`
+
+	// Base image of the component, image tag is now allowed during validation
+	// +kubebuilder:default=pingcap/tidb-binlog
+	// +optional
+	BaseImage string `json:"baseImage"`
+
+	// The storageClassName of the persistent volume for Drainer data storage.
+	// Defaults to Kubernetes default storage class.
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+
+	// The commit ts to start replicating from if drainer has no checkpoint.
+	// Optional: Defaults to -1, which means the current ts of PD
+	// +optional
+	InitialCommitTS *string `json:"initialCommitTs,omitempty"`
+
+	// Sink is the downstream drainer replicates binlogs to
+	// +optional
+	Sink *DrainerSinkSpec `json:"sink,omitempty"`
+
+	// Checkpoint is where drainer saves its checkpoint.
+	// Optional: Defaults to the downstream if it is mysql or tidb, or the
+	// local file otherwise
+	// +optional
+	Checkpoint *DrainerCheckpointSpec `json:"checkpoint,omitempty"`
+
+	// The configuration of Drainer cluster, it is overridden by sink and
+	// checkpoint if they are specified.
+	// +optional
+	Config *config.GenericConfig `json:"config,omitempty"`
+
+	// +k8s:openapi-gen=false
+	// For backward compatibility with helm chart
+	SetTimeZone *bool `json:"setTimeZone,omitempty"`
+}
+
+// DrainerSinkSpec is the downstream of drainer
+// +k8s:openapi-gen=true
+type DrainerSinkSpec struct {
+	// Type of the downstream, rendered as syncer.db-type
+	// +kubebuilder:validation:Enum=mysql;tidb;kafka;file
+	// +kubebuilder:default=file
+	Type string `json:"type"`
+
+	// The configuration of the downstream, rendered as syncer.to
+	// +optional
+	Config *config.GenericConfig `json:"config,omitempty"`
+}
+
+// DrainerCheckpointSpec is the storage of the drainer checkpoint
+// +k8s:openapi-gen=true
+type DrainerCheckpointSpec struct {
+	// Type of the checkpoint storage
+	// +kubebuilder:validation:Enum=mysql;tidb;file
+	Type string `json:"type"`
+
+	// The configuration of the checkpoint storage, rendered as syncer.to.checkpoint
+	// +optional
+	Config *config.GenericConfig `json:"config,omitempty"`
+}
+
 // HelperSpec contains details of helper component
 // +k8s:openapi-gen=true
 type HelperSpec struct {
@@ -710,6 +1084,21 @@ type HelperSpec struct {
 	ImagePullPolicy *corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
 }
 
+// TiDBDrainSpec defines how to drain the client connections of a TiDB pod.
+// The pod is removed from the endpoints of the TiDB service first, then the
+// operator waits until all its connections are closed or the timeout passes.
+// +k8s:openapi-gen=true
+type TiDBDrainSpec struct {
+	// Whether drain the connections before deleting a TiDB pod.
+	// Enabling it triggers a rolling update of TiDB to label the pods.
+	Enabled bool `json:"enabled"`
+
+	// Timeout of waiting for the connections to be closed, in the format of Go Duration.
+	// Defaults to 5m
+	// +optional
+	Timeout *string `json:"timeout,omitempty"`
+}
+
 // TiDBSlowLogTailerSpec represents an optional log tailer sidecar with TiDB
 // +k8s:openapi-gen=true
 type TiDBSlowLogTailerSpec struct {
@@ -834,6 +1223,107 @@ type ComponentSpec struct {
 	// Template.
 	// +optional
 	StatefulSetUpdateStrategy apps.StatefulSetUpdateStrategyType `json:"statefulSetUpdateStrategy,omitempty"`
+
+	// UpgradeStrategy indicates how the pods are upgraded when a revision is made to the component.
+	// It only takes effect for PD, TiKV and TiDB for now.
+	// Optional: Defaults to upgrade all the pods one by one
+	// +optional
+	UpgradeStrategy *UpgradeStrategy `json:"upgradeStrategy,omitempty"`
+}
+
+// UpgradeStrategy is the strategy of upgrading the pods of a component
+// +k8s:openapi-gen=true
+type UpgradeStrategy struct {
+	// Canary upgrades a few pods first and stops at a gate until the
+	// upgrade is approved, the upgrade is rolled back if the upgraded
+	// pods become unhealthy before it is approved.
+	// +optional
+	Canary *CanaryUpgradeStrategy `json:"canary,omitempty"`
+}
+
+// CanaryUpgradeStrategy is the strategy of the canary upgrade.
+// The pods are upgraded in the descending order of the ordinals, the
+// upgrade stops after the canary pods are upgraded and continues after
+// the annotation `<component>.tidb.pingcap.com/upgrade-approved` of the
+// TidbCluster is set to the revision in `.status.<component>.canary.revision`.
+// +k8s:openapi-gen=true
+type CanaryUpgradeStrategy struct {
+	// Pods is the count of the canary pods
+	// Optional: Defaults to 1
+	// +optional
+	Pods *int32 `json:"pods,omitempty"`
+
+	// PerZone upgrades the pods until every zone has at least one upgraded pod,
+	// `pods` is ignored if it is true.
+	// The zone of a pod is got from the `topology.kubernetes.io/zone` or
+	// `failure-domain.beta.kubernetes.io/zone` label of its node.
+	// +optional
+	PerZone bool `json:"perZone,omitempty"`
+
+	// BakeTime is the time to watch the health of the canary pods before the
+	// gate can be approved, it's a Go duration string, e.g. 10m.
+	// Optional: Defaults to 10m
+	// +optional
+	BakeTime *string `json:"bakeTime,omitempty"`
+
+	// AutoRollback rolls back the canary pods to the current revision if they
+	// become unhealthy or restart before the gate is approved.
+	// Optional: Defaults to true
+	// +optional
+	AutoRollback *bool `json:"autoRollback,omitempty"`
+}
+
+// CanaryUpgradePhase is the phase of the canary upgrade
+type CanaryUpgradePhase string
+
+const (
+	// CanaryUpgradePhaseUpgrading means the canary pods are being upgraded
+	CanaryUpgradePhaseUpgrading CanaryUpgradePhase = "Upgrading"
+	// CanaryUpgradePhaseBaking means the health of the canary pods is being watched
+	CanaryUpgradePhaseBaking CanaryUpgradePhase = "Baking"
+	// CanaryUpgradePhaseWaitingForApproval means the bake time has passed and
+	// the upgrade is waiting for the approval
+	CanaryUpgradePhaseWaitingForApproval CanaryUpgradePhase = "WaitingForApproval"
+	// CanaryUpgradePhaseApproved means the upgrade is approved and the rest
+	// pods are being upgraded
+	CanaryUpgradePhaseApproved CanaryUpgradePhase = "Approved"
+	// CanaryUpgradePhaseRolledBack means the canary pods are rolled back and
+	// the failed revision will not be applied again until the spec is changed
+	CanaryUpgradePhaseRolledBack CanaryUpgradePhase = "RolledBack"
+)
+
+// ConfigStatus is the status of the config applied in place to a component
+type ConfigStatus struct {
+	// Revision is the digest of the config applied to the running members,
+	// either online or by rolling the pods
+	Revision string `json:"revision,omitempty"`
+	// RestartRevision is the digest of the config the pods are rolled for,
+	// it changes only when items that can not be changed online are changed
+	RestartRevision string `json:"restartRevision,omitempty"`
+	// OnlineKeys are the config items of the last change applied online
+	OnlineKeys []string `json:"onlineKeys,omitempty"`
+	// RestartKeys are the config items of the last change applied by rolling the pods
+	RestartKeys []string `json:"restartKeys,omitempty"`
+	// LastAppliedTime is the last time a config change was applied
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+	// RestartPending indicates the pods are not rolled for RestartRevision yet,
+	// e.g. the rolling waits for the maintenance window
+	RestartPending bool `json:"restartPending,omitempty"`
+}
+
+// CanaryUpgradeStatus is the status of the canary upgrade of a component
+type CanaryUpgradeStatus struct {
+	Phase CanaryUpgradePhase `json:"phase,omitempty"`
+	// Revision is the update revision of the statefulset being upgraded,
+	// set it to the upgrade-approved annotation to approve the upgrade
+	Revision string `json:"revision,omitempty"`
+	// BakeStartTime is the time when all the canary pods become healthy
+	BakeStartTime *metav1.Time `json:"bakeStartTime,omitempty"`
+	// Restarts is the total restart count of the canary pods when the baking begins
+	Restarts int32 `json:"restarts,omitempty"`
+	// FailedTemplateHash is the hash of the pod template which is rolled back
+	FailedTemplateHash string `json:"failedTemplateHash,omitempty"`
+	Message            string `json:"message,omitempty"`
 }
 
 // ServiceSpec specifies the service object in k8s
@@ -919,6 +1409,16 @@ type PDStatus struct {
 	FailureMembers  map[string]PDFailureMember `json:"failureMembers,omitempty"`
 	UnjoinedMembers map[string]UnjoinedMember  `json:"unjoinedMembers,omitempty"`
 	Image           string                     `json:"image,omitempty"`
+	Canary          *CanaryUpgradeStatus       `json:"canary,omitempty"`
+	// Schedulers are the declared schedulers which have been added to PD by
+	// the operator, the ones already running in PD are not recorded
+	Schedulers []string `json:"schedulers,omitempty"`
+	// Config is the status of the config applied in place
+	Config *ConfigStatus `json:"config,omitempty"`
+	// LostMembers are the members whose local volumes are lost with their nodes, keyed by pod name
+	LostMembers map[string]LostMember `json:"lostMembers,omitempty"`
+	// ReplacingMembers are the members being replaced on demand, keyed by pod name
+	ReplacingMembers map[string]ReplacingMember `json:"replacingMembers,omitempty"`
 }
 
 // PDMember is PD member
@@ -942,6 +1442,53 @@ type PDFailureMember struct {
 	CreatedAt     metav1.Time `json:"createdAt,omitempty"`
 }
 
+// LostMember is a PD member or a TiKV store whose local volumes are lost with its
+// node, it is deleted from the cluster and recreated on another node
+type LostMember struct {
+	PodName string `json:"podName,omitempty"`
+	// ID is the member id of PD or the store id of TiKV
+	ID        string      `json:"id,omitempty"`
+	NodeName  string      `json:"nodeName,omitempty"`
+	CreatedAt metav1.Time `json:"createdAt,omitempty"`
+	// Deleted indicates the member or the store has been deleted through PD
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+// ReplacingMember is a PD member, a TiKV or TiFlash store or a pump whose pod is annotated
+// to be replaced or whose volumes are not of the storage class in the spec, it is deleted
+// from the cluster and its pod is recreated on fresh storage
+type ReplacingMember struct {
+	PodName string `json:"podName,omitempty"`
+	// ID is the member id of PD, the store id of TiKV and TiFlash or the node id of pump
+	ID        string      `json:"id,omitempty"`
+	CreatedAt metav1.Time `json:"createdAt,omitempty"`
+	// Deleted indicates the member or the store has been deleted through PD,
+	// or the pump has been made offline
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+// PVCResizePhase is the phase of resizing a PVC
+type PVCResizePhase string
+
+const (
+	// PVCResizing means the volume is being expanded by the storage provider
+	PVCResizing PVCResizePhase = "Resizing"
+	// PVCFileSystemResizePending means the volume is expanded and the file system
+	// is resized once the pod using it is restarted
+	PVCFileSystemResizePending PVCResizePhase = "FileSystemResizePending"
+)
+
+// PVCResizeStatus is the progress of resizing a PVC
+type PVCResizeStatus struct {
+	// PodName is the name of the pod using the PVC
+	PodName string `json:"podName,omitempty"`
+	// CurrentCapacity is the capacity of the volume
+	CurrentCapacity resource.Quantity `json:"currentCapacity,omitempty"`
+	// RequestedCapacity is the storage request of the PVC
+	RequestedCapacity resource.Quantity `json:"requestedCapacity,omitempty"`
+	Phase             PVCResizePhase    `json:"phase,omitempty"`
+}
+
 // UnjoinedMember is the pd unjoin cluster member information
 type UnjoinedMember struct {
 	PodName   string      `json:"podName,omitempty"`
@@ -957,6 +1504,11 @@ type TiDBStatus struct {
 	FailureMembers           map[string]TiDBFailureMember `json:"failureMembers,omitempty"`
 	ResignDDLOwnerRetryCount int32                        `json:"resignDDLOwnerRetryCount,omitempty"`
 	Image                    string                       `json:"image,omitempty"`
+	Canary                   *CanaryUpgradeStatus         `json:"canary,omitempty"`
+	// Groups is the status of the TiDB groups, keyed by group name
+	Groups map[string]TiDBStatus `json:"groups,omitempty"`
+	// Config is the status of the config applied in place
+	Config *ConfigStatus `json:"config,omitempty"`
 }
 
 // TiDBMember is TiDB member
@@ -985,6 +1537,26 @@ type TiKVStatus struct {
 	TombstoneStores map[string]TiKVStore        `json:"tombstoneStores,omitempty"`
 	FailureStores   map[string]TiKVFailureStore `json:"failureStores,omitempty"`
 	Image           string                      `json:"image,omitempty"`
+	Canary          *CanaryUpgradeStatus        `json:"canary,omitempty"`
+	// TotalCapacity is the total capacity of the stores
+	TotalCapacity resource.Quantity `json:"totalCapacity,omitempty"`
+	// UsedCapacity is the total size used by the data of the stores
+	UsedCapacity resource.Quantity `json:"usedCapacity,omitempty"`
+	// AvailableCapacity is the total available size of the stores
+	AvailableCapacity resource.Quantity `json:"availableCapacity,omitempty"`
+	// Groups is the status of the TiKV groups, keyed by group name
+	Groups map[string]TiKVStatus `json:"groups,omitempty"`
+	// Config is the status of the config applied in place
+	Config *ConfigStatus `json:"config,omitempty"`
+	// DrainingStores are the stores on the nodes being drained, keyed by store id
+	DrainingStores map[string]TiKVDrainingStore `json:"drainingStores,omitempty"`
+	// LostStores are the stores whose local volumes are lost with their nodes, keyed by store id
+	LostStores map[string]LostMember `json:"lostStores,omitempty"`
+	// ReplacingStores are the stores being replaced on demand, keyed by store id
+	ReplacingStores map[string]ReplacingMember `json:"replacingStores,omitempty"`
+	// ReplaceBlockedReason is the reason why the store requested to be replaced
+	// is not deleted yet, e.g. not enough stores are Up
+	ReplaceBlockedReason string `json:"replaceBlockedReason,omitempty"`
 }
 
 // TiFlashStatus is TiFlash status
@@ -997,6 +1569,19 @@ type TiFlashStatus struct {
 	TombstoneStores map[string]TiKVStore        `json:"tombstoneStores,omitempty"`
 	FailureStores   map[string]TiKVFailureStore `json:"failureStores,omitempty"`
 	Image           string                      `json:"image,omitempty"`
+	// TotalCapacity is the total capacity of the stores
+	TotalCapacity resource.Quantity `json:"totalCapacity,omitempty"`
+	// UsedCapacity is the total size used by the data of the stores
+	UsedCapacity resource.Quantity `json:"usedCapacity,omitempty"`
+	// AvailableCapacity is the total available size of the stores
+	AvailableCapacity resource.Quantity `json:"availableCapacity,omitempty"`
+	// DrainingStores are the stores on the nodes being drained, keyed by store id
+	DrainingStores map[string]TiKVDrainingStore `json:"drainingStores,omitempty"`
+	// ReplacingStores are the stores being replaced on demand, keyed by store id
+	ReplacingStores map[string]ReplacingMember `json:"replacingStores,omitempty"`
+	// ReplaceBlockedReason is the reason why the store requested to be replaced
+	// is not deleted yet, e.g. not enough stores are Up
+	ReplaceBlockedReason string `json:"replaceBlockedReason,omitempty"`
 }
 
 // TiCDCStatus is TiCDC status
@@ -1011,6 +1596,7 @@ type TiCDCStatus struct {
 type TiCDCCapture struct {
 	PodName string `json:"podName,omitempty"`
 	ID      string `json:"id,omitempty"`
+	IsOwner bool   `json:"isOwner,omitempty"`
 }
 
 // TiKVStores is either Up/Down/Offline/Tombstone
@@ -1024,6 +1610,15 @@ type TiKVStore struct {
 	LastHeartbeatTime metav1.Time `json:"lastHeartbeatTime"`
 	// Last time the health transitioned from one to another.
 	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	RegionCount        int32       `json:"regionCount,omitempty"`
+	// Capacity is the capacity of the store disk
+	Capacity resource.Quantity `json:"capacity,omitempty"`
+	// Available is the available size of the store disk
+	Available resource.Quantity `json:"available,omitempty"`
+	// UsedSize is the size used by the store data
+	UsedSize resource.Quantity `json:"usedSize,omitempty"`
+	// Labels are the labels of the store in PD
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 // TiKVFailureStore is the tikv failure store information
@@ -1031,12 +1626,66 @@ type TiKVFailureStore struct {
 	PodName   string      `json:"podName,omitempty"`
 	StoreID   string      `json:"storeID,omitempty"`
 	CreatedAt metav1.Time `json:"createdAt,omitempty"`
+	// StoreDeleted indicates the store has been deleted through PD, only used
+	// in ReplaceInPlace failover mode
+	StoreDeleted bool `json:"storeDeleted,omitempty"`
+}
+
+// TiKVDrainingStore is the store on a node being drained, the leaders of the
+// store are evicted, and the store is migrated to another node if its data
+// is on the local volumes of the node
+type TiKVDrainingStore struct {
+	PodName   string      `json:"podName,omitempty"`
+	StoreID   string      `json:"storeID,omitempty"`
+	NodeName  string      `json:"nodeName,omitempty"`
+	CreatedAt metav1.Time `json:"createdAt,omitempty"`
+	// StoreDeleted indicates the store has been deleted through PD to migrate
+	// it off the local volumes
+	StoreDeleted bool `json:"storeDeleted,omitempty"`
 }
 
 // PumpStatus is Pump status
 type PumpStatus struct {
 	Phase       MemberPhase             `json:"phase,omitempty"`
 	StatefulSet *apps.StatefulSetStatus `json:"statefulSet,omitempty"`
+	// Members are the pumps registered in PD, keyed by pod name
+	Members map[string]PumpMember `json:"members,omitempty"`
+	// ReplacingMembers are the pumps being replaced on demand, keyed by pod name
+	ReplacingMembers map[string]ReplacingMember `json:"replacingMembers,omitempty"`
+}
+
+// PumpMember is the status of a pump registered in PD
+type PumpMember struct {
+	// NodeID is the node ID of the pump
+	NodeID string `json:"nodeId"`
+	// Host is the advertise address of the pump
+	Host string `json:"host"`
+	// State is one of online/pausing/paused/closing/offline
+	State string `json:"state"`
+	// MaxCommitTS is the max commit ts of the binlogs written to the pump,
+	// TSO is also uint64, we store it as string like the ids
+	MaxCommitTS string `json:"maxCommitTS,omitempty"`
+}
+
+// DrainerStatus is Drainer status
+type DrainerStatus struct {
+	Phase       MemberPhase             `json:"phase,omitempty"`
+	StatefulSet *apps.StatefulSetStatus `json:"statefulSet,omitempty"`
+	// Members are the drainers registered in PD, keyed by pod name
+	Members map[string]DrainerMember `json:"members,omitempty"`
+}
+
+// DrainerMember is the status of a drainer registered in PD
+type DrainerMember struct {
+	// NodeID is the node ID of the drainer
+	NodeID string `json:"nodeId"`
+	// Host is the advertise address of the drainer
+	Host string `json:"host"`
+	// State is one of online/paused/closing/offline
+	State string `json:"state"`
+	// CheckpointTS is the commit ts of the binlogs replicated to the downstream,
+	// TSO is also uint64, we store it as string like the ids
+	CheckpointTS string `json:"checkpointTS,omitempty"`
 }
 
 // TiDBTLSClient can enable TLS connection between TiDB server and MySQL client
@@ -1714,6 +2363,9 @@ type DMClusterStatus struct {
 	// Represents the latest available observations of a dm cluster's state.
 	// +optional
 	Conditions []DMClusterCondition `json:"conditions,omitempty"`
+	// ResizingPVCs are the PVCs being resized, keyed by PVC name
+	// +optional
+	ResizingPVCs map[string]PVCResizeStatus `json:"resizingPVCs,omitempty"`
 }
 
 // +k8s:openapi-gen=true
@@ -1861,6 +2513,25 @@ const (
 	// - All Master members are healthy.
 	// - All Worker pods are up.
 	DMClusterReady DMClusterConditionType = "Ready"
+	// DMClusterVolumeResizing indicates that there are PVCs whose capacity
+	// is less than the storage request.
+	DMClusterVolumeResizing DMClusterConditionType = "VolumeResizing"
+	// DMClusterMasterAvailable indicates that the quorum of the dm-master members is healthy.
+	DMClusterMasterAvailable DMClusterConditionType = "MasterAvailable"
+	// DMClusterWorkersHealthy indicates that none of the dm-workers is offline.
+	DMClusterWorkersHealthy DMClusterConditionType = "WorkersHealthy"
+	// DMClusterUpgrading indicates that there are components being upgraded.
+	DMClusterUpgrading DMClusterConditionType = "Upgrading"
+	// DMClusterScaling indicates that there are components being scaled.
+	DMClusterScaling DMClusterConditionType = "Scaling"
+	// DMClusterFailoverActive indicates that there are components with
+	// failure members which are failed over.
+	DMClusterFailoverActive DMClusterConditionType = "FailoverActive"
+	// DMClusterConfigOutOfSync indicates that there are components whose
+	// pods are not updated to the latest statefulset revision or whose config
+	// applied in place waits for the pods to be rolled, i.e. the latest config
+	// and spec are not applied to all the pods yet.
+	DMClusterConfigOutOfSync DMClusterConditionType = "ConfigOutOfSync"
 )
 
 // MasterStatus is dm-master status
@@ -21,12 +21,15 @@ import (
 	"path"
 	"path/filepath"
 	"reflect"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/Masterminds/semver"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/label"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	"github.com/robfig/cron"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	apivalidation "k8s.io/apimachinery/pkg/api/validation"
@@ -102,12 +105,21 @@ func validateTiDBClusterSpec(spec *v1alpha1.TidbClusterSpec, fldPath *field.Path
 	if spec.TiKV != nil {
 		allErrs = append(allErrs, validateTiKVSpec(spec.TiKV, fldPath.Child("tikv"))...)
 	}
+	if len(spec.TiKVGroups) > 0 {
+		allErrs = append(allErrs, validateTiKVGroups(spec.TiKVGroups, fldPath.Child("tikvGroups"))...)
+	}
 	if spec.TiDB != nil {
 		allErrs = append(allErrs, validateTiDBSpec(spec.TiDB, fldPath.Child("tidb"))...)
 	}
+	if len(spec.TiDBGroups) > 0 {
+		allErrs = append(allErrs, validateTiDBGroups(spec.TiDBGroups, fldPath.Child("tidbGroups"))...)
+	}
 	if spec.Pump != nil {
 		allErrs = append(allErrs, validatePumpSpec(spec.Pump, fldPath.Child("pump"))...)
 	}
+	if spec.Drainer != nil {
+		allErrs = append(allErrs, validateDrainerSpec(spec.Drainer, fldPath.Child("drainer"))...)
+	}
 	if spec.TiFlash != nil {
 		allErrs = append(allErrs, validateTiFlashSpec(spec.TiFlash, fldPath.Child("tiflash"))...)
 	}
@@ -117,6 +129,45 @@ func validateTiDBClusterSpec(spec *v1alpha1.TidbClusterSpec, fldPath *field.Path
 	if spec.PDAddresses != nil {
 		allErrs = append(allErrs, validatePDAddresses(spec.PDAddresses, fldPath.Child("pdAddresses"))...)
 	}
+	if spec.BootstrapFrom != nil {
+		allErrs = append(allErrs, validateBootstrapFrom(spec.BootstrapFrom, fldPath.Child("bootstrapFrom"))...)
+	}
+	if len(spec.MaintenanceWindows) > 0 {
+		allErrs = append(allErrs, validateMaintenanceWindows(spec.MaintenanceWindows, fldPath.Child("maintenanceWindows"))...)
+	}
+	return allErrs
+}
+
+func validateMaintenanceWindows(windows []v1alpha1.MaintenanceWindow, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, window := range windows {
+		idxPath := fldPath.Index(i)
+		if _, err := cron.ParseStandard(window.Schedule); err != nil {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("schedule"), window.Schedule, err.Error()))
+		}
+		if d, err := time.ParseDuration(window.Duration); err != nil {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("duration"), window.Duration, err.Error()))
+		} else if d <= 0 {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("duration"), window.Duration, "must be greater than 0"))
+		}
+		if window.TimeZone != "" {
+			if _, err := time.LoadLocation(window.TimeZone); err != nil {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("timeZone"), window.TimeZone, err.Error()))
+			}
+		}
+	}
+	return allErrs
+}
+
+func validateBootstrapFrom(spec *v1alpha1.BootstrapFromSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if spec.Backup == "" && spec.Restore == nil {
+		allErrs = append(allErrs, field.Required(fldPath, "either backup or restore must be set"))
+	}
+	// the password of the new tidb cluster can not be derived from the backup
+	if spec.Restore == nil || spec.Restore.To == nil || spec.Restore.To.SecretName == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("restore", "to", "secretName"), "the secret of the password of the tidb cluster must be set"))
+	}
 	return allErrs
 }
 
@@ -127,6 +178,29 @@ func validatePDSpec(spec *v1alpha1.PDSpec, fldPath *field.Path) field.ErrorList
 	if len(spec.StorageVolumes) > 0 {
 		allErrs = append(allErrs, validateStorageVolumes(spec.StorageVolumes, fldPath.Child("storageVolumes"))...)
 	}
+	if len(spec.Schedulers) > 0 {
+		allErrs = append(allErrs, validatePDSchedulers(spec.Schedulers, fldPath.Child("schedulers"))...)
+	}
+	return allErrs
+}
+
+func validatePDSchedulers(schedulers []v1alpha1.PDSchedulerSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := map[string]bool{}
+	for i, scheduler := range schedulers {
+		idxPath := fldPath.Index(i).Child("name")
+		if scheduler.Name == "" {
+			allErrs = append(allErrs, field.Required(idxPath, "scheduler name must not be empty"))
+			continue
+		}
+		if pdapi.IsEvictLeaderScheduler(scheduler.Name) {
+			allErrs = append(allErrs, field.Forbidden(idxPath, "evict-leader schedulers are managed by the operator"))
+		}
+		if names[scheduler.Name] {
+			allErrs = append(allErrs, field.Duplicate(idxPath, scheduler.Name))
+		}
+		names[scheduler.Name] = true
+	}
 	return allErrs
 }
 
@@ -159,6 +233,84 @@ func validateTiKVSpec(spec *v1alpha1.TiKVSpec, fldPath *field.Path) field.ErrorL
 	return allErrs
 }
 
+func validateTiKVGroups(groups []v1alpha1.TiKVGroupSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := map[string]bool{}
+	for i := range groups {
+		idxPath := fldPath.Index(i)
+		name := groups[i].Name
+		for _, msg := range validation.IsDNS1123Label(name) {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("name"), name, msg))
+		}
+		if names[name] {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), name))
+		}
+		names[name] = true
+		allErrs = append(allErrs, validateTiKVSpec(&groups[i].TiKVSpec, idxPath)...)
+	}
+	return allErrs
+}
+
+// validateUpdateTiKVGroups checks that a TiKV group is scaled in to 0 before
+// it is removed, otherwise its StatefulSet and stores would be left behind
+func validateUpdateTiKVGroups(old, groups []v1alpha1.TiKVGroupSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := map[string]bool{}
+	for _, group := range groups {
+		names[group.Name] = true
+	}
+	for _, group := range old {
+		if !names[group.Name] && group.Replicas > 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("tikv group %s must be scaled in to 0 replicas before it is removed", group.Name)))
+		}
+	}
+	return allErrs
+}
+
+// reservedTiDBGroupNames are the suffixes of the resources of the default
+// TiDB, a group named with one of them would collide with these resources
+var reservedTiDBGroupNames = map[string]bool{
+	"peer":        true,
+	"initializer": true,
+}
+
+func validateTiDBGroups(groups []v1alpha1.TiDBGroupSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := map[string]bool{}
+	for i := range groups {
+		idxPath := fldPath.Index(i)
+		name := groups[i].Name
+		for _, msg := range validation.IsDNS1123Label(name) {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("name"), name, msg))
+		}
+		if reservedTiDBGroupNames[name] {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("name"), name, "the name is reserved"))
+		}
+		if names[name] {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), name))
+		}
+		names[name] = true
+		allErrs = append(allErrs, validateTiDBSpec(&groups[i].TiDBSpec, idxPath)...)
+	}
+	return allErrs
+}
+
+// validateUpdateTiDBGroups checks that a TiDB group is scaled in to 0 before
+// it is removed, otherwise its StatefulSet and Service would be left behind
+func validateUpdateTiDBGroups(old, groups []v1alpha1.TiDBGroupSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := map[string]bool{}
+	for _, group := range groups {
+		names[group.Name] = true
+	}
+	for _, group := range old {
+		if !names[group.Name] && group.Replicas > 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("tidb group %s must be scaled in to 0 replicas before it is removed", group.Name)))
+		}
+	}
+	return allErrs
+}
+
 func validateTiFlashSpec(spec *v1alpha1.TiFlashSpec, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	allErrs = append(allErrs, validateComponentSpec(&spec.ComponentSpec, fldPath)...)
@@ -173,6 +325,7 @@ func validateTiFlashSpec(spec *v1alpha1.TiFlashSpec, fldPath *field.Path) field.
 func validateTiCDCSpec(spec *v1alpha1.TiCDCSpec, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	allErrs = append(allErrs, validateComponentSpec(&spec.ComponentSpec, fldPath)...)
+	allErrs = append(allErrs, validateTimeDurationStr(spec.GracefulShutdownTimeout, fldPath.Child("gracefulShutdownTimeout"))...)
 	return allErrs
 }
 
@@ -236,6 +389,9 @@ func validateTiDBSpec(spec *v1alpha1.TiDBSpec, fldPath *field.Path) field.ErrorL
 	if len(spec.StorageVolumes) > 0 {
 		allErrs = append(allErrs, validateStorageVolumes(spec.StorageVolumes, fldPath.Child("storageVolumes"))...)
 	}
+	if spec.Drain != nil {
+		allErrs = append(allErrs, validateTimeDurationStr(spec.Drain.Timeout, fldPath.Child("drain", "timeout"))...)
+	}
 	return allErrs
 }
 
@@ -245,6 +401,23 @@ func validatePumpSpec(spec *v1alpha1.PumpSpec, fldPath *field.Path) field.ErrorL
 	return allErrs
 }
 
+func validateDrainerSpec(spec *v1alpha1.DrainerSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	allErrs = append(allErrs, validateComponentSpec(&spec.ComponentSpec, fldPath)...)
+	if spec.InitialCommitTS != nil {
+		if _, err := strconv.ParseInt(*spec.InitialCommitTS, 10, 64); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("initialCommitTs"), *spec.InitialCommitTS, "initialCommitTs must be an integer"))
+		}
+	}
+	if spec.Sink != nil && spec.Sink.Type == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("sink", "type"), "sink type must not be empty"))
+	}
+	if spec.Checkpoint != nil && spec.Checkpoint.Type == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("checkpoint", "type"), "checkpoint type must not be empty"))
+	}
+	return allErrs
+}
+
 func validateDMClusterSpec(spec *v1alpha1.DMClusterSpec, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	if spec.Version != "" {
@@ -284,6 +457,21 @@ func validateComponentSpec(spec *v1alpha1.ComponentSpec, fldPath *field.Path) fi
 	// TODO validate other fields
 	allErrs = append(allErrs, validateEnv(spec.Env, fldPath.Child("env"))...)
 	allErrs = append(allErrs, validateAdditionalContainers(spec.AdditionalContainers, fldPath.Child("additionalContainers"))...)
+	if spec.UpgradeStrategy != nil {
+		allErrs = append(allErrs, validateCanaryUpgradeStrategy(spec.UpgradeStrategy.Canary, fldPath.Child("upgradeStrategy", "canary"))...)
+	}
+	return allErrs
+}
+
+func validateCanaryUpgradeStrategy(strategy *v1alpha1.CanaryUpgradeStrategy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if strategy == nil {
+		return allErrs
+	}
+	if strategy.Pods != nil && *strategy.Pods < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("pods"), *strategy.Pods, "must be greater than 0"))
+	}
+	allErrs = append(allErrs, validateTimeDurationStr(strategy.BakeTime, fldPath.Child("bakeTime"))...)
 	return allErrs
 }
 
@@ -429,6 +617,8 @@ func ValidateUpdateTidbCluster(old, tc *v1alpha1.TidbCluster) field.ErrorList {
 			"The instance must not be mutate or set value other than the cluster name"))
 	}
 	allErrs = append(allErrs, validateUpdatePDConfig(old.Spec.PD.Config, tc.Spec.PD.Config, field.NewPath("spec.pd.config"))...)
+	allErrs = append(allErrs, validateUpdateTiKVGroups(old.Spec.TiKVGroups, tc.Spec.TiKVGroups, field.NewPath("spec.tikvGroups"))...)
+	allErrs = append(allErrs, validateUpdateTiDBGroups(old.Spec.TiDBGroups, tc.Spec.TiDBGroups, field.NewPath("spec.tidbGroups"))...)
 	allErrs = append(allErrs, disallowUsingLegacyAPIInNewCluster(old, tc)...)
 
 	return allErrs
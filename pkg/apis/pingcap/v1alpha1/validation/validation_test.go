@@ -632,3 +632,166 @@ func TestValidatePDAddresses(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateTiKVGroups(t *testing.T) {
+	group := func(name string) v1alpha1.TiKVGroupSpec {
+		return v1alpha1.TiKVGroupSpec{Name: name, TiKVSpec: v1alpha1.TiKVSpec{
+			Replicas: 3,
+			ResourceRequirements: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse("10G"),
+				},
+			},
+		}}
+	}
+
+	successCases := [][]v1alpha1.TiKVGroupSpec{
+		{group("hot")},
+		{group("hot"), group("cold")},
+	}
+	for _, c := range successCases {
+		errs := validateTiKVGroups(c, field.NewPath("tikvGroups"))
+		if len(errs) > 0 {
+			t.Errorf("expected success: %v", errs)
+		}
+	}
+
+	errorCases := [][]v1alpha1.TiKVGroupSpec{
+		{group("")},
+		{group("Hot")},
+		{group("hot"), group("hot")},
+	}
+	for _, c := range errorCases {
+		errs := validateTiKVGroups(c, field.NewPath("tikvGroups"))
+		if len(errs) == 0 {
+			t.Errorf("expected failure for %v", c)
+		}
+	}
+
+	removed := group("cold")
+	removed.Replicas = 0
+	if errs := validateUpdateTiKVGroups([]v1alpha1.TiKVGroupSpec{group("hot"), removed}, []v1alpha1.TiKVGroupSpec{group("hot")}, field.NewPath("tikvGroups")); len(errs) > 0 {
+		t.Errorf("expected success: %v", errs)
+	}
+	if errs := validateUpdateTiKVGroups([]v1alpha1.TiKVGroupSpec{group("hot"), group("cold")}, []v1alpha1.TiKVGroupSpec{group("hot")}, field.NewPath("tikvGroups")); len(errs) == 0 {
+		t.Errorf("expected failure when removing a group with replicas")
+	}
+}
+
+func TestValidateTiDBGroups(t *testing.T) {
+	group := func(name string) v1alpha1.TiDBGroupSpec {
+		return v1alpha1.TiDBGroupSpec{Name: name, TiDBSpec: v1alpha1.TiDBSpec{Replicas: 2}}
+	}
+
+	successCases := [][]v1alpha1.TiDBGroupSpec{
+		{group("oltp")},
+		{group("oltp"), group("olap")},
+	}
+	for _, c := range successCases {
+		errs := validateTiDBGroups(c, field.NewPath("tidbGroups"))
+		if len(errs) > 0 {
+			t.Errorf("expected success: %v", errs)
+		}
+	}
+
+	errorCases := [][]v1alpha1.TiDBGroupSpec{
+		{group("")},
+		{group("OLTP")},
+		{group("oltp"), group("oltp")},
+		{group("peer")},
+		{group("initializer")},
+	}
+	for _, c := range errorCases {
+		errs := validateTiDBGroups(c, field.NewPath("tidbGroups"))
+		if len(errs) == 0 {
+			t.Errorf("expected failure for %v", c)
+		}
+	}
+
+	removed := group("olap")
+	removed.Replicas = 0
+	if errs := validateUpdateTiDBGroups([]v1alpha1.TiDBGroupSpec{group("oltp"), removed}, []v1alpha1.TiDBGroupSpec{group("oltp")}, field.NewPath("tidbGroups")); len(errs) > 0 {
+		t.Errorf("expected success: %v", errs)
+	}
+	if errs := validateUpdateTiDBGroups([]v1alpha1.TiDBGroupSpec{group("oltp"), group("olap")}, []v1alpha1.TiDBGroupSpec{group("oltp")}, field.NewPath("tidbGroups")); len(errs) == 0 {
+		t.Errorf("expected failure when removing a group with replicas")
+	}
+}
+
+func TestValidatePDSchedulers(t *testing.T) {
+	successCases := [][]v1alpha1.PDSchedulerSpec{
+		{{Name: "balance-hot-region-scheduler"}},
+		{{Name: "shuffle-leader-scheduler"}, {Name: "grant-leader-scheduler"}},
+	}
+	for _, c := range successCases {
+		errs := validatePDSchedulers(c, field.NewPath("schedulers"))
+		if len(errs) > 0 {
+			t.Errorf("expected success: %v", errs)
+		}
+	}
+
+	errorCases := [][]v1alpha1.PDSchedulerSpec{
+		{{Name: ""}},
+		{{Name: "evict-leader-scheduler-1"}},
+		{{Name: "shuffle-leader-scheduler"}, {Name: "shuffle-leader-scheduler"}},
+	}
+	for _, c := range errorCases {
+		errs := validatePDSchedulers(c, field.NewPath("schedulers"))
+		if len(errs) == 0 {
+			t.Errorf("expected failure for %v", c)
+		}
+	}
+}
+
+func TestValidateBootstrapFrom(t *testing.T) {
+	withSecret := &v1alpha1.RestoreSpec{To: &v1alpha1.TiDBAccessConfig{SecretName: "secret"}}
+	successCases := []v1alpha1.BootstrapFromSpec{
+		{Backup: "backup", Restore: withSecret},
+		{Restore: withSecret},
+	}
+	for _, c := range successCases {
+		errs := validateBootstrapFrom(&c, field.NewPath("bootstrapFrom"))
+		if len(errs) > 0 {
+			t.Errorf("expected success: %v", errs)
+		}
+	}
+
+	errorCases := []v1alpha1.BootstrapFromSpec{
+		{},
+		{Backup: "backup"},
+		{Backup: "backup", Restore: &v1alpha1.RestoreSpec{}},
+		{Backup: "backup", Restore: &v1alpha1.RestoreSpec{To: &v1alpha1.TiDBAccessConfig{}}},
+	}
+	for _, c := range errorCases {
+		errs := validateBootstrapFrom(&c, field.NewPath("bootstrapFrom"))
+		if len(errs) == 0 {
+			t.Errorf("expected failure for %v", c)
+		}
+	}
+}
+
+func TestValidateMaintenanceWindows(t *testing.T) {
+	successCases := [][]v1alpha1.MaintenanceWindow{
+		{{Schedule: "0 2 * * 6", Duration: "4h"}},
+		{{Schedule: "0 2 * * *", Duration: "1h", TimeZone: "Asia/Shanghai"}, {Schedule: "30 22 * * 0", Duration: "90m"}},
+	}
+	for _, c := range successCases {
+		errs := validateMaintenanceWindows(c, field.NewPath("maintenanceWindows"))
+		if len(errs) > 0 {
+			t.Errorf("expected success: %v", errs)
+		}
+	}
+
+	errorCases := [][]v1alpha1.MaintenanceWindow{
+		{{Schedule: "0 2 * *", Duration: "4h"}},
+		{{Schedule: "0 2 * * 6", Duration: "4"}},
+		{{Schedule: "0 2 * * 6", Duration: "-1h"}},
+		{{Schedule: "0 2 * * 6", Duration: "4h", TimeZone: "Mars/Olympus"}},
+	}
+	for _, c := range errorCases {
+		errs := validateMaintenanceWindows(c, field.NewPath("maintenanceWindows"))
+		if len(errs) == 0 {
+			t.Errorf("expected failure for %v", c)
+		}
+	}
+}
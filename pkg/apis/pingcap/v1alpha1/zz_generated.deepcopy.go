@@ -519,6 +519,95 @@ func (in *Binlog) DeepCopy() *Binlog {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapFromSpec) DeepCopyInto(out *BootstrapFromSpec) {
+	*out = *in
+	if in.Restore != nil {
+		in, out := &in.Restore, &out.Restore
+		*out = new(RestoreSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapFromSpec.
+func (in *BootstrapFromSpec) DeepCopy() *BootstrapFromSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BootstrapFromSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapStatus) DeepCopyInto(out *BootstrapStatus) {
+	*out = *in
+	in.TimeCompleted.DeepCopyInto(&out.TimeCompleted)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapStatus.
+func (in *BootstrapStatus) DeepCopy() *BootstrapStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BootstrapStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryUpgradeStatus) DeepCopyInto(out *CanaryUpgradeStatus) {
+	*out = *in
+	if in.BakeStartTime != nil {
+		in, out := &in.BakeStartTime, &out.BakeStartTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryUpgradeStatus.
+func (in *CanaryUpgradeStatus) DeepCopy() *CanaryUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryUpgradeStrategy) DeepCopyInto(out *CanaryUpgradeStrategy) {
+	*out = *in
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = new(int32)
+		**out = **in
+	}
+	if in.BakeTime != nil {
+		in, out := &in.BakeTime, &out.BakeTime
+		*out = new(string)
+		**out = **in
+	}
+	if in.AutoRollback != nil {
+		in, out := &in.AutoRollback, &out.AutoRollback
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryUpgradeStrategy.
+func (in *CanaryUpgradeStrategy) DeepCopy() *CanaryUpgradeStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryUpgradeStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterRef) DeepCopyInto(out *ClusterRef) {
 	*out = *in
@@ -661,6 +750,29 @@ func (in *CommonConfig) DeepCopy() *CommonConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentPlan) DeepCopyInto(out *ComponentPlan) {
+	*out = *in
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]PlanAction, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentPlan.
+func (in *ComponentPlan) DeepCopy() *ComponentPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ComponentSpec) DeepCopyInto(out *ComponentSpec) {
 	*out = *in
@@ -763,6 +875,11 @@ func (in *ComponentSpec) DeepCopyInto(out *ComponentSpec) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.UpgradeStrategy != nil {
+		in, out := &in.UpgradeStrategy, &out.UpgradeStrategy
+		*out = new(UpgradeStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -797,6 +914,36 @@ func (in *ConfigMapRef) DeepCopy() *ConfigMapRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigStatus) DeepCopyInto(out *ConfigStatus) {
+	*out = *in
+	if in.OnlineKeys != nil {
+		in, out := &in.OnlineKeys, &out.OnlineKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RestartKeys != nil {
+		in, out := &in.RestartKeys, &out.RestartKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastAppliedTime != nil {
+		in, out := &in.LastAppliedTime, &out.LastAppliedTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigStatus.
+func (in *ConfigStatus) DeepCopy() *ConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CoprocessorCache) DeepCopyInto(out *CoprocessorCache) {
 	*out = *in
@@ -870,6 +1017,8 @@ func (in *CrdKinds) DeepCopyInto(out *CrdKinds) {
 	in.TiDBMonitor.DeepCopyInto(&out.TiDBMonitor)
 	in.TiDBInitializer.DeepCopyInto(&out.TiDBInitializer)
 	in.TidbClusterAutoScaler.DeepCopyInto(&out.TidbClusterAutoScaler)
+	in.TiCDCChangefeed.DeepCopyInto(&out.TiCDCChangefeed)
+	in.PlacementRule.DeepCopyInto(&out.PlacementRule)
 	return
 }
 
@@ -1058,6 +1207,13 @@ func (in *DMClusterStatus) DeepCopyInto(out *DMClusterStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ResizingPVCs != nil {
+		in, out := &in.ResizingPVCs, &out.ResizingPVCs
+		*out = make(map[string]PVCResizeStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 	return
 }
 
@@ -1298,6 +1454,137 @@ func (in *DiscoverySpec) DeepCopy() *DiscoverySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrainerCheckpointSpec) DeepCopyInto(out *DrainerCheckpointSpec) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrainerCheckpointSpec.
+func (in *DrainerCheckpointSpec) DeepCopy() *DrainerCheckpointSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DrainerCheckpointSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrainerMember) DeepCopyInto(out *DrainerMember) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrainerMember.
+func (in *DrainerMember) DeepCopy() *DrainerMember {
+	if in == nil {
+		return nil
+	}
+	out := new(DrainerMember)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrainerSinkSpec) DeepCopyInto(out *DrainerSinkSpec) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrainerSinkSpec.
+func (in *DrainerSinkSpec) DeepCopy() *DrainerSinkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DrainerSinkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrainerSpec) DeepCopyInto(out *DrainerSpec) {
+	*out = *in
+	in.ComponentSpec.DeepCopyInto(&out.ComponentSpec)
+	in.ResourceRequirements.DeepCopyInto(&out.ResourceRequirements)
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.InitialCommitTS != nil {
+		in, out := &in.InitialCommitTS, &out.InitialCommitTS
+		*out = new(string)
+		**out = **in
+	}
+	if in.Sink != nil {
+		in, out := &in.Sink, &out.Sink
+		*out = new(DrainerSinkSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Checkpoint != nil {
+		in, out := &in.Checkpoint, &out.Checkpoint
+		*out = new(DrainerCheckpointSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = (*in).DeepCopy()
+	}
+	if in.SetTimeZone != nil {
+		in, out := &in.SetTimeZone, &out.SetTimeZone
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrainerSpec.
+func (in *DrainerSpec) DeepCopy() *DrainerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DrainerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrainerStatus) DeepCopyInto(out *DrainerStatus) {
+	*out = *in
+	if in.StatefulSet != nil {
+		in, out := &in.StatefulSet, &out.StatefulSet
+		*out = new(appsv1.StatefulSetStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make(map[string]DrainerMember, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrainerStatus.
+func (in *DrainerStatus) DeepCopy() *DrainerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DrainerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DumplingConfig) DeepCopyInto(out *DumplingConfig) {
 	*out = *in
@@ -2121,6 +2408,39 @@ func (in *LogTailerSpec) DeepCopy() *LogTailerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LostMember) DeepCopyInto(out *LostMember) {
+	*out = *in
+	in.CreatedAt.DeepCopyInto(&out.CreatedAt)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LostMember.
+func (in *LostMember) DeepCopy() *LostMember {
+	if in == nil {
+		return nil
+	}
+	out := new(LostMember)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MasterConfig) DeepCopyInto(out *MasterConfig) {
 	*out = *in
@@ -3146,6 +3466,31 @@ func (in PDSchedulerConfigs) DeepCopy() PDSchedulerConfigs {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PDSchedulerSpec) DeepCopyInto(out *PDSchedulerSpec) {
+	*out = *in
+	if in.StoreID != nil {
+		in, out := &in.StoreID, &out.StoreID
+		*out = new(uint64)
+		**out = **in
+	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PDSchedulerSpec.
+func (in *PDSchedulerSpec) DeepCopy() *PDSchedulerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PDSchedulerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PDSecurityConfig) DeepCopyInto(out *PDSecurityConfig) {
 	*out = *in
@@ -3255,6 +3600,13 @@ func (in *PDSpec) DeepCopyInto(out *PDSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Schedulers != nil {
+		in, out := &in.Schedulers, &out.Schedulers
+		*out = make([]PDSchedulerSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -3305,6 +3657,35 @@ func (in *PDStatus) DeepCopyInto(out *PDStatus) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryUpgradeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Schedulers != nil {
+		in, out := &in.Schedulers, &out.Schedulers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = new(ConfigStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LostMembers != nil {
+		in, out := &in.LostMembers, &out.LostMembers
+		*out = make(map[string]LostMember, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.ReplacingMembers != nil {
+		in, out := &in.ReplacingMembers, &out.ReplacingMembers
+		*out = make(map[string]ReplacingMember, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 	return
 }
 
@@ -3366,6 +3747,24 @@ func (in PDStoreLabels) DeepCopy() PDStoreLabels {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PVCResizeStatus) DeepCopyInto(out *PVCResizeStatus) {
+	*out = *in
+	out.CurrentCapacity = in.CurrentCapacity.DeepCopy()
+	out.RequestedCapacity = in.RequestedCapacity.DeepCopy()
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCResizeStatus.
+func (in *PVCResizeStatus) DeepCopy() *PVCResizeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PVCResizeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Performance) DeepCopyInto(out *Performance) {
 	*out = *in
@@ -3493,6 +3892,211 @@ func (in *PessimisticTxn) DeepCopy() *PessimisticTxn {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementLabelConstraint) DeepCopyInto(out *PlacementLabelConstraint) {
+	*out = *in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementLabelConstraint.
+func (in *PlacementLabelConstraint) DeepCopy() *PlacementLabelConstraint {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementLabelConstraint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementRule) DeepCopyInto(out *PlacementRule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementRule.
+func (in *PlacementRule) DeepCopy() *PlacementRule {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PlacementRule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementRuleDrift) DeepCopyInto(out *PlacementRuleDrift) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementRuleDrift.
+func (in *PlacementRuleDrift) DeepCopy() *PlacementRuleDrift {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementRuleDrift)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementRuleItem) DeepCopyInto(out *PlacementRuleItem) {
+	*out = *in
+	if in.LabelConstraints != nil {
+		in, out := &in.LabelConstraints, &out.LabelConstraints
+		*out = make([]PlacementLabelConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LocationLabels != nil {
+		in, out := &in.LocationLabels, &out.LocationLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementRuleItem.
+func (in *PlacementRuleItem) DeepCopy() *PlacementRuleItem {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementRuleItem)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementRuleList) DeepCopyInto(out *PlacementRuleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PlacementRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementRuleList.
+func (in *PlacementRuleList) DeepCopy() *PlacementRuleList {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementRuleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PlacementRuleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementRuleSpec) DeepCopyInto(out *PlacementRuleSpec) {
+	*out = *in
+	out.Cluster = in.Cluster
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]PlacementRuleItem, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementRuleSpec.
+func (in *PlacementRuleSpec) DeepCopy() *PlacementRuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementRuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementRuleStatus) DeepCopyInto(out *PlacementRuleStatus) {
+	*out = *in
+	if in.Drifts != nil {
+		in, out := &in.Drifts, &out.Drifts
+		*out = make([]PlacementRuleDrift, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastDriftTime != nil {
+		in, out := &in.LastDriftTime, &out.LastDriftTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementRuleStatus.
+func (in *PlacementRuleStatus) DeepCopy() *PlacementRuleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementRuleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlanAction) DeepCopyInto(out *PlanAction) {
+	*out = *in
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfigKeys != nil {
+		in, out := &in.ConfigKeys, &out.ConfigKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlanAction.
+func (in *PlanAction) DeepCopy() *PlanAction {
+	if in == nil {
+		return nil
+	}
+	out := new(PlanAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PlanCache) DeepCopyInto(out *PlanCache) {
 	*out = *in
@@ -3788,6 +4392,22 @@ func (in *ProxyProtocol) DeepCopy() *ProxyProtocol {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PumpMember) DeepCopyInto(out *PumpMember) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PumpMember.
+func (in *PumpMember) DeepCopy() *PumpMember {
+	if in == nil {
+		return nil
+	}
+	out := new(PumpMember)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PumpSpec) DeepCopyInto(out *PumpSpec) {
 	*out = *in
@@ -3828,6 +4448,20 @@ func (in *PumpStatus) DeepCopyInto(out *PumpStatus) {
 		*out = new(appsv1.StatefulSetStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make(map[string]PumpMember, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ReplacingMembers != nil {
+		in, out := &in.ReplacingMembers, &out.ReplacingMembers
+		*out = make(map[string]ReplacingMember, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 	return
 }
 
@@ -3880,6 +4514,23 @@ func (in *ReloaderSpec) DeepCopy() *ReloaderSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplacingMember) DeepCopyInto(out *ReplacingMember) {
+	*out = *in
+	in.CreatedAt.DeepCopyInto(&out.CreatedAt)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplacingMember.
+func (in *ReplacingMember) DeepCopy() *ReplacingMember {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplacingMember)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Restore) DeepCopyInto(out *Restore) {
 	*out = *in
@@ -4369,124 +5020,300 @@ func (in *StorageProvider) DeepCopyInto(out *StorageProvider) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageProvider.
-func (in *StorageProvider) DeepCopy() *StorageProvider {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageProvider.
+func (in *StorageProvider) DeepCopy() *StorageProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageVolume) DeepCopyInto(out *StorageVolume) {
+	*out = *in
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageVolume.
+func (in *StorageVolume) DeepCopy() *StorageVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SuspendStatus) DeepCopyInto(out *SuspendStatus) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SuspendStatus.
+func (in *SuspendStatus) DeepCopy() *SuspendStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SuspendStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSCluster) DeepCopyInto(out *TLSCluster) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSCluster.
+func (in *TLSCluster) DeepCopy() *TLSCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSConfig) DeepCopyInto(out *TLSConfig) {
+	*out = *in
+	in.SafeTLSConfig.DeepCopyInto(&out.SafeTLSConfig)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSConfig.
+func (in *TLSConfig) DeepCopy() *TLSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThanosSpec) DeepCopyInto(out *ThanosSpec) {
+	*out = *in
+	in.MonitorContainer.DeepCopyInto(&out.MonitorContainer)
+	if in.ObjectStorageConfig != nil {
+		in, out := &in.ObjectStorageConfig, &out.ObjectStorageConfig
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ObjectStorageConfigFile != nil {
+		in, out := &in.ObjectStorageConfigFile, &out.ObjectStorageConfigFile
+		*out = new(string)
+		**out = **in
+	}
+	if in.TracingConfig != nil {
+		in, out := &in.TracingConfig, &out.TracingConfig
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TracingConfigFile != nil {
+		in, out := &in.TracingConfigFile, &out.TracingConfigFile
+		*out = new(string)
+		**out = **in
+	}
+	if in.GRPCServerTLSConfig != nil {
+		in, out := &in.GRPCServerTLSConfig, &out.GRPCServerTLSConfig
+		*out = new(TLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThanosSpec.
+func (in *ThanosSpec) DeepCopy() *ThanosSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ThanosSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiCDCCapture) DeepCopyInto(out *TiCDCCapture) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiCDCCapture.
+func (in *TiCDCCapture) DeepCopy() *TiCDCCapture {
+	if in == nil {
+		return nil
+	}
+	out := new(TiCDCCapture)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiCDCChangefeed) DeepCopyInto(out *TiCDCChangefeed) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiCDCChangefeed.
+func (in *TiCDCChangefeed) DeepCopy() *TiCDCChangefeed {
 	if in == nil {
 		return nil
 	}
-	out := new(StorageProvider)
+	out := new(TiCDCChangefeed)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TiCDCChangefeed) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StorageVolume) DeepCopyInto(out *StorageVolume) {
+func (in *TiCDCChangefeedError) DeepCopyInto(out *TiCDCChangefeedError) {
 	*out = *in
-	if in.StorageClassName != nil {
-		in, out := &in.StorageClassName, &out.StorageClassName
-		*out = new(string)
-		**out = **in
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageVolume.
-func (in *StorageVolume) DeepCopy() *StorageVolume {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiCDCChangefeedError.
+func (in *TiCDCChangefeedError) DeepCopy() *TiCDCChangefeedError {
 	if in == nil {
 		return nil
 	}
-	out := new(StorageVolume)
+	out := new(TiCDCChangefeedError)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TLSCluster) DeepCopyInto(out *TLSCluster) {
+func (in *TiCDCChangefeedFilter) DeepCopyInto(out *TiCDCChangefeedFilter) {
 	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IgnoreTxnStartTs != nil {
+		in, out := &in.IgnoreTxnStartTs, &out.IgnoreTxnStartTs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSCluster.
-func (in *TLSCluster) DeepCopy() *TLSCluster {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiCDCChangefeedFilter.
+func (in *TiCDCChangefeedFilter) DeepCopy() *TiCDCChangefeedFilter {
 	if in == nil {
 		return nil
 	}
-	out := new(TLSCluster)
+	out := new(TiCDCChangefeedFilter)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TLSConfig) DeepCopyInto(out *TLSConfig) {
+func (in *TiCDCChangefeedList) DeepCopyInto(out *TiCDCChangefeedList) {
 	*out = *in
-	in.SafeTLSConfig.DeepCopyInto(&out.SafeTLSConfig)
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TiCDCChangefeed, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSConfig.
-func (in *TLSConfig) DeepCopy() *TLSConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiCDCChangefeedList.
+func (in *TiCDCChangefeedList) DeepCopy() *TiCDCChangefeedList {
 	if in == nil {
 		return nil
 	}
-	out := new(TLSConfig)
+	out := new(TiCDCChangefeedList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TiCDCChangefeedList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ThanosSpec) DeepCopyInto(out *ThanosSpec) {
+func (in *TiCDCChangefeedSpec) DeepCopyInto(out *TiCDCChangefeedSpec) {
 	*out = *in
-	in.MonitorContainer.DeepCopyInto(&out.MonitorContainer)
-	if in.ObjectStorageConfig != nil {
-		in, out := &in.ObjectStorageConfig, &out.ObjectStorageConfig
-		*out = new(v1.SecretKeySelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ObjectStorageConfigFile != nil {
-		in, out := &in.ObjectStorageConfigFile, &out.ObjectStorageConfigFile
-		*out = new(string)
-		**out = **in
-	}
-	if in.TracingConfig != nil {
-		in, out := &in.TracingConfig, &out.TracingConfig
+	out.Cluster = in.Cluster
+	if in.SinkURISecret != nil {
+		in, out := &in.SinkURISecret, &out.SinkURISecret
 		*out = new(v1.SecretKeySelector)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.TracingConfigFile != nil {
-		in, out := &in.TracingConfigFile, &out.TracingConfigFile
-		*out = new(string)
-		**out = **in
-	}
-	if in.GRPCServerTLSConfig != nil {
-		in, out := &in.GRPCServerTLSConfig, &out.GRPCServerTLSConfig
-		*out = new(TLSConfig)
+	if in.Filter != nil {
+		in, out := &in.Filter, &out.Filter
+		*out = new(TiCDCChangefeedFilter)
 		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThanosSpec.
-func (in *ThanosSpec) DeepCopy() *ThanosSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiCDCChangefeedSpec.
+func (in *TiCDCChangefeedSpec) DeepCopy() *TiCDCChangefeedSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ThanosSpec)
+	out := new(TiCDCChangefeedSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TiCDCCapture) DeepCopyInto(out *TiCDCCapture) {
+func (in *TiCDCChangefeedStatus) DeepCopyInto(out *TiCDCChangefeedStatus) {
 	*out = *in
+	if in.CheckpointTime != nil {
+		in, out := &in.CheckpointTime, &out.CheckpointTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Error != nil {
+		in, out := &in.Error, &out.Error
+		*out = new(TiCDCChangefeedError)
+		**out = **in
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiCDCCapture.
-func (in *TiCDCCapture) DeepCopy() *TiCDCCapture {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiCDCChangefeedStatus.
+func (in *TiCDCChangefeedStatus) DeepCopy() *TiCDCChangefeedStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(TiCDCCapture)
+	out := new(TiCDCChangefeedStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -4537,6 +5364,11 @@ func (in *TiCDCSpec) DeepCopyInto(out *TiCDCSpec) {
 		*out = new(TiCDCConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.GracefulShutdownTimeout != nil {
+		in, out := &in.GracefulShutdownTimeout, &out.GracefulShutdownTimeout
+		*out = new(string)
+		**out = **in
+	}
 	return
 }
 
@@ -4862,6 +5694,27 @@ func (in *TiDBConfigWraper) DeepCopy() *TiDBConfigWraper {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiDBDrainSpec) DeepCopyInto(out *TiDBDrainSpec) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiDBDrainSpec.
+func (in *TiDBDrainSpec) DeepCopy() *TiDBDrainSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TiDBDrainSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TiDBFailureMember) DeepCopyInto(out *TiDBFailureMember) {
 	*out = *in
@@ -4879,6 +5732,23 @@ func (in *TiDBFailureMember) DeepCopy() *TiDBFailureMember {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiDBGroupSpec) DeepCopyInto(out *TiDBGroupSpec) {
+	*out = *in
+	in.TiDBSpec.DeepCopyInto(&out.TiDBSpec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiDBGroupSpec.
+func (in *TiDBGroupSpec) DeepCopy() *TiDBGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TiDBGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TiDBMember) DeepCopyInto(out *TiDBMember) {
 	*out = *in
@@ -4996,6 +5866,11 @@ func (in *TiDBSpec) DeepCopyInto(out *TiDBSpec) {
 		*out = new(TiDBServiceSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Drain != nil {
+		in, out := &in.Drain, &out.Drain
+		*out = new(TiDBDrainSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.BinlogEnabled != nil {
 		in, out := &in.BinlogEnabled, &out.BinlogEnabled
 		*out = new(bool)
@@ -5088,6 +5963,23 @@ func (in *TiDBStatus) DeepCopyInto(out *TiDBStatus) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryUpgradeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make(map[string]TiDBStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = new(ConfigStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -5290,6 +6182,23 @@ func (in *TiFlashStatus) DeepCopyInto(out *TiFlashStatus) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	out.TotalCapacity = in.TotalCapacity.DeepCopy()
+	out.UsedCapacity = in.UsedCapacity.DeepCopy()
+	out.AvailableCapacity = in.AvailableCapacity.DeepCopy()
+	if in.DrainingStores != nil {
+		in, out := &in.DrainingStores, &out.DrainingStores
+		*out = make(map[string]TiKVDrainingStore, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.ReplacingStores != nil {
+		in, out := &in.ReplacingStores, &out.ReplacingStores
+		*out = make(map[string]ReplacingMember, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 	return
 }
 
@@ -6046,6 +6955,23 @@ func (in *TiKVDbConfig) DeepCopy() *TiKVDbConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiKVDrainingStore) DeepCopyInto(out *TiKVDrainingStore) {
+	*out = *in
+	in.CreatedAt.DeepCopyInto(&out.CreatedAt)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiKVDrainingStore.
+func (in *TiKVDrainingStore) DeepCopy() *TiKVDrainingStore {
+	if in == nil {
+		return nil
+	}
+	out := new(TiKVDrainingStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TiKVEncryptionConfig) DeepCopyInto(out *TiKVEncryptionConfig) {
 	*out = *in
@@ -6135,6 +7061,23 @@ func (in *TiKVGCConfig) DeepCopy() *TiKVGCConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiKVGroupSpec) DeepCopyInto(out *TiKVGroupSpec) {
+	*out = *in
+	in.TiKVSpec.DeepCopyInto(&out.TiKVSpec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiKVGroupSpec.
+func (in *TiKVGroupSpec) DeepCopy() *TiKVGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TiKVGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TiKVImportConfig) DeepCopyInto(out *TiKVImportConfig) {
 	*out = *in
@@ -7121,6 +8064,47 @@ func (in *TiKVStatus) DeepCopyInto(out *TiKVStatus) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryUpgradeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	out.TotalCapacity = in.TotalCapacity.DeepCopy()
+	out.UsedCapacity = in.UsedCapacity.DeepCopy()
+	out.AvailableCapacity = in.AvailableCapacity.DeepCopy()
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make(map[string]TiKVStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = new(ConfigStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DrainingStores != nil {
+		in, out := &in.DrainingStores, &out.DrainingStores
+		*out = make(map[string]TiKVDrainingStore, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.LostStores != nil {
+		in, out := &in.LostStores, &out.LostStores
+		*out = make(map[string]LostMember, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.ReplacingStores != nil {
+		in, out := &in.ReplacingStores, &out.ReplacingStores
+		*out = make(map[string]ReplacingMember, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 	return
 }
 
@@ -7246,6 +8230,16 @@ func (in *TiKVStore) DeepCopyInto(out *TiKVStore) {
 	*out = *in
 	in.LastHeartbeatTime.DeepCopyInto(&out.LastHeartbeatTime)
 	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	out.Capacity = in.Capacity.DeepCopy()
+	out.Available = in.Available.DeepCopy()
+	out.UsedSize = in.UsedSize.DeepCopy()
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
@@ -7560,6 +8554,13 @@ func (in *TidbClusterAutoScalerSpec) DeepCopyInto(out *TidbClusterAutoScalerSpec
 		*out = new(TidbAutoScalerSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TiDBGroups != nil {
+		in, out := &in.TiDBGroups, &out.TiDBGroups
+		*out = make(map[string]TidbAutoScalerSpec, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 	return
 }
 
@@ -7654,6 +8655,30 @@ func (in *TidbClusterList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TidbClusterPlan) DeepCopyInto(out *TidbClusterPlan) {
+	*out = *in
+	in.GeneratedTime.DeepCopyInto(&out.GeneratedTime)
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]ComponentPlan, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbClusterPlan.
+func (in *TidbClusterPlan) DeepCopy() *TidbClusterPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(TidbClusterPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TidbClusterRef) DeepCopyInto(out *TidbClusterRef) {
 	*out = *in
@@ -7684,11 +8709,25 @@ func (in *TidbClusterSpec) DeepCopyInto(out *TidbClusterSpec) {
 		*out = new(TiDBSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TiDBGroups != nil {
+		in, out := &in.TiDBGroups, &out.TiDBGroups
+		*out = make([]TiDBGroupSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.TiKV != nil {
 		in, out := &in.TiKV, &out.TiKV
 		*out = new(TiKVSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TiKVGroups != nil {
+		in, out := &in.TiKVGroups, &out.TiKVGroups
+		*out = make([]TiKVGroupSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.TiFlash != nil {
 		in, out := &in.TiFlash, &out.TiFlash
 		*out = new(TiFlashSpec)
@@ -7704,11 +8743,26 @@ func (in *TidbClusterSpec) DeepCopyInto(out *TidbClusterSpec) {
 		*out = new(PumpSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Drainer != nil {
+		in, out := &in.Drainer, &out.Drainer
+		*out = new(DrainerSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Helper != nil {
 		in, out := &in.Helper, &out.Helper
 		*out = new(HelperSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.BootstrapFrom != nil {
+		in, out := &in.BootstrapFrom, &out.BootstrapFrom
+		*out = new(BootstrapFromSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
 	if in.PVReclaimPolicy != nil {
 		in, out := &in.PVReclaimPolicy, &out.PVReclaimPolicy
 		*out = new(v1.PersistentVolumeReclaimPolicy)
@@ -7805,6 +8859,7 @@ func (in *TidbClusterStatus) DeepCopyInto(out *TidbClusterStatus) {
 	in.TiKV.DeepCopyInto(&out.TiKV)
 	in.TiDB.DeepCopyInto(&out.TiDB)
 	in.Pump.DeepCopyInto(&out.Pump)
+	in.Drainer.DeepCopyInto(&out.Drainer)
 	in.TiFlash.DeepCopyInto(&out.TiFlash)
 	in.TiCDC.DeepCopyInto(&out.TiCDC)
 	if in.Monitor != nil {
@@ -7824,6 +8879,28 @@ func (in *TidbClusterStatus) DeepCopyInto(out *TidbClusterStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = new(TidbClusterPlan)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Suspend != nil {
+		in, out := &in.Suspend, &out.Suspend
+		*out = new(SuspendStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Bootstrap != nil {
+		in, out := &in.Bootstrap, &out.Bootstrap
+		*out = new(BootstrapStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResizingPVCs != nil {
+		in, out := &in.ResizingPVCs, &out.ResizingPVCs
+		*out = make(map[string]PVCResizeStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 	return
 }
 
@@ -8256,6 +9333,27 @@ func (in *UnjoinedMember) DeepCopy() *UnjoinedMember {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeStrategy) DeepCopyInto(out *UpgradeStrategy) {
+	*out = *in
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryUpgradeStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeStrategy.
+func (in *UpgradeStrategy) DeepCopy() *UpgradeStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *User) DeepCopyInto(out *User) {
 	*out = *in
@@ -15,8 +15,10 @@ package autoscaler
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/autoscaler/autoscaler/query"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/klog"
 )
@@ -27,6 +29,8 @@ const (
 	externalStatusKey     = "external"
 	specialUseLabelKey    = "specialUse"
 	specialUseHotRegion   = "hotRegion"
+	// The auto-scaling status of a TiDB group will be "group-<group-name>"
+	tidbGroupStatusKeyPattern = "group-%s"
 )
 
 func (am *autoScalerManager) syncExternalResult(tc *v1alpha1.TidbCluster, tac *v1alpha1.TidbClusterAutoScaler, component v1alpha1.MemberType, targetReplicas int32) error {
@@ -117,3 +121,67 @@ func (am *autoScalerManager) updateExternalAutoCluster(externalTc *v1alpha1.Tidb
 	updateLastAutoScalingTimestamp(tac, component.String(), externalStatusKey)
 	return nil
 }
+
+// syncTiDBGroups queries the external service for the recommended replicas of
+// each TiDB group and updates the replicas of the groups in place
+func (am *autoScalerManager) syncTiDBGroups(tc *v1alpha1.TidbCluster, tac *v1alpha1.TidbClusterAutoScaler) error {
+	names := make([]string, 0, len(tac.Spec.TiDBGroups))
+	for name := range tac.Spec.TiDBGroups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	updated := tc.DeepCopy()
+	var scaled []string
+	for _, name := range names {
+		var group *v1alpha1.TiDBGroupSpec
+		for i := range updated.Spec.TiDBGroups {
+			if updated.Spec.TiDBGroups[i].Name == name {
+				group = &updated.Spec.TiDBGroups[i]
+				break
+			}
+		}
+		if group == nil {
+			klog.Warningf("tac[%s/%s] tidb group %s does not exist in tc[%s/%s], skip auto-scaling", tac.Namespace, tac.Name, name, tc.Namespace, tc.Name)
+			continue
+		}
+
+		spec := tac.Spec.TiDBGroups[name]
+		targetReplicas, err := query.ExternalService(tc.TiDBGroupCluster(group), v1alpha1.TiDBMemberType, spec.External.Endpoint, am.deps.KubeClientset)
+		if err != nil {
+			klog.Errorf("tac[%s/%s]'s query to the external endpoint for tidb group %s got error: %v", tac.Namespace, tac.Name, name, err)
+			return err
+		}
+		if targetReplicas > spec.External.MaxReplicas {
+			targetReplicas = spec.External.MaxReplicas
+		}
+		if targetReplicas < 0 || group.Replicas == targetReplicas {
+			continue
+		}
+
+		statusKey := fmt.Sprintf(tidbGroupStatusKeyPattern, name)
+		intervalSeconds := *spec.ScaleOutIntervalSeconds
+		if targetReplicas < group.Replicas {
+			intervalSeconds = *spec.ScaleInIntervalSeconds
+		}
+		if !checkAutoScalingInterval(tac, intervalSeconds, v1alpha1.TiDBMemberType, statusKey) {
+			continue
+		}
+		group.Replicas = targetReplicas
+		scaled = append(scaled, statusKey)
+	}
+
+	if len(scaled) == 0 {
+		return nil
+	}
+	_, err := am.deps.TiDBClusterControl.UpdateTidbCluster(updated, &updated.Status, &tc.Status)
+	if err != nil {
+		klog.Errorf("tac[%s/%s] failed to update the tidb groups of tc[%s/%s], err: %v", tac.Namespace, tac.Name, tc.Namespace, tc.Name, err)
+		return err
+	}
+
+	for _, statusKey := range scaled {
+		updateLastAutoScalingTimestamp(tac, v1alpha1.TiDBMemberType.String(), statusKey)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoscaler
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/autoscaler/autoscaler/query"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+)
+
+func TestSyncTiDBGroups(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	recommended := map[string]int32{"oltp": 5, "olap": 1}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		json.NewEncoder(w).Encode(query.ExternalResponse{
+			Name:                q.Get("name"),
+			Namespace:           q.Get("namespace"),
+			Type:                q.Get("type"),
+			RecommendedReplicas: recommended[q.Get("group")],
+		})
+	}))
+	defer server.Close()
+	host, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	g.Expect(err).NotTo(HaveOccurred())
+	portNum, err := strconv.Atoi(port)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	tc := newTidbCluster()
+	tc.Spec.TiDBGroups = []v1alpha1.TiDBGroupSpec{
+		{Name: "oltp", TiDBSpec: v1alpha1.TiDBSpec{Replicas: 2}},
+		{Name: "olap", TiDBSpec: v1alpha1.TiDBSpec{Replicas: 2}},
+	}
+	tac := newTidbClusterAutoScaler()
+	tac.Spec.TiDB = nil
+	tac.Spec.TiKV = nil
+	tac.Spec.TiDBGroups = map[string]v1alpha1.TidbAutoScalerSpec{}
+	for _, name := range []string{"oltp", "olap"} {
+		tac.Spec.TiDBGroups[name] = v1alpha1.TidbAutoScalerSpec{
+			BasicAutoScalerSpec: v1alpha1.BasicAutoScalerSpec{
+				External: &v1alpha1.ExternalConfig{
+					Endpoint:    v1alpha1.ExternalEndpoint{Host: host, Port: int32(portNum)},
+					MaxReplicas: 4,
+				},
+			},
+		}
+	}
+	defaultTAC(tac, tc)
+	g.Expect(validateTAC(tac)).To(Succeed())
+
+	deps := controller.NewFakeDependencies()
+	indexer := deps.InformerFactory.Pingcap().V1alpha1().TidbClusters().Informer().GetIndexer()
+	g.Expect(indexer.Add(tc)).To(Succeed())
+	am := NewAutoScalerManager(deps)
+	g.Expect(am.syncTiDBGroups(tc, tac)).To(Succeed())
+
+	updated, err := deps.TiDBClusterLister.TidbClusters(tc.Namespace).Get(tc.Name)
+	g.Expect(err).NotTo(HaveOccurred())
+	// the replicas are limited by the max replicas
+	g.Expect(updated.Spec.TiDBGroups[0].Replicas).To(Equal(int32(4)))
+	g.Expect(updated.Spec.TiDBGroups[1].Replicas).To(Equal(int32(1)))
+	g.Expect(tac.Status.TiDB).To(HaveKey("group-oltp"))
+	g.Expect(tac.Status.TiDB).To(HaveKey("group-olap"))
+
+	// the groups are not scaled again within the interval
+	recommended["olap"] = 3
+	g.Expect(am.syncTiDBGroups(updated, tac)).To(Succeed())
+	updated, err = deps.TiDBClusterLister.TidbClusters(tc.Namespace).Get(tc.Name)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(updated.Spec.TiDBGroups[1].Replicas).To(Equal(int32(1)))
+}
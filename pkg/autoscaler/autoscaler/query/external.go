@@ -65,6 +65,9 @@ func sendRequest(tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType, endpo
 		scheme = "https"
 	}
 	url := fmt.Sprintf("%s://%s:%d%s?name=%s&namespace=%s&type=%s", scheme, endpoint.Host, endpoint.Port, endpoint.Path, tc.Name, tc.Namespace, memberType.String())
+	if group := tc.TiDBGroupName(); memberType == v1alpha1.TiDBMemberType && group != "" {
+		url += "&group=" + group
+	}
 	r, err := client.Get(url)
 	if err != nil {
 		return nil, err
@@ -202,6 +202,15 @@ func defaultTAC(tac *v1alpha1.TidbClusterAutoScaler, tc *v1alpha1.TidbCluster) {
 		defaultBasicAutoScaler(tac, v1alpha1.TiKVMemberType)
 	}
 
+	for name, group := range tac.Spec.TiDBGroups {
+		if group.ScaleOutIntervalSeconds == nil {
+			group.ScaleOutIntervalSeconds = pointer.Int32Ptr(300)
+		}
+		if group.ScaleInIntervalSeconds == nil {
+			group.ScaleInIntervalSeconds = pointer.Int32Ptr(500)
+		}
+		tac.Spec.TiDBGroups[name] = group
+	}
 }
 
 func validateBasicAutoScalerSpec(tac *v1alpha1.TidbClusterAutoScaler, component v1alpha1.MemberType) error {
@@ -288,6 +297,12 @@ func validateTAC(tac *v1alpha1.TidbClusterAutoScaler) error {
 		}
 	}
 
+	for name, group := range tac.Spec.TiDBGroups {
+		if group.External == nil {
+			return fmt.Errorf("no external service provided for tidb group %s in %s/%s", name, tac.Namespace, tac.Name)
+		}
+	}
+
 	return nil
 }
 
@@ -579,6 +579,13 @@ func TestValidateTidbClusterAutoScaler(t *testing.T) {
 	}
 	err = validateTAC(tac)
 	g.Expect(err).Should(BeNil())
+
+	// Case 8: No external service for tidb group
+	tac.Spec.TiDBGroups = map[string]v1alpha1.TidbAutoScalerSpec{
+		"olap": {},
+	}
+	err = validateTAC(tac)
+	g.Expect(err).Should(MatchError(fmt.Errorf("no external service provided for tidb group olap in %s/%s", tac.Namespace, tac.Name)))
 }
 
 func newTidbClusterAutoScaler() *v1alpha1.TidbClusterAutoScaler {
@@ -41,10 +41,18 @@ func (c *FakePingcapV1alpha1) DataResources(namespace string) v1alpha1.DataResou
 	return &FakeDataResources{c, namespace}
 }
 
+func (c *FakePingcapV1alpha1) PlacementRules(namespace string) v1alpha1.PlacementRuleInterface {
+	return &FakePlacementRules{c, namespace}
+}
+
 func (c *FakePingcapV1alpha1) Restores(namespace string) v1alpha1.RestoreInterface {
 	return &FakeRestores{c, namespace}
 }
 
+func (c *FakePingcapV1alpha1) TiCDCChangefeeds(namespace string) v1alpha1.TiCDCChangefeedInterface {
+	return &FakeTiCDCChangefeeds{c, namespace}
+}
+
 func (c *FakePingcapV1alpha1) TidbClusters(namespace string) v1alpha1.TidbClusterInterface {
 	return &FakeTidbClusters{c, namespace}
 }
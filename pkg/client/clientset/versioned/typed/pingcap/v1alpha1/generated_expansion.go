@@ -23,8 +23,12 @@ type DMClusterExpansion interface{}
 
 type DataResourceExpansion interface{}
 
+type PlacementRuleExpansion interface{}
+
 type RestoreExpansion interface{}
 
+type TiCDCChangefeedExpansion interface{}
+
 type TidbClusterExpansion interface{}
 
 type TidbClusterAutoScalerExpansion interface{}
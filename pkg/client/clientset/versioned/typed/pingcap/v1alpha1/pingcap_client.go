@@ -27,7 +27,9 @@ type PingcapV1alpha1Interface interface {
 	BackupSchedulesGetter
 	DMClustersGetter
 	DataResourcesGetter
+	PlacementRulesGetter
 	RestoresGetter
+	TiCDCChangefeedsGetter
 	TidbClustersGetter
 	TidbClusterAutoScalersGetter
 	TidbInitializersGetter
@@ -55,10 +57,18 @@ func (c *PingcapV1alpha1Client) DataResources(namespace string) DataResourceInte
 	return newDataResources(c, namespace)
 }
 
+func (c *PingcapV1alpha1Client) PlacementRules(namespace string) PlacementRuleInterface {
+	return newPlacementRules(c, namespace)
+}
+
 func (c *PingcapV1alpha1Client) Restores(namespace string) RestoreInterface {
 	return newRestores(c, namespace)
 }
 
+func (c *PingcapV1alpha1Client) TiCDCChangefeeds(namespace string) TiCDCChangefeedInterface {
+	return newTiCDCChangefeeds(c, namespace)
+}
+
 func (c *PingcapV1alpha1Client) TidbClusters(namespace string) TidbClusterInterface {
 	return newTidbClusters(c, namespace)
 }
@@ -29,8 +29,12 @@ type Interface interface {
 	DMClusters() DMClusterInformer
 	// DataResources returns a DataResourceInformer.
 	DataResources() DataResourceInformer
+	// PlacementRules returns a PlacementRuleInformer.
+	PlacementRules() PlacementRuleInformer
 	// Restores returns a RestoreInformer.
 	Restores() RestoreInformer
+	// TiCDCChangefeeds returns a TiCDCChangefeedInformer.
+	TiCDCChangefeeds() TiCDCChangefeedInformer
 	// TidbClusters returns a TidbClusterInformer.
 	TidbClusters() TidbClusterInformer
 	// TidbClusterAutoScalers returns a TidbClusterAutoScalerInformer.
@@ -72,11 +76,21 @@ func (v *version) DataResources() DataResourceInformer {
 	return &dataResourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// PlacementRules returns a PlacementRuleInformer.
+func (v *version) PlacementRules() PlacementRuleInformer {
+	return &placementRuleInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // Restores returns a RestoreInformer.
 func (v *version) Restores() RestoreInformer {
 	return &restoreInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// TiCDCChangefeeds returns a TiCDCChangefeedInformer.
+func (v *version) TiCDCChangefeeds() TiCDCChangefeedInformer {
+	return &tiCDCChangefeedInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // TidbClusters returns a TidbClusterInformer.
 func (v *version) TidbClusters() TidbClusterInformer {
 	return &tidbClusterInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -47,6 +47,14 @@ type DataResourceListerExpansion interface{}
 // DataResourceNamespaceLister.
 type DataResourceNamespaceListerExpansion interface{}
 
+// PlacementRuleListerExpansion allows custom methods to be added to
+// PlacementRuleLister.
+type PlacementRuleListerExpansion interface{}
+
+// PlacementRuleNamespaceListerExpansion allows custom methods to be added to
+// PlacementRuleNamespaceLister.
+type PlacementRuleNamespaceListerExpansion interface{}
+
 // RestoreListerExpansion allows custom methods to be added to
 // RestoreLister.
 type RestoreListerExpansion interface{}
@@ -55,6 +63,14 @@ type RestoreListerExpansion interface{}
 // RestoreNamespaceLister.
 type RestoreNamespaceListerExpansion interface{}
 
+// TiCDCChangefeedListerExpansion allows custom methods to be added to
+// TiCDCChangefeedLister.
+type TiCDCChangefeedListerExpansion interface{}
+
+// TiCDCChangefeedNamespaceListerExpansion allows custom methods to be added to
+// TiCDCChangefeedNamespaceLister.
+type TiCDCChangefeedNamespaceListerExpansion interface{}
+
 // TidbClusterListerExpansion allows custom methods to be added to
 // TidbClusterLister.
 type TidbClusterListerExpansion interface{}
@@ -69,7 +69,10 @@ func (c *Controller) processNextWorkItem() bool {
 		return false
 	}
 	defer c.queue.Done(key)
-	if err := c.sync(key.(string)); err != nil {
+	startTime := time.Now()
+	err := c.sync(key.(string))
+	controller.ObserveReconcile("tidbclusterautoscaler", key.(string), startTime, err)
+	if err != nil {
 		if perrors.Find(err, controller.IsRequeueError) != nil {
 			klog.Infof("TidbClusterAutoScaler: %v, still need sync: %v, requeuing", key.(string), err)
 		} else {
@@ -95,6 +98,7 @@ func (c *Controller) sync(key string) error {
 	ta, err := c.deps.TiDBClusterAutoScalerLister.TidbClusterAutoScalers(ns).Get(name)
 	if errors.IsNotFound(err) {
 		klog.Infof("TidbClusterAutoScaler has been deleted %v", key)
+		controller.ForgetReconcile("tidbclusterautoscaler", key)
 		return nil
 	}
 	if err != nil {
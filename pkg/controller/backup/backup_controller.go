@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/backup/backup"
 	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/metrics"
 	"k8s.io/apimachinery/pkg/api/errors"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -54,6 +55,7 @@ func NewController(deps *controller.Dependencies) *Controller {
 	backupInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: c.updateBackup,
 		UpdateFunc: func(old, cur interface{}) {
+			observeBackupOutcome(old.(*v1alpha1.Backup), cur.(*v1alpha1.Backup))
 			c.updateBackup(cur)
 		},
 		DeleteFunc: c.updateBackup,
@@ -91,7 +93,10 @@ func (c *Controller) processNextWorkItem() bool {
 		return false
 	}
 	defer c.queue.Done(key)
-	if err := c.sync(key.(string)); err != nil {
+	startTime := time.Now()
+	err := c.sync(key.(string))
+	controller.ObserveReconcile("backup", key.(string), startTime, err)
+	if err != nil {
 		if perrors.Find(err, controller.IsRequeueError) != nil {
 			klog.Infof("Backup: %v, still need sync: %v, requeuing", key.(string), err)
 			c.queue.AddRateLimited(key)
@@ -121,6 +126,7 @@ func (c *Controller) sync(key string) error {
 	backup, err := c.deps.BackupLister.Backups(ns).Get(name)
 	if errors.IsNotFound(err) {
 		klog.Infof("Backup has been deleted %v", key)
+		controller.ForgetReconcile("backup", key)
 		return nil
 	}
 	if err != nil {
@@ -134,6 +140,18 @@ func (c *Controller) syncBackup(backup *v1alpha1.Backup) error {
 	return c.control.UpdateBackup(backup)
 }
 
+// observeBackupOutcome counts the backup when it turns complete or failed
+func observeBackupOutcome(old, cur *v1alpha1.Backup) {
+	if v1alpha1.IsBackupComplete(old) || v1alpha1.IsBackupFailed(old) {
+		return
+	}
+	if v1alpha1.IsBackupComplete(cur) {
+		metrics.BackupOutcomeTotal.WithLabelValues("backup", cur.GetNamespace(), metrics.OutcomeComplete).Inc()
+	} else if v1alpha1.IsBackupFailed(cur) {
+		metrics.BackupOutcomeTotal.WithLabelValues("backup", cur.GetNamespace(), metrics.OutcomeFailed).Inc()
+	}
+}
+
 func (c *Controller) updateBackup(cur interface{}) {
 	newBackup := cur.(*v1alpha1.Backup)
 	ns := newBackup.GetNamespace()
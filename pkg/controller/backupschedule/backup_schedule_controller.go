@@ -91,7 +91,10 @@ func (c *Controller) processNextWorkItem() bool {
 		return false
 	}
 	defer c.queue.Done(key)
-	if err := c.sync(key.(string)); err != nil {
+	startTime := time.Now()
+	err := c.sync(key.(string))
+	controller.ObserveReconcile("backupSchedule", key.(string), startTime, err)
+	if err != nil {
 		if perrors.Find(err, controller.IsRequeueError) != nil {
 			klog.Infof("BackupSchedule: %v, still need sync: %v, requeuing", key.(string), err)
 			c.queue.AddRateLimited(key)
@@ -121,6 +124,7 @@ func (c *Controller) sync(key string) error {
 	bs, err := c.deps.BackupScheduleLister.BackupSchedules(ns).Get(name)
 	if errors.IsNotFound(err) {
 		klog.Infof("BackupSchedule has been deleted %v", key)
+		controller.ForgetReconcile("backupSchedule", key)
 		return nil
 	}
 	if err != nil {
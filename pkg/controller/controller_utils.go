@@ -17,9 +17,13 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"sync"
+	"time"
 
 	"github.com/dustin/go-humanize"
+	perrors "github.com/pingcap/errors"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/metrics"
 	"github.com/pingcap/tidb-operator/pkg/scheme"
 	"github.com/pingcap/tidb-operator/pkg/util"
 	corev1 "k8s.io/api/core/v1"
@@ -99,6 +103,42 @@ func IsIgnoreError(err error) bool {
 	return ok
 }
 
+// deletedObjects are the objects found deleted in the current reconciles,
+// keyed by controller and object key
+var deletedObjects sync.Map
+
+// ForgetReconcile marks the object with the key as deleted, the reconcile
+// metrics of the object are deleted instead of recorded when the current
+// reconcile is observed
+func ForgetReconcile(controllerName, key string) {
+	deletedObjects.Store(controllerName+"/"+key, struct{}{})
+}
+
+// ObserveReconcile records the result and the duration of a reconcile of the object
+// with the key by the controller, the requeue and ignore errors are not failures
+func ObserveReconcile(controllerName, key string, startTime time.Time, err error) {
+	ns, name, splitErr := cache.SplitMetaNamespaceKey(key)
+	if splitErr != nil {
+		return
+	}
+	if _, ok := deletedObjects.Load(controllerName + "/" + key); ok {
+		deletedObjects.Delete(controllerName + "/" + key)
+		metrics.DeleteReconcileMetrics(controllerName, ns, name)
+		return
+	}
+	result := metrics.ReconcileSuccess
+	if err != nil {
+		if perrors.Find(err, IsRequeueError) != nil || perrors.Find(err, IsIgnoreError) != nil {
+			result = metrics.ReconcileRequeue
+		} else {
+			result = metrics.ReconcileError
+			metrics.ReconcileErrors.WithLabelValues(controllerName, ns, name).Inc()
+		}
+	}
+	metrics.ReconcileTotal.WithLabelValues(controllerName, ns, name, result).Inc()
+	metrics.ReconcileDuration.WithLabelValues(controllerName, ns, name).Observe(time.Since(startTime).Seconds())
+}
+
 // GetOwnerRef returns TidbCluster's OwnerReference
 func GetOwnerRef(tc *v1alpha1.TidbCluster) metav1.OwnerReference {
 	controller := true
@@ -253,6 +293,15 @@ func TiKVMemberName(clusterName string) string {
 	return fmt.Sprintf("%s-tikv", clusterName)
 }
 
+// TiKVGroupMemberName returns tikv member name of the given group, it is the
+// tikv member name for the default group
+func TiKVGroupMemberName(clusterName, group string) string {
+	if group == "" {
+		return TiKVMemberName(clusterName)
+	}
+	return fmt.Sprintf("%s-tikv-%s", clusterName, group)
+}
+
 // TiKVPeerMemberName returns tikv peer service name
 func TiKVPeerMemberName(clusterName string) string {
 	return fmt.Sprintf("%s-tikv-peer", clusterName)
@@ -283,6 +332,15 @@ func TiDBMemberName(clusterName string) string {
 	return fmt.Sprintf("%s-tidb", clusterName)
 }
 
+// TiDBGroupMemberName returns tidb member name of the given group, it is the
+// tidb member name for the default group
+func TiDBGroupMemberName(clusterName, group string) string {
+	if group == "" {
+		return TiDBMemberName(clusterName)
+	}
+	return fmt.Sprintf("%s-tidb-%s", clusterName, group)
+}
+
 // TiDBPeerMemberName returns tidb peer service name
 func TiDBPeerMemberName(clusterName string) string {
 	return fmt.Sprintf("%s-tidb-peer", clusterName)
@@ -304,6 +362,17 @@ func PumpPeerMemberName(clusterName string) string {
 	return fmt.Sprintf("%s-pump", clusterName)
 }
 
+// DrainerMemberName returns drainer member name
+func DrainerMemberName(clusterName string) string {
+	return fmt.Sprintf("%s-drainer", clusterName)
+}
+
+// DrainerPeerMemberName returns drainer peer service name, it is the same
+// as the member name like pump, so that the advertise address is short
+func DrainerPeerMemberName(clusterName string) string {
+	return fmt.Sprintf("%s-drainer", clusterName)
+}
+
 // DiscoveryMemberName returns the name of tidb discovery
 func DiscoveryMemberName(clusterName string) string {
 	return fmt.Sprintf("%s-discovery", clusterName)
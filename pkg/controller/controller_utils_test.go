@@ -16,8 +16,11 @@ package controller
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/pingcap/tidb-operator/pkg/label"
+	"github.com/pingcap/tidb-operator/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"k8s.io/apimachinery/pkg/api/resource"
 
 	. "github.com/onsi/gomega"
@@ -593,3 +596,28 @@ func newStatefulSet(tc *v1alpha1.TidbCluster, _ string) *apps.StatefulSet {
 func GetName(tcName string, name string) string {
 	return fmt.Sprintf("%s-%s", tcName, name)
 }
+
+func TestObserveReconcile(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	ObserveReconcile("test", "ns/demo", time.Now(), nil)
+	ObserveReconcile("test", "ns/demo", time.Now(), RequeueErrorf("waiting"))
+	ObserveReconcile("test", "ns/demo", time.Now(), IgnoreErrorf("ignored"))
+	ObserveReconcile("test", "ns/demo", time.Now(), fmt.Errorf("failed"))
+
+	g.Expect(testutil.ToFloat64(metrics.ReconcileTotal.WithLabelValues("test", "ns", "demo", metrics.ReconcileSuccess))).To(Equal(float64(1)))
+	g.Expect(testutil.ToFloat64(metrics.ReconcileTotal.WithLabelValues("test", "ns", "demo", metrics.ReconcileRequeue))).To(Equal(float64(2)))
+	g.Expect(testutil.ToFloat64(metrics.ReconcileTotal.WithLabelValues("test", "ns", "demo", metrics.ReconcileError))).To(Equal(float64(1)))
+	g.Expect(testutil.ToFloat64(metrics.ReconcileErrors.WithLabelValues("test", "ns", "demo"))).To(Equal(float64(1)))
+
+	// the metrics of a deleted object are deleted
+	ForgetReconcile("test", "ns/demo")
+	ObserveReconcile("test", "ns/demo", time.Now(), nil)
+	g.Expect(metrics.ReconcileTotal.DeleteLabelValues("test", "ns", "demo", metrics.ReconcileSuccess)).To(BeFalse())
+	g.Expect(metrics.ReconcileErrors.DeleteLabelValues("test", "ns", "demo")).To(BeFalse())
+	g.Expect(metrics.ReconcileDuration.DeleteLabelValues("test", "ns", "demo")).To(BeFalse())
+
+	// the object is observed again when it is recreated
+	ObserveReconcile("test", "ns/demo", time.Now(), nil)
+	g.Expect(testutil.ToFloat64(metrics.ReconcileTotal.WithLabelValues("test", "ns", "demo", metrics.ReconcileSuccess))).To(Equal(float64(1)))
+}
@@ -76,6 +76,12 @@ type CLIConfig struct {
 	// Selector is used to filter CR labels to decide
 	// what resources should be watched and synced by controller
 	Selector string
+	// NodeDrainTaints are the comma separated taint keys, the nodes with any
+	// of the taints are drained like the unschedulable nodes
+	NodeDrainTaints string
+	// LocalPVRecoveryPeriod is the time a node must be NotReady or deleted before
+	// the members on its local volumes are recreated on other nodes
+	LocalPVRecoveryPeriod time.Duration
 }
 
 // DefaultCLIConfig returns the default command line configuration
@@ -90,6 +96,7 @@ func DefaultCLIConfig() *CLIConfig {
 		TiFlashFailoverPeriod:  5 * time.Minute,
 		MasterFailoverPeriod:   5 * time.Minute,
 		WorkerFailoverPeriod:   5 * time.Minute,
+		LocalPVRecoveryPeriod:  30 * time.Minute,
 		LeaseDuration:          15 * time.Second,
 		RenewDuration:          5 * time.Second,
 		RetryPeriod:            3 * time.Second,
@@ -114,6 +121,7 @@ func (c *CLIConfig) AddFlag(_ *flag.FlagSet) {
 	flag.DurationVar(&c.TiDBFailoverPeriod, "tidb-failover-period", c.TiDBFailoverPeriod, "TiDB failover period")
 	flag.DurationVar(&c.MasterFailoverPeriod, "dm-master-failover-period", c.MasterFailoverPeriod, "dm-master failover period")
 	flag.DurationVar(&c.WorkerFailoverPeriod, "dm-worker-failover-period", c.WorkerFailoverPeriod, "dm-worker failover period")
+	flag.DurationVar(&c.LocalPVRecoveryPeriod, "local-pv-recovery-period", c.LocalPVRecoveryPeriod, "The time a node must be NotReady or deleted before the PD members and TiKV stores on its local volumes are recreated on other nodes, only used when the LocalPVRecovery feature is enabled")
 	flag.DurationVar(&c.ResyncDuration, "resync-duration", c.ResyncDuration, "Resync time of informer")
 	flag.BoolVar(&c.TestMode, "test-mode", false, "whether tidb-operator run in test mode")
 	flag.StringVar(&c.TiDBBackupManagerImage, "tidb-backup-manager-image", c.TiDBBackupManagerImage, "The image of backup manager tool")
@@ -121,6 +129,7 @@ func (c *CLIConfig) AddFlag(_ *flag.FlagSet) {
 	flag.StringVar(&c.TiDBDiscoveryImage, "tidb-discovery-image", c.TiDBDiscoveryImage, "The image of the tidb discovery service")
 	flag.BoolVar(&c.PodWebhookEnabled, "pod-webhook-enabled", false, "Whether Pod admission webhook is enabled")
 	flag.StringVar(&c.Selector, "selector", c.Selector, "Selector (label query) to filter on, supports '=', '==', and '!='")
+	flag.StringVar(&c.NodeDrainTaints, "node-drain-taints", c.NodeDrainTaints, "Comma separated taint keys, the leaders of the TiKV and TiFlash stores on the nodes with any of the taints are evicted like on the unschedulable nodes")
 }
 
 type Controls struct {
@@ -139,8 +148,11 @@ type Controls struct {
 	TiDBClusterControl TidbClusterControlInterface
 	DMClusterControl   DMClusterControlInterface
 	CDCControl         TiCDCControlInterface
+	PumpControl        PumpControlInterface
 	TiDBControl        TiDBControlInterface
+	TiKVControl        TiKVControlInterface
 	BackupControl      BackupControlInterface
+	RestoreControl     RestoreControlInterface
 }
 
 // Dependencies is used to store all shared dependent resources to avoid
@@ -180,6 +192,8 @@ type Dependencies struct {
 	BackupScheduleLister        listers.BackupScheduleLister
 	TiDBInitializerLister       listers.TidbInitializerLister
 	TiDBMonitorLister           listers.TidbMonitorLister
+	TiCDCChangefeedLister       listers.TiCDCChangefeedLister
+	PlacementRuleLister         listers.PlacementRuleLister
 
 	// Controls
 	Controls
@@ -221,8 +235,11 @@ func newRealControls(
 		TiDBClusterControl: NewRealTidbClusterControl(clientset, tidbClusterLister, recorder),
 		DMClusterControl:   NewRealDMClusterControl(clientset, dmClusterLister, recorder),
 		CDCControl:         NewDefaultTiCDCControl(kubeClientset),
+		PumpControl:        NewDefaultPumpControl(kubeClientset),
 		TiDBControl:        NewDefaultTiDBControl(kubeClientset),
+		TiKVControl:        NewDefaultTiKVControl(kubeClientset),
 		BackupControl:      NewRealBackupControl(clientset, recorder),
+		RestoreControl:     NewRealRestoreControl(clientset, recorder),
 	}
 }
 
@@ -267,6 +284,8 @@ func newDependencies(
 		BackupScheduleLister:        informerFactory.Pingcap().V1alpha1().BackupSchedules().Lister(),
 		TiDBInitializerLister:       informerFactory.Pingcap().V1alpha1().TidbInitializers().Lister(),
 		TiDBMonitorLister:           informerFactory.Pingcap().V1alpha1().TidbMonitors().Lister(),
+		TiCDCChangefeedLister:       informerFactory.Pingcap().V1alpha1().TiCDCChangefeeds().Lister(),
+		PlacementRuleLister:         informerFactory.Pingcap().V1alpha1().PlacementRules().Lister(),
 	}
 }
 
@@ -328,9 +347,12 @@ func newFakeControl(kubeClientset kubernetes.Interface, informerFactory informer
 		PDControl:          pdapi.NewFakePDControl(kubeClientset),
 		DMMasterControl:    dmapi.NewFakeMasterControl(kubeClientset),
 		TiDBClusterControl: NewFakeTidbClusterControl(informerFactory.Pingcap().V1alpha1().TidbClusters()),
-		CDCControl:         NewDefaultTiCDCControl(kubeClientset), // TODO: no fake control?
+		CDCControl:         NewFakeTiCDCControl(),
+		PumpControl:        NewFakePumpControl(),
 		TiDBControl:        NewFakeTiDBControl(),
+		TiKVControl:        NewFakeTiKVControl(),
 		BackupControl:      NewFakeBackupControl(informerFactory.Pingcap().V1alpha1().Backups()),
+		RestoreControl:     NewFakeRestoreControl(informerFactory.Pingcap().V1alpha1().Restores()),
 	}
 }
 
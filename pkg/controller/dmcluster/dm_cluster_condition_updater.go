@@ -14,6 +14,8 @@
 package dmcluster
 
 import (
+	"fmt"
+
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	utildmcluster "github.com/pingcap/tidb-operator/pkg/util/dmcluster"
 	appsv1 "k8s.io/api/apps/v1"
@@ -33,6 +35,28 @@ var _ DMClusterConditionUpdater = &dmClusterConditionUpdater{}
 
 func (u *dmClusterConditionUpdater) Update(dc *v1alpha1.DMCluster) error {
 	u.updateReadyCondition(dc)
+	u.updateVolumeResizingCondition(dc)
+	u.updateMasterAvailableCondition(dc)
+	u.updateWorkersHealthyCondition(dc)
+	components := componentStatuses(dc)
+	u.updateComponentCondition(dc, components, v1alpha1.DMClusterUpgrading,
+		utildmcluster.UpgradingSuffix, "%s is being upgraded",
+		utildmcluster.NoComponentsUpgrading, "No components are being upgraded",
+		func(c componentStatus) bool { return c.phase == v1alpha1.UpgradePhase })
+	u.updateComponentCondition(dc, components, v1alpha1.DMClusterScaling,
+		utildmcluster.ScalingSuffix, "%s is being scaled",
+		utildmcluster.NoComponentsScaling, "No components are being scaled",
+		func(c componentStatus) bool { return c.phase == v1alpha1.ScalePhase })
+	u.updateComponentCondition(dc, components, v1alpha1.DMClusterFailoverActive,
+		utildmcluster.FailoverActiveSuffix, "%s has failure members failed over",
+		utildmcluster.NoFailoverActive, "No failure members",
+		func(c componentStatus) bool { return c.failures > 0 })
+	u.updateComponentCondition(dc, components, v1alpha1.DMClusterConfigOutOfSync,
+		utildmcluster.ConfigOutOfSyncSuffix, "%s pods are not updated to the latest revision",
+		utildmcluster.ConfigInSync, "All the pods are up to date",
+		func(c componentStatus) bool {
+			return c.statefulSet != nil && c.statefulSet.CurrentRevision != c.statefulSet.UpdateRevision
+		})
 	// in the future, we may return error when we need to Kubernetes API, etc.
 	return nil
 }
@@ -71,3 +95,78 @@ func (u *dmClusterConditionUpdater) updateReadyCondition(dc *v1alpha1.DMCluster)
 	cond := utildmcluster.NewDMClusterCondition(v1alpha1.DMClusterReady, status, reason, message)
 	utildmcluster.SetDMClusterCondition(&dc.Status, *cond)
 }
+
+func (u *dmClusterConditionUpdater) updateVolumeResizingCondition(dc *v1alpha1.DMCluster) {
+	if len(dc.Status.ResizingPVCs) == 0 {
+		if utildmcluster.GetDMClusterCondition(dc.Status, v1alpha1.DMClusterVolumeResizing) == nil {
+			return
+		}
+		cond := utildmcluster.NewDMClusterCondition(v1alpha1.DMClusterVolumeResizing, v1.ConditionFalse, utildmcluster.NoVolumesResizing, "All the volumes are resized")
+		utildmcluster.SetDMClusterCondition(&dc.Status, *cond)
+		return
+	}
+	cond := utildmcluster.NewDMClusterCondition(v1alpha1.DMClusterVolumeResizing, v1.ConditionTrue, utildmcluster.VolumesResizing, "Volumes are being resized to the storage request")
+	utildmcluster.SetDMClusterCondition(&dc.Status, *cond)
+}
+
+func (u *dmClusterConditionUpdater) updateMasterAvailableCondition(dc *v1alpha1.DMCluster) {
+	var cond *v1alpha1.DMClusterCondition
+	switch {
+	case !dc.MasterIsAvailable():
+		cond = utildmcluster.NewDMClusterCondition(v1alpha1.DMClusterMasterAvailable, v1.ConditionFalse, utildmcluster.MasterQuorumUnavailable, "The quorum of dm-master members is not healthy")
+	case !dc.MasterAllMembersReady():
+		cond = utildmcluster.NewDMClusterCondition(v1alpha1.DMClusterMasterAvailable, v1.ConditionTrue, utildmcluster.MasterUnhealthy, "Some dm-master members are not healthy")
+	default:
+		cond = utildmcluster.NewDMClusterCondition(v1alpha1.DMClusterMasterAvailable, v1.ConditionTrue, utildmcluster.MasterAllMembersHealthy, "All dm-master members are healthy")
+	}
+	utildmcluster.SetDMClusterCondition(&dc.Status, *cond)
+}
+
+func (u *dmClusterConditionUpdater) updateWorkersHealthyCondition(dc *v1alpha1.DMCluster) {
+	if dc.Spec.Worker == nil {
+		return
+	}
+	cond := utildmcluster.NewDMClusterCondition(v1alpha1.DMClusterWorkersHealthy, v1.ConditionTrue, utildmcluster.WorkerAllMembersUp, "All dm-workers are up")
+	if !dc.WorkerAllMembersReady() {
+		cond = utildmcluster.NewDMClusterCondition(v1alpha1.DMClusterWorkersHealthy, v1.ConditionFalse, utildmcluster.WorkerOffline, "Some dm-workers are not up yet")
+	}
+	utildmcluster.SetDMClusterCondition(&dc.Status, *cond)
+}
+
+// componentStatus is the status of a component to update the Upgrading, Scaling,
+// FailoverActive and ConfigOutOfSync conditions
+type componentStatus struct {
+	name        string
+	phase       v1alpha1.MemberPhase
+	statefulSet *appsv1.StatefulSetStatus
+	failures    int
+}
+
+// componentStatuses returns the statuses of the components in the upgrade order
+func componentStatuses(dc *v1alpha1.DMCluster) []componentStatus {
+	return []componentStatus{
+		{name: "DMMaster", phase: dc.Status.Master.Phase, statefulSet: dc.Status.Master.StatefulSet, failures: len(dc.Status.Master.FailureMembers)},
+		{name: "DMWorker", phase: dc.Status.Worker.Phase, statefulSet: dc.Status.Worker.StatefulSet, failures: len(dc.Status.Worker.FailureMembers)},
+	}
+}
+
+// updateComponentCondition sets the condition to True if any component matches, the reason
+// is the name of the first matched component followed by the suffix, e.g. DMMasterUpgrading.
+// The reason changes with the component, so the message is kept up to date.
+func (u *dmClusterConditionUpdater) updateComponentCondition(
+	dc *v1alpha1.DMCluster,
+	components []componentStatus,
+	condType v1alpha1.DMClusterConditionType,
+	suffix, messageFormat string,
+	falseReason, falseMessage string,
+	match func(componentStatus) bool,
+) {
+	cond := utildmcluster.NewDMClusterCondition(condType, v1.ConditionFalse, falseReason, falseMessage)
+	for _, c := range components {
+		if match(c) {
+			cond = utildmcluster.NewDMClusterCondition(condType, v1.ConditionTrue, c.name+suffix, fmt.Sprintf(messageFormat, c.name))
+			break
+		}
+	}
+	utildmcluster.SetDMClusterCondition(&dc.Status, *cond)
+}
@@ -149,3 +149,150 @@ func TestDMClusterConditionUpdater_Ready(t *testing.T) {
 		})
 	}
 }
+
+func TestDMClusterConditionUpdater_VolumeResizing(t *testing.T) {
+	resized := func() v1alpha1.DMClusterStatus {
+		status := v1alpha1.DMClusterStatus{}
+		cond := utildmcluster.NewDMClusterCondition(v1alpha1.DMClusterVolumeResizing, v1.ConditionTrue, utildmcluster.VolumesResizing, "")
+		utildmcluster.SetDMClusterCondition(&status, *cond)
+		return status
+	}
+	tests := []struct {
+		name       string
+		dc         *v1alpha1.DMCluster
+		wantStatus v1.ConditionStatus
+	}{
+		{
+			name:       "never resized",
+			dc:         &v1alpha1.DMCluster{},
+			wantStatus: "",
+		},
+		{
+			name: "resizing",
+			dc: &v1alpha1.DMCluster{
+				Status: v1alpha1.DMClusterStatus{ResizingPVCs: map[string]v1alpha1.PVCResizeStatus{
+					"dm-worker-test-dm-worker-0": {PodName: "test-dm-worker-0", Phase: v1alpha1.PVCFileSystemResizePending},
+				}},
+			},
+			wantStatus: v1.ConditionTrue,
+		},
+		{
+			name:       "resized",
+			dc:         &v1alpha1.DMCluster{Status: resized()},
+			wantStatus: v1.ConditionFalse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conditionUpdater := &dmClusterConditionUpdater{}
+			conditionUpdater.Update(tt.dc)
+			var status v1.ConditionStatus
+			if cond := utildmcluster.GetDMClusterCondition(tt.dc.Status, v1alpha1.DMClusterVolumeResizing); cond != nil {
+				status = cond.Status
+			}
+			if diff := cmp.Diff(tt.wantStatus, status); diff != "" {
+				t.Errorf("unexpected status (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestDMClusterConditionUpdater_Components(t *testing.T) {
+	newDMCluster := func() *v1alpha1.DMCluster {
+		upToDate := &appsv1.StatefulSetStatus{CurrentRevision: "2", UpdateRevision: "2", ReadyReplicas: 3}
+		return &v1alpha1.DMCluster{
+			Spec: v1alpha1.DMClusterSpec{
+				Master: v1alpha1.MasterSpec{Replicas: 3},
+				Worker: &v1alpha1.WorkerSpec{Replicas: 1},
+			},
+			Status: v1alpha1.DMClusterStatus{
+				Master: v1alpha1.MasterStatus{
+					Members: map[string]v1alpha1.MasterMember{
+						"dm-master-0": {Health: true},
+						"dm-master-1": {Health: true},
+						"dm-master-2": {Health: true},
+					},
+					StatefulSet: upToDate,
+				},
+				Worker: v1alpha1.WorkerStatus{
+					Members:     map[string]v1alpha1.WorkerMember{"dm-worker-0": {Stage: "free"}},
+					StatefulSet: upToDate,
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name        string
+		update      func(*v1alpha1.DMCluster)
+		wantReasons map[v1alpha1.DMClusterConditionType]string
+	}{
+		{
+			name: "all healthy",
+			wantReasons: map[v1alpha1.DMClusterConditionType]string{
+				v1alpha1.DMClusterMasterAvailable: utildmcluster.MasterAllMembersHealthy,
+				v1alpha1.DMClusterWorkersHealthy:  utildmcluster.WorkerAllMembersUp,
+				v1alpha1.DMClusterUpgrading:       utildmcluster.NoComponentsUpgrading,
+				v1alpha1.DMClusterScaling:         utildmcluster.NoComponentsScaling,
+				v1alpha1.DMClusterFailoverActive:  utildmcluster.NoFailoverActive,
+				v1alpha1.DMClusterConfigOutOfSync: utildmcluster.ConfigInSync,
+			},
+		},
+		{
+			name: "members unhealthy",
+			update: func(dc *v1alpha1.DMCluster) {
+				dc.Status.Master.Members["dm-master-2"] = v1alpha1.MasterMember{Health: false}
+				dc.Status.Worker.Members["dm-worker-0"] = v1alpha1.WorkerMember{Stage: "offline"}
+			},
+			wantReasons: map[v1alpha1.DMClusterConditionType]string{
+				v1alpha1.DMClusterMasterAvailable: utildmcluster.MasterUnhealthy,
+				v1alpha1.DMClusterWorkersHealthy:  utildmcluster.WorkerOffline,
+			},
+		},
+		{
+			name: "quorum unavailable",
+			update: func(dc *v1alpha1.DMCluster) {
+				dc.Status.Master.Members["dm-master-1"] = v1alpha1.MasterMember{Health: false}
+				dc.Status.Master.Members["dm-master-2"] = v1alpha1.MasterMember{Health: false}
+			},
+			wantReasons: map[v1alpha1.DMClusterConditionType]string{
+				v1alpha1.DMClusterMasterAvailable: utildmcluster.MasterQuorumUnavailable,
+			},
+		},
+		{
+			name: "operations in progress",
+			update: func(dc *v1alpha1.DMCluster) {
+				dc.Status.Master.Phase = v1alpha1.ScalePhase
+				dc.Status.Worker.Phase = v1alpha1.UpgradePhase
+				dc.Status.Worker.StatefulSet = &appsv1.StatefulSetStatus{CurrentRevision: "2", UpdateRevision: "3"}
+				dc.Status.Worker.FailureMembers = map[string]v1alpha1.WorkerFailureMember{"dm-worker-0": {PodName: "dm-worker-0"}}
+			},
+			wantReasons: map[v1alpha1.DMClusterConditionType]string{
+				v1alpha1.DMClusterUpgrading:       "DMWorkerUpgrading",
+				v1alpha1.DMClusterScaling:         "DMMasterScaling",
+				v1alpha1.DMClusterFailoverActive:  "DMWorkerFailoverActive",
+				v1alpha1.DMClusterConfigOutOfSync: "DMWorkerConfigOutOfSync",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dc := newDMCluster()
+			if tt.update != nil {
+				tt.update(dc)
+			}
+			conditionUpdater := &dmClusterConditionUpdater{}
+			conditionUpdater.Update(dc)
+			for condType, wantReason := range tt.wantReasons {
+				cond := utildmcluster.GetDMClusterCondition(dc.Status, condType)
+				if cond == nil {
+					t.Fatalf("condition %s is not set", condType)
+				}
+				if diff := cmp.Diff(wantReason, cond.Reason); diff != "" {
+					t.Errorf("unexpected reason of %s (-want, +got): %s", condType, diff)
+				}
+			}
+		})
+	}
+}
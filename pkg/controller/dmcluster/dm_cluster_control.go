@@ -18,8 +18,10 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1/defaulting"
 	v1alpha1validation "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1/validation"
 	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
 	"github.com/pingcap/tidb-operator/pkg/manager"
 	"github.com/pingcap/tidb-operator/pkg/manager/member"
+	"github.com/pingcap/tidb-operator/pkg/metrics"
 	v1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	errorutils "k8s.io/apimachinery/pkg/util/errors"
@@ -87,6 +89,10 @@ func (c *defaultDMClusterControl) UpdateDMCluster(dc *v1alpha1.DMCluster) error
 	if err := c.updateDMCluster(dc); err != nil {
 		errs = append(errs, err)
 	}
+	metrics.ObserveUpgradePhase(dc.GetNamespace(), dc.GetName(), label.DMMasterLabelVal, dc.MasterUpgrading())
+	if dc.Spec.Worker != nil {
+		metrics.ObserveUpgradePhase(dc.GetNamespace(), dc.GetName(), label.DMWorkerLabelVal, dc.Status.Worker.Phase == v1alpha1.UpgradePhase)
+	}
 
 	if err := c.conditionUpdater.Update(dc); err != nil {
 		errs = append(errs, err)
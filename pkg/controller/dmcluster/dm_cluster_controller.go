@@ -22,6 +22,7 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	mm "github.com/pingcap/tidb-operator/pkg/manager/member"
 	"github.com/pingcap/tidb-operator/pkg/manager/meta"
+	"github.com/pingcap/tidb-operator/pkg/metrics"
 
 	apps "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -111,7 +112,10 @@ func (c *Controller) processNextWorkItem() bool {
 		return false
 	}
 	defer c.queue.Done(key)
-	if err := c.sync(key.(string)); err != nil {
+	startTime := time.Now()
+	err := c.sync(key.(string))
+	controller.ObserveReconcile("dmcluster", key.(string), startTime, err)
+	if err != nil {
 		if perrors.Find(err, controller.IsRequeueError) != nil {
 			klog.Infof("DMCluster: %v, still need sync: %v, requeuing", key.(string), err)
 		} else {
@@ -138,6 +142,8 @@ func (c *Controller) sync(key string) error {
 	dc, err := c.deps.DMClusterLister.DMClusters(ns).Get(name)
 	if errors.IsNotFound(err) {
 		klog.Infof("DMCluster has been deleted %v", key)
+		controller.ForgetReconcile("dmcluster", key)
+		metrics.DeleteClusterMetrics(ns, name)
 		return nil
 	}
 	if err != nil {
@@ -0,0 +1,377 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package placementrule
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog"
+	"k8s.io/kubernetes/pkg/util/slice"
+)
+
+// reservedRuleGroups are the rule groups managed by PD and TiDB,
+// the default rule lives in group "pd" and the TiFlash replicas in group "tiflash"
+var reservedRuleGroups = map[string]bool{
+	"pd":      true,
+	"tiflash": true,
+}
+
+// ControlInterface reconciles PlacementRule
+type ControlInterface interface {
+	// ReconcilePlacementRule implements the reconcile logic of PlacementRule
+	ReconcilePlacementRule(pr *v1alpha1.PlacementRule) error
+}
+
+// NewDefaultPlacementRuleControl returns a new instance of the default PlacementRule ControlInterface
+func NewDefaultPlacementRuleControl(deps *controller.Dependencies) ControlInterface {
+	return &defaultPlacementRuleControl{deps: deps}
+}
+
+type defaultPlacementRuleControl struct {
+	deps *controller.Dependencies
+}
+
+func (c *defaultPlacementRuleControl) ReconcilePlacementRule(pr *v1alpha1.PlacementRule) error {
+	if pr.DeletionTimestamp != nil {
+		return c.removePlacementRule(pr)
+	}
+
+	if !slice.ContainsString(pr.Finalizers, label.PlacementRuleFinalizer, nil) {
+		// make a copy so we don't mutate the shared cache
+		pr = pr.DeepCopy()
+		pr.Finalizers = append(pr.Finalizers, label.PlacementRuleFinalizer)
+		updated, err := c.deps.Clientset.PingcapV1alpha1().PlacementRules(pr.Namespace).Update(pr)
+		if err != nil {
+			return fmt.Errorf("add placement rule %s/%s finalizer failed, err: %v", pr.Namespace, pr.Name, err)
+		}
+		pr = updated
+	}
+
+	tc, err := c.getTidbCluster(pr)
+	if err != nil {
+		return fmt.Errorf("failed to get TidbCluster %s/%s for placement rule %s/%s, error: %v", pr.GetClusterNamespace(), pr.Spec.Cluster.Name, pr.Namespace, pr.Name, err)
+	}
+	if tc.Spec.PD == nil {
+		return fmt.Errorf("TidbCluster %s/%s has no pd for placement rule %s/%s", tc.Namespace, tc.Name, pr.Namespace, pr.Name)
+	}
+	if tc.Spec.Paused {
+		klog.Infof("TidbCluster %s/%s is paused, skip syncing placement rule %s/%s", tc.Namespace, tc.Name, pr.Namespace, pr.Name)
+		return nil
+	}
+
+	return c.syncPlacementRule(tc, pr.DeepCopy())
+}
+
+func (c *defaultPlacementRuleControl) syncPlacementRule(tc *v1alpha1.TidbCluster, pr *v1alpha1.PlacementRule) error {
+	ns := pr.GetNamespace()
+	name := pr.GetName()
+	groupID := pr.GetGroupID()
+
+	if reservedRuleGroups[groupID] {
+		return c.syncFailed(pr, fmt.Errorf("rule group %s of placement rule %s/%s is reserved", groupID, ns, name))
+	}
+
+	pdCli := controller.GetPDClient(c.deps.PDControl, tc)
+	if err := enablePlacementRules(pdCli, tc); err != nil {
+		return c.syncFailed(pr, fmt.Errorf("failed to enable placement rules for placement rule %s/%s, error: %v", ns, name, err))
+	}
+
+	// the group ID is changed, the rules of the old group should be removed first
+	if pr.Status.GroupID != "" && pr.Status.GroupID != groupID {
+		if err := removeRuleGroup(pdCli, pr.Status.GroupID); err != nil {
+			return c.syncFailed(pr, fmt.Errorf("failed to remove rule group %s for placement rule %s/%s, error: %v", pr.Status.GroupID, ns, name, err))
+		}
+		klog.Infof("rule group %s of placement rule %s/%s is removed since the group ID is changed to %s", pr.Status.GroupID, ns, name, groupID)
+		pr.Status.GroupID = ""
+	}
+
+	drifts, err := applyRuleGroup(pdCli, newPDRuleGroup(pr), newPDRules(pr))
+	if err != nil {
+		return c.syncFailed(pr, fmt.Errorf("failed to sync rule group %s for placement rule %s/%s, error: %v", groupID, ns, name, err))
+	}
+
+	now := metav1.Now()
+	// the differences before the spec is applied are expected,
+	// only the changes made to PD behind the operator are drifts
+	if pr.IsSpecApplied() && len(drifts) > 0 {
+		klog.Infof("rule group %s of placement rule %s/%s drifted from the spec: %v, reverted", groupID, ns, name, drifts)
+		pr.Status.Drifts = drifts
+		pr.Status.LastDriftTime = &now
+	} else {
+		pr.Status.Drifts = nil
+	}
+	// the sync time is only changed when the rules are applied, or the status
+	// write of every sync triggers another sync at once
+	if len(drifts) > 0 || pr.Status.LastSyncTime == nil || !pr.Status.Synced ||
+		pr.Status.GroupID != groupID || pr.Status.ObservedGeneration != pr.Generation {
+		pr.Status.LastSyncTime = &now
+	}
+	pr.Status.GroupID = groupID
+	pr.Status.ObservedGeneration = pr.Generation
+	pr.Status.Synced = true
+	pr.Status.Message = ""
+	return c.updatePlacementRule(pr)
+}
+
+// syncFailed records the error in status and returns it
+func (c *defaultPlacementRuleControl) syncFailed(pr *v1alpha1.PlacementRule, err error) error {
+	pr.Status.Synced = false
+	pr.Status.Message = err.Error()
+	if updateErr := c.updatePlacementRule(pr); updateErr != nil {
+		klog.Errorf("failed to update status of placement rule %s/%s, error: %v", pr.Namespace, pr.Name, updateErr)
+	}
+	return err
+}
+
+// applyRuleGroup makes the rule group in PD match the desired one and
+// returns the differences found before applying
+func applyRuleGroup(pdCli pdapi.PDClient, group *pdapi.PlacementRuleGroup, rules []*pdapi.PlacementRule) ([]v1alpha1.PlacementRuleDrift, error) {
+	var drifts []v1alpha1.PlacementRuleDrift
+
+	groups, err := pdCli.GetPlacementRuleGroups()
+	if err != nil {
+		return nil, err
+	}
+	var current *pdapi.PlacementRuleGroup
+	for _, g := range groups {
+		if g.ID == group.ID {
+			current = g
+			break
+		}
+	}
+	if current == nil || !apiequality.Semantic.DeepEqual(current, group) {
+		driftType := v1alpha1.PlacementRuleDriftModified
+		if current == nil {
+			driftType = v1alpha1.PlacementRuleDriftMissing
+		}
+		drifts = append(drifts, v1alpha1.PlacementRuleDrift{Type: driftType})
+		if err := pdCli.SetPlacementRuleGroup(group); err != nil {
+			return nil, err
+		}
+	}
+
+	currentRules, err := pdCli.GetPlacementRules(group.ID)
+	if err != nil {
+		return nil, err
+	}
+	existing := map[string]*pdapi.PlacementRule{}
+	for _, rule := range currentRules {
+		existing[rule.ID] = rule
+	}
+	desired := map[string]bool{}
+	for _, rule := range rules {
+		desired[rule.ID] = true
+		current, ok := existing[rule.ID]
+		if ok && placementRuleEqual(current, rule) {
+			continue
+		}
+		driftType := v1alpha1.PlacementRuleDriftModified
+		if !ok {
+			driftType = v1alpha1.PlacementRuleDriftMissing
+		}
+		drifts = append(drifts, v1alpha1.PlacementRuleDrift{RuleID: rule.ID, Type: driftType})
+		if err := pdCli.SetPlacementRule(rule); err != nil {
+			return nil, err
+		}
+	}
+	for _, rule := range currentRules {
+		if desired[rule.ID] {
+			continue
+		}
+		drifts = append(drifts, v1alpha1.PlacementRuleDrift{RuleID: rule.ID, Type: v1alpha1.PlacementRuleDriftUnexpected})
+		if err := pdCli.DeletePlacementRule(rule.GroupID, rule.ID); err != nil {
+			return nil, err
+		}
+	}
+	return drifts, nil
+}
+
+// placementRuleEqual compares two rules, PD returns the keys in lower case hex
+func placementRuleEqual(a, b *pdapi.PlacementRule) bool {
+	x, y := *a, *b
+	x.StartKeyHex, y.StartKeyHex = strings.ToLower(x.StartKeyHex), strings.ToLower(y.StartKeyHex)
+	x.EndKeyHex, y.EndKeyHex = strings.ToLower(x.EndKeyHex), strings.ToLower(y.EndKeyHex)
+	return apiequality.Semantic.DeepEqual(x, y)
+}
+
+func newPDRuleGroup(pr *v1alpha1.PlacementRule) *pdapi.PlacementRuleGroup {
+	return &pdapi.PlacementRuleGroup{
+		ID:       pr.GetGroupID(),
+		Index:    pr.Spec.GroupIndex,
+		Override: pr.Spec.GroupOverride,
+	}
+}
+
+func newPDRules(pr *v1alpha1.PlacementRule) []*pdapi.PlacementRule {
+	var rules []*pdapi.PlacementRule
+	for _, item := range pr.Spec.Rules {
+		rule := &pdapi.PlacementRule{
+			GroupID:        pr.GetGroupID(),
+			ID:             item.ID,
+			Index:          item.Index,
+			Override:       item.Override,
+			StartKeyHex:    item.StartKeyHex,
+			EndKeyHex:      item.EndKeyHex,
+			Role:           string(item.Role),
+			Count:          item.Count,
+			LocationLabels: item.LocationLabels,
+			IsolationLevel: item.IsolationLevel,
+		}
+		for _, constraint := range item.LabelConstraints {
+			rule.LabelConstraints = append(rule.LabelConstraints, pdapi.PlacementLabelConstraint{
+				Key:    constraint.Key,
+				Op:     constraint.Op,
+				Values: constraint.Values,
+			})
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// enablePlacementRules turns on placement rules of PD, the rules are ignored otherwise
+func enablePlacementRules(pdCli pdapi.PDClient, tc *v1alpha1.TidbCluster) error {
+	config, err := pdCli.GetConfig()
+	if err != nil {
+		return err
+	}
+	if config.Replication != nil && config.Replication.EnablePlacementRules != nil && !*config.Replication.EnablePlacementRules {
+		klog.Infof("Cluster %s/%s enable-placement-rules is false, set it to true", tc.Namespace, tc.Name)
+		enable := true
+		return pdCli.UpdateReplicationConfig(pdapi.PDReplicationConfig{
+			EnablePlacementRules: &enable,
+		})
+	}
+	return nil
+}
+
+// removeRuleGroup removes all the rules of the group and then the group itself
+func removeRuleGroup(pdCli pdapi.PDClient, groupID string) error {
+	rules, err := pdCli.GetPlacementRules(groupID)
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		if err := pdCli.DeletePlacementRule(groupID, rule.ID); err != nil {
+			return err
+		}
+	}
+	return pdCli.DeletePlacementRuleGroup(groupID)
+}
+
+// removePlacementRule removes the rules from PD and then removes the finalizer
+func (c *defaultPlacementRuleControl) removePlacementRule(pr *v1alpha1.PlacementRule) error {
+	ns := pr.GetNamespace()
+	name := pr.GetName()
+
+	if !slice.ContainsString(pr.Finalizers, label.PlacementRuleFinalizer, nil) {
+		return nil
+	}
+
+	tc, err := c.getTidbCluster(pr)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	groupID := pr.Status.GroupID
+	// the rules have gone with the TidbCluster, and the rules of the
+	// reserved groups have never been applied
+	if err == nil && tc.Spec.PD != nil && groupID != "" && !reservedRuleGroups[groupID] {
+		pdCli := controller.GetPDClient(c.deps.PDControl, tc)
+		if err := removeRuleGroup(pdCli, groupID); err != nil {
+			return fmt.Errorf("failed to remove rule group %s for placement rule %s/%s, error: %v", groupID, ns, name, err)
+		}
+		klog.Infof("rule group %s of placement rule %s/%s is removed", groupID, ns, name)
+	}
+
+	pr = pr.DeepCopy()
+	pr.Finalizers = slice.RemoveString(pr.Finalizers, label.PlacementRuleFinalizer, nil)
+	_, err = c.deps.Clientset.PingcapV1alpha1().PlacementRules(ns).Update(pr)
+	if err != nil {
+		return fmt.Errorf("remove placement rule %s/%s finalizer failed, err: %v", ns, name, err)
+	}
+	return nil
+}
+
+func (c *defaultPlacementRuleControl) getTidbCluster(pr *v1alpha1.PlacementRule) (*v1alpha1.TidbCluster, error) {
+	return c.deps.TiDBClusterLister.TidbClusters(pr.GetClusterNamespace()).Get(pr.Spec.Cluster.Name)
+}
+
+func (c *defaultPlacementRuleControl) updatePlacementRule(pr *v1alpha1.PlacementRule) error {
+	ns := pr.GetNamespace()
+	name := pr.GetName()
+
+	old, err := c.deps.PlacementRuleLister.PlacementRules(ns).Get(name)
+	if err == nil && apiequality.Semantic.DeepEqual(old.Status, pr.Status) {
+		return nil
+	}
+
+	status := pr.Status.DeepCopy()
+	// don't wait due to limited number of clients, but backoff after the default number of steps
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		_, updateErr := c.deps.Clientset.PingcapV1alpha1().PlacementRules(ns).Update(pr)
+		if updateErr == nil {
+			klog.V(4).Infof("PlacementRule: [%s/%s] updated successfully", ns, name)
+			return nil
+		}
+		klog.V(4).Infof("failed to update PlacementRule: [%s/%s], error: %v", ns, name, updateErr)
+
+		if updated, err := c.deps.PlacementRuleLister.PlacementRules(ns).Get(name); err == nil {
+			// make a copy so we don't mutate the shared cache
+			pr = updated.DeepCopy()
+			pr.Status = *status
+		} else {
+			utilruntime.HandleError(fmt.Errorf("error getting updated PlacementRule %s/%s from lister: %v", ns, name, err))
+		}
+
+		return updateErr
+	})
+	if err != nil {
+		klog.Errorf("failed to update PlacementRule: [%s/%s], error: %v", ns, name, err)
+	}
+	return err
+}
+
+var _ ControlInterface = &defaultPlacementRuleControl{}
+
+// FakePlacementRuleControl is a fake PlacementRule ControlInterface
+type FakePlacementRuleControl struct {
+	err error
+}
+
+// NewFakePlacementRuleControl returns a FakePlacementRuleControl
+func NewFakePlacementRuleControl() *FakePlacementRuleControl {
+	return &FakePlacementRuleControl{}
+}
+
+// SetReconcilePlacementRuleError sets error for PlacementRuleControl
+func (c *FakePlacementRuleControl) SetReconcilePlacementRuleError(err error) {
+	c.err = err
+}
+
+// ReconcilePlacementRule fake ReconcilePlacementRule
+func (c *FakePlacementRuleControl) ReconcilePlacementRule(_ *v1alpha1.PlacementRule) error {
+	return c.err
+}
+
+var _ ControlInterface = &FakePlacementRuleControl{}
@@ -0,0 +1,299 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package placementrule
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeRuleStore keeps the placement rules of the fake PD client
+type fakeRuleStore struct {
+	groups map[string]*pdapi.PlacementRuleGroup
+	rules  map[string]map[string]*pdapi.PlacementRule
+	err    error
+}
+
+func newFakeRuleStore(pdClient *pdapi.FakePDClient) *fakeRuleStore {
+	s := &fakeRuleStore{
+		groups: map[string]*pdapi.PlacementRuleGroup{},
+		rules:  map[string]map[string]*pdapi.PlacementRule{},
+	}
+	pdClient.AddReaction(pdapi.GetConfigActionType, func(action *pdapi.Action) (interface{}, error) {
+		return &pdapi.PDConfigFromAPI{Replication: &pdapi.PDReplicationConfig{}}, nil
+	})
+	pdClient.AddReaction(pdapi.GetPlacementRuleGroupsActionType, func(action *pdapi.Action) (interface{}, error) {
+		var groups []*pdapi.PlacementRuleGroup
+		for _, g := range s.groups {
+			groups = append(groups, g)
+		}
+		return groups, s.err
+	})
+	pdClient.AddReaction(pdapi.SetPlacementRuleGroupActionType, func(action *pdapi.Action) (interface{}, error) {
+		s.groups[action.RuleGroup.ID] = action.RuleGroup
+		return nil, s.err
+	})
+	pdClient.AddReaction(pdapi.DeletePlacementRuleGroupActionType, func(action *pdapi.Action) (interface{}, error) {
+		delete(s.groups, action.RuleGroup.ID)
+		return nil, s.err
+	})
+	pdClient.AddReaction(pdapi.GetPlacementRulesActionType, func(action *pdapi.Action) (interface{}, error) {
+		var rules []*pdapi.PlacementRule
+		for _, r := range s.rules[action.Name] {
+			rules = append(rules, r)
+		}
+		return rules, s.err
+	})
+	pdClient.AddReaction(pdapi.SetPlacementRuleActionType, func(action *pdapi.Action) (interface{}, error) {
+		s.setRule(action.Rule)
+		return nil, s.err
+	})
+	pdClient.AddReaction(pdapi.DeletePlacementRuleActionType, func(action *pdapi.Action) (interface{}, error) {
+		delete(s.rules[action.Rule.GroupID], action.Rule.ID)
+		return nil, s.err
+	})
+	return s
+}
+
+func (s *fakeRuleStore) setRule(rule *pdapi.PlacementRule) {
+	if s.rules[rule.GroupID] == nil {
+		s.rules[rule.GroupID] = map[string]*pdapi.PlacementRule{}
+	}
+	s.rules[rule.GroupID][rule.ID] = rule
+}
+
+func TestPlacementRuleControlReconcile(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name        string
+		update      func(pr *v1alpha1.PlacementRule)
+		prepare     func(s *fakeRuleStore)
+		errExpectFn func(*GomegaWithT, error)
+		expectFn    func(*GomegaWithT, *v1alpha1.PlacementRule, *fakeRuleStore)
+	}
+
+	testFn := func(test *testcase) {
+		t.Log(test.name)
+
+		pr := newPlacementRule()
+		if test.update != nil {
+			test.update(pr)
+		}
+		control, deps, store := newFakePlacementRuleControl()
+		if test.prepare != nil {
+			test.prepare(store)
+		}
+		_, err := deps.Clientset.PingcapV1alpha1().PlacementRules(pr.Namespace).Create(pr)
+		g.Expect(err).NotTo(HaveOccurred())
+		deps.InformerFactory.Pingcap().V1alpha1().PlacementRules().Informer().GetIndexer().Add(pr)
+
+		err = control.ReconcilePlacementRule(pr)
+		if test.errExpectFn != nil {
+			test.errExpectFn(g, err)
+		} else {
+			g.Expect(err).NotTo(HaveOccurred())
+		}
+
+		updated, err := deps.Clientset.PingcapV1alpha1().PlacementRules(pr.Namespace).Get(pr.Name, metav1.GetOptions{})
+		g.Expect(err).NotTo(HaveOccurred())
+		if test.expectFn != nil {
+			test.expectFn(g, updated, store)
+		}
+	}
+
+	tests := []testcase{
+		{
+			name: "create rules",
+			expectFn: func(g *GomegaWithT, pr *v1alpha1.PlacementRule, s *fakeRuleStore) {
+				g.Expect(pr.Finalizers).To(ContainElement(label.PlacementRuleFinalizer))
+				g.Expect(pr.Status.GroupID).To(Equal("zone"))
+				g.Expect(pr.Status.ObservedGeneration).To(Equal(int64(1)))
+				g.Expect(pr.Status.Synced).To(BeTrue())
+				g.Expect(pr.Status.Drifts).To(BeEmpty())
+				g.Expect(s.groups).To(HaveKey("zone"))
+				g.Expect(s.rules["zone"]).To(HaveLen(1))
+				rule := s.rules["zone"]["leader"]
+				g.Expect(rule.Role).To(Equal("leader"))
+				g.Expect(rule.LabelConstraints).To(HaveLen(1))
+			},
+		},
+		{
+			name: "reserved rule group",
+			update: func(pr *v1alpha1.PlacementRule) {
+				pr.Spec.GroupID = "pd"
+			},
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring("is reserved"))
+			},
+			expectFn: func(g *GomegaWithT, pr *v1alpha1.PlacementRule, s *fakeRuleStore) {
+				g.Expect(pr.Status.Synced).To(BeFalse())
+				g.Expect(pr.Status.Message).To(ContainSubstring("is reserved"))
+				g.Expect(s.groups).To(BeEmpty())
+			},
+		},
+		{
+			name: "pd is unavailable",
+			prepare: func(s *fakeRuleStore) {
+				s.err = fmt.Errorf("pd is unavailable")
+			},
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring("pd is unavailable"))
+			},
+			expectFn: func(g *GomegaWithT, pr *v1alpha1.PlacementRule, s *fakeRuleStore) {
+				g.Expect(pr.Status.Synced).To(BeFalse())
+				g.Expect(pr.Status.Message).To(ContainSubstring("pd is unavailable"))
+			},
+		},
+		{
+			name: "revert drift of applied rules",
+			update: func(pr *v1alpha1.PlacementRule) {
+				pr.Finalizers = []string{label.PlacementRuleFinalizer}
+				pr.Status.GroupID = "zone"
+				pr.Status.ObservedGeneration = 1
+			},
+			prepare: func(s *fakeRuleStore) {
+				s.groups["zone"] = &pdapi.PlacementRuleGroup{ID: "zone", Index: 1}
+				s.setRule(&pdapi.PlacementRule{GroupID: "zone", ID: "leader", Role: "voter", Count: 1})
+				s.setRule(&pdapi.PlacementRule{GroupID: "zone", ID: "manual", Role: "learner", Count: 1})
+			},
+			expectFn: func(g *GomegaWithT, pr *v1alpha1.PlacementRule, s *fakeRuleStore) {
+				g.Expect(pr.Status.Synced).To(BeTrue())
+				g.Expect(pr.Status.Drifts).To(ConsistOf(
+					v1alpha1.PlacementRuleDrift{RuleID: "leader", Type: v1alpha1.PlacementRuleDriftModified},
+					v1alpha1.PlacementRuleDrift{RuleID: "manual", Type: v1alpha1.PlacementRuleDriftUnexpected},
+				))
+				g.Expect(pr.Status.LastDriftTime).NotTo(BeNil())
+				g.Expect(s.rules["zone"]).To(HaveLen(1))
+				g.Expect(s.rules["zone"]["leader"].Role).To(Equal("leader"))
+			},
+		},
+		{
+			name: "change group ID",
+			update: func(pr *v1alpha1.PlacementRule) {
+				pr.Finalizers = []string{label.PlacementRuleFinalizer}
+				pr.Status.GroupID = "old"
+				pr.Status.ObservedGeneration = 1
+			},
+			prepare: func(s *fakeRuleStore) {
+				s.groups["old"] = &pdapi.PlacementRuleGroup{ID: "old", Index: 1}
+				s.setRule(&pdapi.PlacementRule{GroupID: "old", ID: "leader", Role: "leader", Count: 1})
+			},
+			expectFn: func(g *GomegaWithT, pr *v1alpha1.PlacementRule, s *fakeRuleStore) {
+				g.Expect(pr.Status.GroupID).To(Equal("zone"))
+				g.Expect(pr.Status.Drifts).To(BeEmpty())
+				g.Expect(s.groups).NotTo(HaveKey("old"))
+				g.Expect(s.rules["old"]).To(BeEmpty())
+				g.Expect(s.rules["zone"]).To(HaveKey("leader"))
+			},
+		},
+		{
+			name: "remove rules on deletion",
+			update: func(pr *v1alpha1.PlacementRule) {
+				now := metav1.Now()
+				pr.DeletionTimestamp = &now
+				pr.Finalizers = []string{label.PlacementRuleFinalizer}
+				pr.Status.GroupID = "zone"
+			},
+			prepare: func(s *fakeRuleStore) {
+				s.groups["zone"] = &pdapi.PlacementRuleGroup{ID: "zone", Index: 1}
+				s.setRule(&pdapi.PlacementRule{GroupID: "zone", ID: "leader", Role: "leader", Count: 1})
+			},
+			expectFn: func(g *GomegaWithT, pr *v1alpha1.PlacementRule, s *fakeRuleStore) {
+				g.Expect(pr.Finalizers).NotTo(ContainElement(label.PlacementRuleFinalizer))
+				g.Expect(s.groups).NotTo(HaveKey("zone"))
+				g.Expect(s.rules["zone"]).To(BeEmpty())
+			},
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i])
+	}
+}
+
+func TestPlacementRuleControlSyncUnchanged(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	pr := newPlacementRule()
+	pr.Finalizers = []string{label.PlacementRuleFinalizer}
+	control, deps, _ := newFakePlacementRuleControl()
+	_, err := deps.Clientset.PingcapV1alpha1().PlacementRules(pr.Namespace).Create(pr)
+	g.Expect(err).NotTo(HaveOccurred())
+	indexer := deps.InformerFactory.Pingcap().V1alpha1().PlacementRules().Informer().GetIndexer()
+	indexer.Add(pr)
+
+	g.Expect(control.ReconcilePlacementRule(pr)).To(Succeed())
+	updated, err := deps.Clientset.PingcapV1alpha1().PlacementRules(pr.Namespace).Get(pr.Name, metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(updated.Status.Synced).To(BeTrue())
+	g.Expect(updated.Status.LastSyncTime).NotTo(BeNil())
+
+	// the second sync of the applied rules does not write
+	indexer.Update(updated)
+	g.Expect(control.ReconcilePlacementRule(updated)).To(Succeed())
+	again, err := deps.Clientset.PingcapV1alpha1().PlacementRules(pr.Namespace).Get(pr.Name, metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(again.Status.LastSyncTime).To(Equal(updated.Status.LastSyncTime))
+}
+
+func newFakePlacementRuleControl() (ControlInterface, *controller.Dependencies, *fakeRuleStore) {
+	deps := controller.NewFakeDependencies()
+	tc := &v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "demo",
+			Namespace: corev1.NamespaceDefault,
+		},
+		Spec: v1alpha1.TidbClusterSpec{
+			PD: &v1alpha1.PDSpec{Replicas: 3},
+		},
+	}
+	deps.InformerFactory.Pingcap().V1alpha1().TidbClusters().Informer().GetIndexer().Add(tc)
+	pdClient := controller.NewFakePDClient(deps.PDControl.(*pdapi.FakePDControl), tc)
+	return NewDefaultPlacementRuleControl(deps), deps, newFakeRuleStore(pdClient)
+}
+
+func newPlacementRule() *v1alpha1.PlacementRule {
+	return &v1alpha1.PlacementRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "zone",
+			Namespace:  corev1.NamespaceDefault,
+			Generation: 1,
+		},
+		Spec: v1alpha1.PlacementRuleSpec{
+			Cluster:    v1alpha1.TidbClusterRef{Name: "demo"},
+			GroupIndex: 1,
+			Rules: []v1alpha1.PlacementRuleItem{
+				{
+					ID:    "leader",
+					Role:  v1alpha1.PlacementRuleRoleLeader,
+					Count: 1,
+					LabelConstraints: []v1alpha1.PlacementLabelConstraint{
+						{Key: "zone", Op: "in", Values: []string{"z1"}},
+					},
+				},
+			},
+		},
+	}
+}
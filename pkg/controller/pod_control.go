@@ -39,6 +39,9 @@ type PodControlInterface interface {
 	// TODO change this to UpdatePod
 	UpdateMetaInfo(*v1alpha1.TidbCluster, *corev1.Pod) (*corev1.Pod, error)
 	DeletePod(runtime.Object, *corev1.Pod) error
+	// ForceDeletePod deletes the pod immediately without waiting for the kubelet to confirm
+	// the termination, it is only used for the pods on the unavailable nodes
+	ForceDeletePod(runtime.Object, *corev1.Pod) error
 	UpdatePod(runtime.Object, *corev1.Pod) (*corev1.Pod, error)
 }
 
@@ -223,6 +226,29 @@ func (c *realPodControl) DeletePod(controller runtime.Object, pod *corev1.Pod) e
 	return err
 }
 
+func (c *realPodControl) ForceDeletePod(controller runtime.Object, pod *corev1.Pod) error {
+	controllerMo, ok := controller.(metav1.Object)
+	if !ok {
+		return fmt.Errorf("%T is not a metav1.Object, cannot call setControllerReference", controller)
+	}
+	kind := controller.GetObjectKind().GroupVersionKind().Kind
+	name := controllerMo.GetName()
+	namespace := controllerMo.GetNamespace()
+
+	podName := pod.GetName()
+	var gracePeriod int64
+	preconditions := metav1.Preconditions{UID: &pod.UID}
+	deleteOptions := metav1.DeleteOptions{Preconditions: &preconditions, GracePeriodSeconds: &gracePeriod}
+	err := c.kubeCli.CoreV1().Pods(namespace).Delete(podName, &deleteOptions)
+	if err != nil {
+		klog.Errorf("failed to force delete Pod: [%s/%s], %s: %s, %v", namespace, podName, kind, namespace, err)
+	} else {
+		klog.Infof("force delete Pod: [%s/%s] successfully, %s: %s", namespace, podName, kind, namespace)
+	}
+	c.recordPodEvent("delete", kind, name, controller, podName, err)
+	return err
+}
+
 func (c *realPodControl) recordPodEvent(verb, kind, name string, object runtime.Object, podName string, err error) {
 	if err == nil {
 		reason := fmt.Sprintf("Successful%s", strings.Title(verb))
@@ -342,6 +368,10 @@ func (c *FakePodControl) DeletePod(_ runtime.Object, pod *corev1.Pod) error {
 	return c.PodIndexer.Delete(pod)
 }
 
+func (c *FakePodControl) ForceDeletePod(controller runtime.Object, pod *corev1.Pod) error {
+	return c.DeletePod(controller, pod)
+}
+
 func (c *FakePodControl) UpdatePod(_ runtime.Object, pod *corev1.Pod) (*corev1.Pod, error) {
 	defer c.updatePodTracker.Inc()
 	if c.updatePodTracker.ErrorReady() {
@@ -0,0 +1,255 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	httputil "github.com/pingcap/tidb-operator/pkg/util/http"
+	"k8s.io/client-go/kubernetes"
+)
+
+// BinlogNode is the status of a pump or drainer registered in PD
+type BinlogNode struct {
+	NodeID string `json:"nodeId"`
+	Host   string `json:"host"`
+	State  string `json:"state"`
+	// MaxCommitTS is the checkpoint for drainers
+	MaxCommitTS int64 `json:"maxCommitTS"`
+	UpdateTS    int64 `json:"updateTS"`
+}
+
+// PodName returns the name of the pod the node runs in, the host of a node
+// is its advertise address in the form of ${HOSTNAME}.${HEADLESS_SERVICE_NAME}:${PORT}
+func (n *BinlogNode) PodName() string {
+	return strings.SplitN(strings.SplitN(n.Host, ":", 2)[0], ".", 2)[0]
+}
+
+// binlogStatus is the response of the status APIs of pump
+type binlogStatus struct {
+	StatusMap map[string]*BinlogNode `json:"status"`
+	ErrMsg    string                 `json:"ErrMsg"`
+}
+
+// PumpControlInterface is the interface that knows how to manage pumps
+type PumpControlInterface interface {
+	// GetPumps returns all the pumps registered in PD
+	GetPumps(tc *v1alpha1.TidbCluster) ([]*BinlogNode, error)
+	// GetDrainers returns all the drainers registered in PD, the status
+	// of drainers is served by pumps too
+	GetDrainers(tc *v1alpha1.TidbCluster) ([]*BinlogNode, error)
+	// OfflinePump makes the pump in the given ordinal go offline
+	OfflinePump(tc *v1alpha1.TidbCluster, ordinal int32, nodeID string) error
+	// OfflineDrainer makes the drainer in the given ordinal go offline
+	OfflineDrainer(tc *v1alpha1.TidbCluster, ordinal int32, nodeID string) error
+}
+
+// defaultPumpControl is default implementation of PumpControlInterface.
+type defaultPumpControl struct {
+	httpClient
+	// for unit test only
+	testURL string
+}
+
+// NewDefaultPumpControl returns a defaultPumpControl instance
+func NewDefaultPumpControl(kubeCli kubernetes.Interface) *defaultPumpControl {
+	return &defaultPumpControl{httpClient: httpClient{kubeCli: kubeCli}}
+}
+
+func (c *defaultPumpControl) GetPumps(tc *v1alpha1.TidbCluster) ([]*BinlogNode, error) {
+	body, url, err := c.get(tc, "status")
+	if err != nil {
+		return nil, err
+	}
+
+	status := binlogStatus{}
+	if err := json.Unmarshal(body, &status); err != nil {
+		return nil, err
+	}
+	if status.ErrMsg != "" {
+		return nil, fmt.Errorf("failed to get binlog nodes, URL %s: %s", url, status.ErrMsg)
+	}
+	nodes := make([]*BinlogNode, 0, len(status.StatusMap))
+	for _, node := range status.StatusMap {
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+func (c *defaultPumpControl) GetDrainers(tc *v1alpha1.TidbCluster) ([]*BinlogNode, error) {
+	body, url, err := c.get(tc, "drainers")
+	if err != nil {
+		return nil, err
+	}
+
+	// unlike the status of pumps, the drainers are responded as a list
+	var nodes []*BinlogNode
+	if err := json.Unmarshal(body, &nodes); err != nil {
+		return nil, fmt.Errorf("failed to decode drainers, URL %s: %v", url, err)
+	}
+	return nodes, nil
+}
+
+// get requests the API of the pump service and returns the body and the URL
+func (c *defaultPumpControl) get(tc *v1alpha1.TidbCluster, api string) ([]byte, string, error) {
+	httpClient, err := c.getHTTPClient(tc)
+	if err != nil {
+		return nil, "", err
+	}
+
+	url := fmt.Sprintf("%s/%s", c.getServiceURL(tc), api)
+	body, err := getBodyOK(httpClient, url)
+	return body, url, err
+}
+
+func (c *defaultPumpControl) OfflinePump(tc *v1alpha1.TidbCluster, ordinal int32, nodeID string) error {
+	httpClient, err := c.getHTTPClient(tc)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/state/%s/close", c.getBaseURL(tc, ordinal), nodeID)
+	_, err = httputil.PutBodyOK(httpClient, url)
+	return err
+}
+
+func (c *defaultPumpControl) OfflineDrainer(tc *v1alpha1.TidbCluster, ordinal int32, nodeID string) error {
+	httpClient, err := c.getHTTPClient(tc)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/state/%s/close", c.getDrainerBaseURL(tc, ordinal), nodeID)
+	_, err = httputil.PutBodyOK(httpClient, url)
+	return err
+}
+
+func (c *defaultPumpControl) getBaseURL(tc *v1alpha1.TidbCluster, ordinal int32) string {
+	if c.testURL != "" {
+		return c.testURL
+	}
+
+	tcName := tc.GetName()
+	ns := tc.GetNamespace()
+	scheme := tc.Scheme()
+	hostName := fmt.Sprintf("%s-%d", PumpMemberName(tcName), ordinal)
+
+	return fmt.Sprintf("%s://%s.%s.%s:8250", scheme, hostName, PumpPeerMemberName(tcName), ns)
+}
+
+func (c *defaultPumpControl) getDrainerBaseURL(tc *v1alpha1.TidbCluster, ordinal int32) string {
+	if c.testURL != "" {
+		return c.testURL
+	}
+
+	tcName := tc.GetName()
+	ns := tc.GetNamespace()
+	scheme := tc.Scheme()
+	hostName := fmt.Sprintf("%s-%d", DrainerMemberName(tcName), ordinal)
+
+	return fmt.Sprintf("%s://%s.%s.%s:8249", scheme, hostName, DrainerPeerMemberName(tcName), ns)
+}
+
+// getServiceURL returns the URL of the pump headless service, any pump
+// reports the status of all the pumps registered in PD.
+func (c *defaultPumpControl) getServiceURL(tc *v1alpha1.TidbCluster) string {
+	if c.testURL != "" {
+		return c.testURL
+	}
+
+	tcName := tc.GetName()
+	ns := tc.GetNamespace()
+	scheme := tc.Scheme()
+
+	return fmt.Sprintf("%s://%s.%s:8250", scheme, PumpPeerMemberName(tcName), ns)
+}
+
+// FakePumpControl is a fake implementation of PumpControlInterface.
+type FakePumpControl struct {
+	pumps    map[string]*BinlogNode
+	drainers map[string]*BinlogNode
+	err      error
+}
+
+// NewFakePumpControl returns a FakePumpControl instance
+func NewFakePumpControl() *FakePumpControl {
+	return &FakePumpControl{pumps: map[string]*BinlogNode{}, drainers: map[string]*BinlogNode{}}
+}
+
+// SetPump sets a pump for FakePumpControl
+func (c *FakePumpControl) SetPump(pump *BinlogNode) {
+	c.pumps[pump.NodeID] = pump
+}
+
+// SetDrainer sets a drainer for FakePumpControl
+func (c *FakePumpControl) SetDrainer(drainer *BinlogNode) {
+	c.drainers[drainer.NodeID] = drainer
+}
+
+// SetError sets the error returned by FakePumpControl
+func (c *FakePumpControl) SetError(err error) {
+	c.err = err
+}
+
+func (c *FakePumpControl) GetPumps(tc *v1alpha1.TidbCluster) ([]*BinlogNode, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	pumps := make([]*BinlogNode, 0, len(c.pumps))
+	for _, pump := range c.pumps {
+		pumps = append(pumps, pump)
+	}
+	return pumps, nil
+}
+
+func (c *FakePumpControl) GetDrainers(tc *v1alpha1.TidbCluster) ([]*BinlogNode, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	drainers := make([]*BinlogNode, 0, len(c.drainers))
+	for _, drainer := range c.drainers {
+		drainers = append(drainers, drainer)
+	}
+	return drainers, nil
+}
+
+func (c *FakePumpControl) OfflinePump(tc *v1alpha1.TidbCluster, ordinal int32, nodeID string) error {
+	if c.err != nil {
+		return c.err
+	}
+	pump, ok := c.pumps[nodeID]
+	if !ok {
+		return fmt.Errorf("pump %s does not exist", nodeID)
+	}
+	pump.State = v1alpha1.PumpStateClosing
+	return nil
+}
+
+func (c *FakePumpControl) OfflineDrainer(tc *v1alpha1.TidbCluster, ordinal int32, nodeID string) error {
+	if c.err != nil {
+		return c.err
+	}
+	drainer, ok := c.drainers[nodeID]
+	if !ok {
+		return fmt.Errorf("drainer %s does not exist", nodeID)
+	}
+	drainer.State = v1alpha1.DrainerStateClosing
+	return nil
+}
+
+var _ PumpControlInterface = &defaultPumpControl{}
+var _ PumpControlInterface = &FakePumpControl{}
@@ -0,0 +1,145 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPumpControlGetPumps(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		caseName string
+		resp     binlogStatus
+		failed   bool
+	}{
+		{
+			caseName: "GetPumps",
+			resp: binlogStatus{StatusMap: map[string]*BinlogNode{
+				"pump-0": {NodeID: "pump-0", Host: "demo-pump-0.demo-pump.default:8250", State: "online", MaxCommitTS: 42},
+			}},
+		},
+		{
+			caseName: "GetPumps with error message",
+			resp:     binlogStatus{ErrMsg: "failed to get pumps from pd"},
+			failed:   true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Log(c.caseName)
+		svc := getClientServer(func(w http.ResponseWriter, request *http.Request) {
+			g.Expect(request.Method).To(Equal("GET"))
+			g.Expect(request.URL.Path).To(Equal("/status"))
+			data, err := json.Marshal(c.resp)
+			g.Expect(err).NotTo(HaveOccurred())
+			w.Header().Set("Content-Type", ContentTypeJSON)
+			w.Write(data)
+		})
+		defer svc.Close()
+
+		control := NewDefaultPumpControl(fake.NewSimpleClientset())
+		control.testURL = svc.URL
+		pumps, err := control.GetPumps(getTidbCluster())
+		if c.failed {
+			g.Expect(err).To(HaveOccurred())
+			continue
+		}
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(pumps).To(HaveLen(1))
+		g.Expect(pumps[0].PodName()).To(Equal("demo-pump-0"))
+		g.Expect(pumps[0].MaxCommitTS).To(Equal(int64(42)))
+	}
+}
+
+func TestPumpControlGetDrainers(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		caseName string
+		resp     string
+		failed   bool
+	}{
+		{
+			caseName: "GetDrainers",
+			resp:     `[{"nodeId":"drainer-0","host":"demo-drainer-0.demo-drainer.default:8249","state":"online","maxCommitTS":42,"updateTS":43}]`,
+		},
+		{
+			caseName: "GetDrainers with unexpected response",
+			resp:     `{"status":{}}`,
+			failed:   true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Log(c.caseName)
+		svc := getClientServer(func(w http.ResponseWriter, request *http.Request) {
+			g.Expect(request.Method).To(Equal("GET"))
+			g.Expect(request.URL.Path).To(Equal("/drainers"))
+			w.Header().Set("Content-Type", ContentTypeJSON)
+			w.Write([]byte(c.resp))
+		})
+		defer svc.Close()
+
+		control := NewDefaultPumpControl(fake.NewSimpleClientset())
+		control.testURL = svc.URL
+		drainers, err := control.GetDrainers(getTidbCluster())
+		if c.failed {
+			g.Expect(err).To(HaveOccurred())
+			continue
+		}
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(drainers).To(HaveLen(1))
+		g.Expect(drainers[0].NodeID).To(Equal("drainer-0"))
+		g.Expect(drainers[0].PodName()).To(Equal("demo-drainer-0"))
+		g.Expect(drainers[0].State).To(Equal("online"))
+		g.Expect(drainers[0].MaxCommitTS).To(Equal(int64(42)))
+	}
+}
+
+func TestPumpControlOfflinePump(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	svc := getClientServer(func(w http.ResponseWriter, request *http.Request) {
+		g.Expect(request.Method).To(Equal("PUT"))
+		g.Expect(request.URL.Path).To(Equal("/state/pump-0/close"))
+		w.WriteHeader(http.StatusOK)
+	})
+	defer svc.Close()
+
+	control := NewDefaultPumpControl(fake.NewSimpleClientset())
+	control.testURL = svc.URL
+	g.Expect(control.OfflinePump(getTidbCluster(), 0, "pump-0")).To(Succeed())
+}
+
+func TestPumpControlOfflineDrainer(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	svc := getClientServer(func(w http.ResponseWriter, request *http.Request) {
+		g.Expect(request.Method).To(Equal("PUT"))
+		g.Expect(request.URL.Path).To(Equal("/state/drainer-0/close"))
+		w.WriteHeader(http.StatusOK)
+	})
+	defer svc.Close()
+
+	control := NewDefaultPumpControl(fake.NewSimpleClientset())
+	control.testURL = svc.URL
+	g.Expect(control.OfflineDrainer(getTidbCluster(), 0, "drainer-0")).To(Succeed())
+}
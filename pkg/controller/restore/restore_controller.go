@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/backup/restore"
 	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/metrics"
 	"k8s.io/apimachinery/pkg/api/errors"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -54,6 +55,7 @@ func NewController(deps *controller.Dependencies) *Controller {
 	restoreInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: c.updateRestore,
 		UpdateFunc: func(old, cur interface{}) {
+			observeRestoreOutcome(old.(*v1alpha1.Restore), cur.(*v1alpha1.Restore))
 			c.updateRestore(cur)
 		},
 		DeleteFunc: c.enqueueRestore,
@@ -90,7 +92,10 @@ func (c *Controller) processNextWorkItem() bool {
 		return false
 	}
 	defer c.queue.Done(key)
-	if err := c.sync(key.(string)); err != nil {
+	startTime := time.Now()
+	err := c.sync(key.(string))
+	controller.ObserveReconcile("restore", key.(string), startTime, err)
+	if err != nil {
 		if perrors.Find(err, controller.IsRequeueError) != nil {
 			klog.Infof("Restore: %v, still need sync: %v, requeuing", key.(string), err)
 			c.queue.AddRateLimited(key)
@@ -120,6 +125,7 @@ func (c *Controller) sync(key string) error {
 	restore, err := c.deps.RestoreLister.Restores(ns).Get(name)
 	if errors.IsNotFound(err) {
 		klog.Infof("Restore has been deleted %v", key)
+		controller.ForgetReconcile("restore", key)
 		return nil
 	}
 	if err != nil {
@@ -133,6 +139,18 @@ func (c *Controller) syncRestore(tc *v1alpha1.Restore) error {
 	return c.control.UpdateRestore(tc)
 }
 
+// observeRestoreOutcome counts the restore when it turns complete or failed
+func observeRestoreOutcome(old, cur *v1alpha1.Restore) {
+	if v1alpha1.IsRestoreComplete(old) || v1alpha1.IsRestoreFailed(old) {
+		return
+	}
+	if v1alpha1.IsRestoreComplete(cur) {
+		metrics.BackupOutcomeTotal.WithLabelValues("restore", cur.GetNamespace(), metrics.OutcomeComplete).Inc()
+	} else if v1alpha1.IsRestoreFailed(cur) {
+		metrics.BackupOutcomeTotal.WithLabelValues("restore", cur.GetNamespace(), metrics.OutcomeFailed).Inc()
+	}
+}
+
 func (c *Controller) updateRestore(cur interface{}) {
 	newRestore := cur.(*v1alpha1.Restore)
 	ns := newRestore.GetNamespace()
@@ -0,0 +1,132 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned"
+	informers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions/pingcap/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog"
+)
+
+// RestoreControlInterface manages Restores used to bootstrap TidbClusters
+type RestoreControlInterface interface {
+	CreateRestore(tc *v1alpha1.TidbCluster, restore *v1alpha1.Restore) (*v1alpha1.Restore, error)
+	DeleteRestore(tc *v1alpha1.TidbCluster, restore *v1alpha1.Restore) error
+}
+
+type realRestoreControl struct {
+	cli      versioned.Interface
+	recorder record.EventRecorder
+}
+
+// NewRealRestoreControl creates a new RestoreControlInterface
+func NewRealRestoreControl(
+	cli versioned.Interface,
+	recorder record.EventRecorder,
+) RestoreControlInterface {
+	return &realRestoreControl{
+		cli:      cli,
+		recorder: recorder,
+	}
+}
+
+func (c *realRestoreControl) CreateRestore(tc *v1alpha1.TidbCluster, restore *v1alpha1.Restore) (*v1alpha1.Restore, error) {
+	ns := restore.GetNamespace()
+	restoreName := restore.GetName()
+
+	created, err := c.cli.PingcapV1alpha1().Restores(ns).Create(restore)
+	if err != nil {
+		klog.Errorf("failed to create Restore: [%s/%s] for tidbcluster/%s, err: %v", ns, restoreName, tc.GetName(), err)
+	} else {
+		klog.V(4).Infof("create Restore: [%s/%s] for tidbcluster/%s successfully", ns, restoreName, tc.GetName())
+	}
+	c.recordRestoreEvent("create", tc, restore, err)
+	return created, err
+}
+
+func (c *realRestoreControl) DeleteRestore(tc *v1alpha1.TidbCluster, restore *v1alpha1.Restore) error {
+	ns := restore.GetNamespace()
+	restoreName := restore.GetName()
+
+	err := c.cli.PingcapV1alpha1().Restores(ns).Delete(restoreName, nil)
+	if err != nil {
+		klog.Errorf("failed to delete Restore: [%s/%s] for tidbcluster/%s, err: %v", ns, restoreName, tc.GetName(), err)
+	} else {
+		klog.V(4).Infof("delete Restore: [%s/%s] for tidbcluster/%s successfully", ns, restoreName, tc.GetName())
+	}
+	c.recordRestoreEvent("delete", tc, restore, err)
+	return err
+}
+
+func (c *realRestoreControl) recordRestoreEvent(verb string, tc *v1alpha1.TidbCluster, restore *v1alpha1.Restore, err error) {
+	restoreName := restore.GetName()
+	ns := restore.GetNamespace()
+
+	if err == nil {
+		reason := fmt.Sprintf("Successful%s", strings.Title(verb))
+		msg := fmt.Sprintf("%s Restore %s/%s for tidbcluster/%s successful",
+			strings.ToLower(verb), ns, restoreName, tc.GetName())
+		c.recorder.Event(tc, corev1.EventTypeNormal, reason, msg)
+	} else {
+		reason := fmt.Sprintf("Failed%s", strings.Title(verb))
+		msg := fmt.Sprintf("%s Restore %s/%s for tidbcluster/%s failed error: %s",
+			strings.ToLower(verb), ns, restoreName, tc.GetName(), err)
+		c.recorder.Event(tc, corev1.EventTypeWarning, reason, msg)
+	}
+}
+
+var _ RestoreControlInterface = &realRestoreControl{}
+
+// FakeRestoreControl is a fake RestoreControlInterface
+type FakeRestoreControl struct {
+	restoreIndexer       cache.Indexer
+	createRestoreTracker RequestTracker
+}
+
+// NewFakeRestoreControl returns a FakeRestoreControl
+func NewFakeRestoreControl(restoreInformer informers.RestoreInformer) *FakeRestoreControl {
+	return &FakeRestoreControl{
+		restoreIndexer: restoreInformer.Informer().GetIndexer(),
+	}
+}
+
+// SetCreateRestoreError sets the error attributes of createRestoreTracker
+func (c *FakeRestoreControl) SetCreateRestoreError(err error, after int) {
+	c.createRestoreTracker.SetError(err).SetAfter(after)
+}
+
+// CreateRestore adds the restore to RestoreIndexer
+func (c *FakeRestoreControl) CreateRestore(_ *v1alpha1.TidbCluster, restore *v1alpha1.Restore) (*v1alpha1.Restore, error) {
+	defer c.createRestoreTracker.Inc()
+	if c.createRestoreTracker.ErrorReady() {
+		defer c.createRestoreTracker.Reset()
+		return restore, c.createRestoreTracker.GetError()
+	}
+
+	return restore, c.restoreIndexer.Add(restore)
+}
+
+// DeleteRestore deletes the restore from RestoreIndexer
+func (c *FakeRestoreControl) DeleteRestore(_ *v1alpha1.TidbCluster, restore *v1alpha1.Restore) error {
+	return c.restoreIndexer.Delete(restore)
+}
+
+var _ RestoreControlInterface = &FakeRestoreControl{}
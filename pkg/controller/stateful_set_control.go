@@ -106,7 +106,7 @@ func (c *realStatefulSetControl) UpdateStatefulSet(controller runtime.Object, se
 	return updatedSS, err
 }
 
-// DeleteStatefulSet delete a StatefulSet in a TidbCluster.
+// DeleteStatefulSet delete a StatefulSet in a TidbCluster, its pods are kept.
 func (c *realStatefulSetControl) DeleteStatefulSet(controller runtime.Object, set *apps.StatefulSet) error {
 	controllerMo, ok := controller.(metav1.Object)
 	if !ok {
@@ -116,7 +116,9 @@ func (c *realStatefulSetControl) DeleteStatefulSet(controller runtime.Object, se
 	name := controllerMo.GetName()
 	namespace := controllerMo.GetNamespace()
 
-	err := c.kubeCli.AppsV1().StatefulSets(namespace).Delete(set.Name, nil)
+	// the pods are orphaned and adopted by the StatefulSet recreated with the same selector
+	orphan := metav1.DeletePropagationOrphan
+	err := c.kubeCli.AppsV1().StatefulSets(namespace).Delete(set.Name, &metav1.DeleteOptions{PropagationPolicy: &orphan})
 	c.recordStatefulSetEvent("delete", kind, name, controller, set, err)
 	return err
 }
@@ -224,8 +226,13 @@ func (c *FakeStatefulSetControl) UpdateStatefulSet(_ runtime.Object, set *apps.S
 }
 
 // DeleteStatefulSet deletes the statefulset of SetIndexer
-func (c *FakeStatefulSetControl) DeleteStatefulSet(_ runtime.Object, _ *apps.StatefulSet) error {
-	return nil
+func (c *FakeStatefulSetControl) DeleteStatefulSet(_ runtime.Object, set *apps.StatefulSet) error {
+	defer c.deleteStatefulSetTracker.Inc()
+	if c.deleteStatefulSetTracker.ErrorReady() {
+		defer c.deleteStatefulSetTracker.Reset()
+		return c.deleteStatefulSetTracker.GetError()
+	}
+	return c.SetIndexer.Delete(set)
 }
 
 var _ StatefulSetControlInterface = &FakeStatefulSetControl{}
@@ -14,21 +14,136 @@
 package controller
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	httputil "github.com/pingcap/tidb-operator/pkg/util/http"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog"
+)
+
+const (
+	// changefeedNotExistsCode is the error code returned by TiCDC when the changefeed does not exist
+	changefeedNotExistsCode = "CDC:ErrChangeFeedNotExists"
+	changefeedsPrefix       = "api/v1/changefeeds"
+	capturesPrefix          = "api/v1/captures"
+	resignOwnerPrefix       = "api/v1/owner/resign"
 )
 
 type CaptureStatus struct {
-	ID string `json:"id"`
+	ID      string `json:"id"`
+	Version string `json:"version"`
+	IsOwner bool   `json:"is_owner"`
+}
+
+// CaptureInfo is a capture returned by the TiCDC OpenAPI
+type CaptureInfo struct {
+	ID            string `json:"id"`
+	IsOwner       bool   `json:"is_owner"`
+	AdvertiseAddr string `json:"address"`
+}
+
+// PodName returns the name of the pod the capture runs in, the advertise
+// address of a capture is in the form of ${POD_NAME}.${HEADLESS_SERVICE_NAME}.${NAMESPACE}.svc:8301
+func (c *CaptureInfo) PodName() string {
+	return strings.SplitN(c.AdvertiseAddr, ".", 2)[0]
+}
+
+type drainCaptureRequest struct {
+	CaptureID string `json:"capture_id"`
+}
+
+type drainCaptureResponse struct {
+	CurrentTableCount int `json:"current_table_count"`
+}
+
+// ChangefeedConfig is the config used to create or update a changefeed
+// through the TiCDC OpenAPI
+type ChangefeedConfig struct {
+	ChangefeedID          string      `json:"changefeed_id,omitempty"`
+	StartTs               uint64      `json:"start_ts,omitempty"`
+	TargetTs              uint64      `json:"target_ts,omitempty"`
+	SinkURI               string      `json:"sink_uri,omitempty"`
+	ForceReplicate        bool        `json:"force_replicate"`
+	IgnoreIneligibleTable bool        `json:"ignore_ineligible_table"`
+	FilterRules           []string    `json:"filter_rules,omitempty"`
+	IgnoreTxnStartTs      []uint64    `json:"ignore_txn_start_ts,omitempty"`
+	SinkConfig            *SinkConfig `json:"sink_config,omitempty"`
+}
+
+// SinkConfig is the sink config of a changefeed
+type SinkConfig struct {
+	Protocol string `json:"protocol,omitempty"`
+}
+
+// ChangefeedError is the error of a changefeed reported by TiCDC
+type ChangefeedError struct {
+	Addr    string `json:"addr"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ChangefeedDetail is a changefeed returned by the TiCDC OpenAPI
+type ChangefeedDetail struct {
+	ID            string           `json:"id"`
+	SinkURI       string           `json:"sink_uri"`
+	StartTs       uint64           `json:"start_ts"`
+	TargetTs      uint64           `json:"target_ts"`
+	CheckpointTSO uint64           `json:"checkpoint_tso"`
+	State         string           `json:"state"`
+	Error         *ChangefeedError `json:"error,omitempty"`
+}
+
+// ticdcAPIError is the error body returned by the TiCDC OpenAPI
+type ticdcAPIError struct {
+	Message string `json:"error_msg"`
+	Code    string `json:"error_code"`
+}
+
+// changefeedNotFoundError is returned when the changefeed does not exist in TiCDC
+type changefeedNotFoundError struct {
+	id string
+}
+
+func (e *changefeedNotFoundError) Error() string {
+	return fmt.Sprintf("changefeed %s does not exist", e.id)
+}
+
+// IsChangefeedNotFound returns whether the error means the changefeed does not exist in TiCDC
+func IsChangefeedNotFound(err error) bool {
+	_, ok := err.(*changefeedNotFoundError)
+	return ok
 }
 
 // TiCDCControlInterface is the interface that knows how to manage ticdc captures
 type TiCDCControlInterface interface {
 	// GetStatus returns ticdc's status
 	GetStatus(tc *v1alpha1.TidbCluster, ordinal int32) (*CaptureStatus, error)
+	// GetCaptures returns all the captures of the ticdc cluster
+	GetCaptures(tc *v1alpha1.TidbCluster) ([]*CaptureInfo, error)
+	// DrainCapture moves the tables of the capture in the given ordinal to
+	// other captures, it returns the number of tables still on the capture
+	DrainCapture(tc *v1alpha1.TidbCluster, ordinal int32) (int, error)
+	// ResignOwner resigns the ownership of the capture in the given ordinal,
+	// it returns true if the capture was the owner and has resigned
+	ResignOwner(tc *v1alpha1.TidbCluster, ordinal int32) (bool, error)
+	// CreateChangefeed creates a changefeed
+	CreateChangefeed(tc *v1alpha1.TidbCluster, config *ChangefeedConfig) error
+	// GetChangefeed returns the changefeed of the given ID
+	GetChangefeed(tc *v1alpha1.TidbCluster, id string) (*ChangefeedDetail, error)
+	// UpdateChangefeed updates a paused changefeed
+	UpdateChangefeed(tc *v1alpha1.TidbCluster, id string, config *ChangefeedConfig) error
+	// PauseChangefeed pauses a changefeed
+	PauseChangefeed(tc *v1alpha1.TidbCluster, id string) error
+	// ResumeChangefeed resumes a paused changefeed
+	ResumeChangefeed(tc *v1alpha1.TidbCluster, id string) error
+	// RemoveChangefeed removes a changefeed, it returns nil if the changefeed does not exist
+	RemoveChangefeed(tc *v1alpha1.TidbCluster, id string) error
 }
 
 // defaultTiCDCControl is default implementation of TiCDCControlInterface.
@@ -61,6 +176,189 @@ func (c *defaultTiCDCControl) GetStatus(tc *v1alpha1.TidbCluster, ordinal int32)
 	return &status, err
 }
 
+func (c *defaultTiCDCControl) GetCaptures(tc *v1alpha1.TidbCluster) ([]*CaptureInfo, error) {
+	url := fmt.Sprintf("%s/%s", c.getServiceURL(tc), capturesPrefix)
+	body, err := c.doRequest(tc, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	captures := []*CaptureInfo{}
+	err = json.Unmarshal(body, &captures)
+	return captures, err
+}
+
+func (c *defaultTiCDCControl) DrainCapture(tc *v1alpha1.TidbCluster, ordinal int32) (int, error) {
+	captures, err := c.GetCaptures(tc)
+	if err != nil {
+		return 0, err
+	}
+	capture := getCaptureByOrdinal(tc, captures, ordinal)
+	if capture == nil || len(captures) <= 1 {
+		// the capture has gone or there is no other capture to take over its tables
+		return 0, nil
+	}
+	if capture.IsOwner {
+		return 0, fmt.Errorf("capture %s of %s is the owner, resign it before draining", capture.ID, capture.PodName())
+	}
+
+	url := fmt.Sprintf("%s/%s/drain", c.getServiceURL(tc), capturesPrefix)
+	body, err := c.doRequest(tc, "PUT", url, &drainCaptureRequest{CaptureID: capture.ID})
+	if err != nil {
+		if isTiCDCAPINotFound(err) {
+			klog.Warningf("ticdc of %s/%s does not support draining captures, skip draining capture %s",
+				tc.GetNamespace(), tc.GetName(), capture.PodName())
+			return 0, nil
+		}
+		return 0, err
+	}
+	resp := drainCaptureResponse{}
+	err = json.Unmarshal(body, &resp)
+	return resp.CurrentTableCount, err
+}
+
+func (c *defaultTiCDCControl) ResignOwner(tc *v1alpha1.TidbCluster, ordinal int32) (bool, error) {
+	captures, err := c.GetCaptures(tc)
+	if err != nil {
+		return false, err
+	}
+	capture := getCaptureByOrdinal(tc, captures, ordinal)
+	if capture == nil || !capture.IsOwner || len(captures) <= 1 {
+		return false, nil
+	}
+
+	url := fmt.Sprintf("%s/%s", c.getServiceURL(tc), resignOwnerPrefix)
+	if _, err := c.doRequest(tc, "POST", url, nil); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *defaultTiCDCControl) CreateChangefeed(tc *v1alpha1.TidbCluster, config *ChangefeedConfig) error {
+	url := fmt.Sprintf("%s/%s", c.getServiceURL(tc), changefeedsPrefix)
+	_, err := c.doChangefeedRequest(tc, config.ChangefeedID, "POST", url, config)
+	return err
+}
+
+func (c *defaultTiCDCControl) GetChangefeed(tc *v1alpha1.TidbCluster, id string) (*ChangefeedDetail, error) {
+	url := fmt.Sprintf("%s/%s/%s", c.getServiceURL(tc), changefeedsPrefix, id)
+	body, err := c.doChangefeedRequest(tc, id, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	detail := &ChangefeedDetail{}
+	err = json.Unmarshal(body, detail)
+	return detail, err
+}
+
+func (c *defaultTiCDCControl) UpdateChangefeed(tc *v1alpha1.TidbCluster, id string, config *ChangefeedConfig) error {
+	url := fmt.Sprintf("%s/%s/%s", c.getServiceURL(tc), changefeedsPrefix, id)
+	_, err := c.doChangefeedRequest(tc, id, "PUT", url, config)
+	return err
+}
+
+func (c *defaultTiCDCControl) PauseChangefeed(tc *v1alpha1.TidbCluster, id string) error {
+	url := fmt.Sprintf("%s/%s/%s/pause", c.getServiceURL(tc), changefeedsPrefix, id)
+	_, err := c.doChangefeedRequest(tc, id, "POST", url, nil)
+	return err
+}
+
+func (c *defaultTiCDCControl) ResumeChangefeed(tc *v1alpha1.TidbCluster, id string) error {
+	url := fmt.Sprintf("%s/%s/%s/resume", c.getServiceURL(tc), changefeedsPrefix, id)
+	_, err := c.doChangefeedRequest(tc, id, "POST", url, nil)
+	return err
+}
+
+func (c *defaultTiCDCControl) RemoveChangefeed(tc *v1alpha1.TidbCluster, id string) error {
+	url := fmt.Sprintf("%s/%s/%s", c.getServiceURL(tc), changefeedsPrefix, id)
+	_, err := c.doChangefeedRequest(tc, id, "DELETE", url, nil)
+	if IsChangefeedNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// doChangefeedRequest sends a changefeed request to the TiCDC OpenAPI, the error
+// code in the response body is parsed to tell whether the changefeed exists.
+func (c *defaultTiCDCControl) doChangefeedRequest(tc *v1alpha1.TidbCluster, id, method, url string, reqBody interface{}) ([]byte, error) {
+	body, err := c.doRequest(tc, method, url, reqBody)
+	if apiErr, ok := err.(*ticdcRequestError); ok {
+		if apiErr.Code == changefeedNotExistsCode {
+			return nil, &changefeedNotFoundError{id: id}
+		}
+		return nil, fmt.Errorf("failed to %s changefeed %s: %v", method, id, err)
+	}
+	return body, err
+}
+
+// doRequest sends a request to the TiCDC OpenAPI
+func (c *defaultTiCDCControl) doRequest(tc *v1alpha1.TidbCluster, method, url string, reqBody interface{}) ([]byte, error) {
+	httpClient, err := c.getHTTPClient(tc)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	if reqBody != nil {
+		data, err = json.Marshal(reqBody)
+		if err != nil {
+			return nil, err
+		}
+	}
+	req, err := http.NewRequest(method, url, bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer httputil.DeferClose(res.Body)
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode >= 400 {
+		reqErr := &ticdcRequestError{statusCode: res.StatusCode, method: method, url: url, body: string(body)}
+		apiErr := ticdcAPIError{}
+		if json.Unmarshal(body, &apiErr) == nil {
+			reqErr.Code = apiErr.Code
+		}
+		return nil, reqErr
+	}
+	return body, nil
+}
+
+// ticdcRequestError is returned when the TiCDC OpenAPI responds with an error status
+type ticdcRequestError struct {
+	Code       string
+	statusCode int
+	method     string
+	url        string
+	body       string
+}
+
+func (e *ticdcRequestError) Error() string {
+	return fmt.Sprintf("failed %v to %s %s: %s", e.statusCode, e.method, e.url, e.body)
+}
+
+// isTiCDCAPINotFound returns whether the API is not served by TiCDC, e.g. TiCDC is too old
+func isTiCDCAPINotFound(err error) bool {
+	reqErr, ok := err.(*ticdcRequestError)
+	return ok && reqErr.statusCode == http.StatusNotFound
+}
+
+// getCaptureByOrdinal returns the capture running in the pod of the given ordinal
+func getCaptureByOrdinal(tc *v1alpha1.TidbCluster, captures []*CaptureInfo, ordinal int32) *CaptureInfo {
+	podName := fmt.Sprintf("%s-%d", TiCDCMemberName(tc.GetName()), ordinal)
+	for _, capture := range captures {
+		if capture.PodName() == podName {
+			return capture
+		}
+	}
+	return nil
+}
+
 func (c *defaultTiCDCControl) getBaseURL(tc *v1alpha1.TidbCluster, ordinal int32) string {
 	if c.testURL != "" {
 		return c.testURL
@@ -74,17 +372,183 @@ func (c *defaultTiCDCControl) getBaseURL(tc *v1alpha1.TidbCluster, ordinal int32
 	return fmt.Sprintf("%s://%s.%s.%s:8301", scheme, hostName, TiCDCPeerMemberName(tcName), ns)
 }
 
+// getServiceURL returns the URL of the TiCDC peer service, any capture
+// forwards the changefeed requests to the owner.
+func (c *defaultTiCDCControl) getServiceURL(tc *v1alpha1.TidbCluster) string {
+	if c.testURL != "" {
+		return c.testURL
+	}
+
+	tcName := tc.GetName()
+	ns := tc.GetNamespace()
+	scheme := tc.Scheme()
+
+	return fmt.Sprintf("%s://%s.%s:8301", scheme, TiCDCPeerMemberName(tcName), ns)
+}
+
 // FakeTiCDCControl is a fake implementation of TiCDCControlInterface.
 type FakeTiCDCControl struct {
-	status *CaptureStatus
+	status      *CaptureStatus
+	captures    []*CaptureInfo
+	tableCounts map[string]int
+	changefeeds map[string]*ChangefeedDetail
+	err         error
 }
 
 // NewFakeTiCDCControl returns a FakeTiCDCControl instance
 func NewFakeTiCDCControl() *FakeTiCDCControl {
-	return &FakeTiCDCControl{}
+	return &FakeTiCDCControl{
+		tableCounts: map[string]int{},
+		changefeeds: map[string]*ChangefeedDetail{},
+	}
 }
 
-// SetHealth set health info for FakeTiCDCControl
+// SetStatus set status info for FakeTiCDCControl
 func (c *FakeTiCDCControl) SetStatus(status *CaptureStatus) {
 	c.status = status
 }
+
+// SetCaptures sets the captures of the ticdc cluster for FakeTiCDCControl
+func (c *FakeTiCDCControl) SetCaptures(captures []*CaptureInfo) {
+	c.captures = captures
+}
+
+// SetTableCount sets the number of tables on the capture for FakeTiCDCControl
+func (c *FakeTiCDCControl) SetTableCount(captureID string, count int) {
+	c.tableCounts[captureID] = count
+}
+
+// SetChangefeed sets a changefeed for FakeTiCDCControl
+func (c *FakeTiCDCControl) SetChangefeed(detail *ChangefeedDetail) {
+	c.changefeeds[detail.ID] = detail
+}
+
+// SetError sets the error returned by all the changefeed calls of FakeTiCDCControl
+func (c *FakeTiCDCControl) SetError(err error) {
+	c.err = err
+}
+
+func (c *FakeTiCDCControl) GetStatus(tc *v1alpha1.TidbCluster, ordinal int32) (*CaptureStatus, error) {
+	if c.status == nil {
+		return nil, fmt.Errorf("status of capture %d is not set", ordinal)
+	}
+	return c.status, nil
+}
+
+func (c *FakeTiCDCControl) GetCaptures(tc *v1alpha1.TidbCluster) ([]*CaptureInfo, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.captures, nil
+}
+
+func (c *FakeTiCDCControl) DrainCapture(tc *v1alpha1.TidbCluster, ordinal int32) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	capture := getCaptureByOrdinal(tc, c.captures, ordinal)
+	if capture == nil || len(c.captures) <= 1 {
+		return 0, nil
+	}
+	if capture.IsOwner {
+		return 0, fmt.Errorf("capture %s is the owner", capture.ID)
+	}
+	return c.tableCounts[capture.ID], nil
+}
+
+func (c *FakeTiCDCControl) ResignOwner(tc *v1alpha1.TidbCluster, ordinal int32) (bool, error) {
+	if c.err != nil {
+		return false, c.err
+	}
+	capture := getCaptureByOrdinal(tc, c.captures, ordinal)
+	if capture == nil || !capture.IsOwner || len(c.captures) <= 1 {
+		return false, nil
+	}
+	capture.IsOwner = false
+	for _, other := range c.captures {
+		if other != capture {
+			other.IsOwner = true
+			break
+		}
+	}
+	return true, nil
+}
+
+func (c *FakeTiCDCControl) CreateChangefeed(tc *v1alpha1.TidbCluster, config *ChangefeedConfig) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.changefeeds[config.ChangefeedID] = &ChangefeedDetail{
+		ID:            config.ChangefeedID,
+		SinkURI:       config.SinkURI,
+		StartTs:       config.StartTs,
+		TargetTs:      config.TargetTs,
+		CheckpointTSO: config.StartTs,
+		State:         string(v1alpha1.TiCDCChangefeedStateNormal),
+	}
+	return nil
+}
+
+// GetChangefeed returns a copy of the changefeed, so the callers see the changes
+// made after it is got only by getting it again like a real TiCDC
+func (c *FakeTiCDCControl) GetChangefeed(tc *v1alpha1.TidbCluster, id string) (*ChangefeedDetail, error) {
+	detail, err := c.getChangefeed(id)
+	if err != nil {
+		return nil, err
+	}
+	copied := *detail
+	return &copied, nil
+}
+
+func (c *FakeTiCDCControl) getChangefeed(id string) (*ChangefeedDetail, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	detail, ok := c.changefeeds[id]
+	if !ok {
+		return nil, &changefeedNotFoundError{id: id}
+	}
+	return detail, nil
+}
+
+func (c *FakeTiCDCControl) UpdateChangefeed(tc *v1alpha1.TidbCluster, id string, config *ChangefeedConfig) error {
+	detail, err := c.getChangefeed(id)
+	if err != nil {
+		return err
+	}
+	if detail.State != string(v1alpha1.TiCDCChangefeedStateStopped) {
+		return fmt.Errorf("can only update changefeed config when it is stopped")
+	}
+	detail.SinkURI = config.SinkURI
+	detail.TargetTs = config.TargetTs
+	return nil
+}
+
+func (c *FakeTiCDCControl) PauseChangefeed(tc *v1alpha1.TidbCluster, id string) error {
+	detail, err := c.getChangefeed(id)
+	if err != nil {
+		return err
+	}
+	detail.State = string(v1alpha1.TiCDCChangefeedStateStopped)
+	return nil
+}
+
+func (c *FakeTiCDCControl) ResumeChangefeed(tc *v1alpha1.TidbCluster, id string) error {
+	detail, err := c.getChangefeed(id)
+	if err != nil {
+		return err
+	}
+	detail.State = string(v1alpha1.TiCDCChangefeedStateNormal)
+	return nil
+}
+
+func (c *FakeTiCDCControl) RemoveChangefeed(tc *v1alpha1.TidbCluster, id string) error {
+	if c.err != nil {
+		return c.err
+	}
+	delete(c.changefeeds, id)
+	return nil
+}
+
+var _ TiCDCControlInterface = &defaultTiCDCControl{}
+var _ TiCDCControlInterface = &FakeTiCDCControl{}
@@ -0,0 +1,273 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestTiCDCControlChangefeed(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		caseName string
+		path     string
+		method   string
+		status   int
+		resp     interface{}
+		call     func(control *defaultTiCDCControl) error
+		errFn    func(err error)
+	}{
+		{
+			caseName: "CreateChangefeed",
+			path:     "/api/v1/changefeeds",
+			method:   "POST",
+			status:   http.StatusAccepted,
+			call: func(control *defaultTiCDCControl) error {
+				return control.CreateChangefeed(getTidbCluster(), &ChangefeedConfig{ChangefeedID: "cf", SinkURI: "blackhole://"})
+			},
+		},
+		{
+			caseName: "GetChangefeed",
+			path:     "/api/v1/changefeeds/cf",
+			method:   "GET",
+			status:   http.StatusOK,
+			resp:     ChangefeedDetail{ID: "cf", CheckpointTSO: 42, State: "normal"},
+			call: func(control *defaultTiCDCControl) error {
+				detail, err := control.GetChangefeed(getTidbCluster(), "cf")
+				if err == nil {
+					g.Expect(detail.CheckpointTSO).To(Equal(uint64(42)))
+					g.Expect(detail.State).To(Equal("normal"))
+				}
+				return err
+			},
+		},
+		{
+			caseName: "GetChangefeed not found",
+			path:     "/api/v1/changefeeds/cf",
+			method:   "GET",
+			status:   http.StatusBadRequest,
+			resp:     ticdcAPIError{Code: changefeedNotExistsCode, Message: "changefeed not exists"},
+			call: func(control *defaultTiCDCControl) error {
+				_, err := control.GetChangefeed(getTidbCluster(), "cf")
+				return err
+			},
+			errFn: func(err error) {
+				g.Expect(IsChangefeedNotFound(err)).To(BeTrue())
+			},
+		},
+		{
+			caseName: "UpdateChangefeed failed",
+			path:     "/api/v1/changefeeds/cf",
+			method:   "PUT",
+			status:   http.StatusBadRequest,
+			resp:     ticdcAPIError{Code: "CDC:ErrChangefeedUpdateRefused", Message: "can only update changefeed config when it is stopped"},
+			call: func(control *defaultTiCDCControl) error {
+				return control.UpdateChangefeed(getTidbCluster(), "cf", &ChangefeedConfig{SinkURI: "blackhole://"})
+			},
+			errFn: func(err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(IsChangefeedNotFound(err)).To(BeFalse())
+			},
+		},
+		{
+			caseName: "PauseChangefeed",
+			path:     "/api/v1/changefeeds/cf/pause",
+			method:   "POST",
+			status:   http.StatusAccepted,
+			call: func(control *defaultTiCDCControl) error {
+				return control.PauseChangefeed(getTidbCluster(), "cf")
+			},
+		},
+		{
+			caseName: "ResumeChangefeed",
+			path:     "/api/v1/changefeeds/cf/resume",
+			method:   "POST",
+			status:   http.StatusAccepted,
+			call: func(control *defaultTiCDCControl) error {
+				return control.ResumeChangefeed(getTidbCluster(), "cf")
+			},
+		},
+		{
+			caseName: "RemoveChangefeed not found",
+			path:     "/api/v1/changefeeds/cf",
+			method:   "DELETE",
+			status:   http.StatusBadRequest,
+			resp:     ticdcAPIError{Code: changefeedNotExistsCode, Message: "changefeed not exists"},
+			call: func(control *defaultTiCDCControl) error {
+				return control.RemoveChangefeed(getTidbCluster(), "cf")
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Log(c.caseName)
+		svc := getClientServer(func(w http.ResponseWriter, request *http.Request) {
+			g.Expect(request.Method).To(Equal(c.method), "check method")
+			g.Expect(request.URL.Path).To(Equal(c.path), "check url")
+			if c.method == "POST" && request.ContentLength > 0 {
+				body, err := ioutil.ReadAll(request.Body)
+				g.Expect(err).NotTo(HaveOccurred())
+				config := ChangefeedConfig{}
+				g.Expect(json.Unmarshal(body, &config)).To(Succeed())
+				g.Expect(config.ChangefeedID).To(Equal("cf"))
+			}
+
+			w.Header().Set("Content-Type", ContentTypeJSON)
+			w.WriteHeader(c.status)
+			if c.resp != nil {
+				data, err := json.Marshal(c.resp)
+				g.Expect(err).NotTo(HaveOccurred())
+				w.Write(data)
+			}
+		})
+
+		control := NewDefaultTiCDCControl(&fake.Clientset{})
+		control.testURL = svc.URL
+		err := c.call(control)
+		if c.errFn != nil {
+			c.errFn(err)
+		} else {
+			g.Expect(err).NotTo(HaveOccurred())
+		}
+		svc.Close()
+	}
+}
+
+func TestTiCDCControlCapture(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	captures := []*CaptureInfo{
+		{ID: "capture-0", IsOwner: true, AdvertiseAddr: "demo-ticdc-0.demo-ticdc-peer.default.svc:8301"},
+		{ID: "capture-1", AdvertiseAddr: "demo-ticdc-1.demo-ticdc-peer.default.svc:8301"},
+	}
+
+	cases := []struct {
+		caseName string
+		drain    bool
+		call     func(control *defaultTiCDCControl)
+		expectFn func(requests []string)
+	}{
+		{
+			caseName: "GetCaptures",
+			call: func(control *defaultTiCDCControl) {
+				result, err := control.GetCaptures(getTidbCluster())
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(result).To(HaveLen(2))
+				g.Expect(result[0].PodName()).To(Equal("demo-ticdc-0"))
+			},
+		},
+		{
+			caseName: "DrainCapture",
+			drain:    true,
+			call: func(control *defaultTiCDCControl) {
+				count, err := control.DrainCapture(getTidbCluster(), 1)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(count).To(Equal(3))
+			},
+			expectFn: func(requests []string) {
+				g.Expect(requests).To(ContainElement("PUT /api/v1/captures/drain"))
+			},
+		},
+		{
+			caseName: "DrainCapture not supported",
+			call: func(control *defaultTiCDCControl) {
+				count, err := control.DrainCapture(getTidbCluster(), 1)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(count).To(Equal(0))
+			},
+		},
+		{
+			caseName: "DrainCapture of the owner",
+			call: func(control *defaultTiCDCControl) {
+				_, err := control.DrainCapture(getTidbCluster(), 0)
+				g.Expect(err).To(HaveOccurred())
+			},
+			expectFn: func(requests []string) {
+				g.Expect(requests).NotTo(ContainElement("PUT /api/v1/captures/drain"))
+			},
+		},
+		{
+			caseName: "ResignOwner",
+			call: func(control *defaultTiCDCControl) {
+				resigned, err := control.ResignOwner(getTidbCluster(), 0)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(resigned).To(BeTrue())
+			},
+			expectFn: func(requests []string) {
+				g.Expect(requests).To(ContainElement("POST /api/v1/owner/resign"))
+			},
+		},
+		{
+			caseName: "ResignOwner of a non-owner capture",
+			call: func(control *defaultTiCDCControl) {
+				resigned, err := control.ResignOwner(getTidbCluster(), 1)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(resigned).To(BeFalse())
+			},
+			expectFn: func(requests []string) {
+				g.Expect(requests).NotTo(ContainElement("POST /api/v1/owner/resign"))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Log(c.caseName)
+		requests := []string{}
+		svc := getClientServer(func(w http.ResponseWriter, request *http.Request) {
+			requests = append(requests, request.Method+" "+request.URL.Path)
+			w.Header().Set("Content-Type", ContentTypeJSON)
+			var resp interface{}
+			switch request.URL.Path {
+			case "/api/v1/captures":
+				resp = captures
+			case "/api/v1/captures/drain":
+				if !c.drain {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				body, err := ioutil.ReadAll(request.Body)
+				g.Expect(err).NotTo(HaveOccurred())
+				req := drainCaptureRequest{}
+				g.Expect(json.Unmarshal(body, &req)).To(Succeed())
+				g.Expect(req.CaptureID).To(Equal("capture-1"))
+				w.WriteHeader(http.StatusAccepted)
+				resp = drainCaptureResponse{CurrentTableCount: 3}
+			case "/api/v1/owner/resign":
+				w.WriteHeader(http.StatusAccepted)
+				return
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			data, err := json.Marshal(resp)
+			g.Expect(err).NotTo(HaveOccurred())
+			w.Write(data)
+		})
+
+		control := NewDefaultTiCDCControl(&fake.Clientset{})
+		control.testURL = svc.URL
+		c.call(control)
+		if c.expectFn != nil {
+			c.expectFn(requests)
+		}
+		svc.Close()
+	}
+}
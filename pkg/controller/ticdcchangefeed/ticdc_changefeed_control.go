@@ -0,0 +1,362 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ticdcchangefeed
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog"
+	"k8s.io/kubernetes/pkg/util/slice"
+)
+
+// physicalShiftBits is the number of bits of the logical part of a TSO
+const physicalShiftBits = 18
+
+// ControlInterface reconciles TiCDCChangefeed
+type ControlInterface interface {
+	// ReconcileTiCDCChangefeed implements the reconcile logic of TiCDCChangefeed
+	ReconcileTiCDCChangefeed(cf *v1alpha1.TiCDCChangefeed) error
+}
+
+// NewDefaultTiCDCChangefeedControl returns a new instance of the default TiCDCChangefeed ControlInterface
+func NewDefaultTiCDCChangefeedControl(deps *controller.Dependencies) ControlInterface {
+	return &defaultTiCDCChangefeedControl{deps: deps}
+}
+
+type defaultTiCDCChangefeedControl struct {
+	deps *controller.Dependencies
+}
+
+func (c *defaultTiCDCChangefeedControl) ReconcileTiCDCChangefeed(cf *v1alpha1.TiCDCChangefeed) error {
+	if cf.DeletionTimestamp != nil {
+		return c.removeChangefeed(cf)
+	}
+
+	if !slice.ContainsString(cf.Finalizers, label.TiCDCChangefeedFinalizer, nil) {
+		// make a copy so we don't mutate the shared cache
+		cf = cf.DeepCopy()
+		cf.Finalizers = append(cf.Finalizers, label.TiCDCChangefeedFinalizer)
+		updated, err := c.deps.Clientset.PingcapV1alpha1().TiCDCChangefeeds(cf.Namespace).Update(cf)
+		if err != nil {
+			return fmt.Errorf("add ticdc changefeed %s/%s finalizer failed, err: %v", cf.Namespace, cf.Name, err)
+		}
+		cf = updated
+	}
+
+	tc, err := c.getTidbCluster(cf)
+	if err != nil {
+		return fmt.Errorf("failed to get TidbCluster %s/%s for changefeed %s/%s, error: %v", cf.GetClusterNamespace(), cf.Spec.Cluster.Name, cf.Namespace, cf.Name, err)
+	}
+	if tc.Spec.TiCDC == nil {
+		return fmt.Errorf("TidbCluster %s/%s has no ticdc for changefeed %s/%s", tc.Namespace, tc.Name, cf.Namespace, cf.Name)
+	}
+	if tc.Spec.Paused {
+		klog.Infof("TidbCluster %s/%s is paused, skip syncing changefeed %s/%s", tc.Namespace, tc.Name, cf.Namespace, cf.Name)
+		return nil
+	}
+
+	return c.syncChangefeed(tc, cf.DeepCopy())
+}
+
+func (c *defaultTiCDCChangefeedControl) syncChangefeed(tc *v1alpha1.TidbCluster, cf *v1alpha1.TiCDCChangefeed) error {
+	ns := cf.GetNamespace()
+	name := cf.GetName()
+	id := cf.GetChangefeedID()
+	cdc := c.deps.CDCControl
+
+	// the changefeed ID is changed, the old changefeed should be removed first
+	if cf.Status.ChangefeedID != "" && cf.Status.ChangefeedID != id {
+		if err := cdc.RemoveChangefeed(tc, cf.Status.ChangefeedID); err != nil {
+			return fmt.Errorf("failed to remove changefeed %s for %s/%s, error: %v", cf.Status.ChangefeedID, ns, name, err)
+		}
+		klog.Infof("changefeed %s of %s/%s is removed since the changefeed ID is changed to %s", cf.Status.ChangefeedID, ns, name, id)
+		cf.Status.ChangefeedID = ""
+	}
+
+	detail, err := cdc.GetChangefeed(tc, id)
+	if controller.IsChangefeedNotFound(err) {
+		config, err := c.getChangefeedConfig(cf)
+		if err != nil {
+			return err
+		}
+		if err := cdc.CreateChangefeed(tc, config); err != nil {
+			return fmt.Errorf("failed to create changefeed %s for %s/%s, error: %v", id, ns, name, err)
+		}
+		klog.Infof("changefeed %s of %s/%s is created", id, ns, name)
+		cf.Status.ChangefeedID = id
+		cf.Status.ObservedGeneration = cf.Generation
+		cf.Status.State = v1alpha1.TiCDCChangefeedStatePending
+		if cf.Spec.Paused {
+			if err := cdc.PauseChangefeed(tc, id); err != nil {
+				return err
+			}
+		}
+		return c.updateChangefeed(cf)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get changefeed %s for %s/%s, error: %v", id, ns, name, err)
+	}
+
+	stopped := detail.State == string(v1alpha1.TiCDCChangefeedStateStopped)
+	// changed is whether the changefeed is updated, paused or resumed in this sync,
+	// the detail must be re-fetched to report the status after the changes
+	changed := false
+	if !cf.IsSpecApplied() {
+		config, err := c.getChangefeedConfig(cf)
+		if err != nil {
+			return err
+		}
+		// TiCDC only accepts config updates of a stopped changefeed
+		if !stopped {
+			if err := cdc.PauseChangefeed(tc, id); err != nil {
+				return fmt.Errorf("failed to pause changefeed %s for %s/%s before updating, error: %v", id, ns, name, err)
+			}
+			stopped = true
+		}
+		if err := cdc.UpdateChangefeed(tc, id, config); err != nil {
+			return fmt.Errorf("failed to update changefeed %s for %s/%s, error: %v", id, ns, name, err)
+		}
+		klog.Infof("changefeed %s of %s/%s is updated to generation %d", id, ns, name, cf.Generation)
+		cf.Status.ChangefeedID = id
+		cf.Status.ObservedGeneration = cf.Generation
+		changed = true
+	}
+
+	if cf.Spec.Paused && !stopped {
+		if err := cdc.PauseChangefeed(tc, id); err != nil {
+			return fmt.Errorf("failed to pause changefeed %s for %s/%s, error: %v", id, ns, name, err)
+		}
+		changed = true
+	} else if !cf.Spec.Paused && stopped {
+		if err := cdc.ResumeChangefeed(tc, id); err != nil {
+			return fmt.Errorf("failed to resume changefeed %s for %s/%s, error: %v", id, ns, name, err)
+		}
+		changed = true
+	}
+	if changed {
+		detail, err = cdc.GetChangefeed(tc, id)
+		if err != nil {
+			return fmt.Errorf("failed to get changefeed %s for %s/%s, error: %v", id, ns, name, err)
+		}
+	}
+
+	syncChangefeedStatus(cf, detail)
+	return c.updateChangefeed(cf)
+}
+
+func syncChangefeedStatus(cf *v1alpha1.TiCDCChangefeed, detail *controller.ChangefeedDetail) {
+	now := metav1.Now()
+	cf.Status.State = v1alpha1.TiCDCChangefeedState(detail.State)
+	cf.Status.CheckpointTs = strconv.FormatUint(detail.CheckpointTSO, 10)
+	if detail.CheckpointTSO > 0 {
+		checkpointTime := metav1.NewTime(tsoToTime(detail.CheckpointTSO))
+		cf.Status.CheckpointTime = &checkpointTime
+		cf.Status.LagSeconds = int64(now.Sub(checkpointTime.Time) / time.Second)
+	}
+	if detail.Error != nil {
+		cf.Status.Error = &v1alpha1.TiCDCChangefeedError{
+			Addr:    detail.Error.Addr,
+			Code:    detail.Error.Code,
+			Message: detail.Error.Message,
+		}
+	} else {
+		cf.Status.Error = nil
+	}
+	cf.Status.LastSyncTime = &now
+}
+
+// statusChanged returns whether the status should be written. The checkpoint, the
+// lag and the sync time change in every sync of a running changefeed, they are
+// only written once per resync duration, or each status write triggers another sync.
+func (c *defaultTiCDCChangefeedControl) statusChanged(old, cur *v1alpha1.TiCDCChangefeedStatus) bool {
+	if old.LastSyncTime == nil || cur.LastSyncTime == nil ||
+		cur.LastSyncTime.Sub(old.LastSyncTime.Time) >= c.deps.CLIConfig.ResyncDuration {
+		return !apiequality.Semantic.DeepEqual(old, cur)
+	}
+	progress := cur.DeepCopy()
+	progress.CheckpointTs = old.CheckpointTs
+	progress.CheckpointTime = old.CheckpointTime
+	progress.LagSeconds = old.LagSeconds
+	progress.LastSyncTime = old.LastSyncTime
+	return !apiequality.Semantic.DeepEqual(old, progress)
+}
+
+// tsoToTime returns the physical time of a TSO
+func tsoToTime(tso uint64) time.Time {
+	ms := int64(tso >> physicalShiftBits)
+	return time.Unix(ms/1e3, (ms%1e3)*1e6)
+}
+
+func (c *defaultTiCDCChangefeedControl) getChangefeedConfig(cf *v1alpha1.TiCDCChangefeed) (*controller.ChangefeedConfig, error) {
+	ns := cf.GetNamespace()
+	name := cf.GetName()
+
+	sinkURI := cf.Spec.SinkURI
+	if cf.Spec.SinkURISecret != nil {
+		secret, err := c.deps.SecretLister.Secrets(ns).Get(cf.Spec.SinkURISecret.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get sink uri secret %s for changefeed %s/%s, error: %v", cf.Spec.SinkURISecret.Name, ns, name, err)
+		}
+		data, ok := secret.Data[cf.Spec.SinkURISecret.Key]
+		if !ok {
+			return nil, fmt.Errorf("key %s does not exist in sink uri secret %s/%s", cf.Spec.SinkURISecret.Key, ns, cf.Spec.SinkURISecret.Name)
+		}
+		sinkURI = string(data)
+	}
+	if sinkURI == "" {
+		return nil, fmt.Errorf("sink uri of changefeed %s/%s is empty", ns, name)
+	}
+
+	config := &controller.ChangefeedConfig{
+		ChangefeedID:          cf.GetChangefeedID(),
+		SinkURI:               sinkURI,
+		ForceReplicate:        cf.Spec.ForceReplicate,
+		IgnoreIneligibleTable: cf.Spec.IgnoreIneligibleTable,
+	}
+	var err error
+	if config.StartTs, err = parseTSO(cf.Spec.StartTs); err != nil {
+		return nil, fmt.Errorf("invalid startTs of changefeed %s/%s, error: %v", ns, name, err)
+	}
+	if config.TargetTs, err = parseTSO(cf.Spec.TargetTs); err != nil {
+		return nil, fmt.Errorf("invalid targetTs of changefeed %s/%s, error: %v", ns, name, err)
+	}
+	if cf.Spec.Filter != nil {
+		config.FilterRules = cf.Spec.Filter.Rules
+		for _, ts := range cf.Spec.Filter.IgnoreTxnStartTs {
+			startTs, err := parseTSO(ts)
+			if err != nil {
+				return nil, fmt.Errorf("invalid ignoreTxnStartTs of changefeed %s/%s, error: %v", ns, name, err)
+			}
+			config.IgnoreTxnStartTs = append(config.IgnoreTxnStartTs, startTs)
+		}
+	}
+	if cf.Spec.Protocol != "" {
+		config.SinkConfig = &controller.SinkConfig{Protocol: cf.Spec.Protocol}
+	}
+	return config, nil
+}
+
+func parseTSO(ts string) (uint64, error) {
+	if ts == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(ts, 10, 64)
+}
+
+// removeChangefeed removes the changefeed from TiCDC and then removes the finalizer
+func (c *defaultTiCDCChangefeedControl) removeChangefeed(cf *v1alpha1.TiCDCChangefeed) error {
+	ns := cf.GetNamespace()
+	name := cf.GetName()
+
+	if !slice.ContainsString(cf.Finalizers, label.TiCDCChangefeedFinalizer, nil) {
+		return nil
+	}
+
+	tc, err := c.getTidbCluster(cf)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	// the changefeed has gone with the TidbCluster
+	if err == nil && tc.Spec.TiCDC != nil {
+		id := cf.Status.ChangefeedID
+		if id == "" {
+			id = cf.GetChangefeedID()
+		}
+		if err := c.deps.CDCControl.RemoveChangefeed(tc, id); err != nil {
+			return fmt.Errorf("failed to remove changefeed %s for %s/%s, error: %v", id, ns, name, err)
+		}
+		klog.Infof("changefeed %s of %s/%s is removed", id, ns, name)
+	}
+
+	cf = cf.DeepCopy()
+	cf.Finalizers = slice.RemoveString(cf.Finalizers, label.TiCDCChangefeedFinalizer, nil)
+	_, err = c.deps.Clientset.PingcapV1alpha1().TiCDCChangefeeds(ns).Update(cf)
+	if err != nil {
+		return fmt.Errorf("remove ticdc changefeed %s/%s finalizer failed, err: %v", ns, name, err)
+	}
+	return nil
+}
+
+func (c *defaultTiCDCChangefeedControl) getTidbCluster(cf *v1alpha1.TiCDCChangefeed) (*v1alpha1.TidbCluster, error) {
+	return c.deps.TiDBClusterLister.TidbClusters(cf.GetClusterNamespace()).Get(cf.Spec.Cluster.Name)
+}
+
+func (c *defaultTiCDCChangefeedControl) updateChangefeed(cf *v1alpha1.TiCDCChangefeed) error {
+	ns := cf.GetNamespace()
+	name := cf.GetName()
+
+	old, err := c.deps.TiCDCChangefeedLister.TiCDCChangefeeds(ns).Get(name)
+	if err == nil && !c.statusChanged(&old.Status, &cf.Status) {
+		return nil
+	}
+
+	status := cf.Status.DeepCopy()
+	// don't wait due to limited number of clients, but backoff after the default number of steps
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		_, updateErr := c.deps.Clientset.PingcapV1alpha1().TiCDCChangefeeds(ns).Update(cf)
+		if updateErr == nil {
+			klog.V(4).Infof("TiCDCChangefeed: [%s/%s] updated successfully", ns, name)
+			return nil
+		}
+		klog.V(4).Infof("failed to update TiCDCChangefeed: [%s/%s], error: %v", ns, name, updateErr)
+
+		if updated, err := c.deps.TiCDCChangefeedLister.TiCDCChangefeeds(ns).Get(name); err == nil {
+			// make a copy so we don't mutate the shared cache
+			cf = updated.DeepCopy()
+			cf.Status = *status
+		} else {
+			utilruntime.HandleError(fmt.Errorf("error getting updated TiCDCChangefeed %s/%s from lister: %v", ns, name, err))
+		}
+
+		return updateErr
+	})
+	if err != nil {
+		klog.Errorf("failed to update TiCDCChangefeed: [%s/%s], error: %v", ns, name, err)
+	}
+	return err
+}
+
+var _ ControlInterface = &defaultTiCDCChangefeedControl{}
+
+// FakeTiCDCChangefeedControl is a fake TiCDCChangefeed ControlInterface
+type FakeTiCDCChangefeedControl struct {
+	err error
+}
+
+// NewFakeTiCDCChangefeedControl returns a FakeTiCDCChangefeedControl
+func NewFakeTiCDCChangefeedControl() *FakeTiCDCChangefeedControl {
+	return &FakeTiCDCChangefeedControl{}
+}
+
+// SetReconcileTiCDCChangefeedError sets error for TiCDCChangefeedControl
+func (c *FakeTiCDCChangefeedControl) SetReconcileTiCDCChangefeedError(err error) {
+	c.err = err
+}
+
+// ReconcileTiCDCChangefeed fake ReconcileTiCDCChangefeed
+func (c *FakeTiCDCChangefeedControl) ReconcileTiCDCChangefeed(_ *v1alpha1.TiCDCChangefeed) error {
+	return c.err
+}
+
+var _ ControlInterface = &FakeTiCDCChangefeedControl{}
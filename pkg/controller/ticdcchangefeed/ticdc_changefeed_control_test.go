@@ -0,0 +1,260 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ticdcchangefeed
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTiCDCChangefeedControlReconcile(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name        string
+		update      func(cf *v1alpha1.TiCDCChangefeed)
+		existing    *controller.ChangefeedDetail
+		cdcErr      error
+		errExpectFn func(*GomegaWithT, error)
+		expectFn    func(*GomegaWithT, *v1alpha1.TiCDCChangefeed, *controller.FakeTiCDCControl)
+	}
+
+	testFn := func(test *testcase) {
+		t.Log(test.name)
+
+		cf := newTiCDCChangefeed()
+		if test.update != nil {
+			test.update(cf)
+		}
+		control, deps := newFakeTiCDCChangefeedControl()
+		cdc := deps.CDCControl.(*controller.FakeTiCDCControl)
+		if test.existing != nil {
+			cdc.SetChangefeed(test.existing)
+		}
+		if test.cdcErr != nil {
+			cdc.SetError(test.cdcErr)
+		}
+		_, err := deps.Clientset.PingcapV1alpha1().TiCDCChangefeeds(cf.Namespace).Create(cf)
+		g.Expect(err).NotTo(HaveOccurred())
+		deps.InformerFactory.Pingcap().V1alpha1().TiCDCChangefeeds().Informer().GetIndexer().Add(cf)
+
+		err = control.ReconcileTiCDCChangefeed(cf)
+		if test.errExpectFn != nil {
+			test.errExpectFn(g, err)
+		} else {
+			g.Expect(err).NotTo(HaveOccurred())
+		}
+
+		updated, err := deps.Clientset.PingcapV1alpha1().TiCDCChangefeeds(cf.Namespace).Get(cf.Name, metav1.GetOptions{})
+		g.Expect(err).NotTo(HaveOccurred())
+		if test.expectFn != nil {
+			test.expectFn(g, updated, cdc)
+		}
+	}
+
+	tests := []testcase{
+		{
+			name: "create changefeed",
+			expectFn: func(g *GomegaWithT, cf *v1alpha1.TiCDCChangefeed, cdc *controller.FakeTiCDCControl) {
+				g.Expect(cf.Finalizers).To(ContainElement(label.TiCDCChangefeedFinalizer))
+				g.Expect(cf.Status.ChangefeedID).To(Equal("cf"))
+				g.Expect(cf.Status.ObservedGeneration).To(Equal(int64(1)))
+				g.Expect(cf.Status.State).To(Equal(v1alpha1.TiCDCChangefeedStatePending))
+				detail, err := cdc.GetChangefeed(nil, "cf")
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(detail.SinkURI).To(Equal("mysql://root@127.0.0.1:3306/"))
+			},
+		},
+		{
+			name: "create changefeed with invalid start ts",
+			update: func(cf *v1alpha1.TiCDCChangefeed) {
+				cf.Spec.StartTs = "abc"
+			},
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring("invalid startTs"))
+			},
+		},
+		{
+			name: "create changefeed with sink uri secret not found",
+			update: func(cf *v1alpha1.TiCDCChangefeed) {
+				cf.Spec.SinkURISecret = &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "sink"},
+					Key:                  "uri",
+				}
+			},
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring("failed to get sink uri secret"))
+			},
+		},
+		{
+			name:   "ticdc is unavailable",
+			cdcErr: fmt.Errorf("ticdc is unavailable"),
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring("ticdc is unavailable"))
+			},
+		},
+		{
+			name: "sync status of an applied changefeed",
+			update: func(cf *v1alpha1.TiCDCChangefeed) {
+				cf.Finalizers = []string{label.TiCDCChangefeedFinalizer}
+				cf.Status.ChangefeedID = "cf"
+				cf.Status.ObservedGeneration = 1
+			},
+			existing: &controller.ChangefeedDetail{
+				ID:            "cf",
+				CheckpointTSO: uint64(time.Now().Add(-time.Minute).UnixNano()/int64(time.Millisecond)) << physicalShiftBits,
+				State:         string(v1alpha1.TiCDCChangefeedStateError),
+				Error:         &controller.ChangefeedError{Code: "CDC:ErrSinkURIInvalid", Message: "invalid sink"},
+			},
+			expectFn: func(g *GomegaWithT, cf *v1alpha1.TiCDCChangefeed, cdc *controller.FakeTiCDCControl) {
+				g.Expect(cf.Status.State).To(Equal(v1alpha1.TiCDCChangefeedStateError))
+				g.Expect(cf.Status.CheckpointTime).NotTo(BeNil())
+				g.Expect(cf.Status.LagSeconds).To(BeNumerically("~", 60, 5))
+				g.Expect(cf.Status.Error).NotTo(BeNil())
+				g.Expect(cf.Status.Error.Code).To(Equal("CDC:ErrSinkURIInvalid"))
+			},
+		},
+		{
+			name: "update changefeed and keep it paused",
+			update: func(cf *v1alpha1.TiCDCChangefeed) {
+				cf.Generation = 2
+				cf.Spec.Paused = true
+				cf.Spec.SinkURI = "kafka://127.0.0.1:9092/topic"
+				cf.Status.ChangefeedID = "cf"
+				cf.Status.ObservedGeneration = 1
+			},
+			existing: &controller.ChangefeedDetail{
+				ID:    "cf",
+				State: string(v1alpha1.TiCDCChangefeedStateNormal),
+			},
+			expectFn: func(g *GomegaWithT, cf *v1alpha1.TiCDCChangefeed, cdc *controller.FakeTiCDCControl) {
+				g.Expect(cf.Status.ObservedGeneration).To(Equal(int64(2)))
+				g.Expect(cf.Status.State).To(Equal(v1alpha1.TiCDCChangefeedStateStopped))
+				detail, err := cdc.GetChangefeed(nil, "cf")
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(detail.SinkURI).To(Equal("kafka://127.0.0.1:9092/topic"))
+			},
+		},
+		{
+			name: "resume a paused changefeed",
+			update: func(cf *v1alpha1.TiCDCChangefeed) {
+				cf.Status.ChangefeedID = "cf"
+				cf.Status.ObservedGeneration = 1
+			},
+			existing: &controller.ChangefeedDetail{
+				ID:    "cf",
+				State: string(v1alpha1.TiCDCChangefeedStateStopped),
+			},
+			expectFn: func(g *GomegaWithT, cf *v1alpha1.TiCDCChangefeed, cdc *controller.FakeTiCDCControl) {
+				g.Expect(cf.Status.State).To(Equal(v1alpha1.TiCDCChangefeedStateNormal))
+			},
+		},
+		{
+			name: "remove changefeed on deletion",
+			update: func(cf *v1alpha1.TiCDCChangefeed) {
+				now := metav1.Now()
+				cf.DeletionTimestamp = &now
+				cf.Finalizers = []string{label.TiCDCChangefeedFinalizer}
+				cf.Status.ChangefeedID = "cf"
+			},
+			existing: &controller.ChangefeedDetail{
+				ID:    "cf",
+				State: string(v1alpha1.TiCDCChangefeedStateNormal),
+			},
+			expectFn: func(g *GomegaWithT, cf *v1alpha1.TiCDCChangefeed, cdc *controller.FakeTiCDCControl) {
+				g.Expect(cf.Finalizers).NotTo(ContainElement(label.TiCDCChangefeedFinalizer))
+				_, err := cdc.GetChangefeed(nil, "cf")
+				g.Expect(controller.IsChangefeedNotFound(err)).To(BeTrue())
+			},
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i])
+	}
+}
+
+func TestTiCDCChangefeedControlSyncUnchanged(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cf := newTiCDCChangefeed()
+	cf.Finalizers = []string{label.TiCDCChangefeedFinalizer}
+	cf.Status.ChangefeedID = "cf"
+	cf.Status.ObservedGeneration = 1
+	control, deps := newFakeTiCDCChangefeedControl()
+	cdc := deps.CDCControl.(*controller.FakeTiCDCControl)
+	cdc.SetChangefeed(&controller.ChangefeedDetail{
+		ID:            "cf",
+		CheckpointTSO: uint64(time.Now().Add(-time.Minute).UnixNano()/int64(time.Millisecond)) << physicalShiftBits,
+		State:         string(v1alpha1.TiCDCChangefeedStateNormal),
+	})
+	_, err := deps.Clientset.PingcapV1alpha1().TiCDCChangefeeds(cf.Namespace).Create(cf)
+	g.Expect(err).NotTo(HaveOccurred())
+	indexer := deps.InformerFactory.Pingcap().V1alpha1().TiCDCChangefeeds().Informer().GetIndexer()
+	indexer.Add(cf)
+
+	g.Expect(control.ReconcileTiCDCChangefeed(cf)).To(Succeed())
+	updated, err := deps.Clientset.PingcapV1alpha1().TiCDCChangefeeds(cf.Namespace).Get(cf.Name, metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(updated.Status.State).To(Equal(v1alpha1.TiCDCChangefeedStateNormal))
+	g.Expect(updated.Status.LastSyncTime).NotTo(BeNil())
+
+	// the second sync of the unchanged changefeed within the resync duration does not write
+	indexer.Update(updated)
+	g.Expect(control.ReconcileTiCDCChangefeed(updated)).To(Succeed())
+	again, err := deps.Clientset.PingcapV1alpha1().TiCDCChangefeeds(cf.Namespace).Get(cf.Name, metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(again.ResourceVersion).To(Equal(updated.ResourceVersion))
+	g.Expect(again.Status.LastSyncTime).To(Equal(updated.Status.LastSyncTime))
+}
+
+func newFakeTiCDCChangefeedControl() (ControlInterface, *controller.Dependencies) {
+	deps := controller.NewFakeDependencies()
+	tc := &v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "demo",
+			Namespace: corev1.NamespaceDefault,
+		},
+		Spec: v1alpha1.TidbClusterSpec{
+			TiCDC: &v1alpha1.TiCDCSpec{Replicas: 1},
+		},
+	}
+	deps.InformerFactory.Pingcap().V1alpha1().TidbClusters().Informer().GetIndexer().Add(tc)
+	return NewDefaultTiCDCChangefeedControl(deps), deps
+}
+
+func newTiCDCChangefeed() *v1alpha1.TiCDCChangefeed {
+	return &v1alpha1.TiCDCChangefeed{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "cf",
+			Namespace:  corev1.NamespaceDefault,
+			Generation: 1,
+		},
+		Spec: v1alpha1.TiCDCChangefeedSpec{
+			Cluster: v1alpha1.TidbClusterRef{Name: "demo"},
+			SinkURI: "mysql://root@127.0.0.1:3306/",
+		},
+	}
+}
@@ -18,6 +18,8 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
@@ -37,6 +39,13 @@ type DBInfo struct {
 	IsOwner bool `json:"is_owner"`
 }
 
+// https://github.com/pingcap/tidb/blob/master/server/http_status.go
+type tidbStatus struct {
+	Connections int    `json:"connections"`
+	Version     string `json:"version"`
+	GitHash     string `json:"git_hash"`
+}
+
 // TiDBControlInterface is the interface that knows how to manage tidb peers
 type TiDBControlInterface interface {
 	// GetHealth returns tidb's health info
@@ -45,6 +54,14 @@ type TiDBControlInterface interface {
 	GetInfo(tc *v1alpha1.TidbCluster, ordinal int32) (*DBInfo, error)
 	// GetSettings return the TiDB instance settings
 	GetSettings(tc *v1alpha1.TidbCluster, ordinal int32) (*config.Config, error)
+	// GetConnectionCount returns the count of the client connections of the TiDB instance
+	GetConnectionCount(tc *v1alpha1.TidbCluster, ordinal int32) (int, error)
+	// UpdateSettings updates the online changeable settings of the TiDB instance,
+	// the settings are keyed by their names in the status API, e.g. log_level
+	UpdateSettings(tc *v1alpha1.TidbCluster, ordinal int32, settings map[string]string) error
+	// ResignDDLOwner resigns the ddl owner of the TiDB instance, it returns true
+	// if the instance is not the ddl owner
+	ResignDDLOwner(tc *v1alpha1.TidbCluster, ordinal int32) (bool, error)
 }
 
 // defaultTiDBControl is default implementation of TiDBControlInterface.
@@ -137,6 +154,77 @@ func (c *defaultTiDBControl) GetSettings(tc *v1alpha1.TidbCluster, ordinal int32
 	return &info, nil
 }
 
+func (c *defaultTiDBControl) GetConnectionCount(tc *v1alpha1.TidbCluster, ordinal int32) (int, error) {
+	httpClient, err := c.getHTTPClient(tc)
+	if err != nil {
+		return 0, err
+	}
+
+	baseURL := c.getBaseURL(tc, ordinal)
+	url := fmt.Sprintf("%s/status", baseURL)
+	body, err := getBodyOK(httpClient, url)
+	if err != nil {
+		return 0, err
+	}
+	status := tidbStatus{}
+	err = json.Unmarshal(body, &status)
+	if err != nil {
+		return 0, err
+	}
+	return status.Connections, nil
+}
+
+func (c *defaultTiDBControl) UpdateSettings(tc *v1alpha1.TidbCluster, ordinal int32, settings map[string]string) error {
+	httpClient, err := c.getHTTPClient(tc)
+	if err != nil {
+		return err
+	}
+
+	form := url.Values{}
+	for k, v := range settings {
+		form.Set(k, v)
+	}
+	apiURL := fmt.Sprintf("%s/settings", c.getBaseURL(tc, ordinal))
+	res, err := httpClient.Post(apiURL, "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	defer httputil.DeferClose(res.Body)
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("Error response %s:%v URL: %s", string(body), res.StatusCode, apiURL)
+	}
+	return nil
+}
+
+func (c *defaultTiDBControl) ResignDDLOwner(tc *v1alpha1.TidbCluster, ordinal int32) (bool, error) {
+	httpClient, err := c.getHTTPClient(tc)
+	if err != nil {
+		return false, err
+	}
+
+	apiURL := fmt.Sprintf("%s/ddl/owner/resign", c.getBaseURL(tc, ordinal))
+	res, err := httpClient.Post(apiURL, "", nil)
+	if err != nil {
+		return false, err
+	}
+	defer httputil.DeferClose(res.Body)
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return false, err
+	}
+	if res.StatusCode == http.StatusOK {
+		return false, nil
+	}
+	if strings.Contains(string(body), NotDDLOwnerError) {
+		return true, nil
+	}
+	return false, fmt.Errorf("Error response %s:%v URL: %s", string(body), res.StatusCode, apiURL)
+}
+
 func getBodyOK(httpClient *http.Client, apiURL string) ([]byte, error) {
 	res, err := httpClient.Get(apiURL)
 	if err != nil {
@@ -163,7 +251,7 @@ func (c *defaultTiDBControl) getBaseURL(tc *v1alpha1.TidbCluster, ordinal int32)
 	tcName := tc.GetName()
 	ns := tc.GetNamespace()
 	scheme := tc.Scheme()
-	hostName := fmt.Sprintf("%s-%d", TiDBMemberName(tcName), ordinal)
+	hostName := fmt.Sprintf("%s-%d", TiDBGroupMemberName(tcName, tc.TiDBGroupName()), ordinal)
 
 	return fmt.Sprintf("%s://%s.%s.%s:10080", scheme, hostName, TiDBPeerMemberName(tcName), ns)
 }
@@ -174,11 +262,17 @@ type FakeTiDBControl struct {
 	tiDBInfo     *DBInfo
 	getInfoError error
 	tidbConfig   *config.Config
+	connections  map[string]int
+	// UpdatedSettings records the settings updated, keyed by pod name
+	UpdatedSettings map[string]map[string]string
+	// ResignedDDLOwners records the pods whose ddl owner is resigned
+	ResignedDDLOwners   []string
+	resignDDLOwnerError error
 }
 
 // NewFakeTiDBControl returns a FakeTiDBControl instance
 func NewFakeTiDBControl() *FakeTiDBControl {
-	return &FakeTiDBControl{}
+	return &FakeTiDBControl{UpdatedSettings: map[string]map[string]string{}}
 }
 
 // SetHealth set health info for FakeTiDBControl
@@ -187,7 +281,7 @@ func (c *FakeTiDBControl) SetHealth(healthInfo map[string]bool) {
 }
 
 func (c *FakeTiDBControl) GetHealth(tc *v1alpha1.TidbCluster, ordinal int32) (bool, error) {
-	podName := fmt.Sprintf("%s-%d", TiDBMemberName(tc.GetName()), ordinal)
+	podName := fmt.Sprintf("%s-%d", TiDBGroupMemberName(tc.GetName(), tc.TiDBGroupName()), ordinal)
 	if c.healthInfo == nil {
 		return false, nil
 	}
@@ -197,6 +291,14 @@ func (c *FakeTiDBControl) GetHealth(tc *v1alpha1.TidbCluster, ordinal int32) (bo
 	return false, nil
 }
 
+// SetConnectionCount sets the connection count of a tidb pod for FakeTiDBControl
+func (c *FakeTiDBControl) SetConnectionCount(podName string, count int) {
+	if c.connections == nil {
+		c.connections = map[string]int{}
+	}
+	c.connections[podName] = count
+}
+
 func (c *FakeTiDBControl) GetInfo(tc *v1alpha1.TidbCluster, ordinal int32) (*DBInfo, error) {
 	return c.tiDBInfo, c.getInfoError
 }
@@ -204,3 +306,31 @@ func (c *FakeTiDBControl) GetInfo(tc *v1alpha1.TidbCluster, ordinal int32) (*DBI
 func (c *FakeTiDBControl) GetSettings(tc *v1alpha1.TidbCluster, ordinal int32) (*config.Config, error) {
 	return c.tidbConfig, c.getInfoError
 }
+
+func (c *FakeTiDBControl) GetConnectionCount(tc *v1alpha1.TidbCluster, ordinal int32) (int, error) {
+	podName := fmt.Sprintf("%s-%d", TiDBGroupMemberName(tc.GetName(), tc.TiDBGroupName()), ordinal)
+	return c.connections[podName], c.getInfoError
+}
+
+func (c *FakeTiDBControl) UpdateSettings(tc *v1alpha1.TidbCluster, ordinal int32, settings map[string]string) error {
+	if c.getInfoError != nil {
+		return c.getInfoError
+	}
+	podName := fmt.Sprintf("%s-%d", TiDBGroupMemberName(tc.GetName(), tc.TiDBGroupName()), ordinal)
+	c.UpdatedSettings[podName] = settings
+	return nil
+}
+
+// SetResignDDLOwnerError sets the error returned by ResignDDLOwner for FakeTiDBControl
+func (c *FakeTiDBControl) SetResignDDLOwnerError(err error) {
+	c.resignDDLOwnerError = err
+}
+
+func (c *FakeTiDBControl) ResignDDLOwner(tc *v1alpha1.TidbCluster, ordinal int32) (bool, error) {
+	if c.resignDDLOwnerError != nil {
+		return false, c.resignDDLOwnerError
+	}
+	podName := fmt.Sprintf("%s-%d", TiDBGroupMemberName(tc.GetName(), tc.TiDBGroupName()), ordinal)
+	c.ResignedDDLOwners = append(c.ResignedDDLOwners, podName)
+	return false, nil
+}
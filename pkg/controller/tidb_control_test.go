@@ -265,6 +265,64 @@ func TestSettings(t *testing.T) {
 	}
 }
 
+func TestConnectionCount(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		caseName string
+		path     string
+		method   string
+		failed   bool
+		resp     tidbStatus
+		expected int
+	}{
+		{
+			caseName: "GetConnectionCount",
+			path:     "/status",
+			method:   "GET",
+			failed:   false,
+			resp:     tidbStatus{Connections: 3, Version: "5.7.25-TiDB-v4.0.0"},
+			expected: 3,
+		},
+		{
+			caseName: "GetConnectionCount failed",
+			path:     "/status",
+			method:   "GET",
+			failed:   true,
+			expected: 0,
+		},
+	}
+
+	for _, c := range cases {
+		svc := getClientServer(func(w http.ResponseWriter, request *http.Request) {
+			g.Expect(request.Method).To(Equal(c.method), "check method")
+			g.Expect(request.URL.Path).To(Equal(c.path), "check url")
+
+			w.Header().Set("Content-Type", ContentTypeJSON)
+			if c.failed {
+				w.WriteHeader(http.StatusInternalServerError)
+			} else {
+				data, err := json.Marshal(c.resp)
+				g.Expect(err).NotTo(HaveOccurred())
+				w.Write(data)
+			}
+		})
+		defer svc.Close()
+
+		fakeClient := &fake.Clientset{}
+		control := NewDefaultTiDBControl(fakeClient)
+		control.testURL = svc.URL
+		tc := getTidbCluster()
+		result, err := control.GetConnectionCount(tc, 0)
+		if c.failed {
+			g.Expect(err).To(HaveOccurred())
+		} else {
+			g.Expect(err).NotTo(HaveOccurred())
+		}
+		g.Expect(result).To(Equal(c.expected))
+	}
+}
+
 func TestGetHTTPClient(t *testing.T) {
 	g := NewGomegaWithT(t)
 
@@ -337,3 +395,93 @@ func fakeSecret(fakeClient *fake.Clientset) {
 		}, nil
 	})
 }
+
+func TestUpdateSettings(t *testing.T) {
+	g := NewGomegaWithT(t)
+	cases := []struct {
+		caseName string
+		failed   bool
+	}{
+		{
+			caseName: "UpdateSettings",
+			failed:   false,
+		},
+		{
+			caseName: "UpdateSettings failed",
+			failed:   true,
+		},
+	}
+	for _, c := range cases {
+		svc := getClientServer(func(w http.ResponseWriter, request *http.Request) {
+			g.Expect(request.Method).To(Equal("POST"), "check method")
+			g.Expect(request.URL.Path).To(Equal("/settings"), "check url")
+			g.Expect(request.ParseForm()).To(Succeed())
+			g.Expect(request.PostForm.Get("log_level")).To(Equal("warn"))
+			if c.failed {
+				w.WriteHeader(http.StatusInternalServerError)
+			} else {
+				w.WriteHeader(http.StatusOK)
+			}
+		})
+		defer svc.Close()
+		fakeClient := &fake.Clientset{}
+		control := NewDefaultTiDBControl(fakeClient)
+		control.testURL = svc.URL
+		tc := getTidbCluster()
+		err := control.UpdateSettings(tc, 0, map[string]string{"log_level": "warn"})
+		if c.failed {
+			g.Expect(err).To(HaveOccurred(), c.caseName)
+		} else {
+			g.Expect(err).NotTo(HaveOccurred(), c.caseName)
+		}
+	}
+}
+
+func TestResignDDLOwner(t *testing.T) {
+	g := NewGomegaWithT(t)
+	cases := []struct {
+		caseName   string
+		statusCode int
+		body       string
+		notOwner   bool
+		failed     bool
+	}{
+		{
+			caseName:   "ResignDDLOwner",
+			statusCode: http.StatusOK,
+			body:       "success!",
+		},
+		{
+			caseName:   "ResignDDLOwner not owner",
+			statusCode: http.StatusBadRequest,
+			body:       NotDDLOwnerError,
+			notOwner:   true,
+		},
+		{
+			caseName:   "ResignDDLOwner failed",
+			statusCode: http.StatusInternalServerError,
+			body:       "internal error",
+			failed:     true,
+		},
+	}
+	for _, c := range cases {
+		svc := getClientServer(func(w http.ResponseWriter, request *http.Request) {
+			g.Expect(request.Method).To(Equal("POST"), "check method")
+			g.Expect(request.URL.Path).To(Equal("/ddl/owner/resign"), "check url")
+			w.WriteHeader(c.statusCode)
+			w.Write([]byte(c.body))
+		})
+		defer svc.Close()
+		fakeClient := &fake.Clientset{}
+		control := NewDefaultTiDBControl(fakeClient)
+		control.testURL = svc.URL
+		tc := getTidbCluster()
+		notOwner, err := control.ResignDDLOwner(tc, 0)
+		if c.failed {
+			g.Expect(err).To(HaveOccurred(), c.caseName)
+		} else {
+			g.Expect(err).NotTo(HaveOccurred(), c.caseName)
+		}
+		g.Expect(notOwner).To(Equal(c.notOwner), c.caseName)
+	}
+}
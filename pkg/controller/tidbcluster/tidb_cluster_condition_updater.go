@@ -14,6 +14,10 @@
 package tidbcluster
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
 	appsv1 "k8s.io/api/apps/v1"
@@ -33,6 +37,34 @@ var _ TidbClusterConditionUpdater = &tidbClusterConditionUpdater{}
 
 func (u *tidbClusterConditionUpdater) Update(tc *v1alpha1.TidbCluster) error {
 	u.updateReadyCondition(tc)
+	u.updateMaintenancePendingCondition(tc)
+	u.updateNodeDrainingCondition(tc)
+	u.updateVolumeResizingCondition(tc)
+	u.updatePDAvailableCondition(tc)
+	u.updateTiKVStoresHealthyCondition(tc)
+	u.updateTiDBAvailableCondition(tc)
+	components := componentStatuses(tc)
+	u.updateComponentCondition(tc, components, v1alpha1.TidbClusterUpgrading,
+		utiltidbcluster.UpgradingSuffix, "%s is being upgraded",
+		utiltidbcluster.NoComponentsUpgrading, "No components are being upgraded",
+		func(c componentStatus) bool { return c.phase == v1alpha1.UpgradePhase })
+	u.updateComponentCondition(tc, components, v1alpha1.TidbClusterScaling,
+		utiltidbcluster.ScalingSuffix, "%s is being scaled",
+		utiltidbcluster.NoComponentsScaling, "No components are being scaled",
+		func(c componentStatus) bool { return c.phase == v1alpha1.ScalePhase })
+	u.updateComponentCondition(tc, components, v1alpha1.TidbClusterFailoverActive,
+		utiltidbcluster.FailoverActiveSuffix, "%s has failure members or stores failed over",
+		utiltidbcluster.NoFailoverActive, "No failure members or stores",
+		func(c componentStatus) bool { return c.failures > 0 })
+	u.updateComponentCondition(tc, components, v1alpha1.TidbClusterConfigOutOfSync,
+		utiltidbcluster.ConfigOutOfSyncSuffix, "%s pods are not updated to the latest revision or config",
+		utiltidbcluster.ConfigInSync, "All the pods are up to date",
+		func(c componentStatus) bool {
+			if c.config != nil && c.config.RestartPending {
+				return true
+			}
+			return c.statefulSet != nil && c.statefulSet.CurrentRevision != c.statefulSet.UpdateRevision
+		})
 	// in the future, we may return error when we need to Kubernetes API, etc.
 	return nil
 }
@@ -56,6 +88,9 @@ func (u *tidbClusterConditionUpdater) updateReadyCondition(tc *v1alpha1.TidbClus
 	message := ""
 
 	switch {
+	case tc.Status.Suspend != nil:
+		reason = utiltidbcluster.Suspended
+		message = fmt.Sprintf("TiDB cluster is %s", strings.ToLower(string(tc.Status.Suspend.Phase)))
 	case !allStatefulSetsAreUpToDate(tc):
 		reason = utiltidbcluster.StatfulSetNotUpToDate
 		message = "Statefulset(s) are in progress"
@@ -71,6 +106,15 @@ func (u *tidbClusterConditionUpdater) updateReadyCondition(tc *v1alpha1.TidbClus
 	case !tc.TiFlashAllStoresReady():
 		reason = utiltidbcluster.TiFlashStoreNotUp
 		message = "TiFlash store(s) are not up"
+	case tc.Bootstrapping():
+		reason = utiltidbcluster.Bootstrapping
+		message = "TiDB cluster is being restored from backup"
+		if tc.Status.Bootstrap.Phase != "" {
+			message = fmt.Sprintf("TiDB cluster is being restored from backup, the phase of restore %s is %s", tc.Status.Bootstrap.Restore, tc.Status.Bootstrap.Phase)
+		}
+		if tc.Status.Bootstrap.Message != "" {
+			message = fmt.Sprintf("TiDB cluster failed to be restored from backup, %s", tc.Status.Bootstrap.Message)
+		}
 	default:
 		status = v1.ConditionTrue
 		reason = utiltidbcluster.Ready
@@ -79,3 +123,184 @@ func (u *tidbClusterConditionUpdater) updateReadyCondition(tc *v1alpha1.TidbClus
 	cond := utiltidbcluster.NewTidbClusterCondition(v1alpha1.TidbClusterReady, status, reason, message)
 	utiltidbcluster.SetTidbClusterCondition(&tc.Status, *cond)
 }
+
+// updateMaintenancePendingCondition clears the MaintenancePending condition in the
+// maintenance windows or if no operation is pending, the condition is set when a
+// disruptive operation waits
+func (u *tidbClusterConditionUpdater) updateMaintenancePendingCondition(tc *v1alpha1.TidbCluster) {
+	current := utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.TidbClusterMaintenancePending)
+	if len(tc.Spec.MaintenanceWindows) == 0 && current == nil {
+		return
+	}
+	inWindow, err := utiltidbcluster.InMaintenanceWindow(tc, time.Now())
+	if err != nil {
+		return
+	}
+	var cond *v1alpha1.TidbClusterCondition
+	switch {
+	case inWindow:
+		cond = utiltidbcluster.NewTidbClusterCondition(v1alpha1.TidbClusterMaintenancePending, v1.ConditionFalse, utiltidbcluster.WithinMaintenanceWindow, "Disruptive operations are allowed")
+	case current == nil || current.Status != v1.ConditionTrue:
+		cond = utiltidbcluster.NewTidbClusterCondition(v1alpha1.TidbClusterMaintenancePending, v1.ConditionFalse, utiltidbcluster.NoMaintenancePending, "No disruptive operations are waiting for the maintenance window")
+	default:
+		return
+	}
+	utiltidbcluster.SetTidbClusterCondition(&tc.Status, *cond)
+}
+
+// updateNodeDrainingCondition sets the NodeDraining condition if there are TiKV or
+// TiFlash stores on the nodes being drained, the stores are listed in the
+// drainingStores of the status. The condition is not added until the first node drain.
+func (u *tidbClusterConditionUpdater) updateNodeDrainingCondition(tc *v1alpha1.TidbCluster) {
+	draining := len(tc.Status.TiKV.DrainingStores) > 0 || len(tc.Status.TiFlash.DrainingStores) > 0
+	for _, group := range tc.Status.TiKV.Groups {
+		draining = draining || len(group.DrainingStores) > 0
+	}
+
+	if !draining {
+		if utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.TidbClusterNodeDraining) == nil {
+			return
+		}
+		cond := utiltidbcluster.NewTidbClusterCondition(v1alpha1.TidbClusterNodeDraining, v1.ConditionFalse, utiltidbcluster.NoStoresDraining, "No stores are on the nodes being drained")
+		utiltidbcluster.SetTidbClusterCondition(&tc.Status, *cond)
+		return
+	}
+	cond := utiltidbcluster.NewTidbClusterCondition(v1alpha1.TidbClusterNodeDraining, v1.ConditionTrue, utiltidbcluster.StoresDraining, "Stores are being moved off the nodes being drained")
+	utiltidbcluster.SetTidbClusterCondition(&tc.Status, *cond)
+}
+
+func (u *tidbClusterConditionUpdater) updateVolumeResizingCondition(tc *v1alpha1.TidbCluster) {
+	if len(tc.Status.ResizingPVCs) == 0 {
+		if utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.TidbClusterVolumeResizing) == nil {
+			return
+		}
+		cond := utiltidbcluster.NewTidbClusterCondition(v1alpha1.TidbClusterVolumeResizing, v1.ConditionFalse, utiltidbcluster.NoVolumesResizing, "All the volumes are resized")
+		utiltidbcluster.SetTidbClusterCondition(&tc.Status, *cond)
+		return
+	}
+	cond := utiltidbcluster.NewTidbClusterCondition(v1alpha1.TidbClusterVolumeResizing, v1.ConditionTrue, utiltidbcluster.VolumesResizing, "Volumes are being resized to the storage request")
+	utiltidbcluster.SetTidbClusterCondition(&tc.Status, *cond)
+}
+
+func (u *tidbClusterConditionUpdater) updatePDAvailableCondition(tc *v1alpha1.TidbCluster) {
+	if tc.Spec.PD == nil {
+		return
+	}
+	var cond *v1alpha1.TidbClusterCondition
+	switch {
+	case !tc.PDIsAvailable():
+		cond = utiltidbcluster.NewTidbClusterCondition(v1alpha1.TidbClusterPDAvailable, v1.ConditionFalse, utiltidbcluster.PDQuorumUnavailable, "The quorum of PD members is not healthy")
+	case !tc.PDAllMembersReady():
+		cond = utiltidbcluster.NewTidbClusterCondition(v1alpha1.TidbClusterPDAvailable, v1.ConditionTrue, utiltidbcluster.PDUnhealthy, "Some PD members are not healthy")
+	default:
+		cond = utiltidbcluster.NewTidbClusterCondition(v1alpha1.TidbClusterPDAvailable, v1.ConditionTrue, utiltidbcluster.PDAllMembersHealthy, "All PD members are healthy")
+	}
+	utiltidbcluster.SetTidbClusterCondition(&tc.Status, *cond)
+}
+
+// updateTiKVStoresHealthyCondition sets the TiKVStoresHealthy condition by the stores
+// of the default TiKV and the TiKV groups
+func (u *tidbClusterConditionUpdater) updateTiKVStoresHealthyCondition(tc *v1alpha1.TidbCluster) {
+	if tc.Spec.TiKV == nil && len(tc.Spec.TiKVGroups) == 0 {
+		return
+	}
+	ready := tc.Spec.TiKV == nil || tc.TiKVAllStoresReady()
+	for i := range tc.Spec.TiKVGroups {
+		ready = ready && tc.TiKVGroupCluster(&tc.Spec.TiKVGroups[i]).TiKVAllStoresReady()
+	}
+	cond := utiltidbcluster.NewTidbClusterCondition(v1alpha1.TidbClusterTiKVStoresHealthy, v1.ConditionTrue, utiltidbcluster.TiKVAllStoresUp, "All TiKV stores are up")
+	if !ready {
+		cond = utiltidbcluster.NewTidbClusterCondition(v1alpha1.TidbClusterTiKVStoresHealthy, v1.ConditionFalse, utiltidbcluster.TiKVStoreNotUp, "TiKV store(s) are not up")
+	}
+	utiltidbcluster.SetTidbClusterCondition(&tc.Status, *cond)
+}
+
+// updateTiDBAvailableCondition sets the TiDBAvailable condition by the members of the
+// default TiDB and the TiDB groups
+func (u *tidbClusterConditionUpdater) updateTiDBAvailableCondition(tc *v1alpha1.TidbCluster) {
+	if tc.Spec.TiDB == nil && len(tc.Spec.TiDBGroups) == 0 {
+		return
+	}
+	healthy := 0
+	ready := true
+	countHealthy := func(tc *v1alpha1.TidbCluster) {
+		for _, member := range tc.Status.TiDB.Members {
+			if member.Health {
+				healthy++
+			}
+		}
+		ready = ready && tc.TiDBAllMembersReady()
+	}
+	if tc.Spec.TiDB != nil {
+		countHealthy(tc)
+	}
+	for i := range tc.Spec.TiDBGroups {
+		countHealthy(tc.TiDBGroupCluster(&tc.Spec.TiDBGroups[i]))
+	}
+	var cond *v1alpha1.TidbClusterCondition
+	switch {
+	case healthy == 0:
+		cond = utiltidbcluster.NewTidbClusterCondition(v1alpha1.TidbClusterTiDBAvailable, v1.ConditionFalse, utiltidbcluster.TiDBNoMemberHealthy, "None of the TiDB members is healthy")
+	case !ready:
+		cond = utiltidbcluster.NewTidbClusterCondition(v1alpha1.TidbClusterTiDBAvailable, v1.ConditionTrue, utiltidbcluster.TiDBUnhealthy, "Some TiDB members are not healthy")
+	default:
+		cond = utiltidbcluster.NewTidbClusterCondition(v1alpha1.TidbClusterTiDBAvailable, v1.ConditionTrue, utiltidbcluster.TiDBAllMembersHealthy, "All TiDB members are healthy")
+	}
+	utiltidbcluster.SetTidbClusterCondition(&tc.Status, *cond)
+}
+
+// componentStatus is the status of a component to update the Upgrading, Scaling,
+// FailoverActive and ConfigOutOfSync conditions
+type componentStatus struct {
+	name        string
+	phase       v1alpha1.MemberPhase
+	statefulSet *appsv1.StatefulSetStatus
+	failures    int
+	// config is the status of the config applied in place
+	config *v1alpha1.ConfigStatus
+}
+
+// componentStatuses returns the statuses of the components in the upgrade order,
+// the TiKV and TiDB groups are reported as TiKV and TiDB
+func componentStatuses(tc *v1alpha1.TidbCluster) []componentStatus {
+	components := []componentStatus{
+		{name: "PD", phase: tc.Status.PD.Phase, statefulSet: tc.Status.PD.StatefulSet, failures: len(tc.Status.PD.FailureMembers), config: tc.Status.PD.Config},
+		{name: "TiKV", phase: tc.Status.TiKV.Phase, statefulSet: tc.Status.TiKV.StatefulSet, failures: len(tc.Status.TiKV.FailureStores), config: tc.Status.TiKV.Config},
+	}
+	for _, group := range tc.Status.TiKV.Groups {
+		components = append(components, componentStatus{name: "TiKV", phase: group.Phase, statefulSet: group.StatefulSet, failures: len(group.FailureStores), config: group.Config})
+	}
+	components = append(components,
+		componentStatus{name: "TiFlash", phase: tc.Status.TiFlash.Phase, statefulSet: tc.Status.TiFlash.StatefulSet, failures: len(tc.Status.TiFlash.FailureStores)},
+		componentStatus{name: "TiDB", phase: tc.Status.TiDB.Phase, statefulSet: tc.Status.TiDB.StatefulSet, failures: len(tc.Status.TiDB.FailureMembers), config: tc.Status.TiDB.Config},
+	)
+	for _, group := range tc.Status.TiDB.Groups {
+		components = append(components, componentStatus{name: "TiDB", phase: group.Phase, statefulSet: group.StatefulSet, failures: len(group.FailureMembers), config: group.Config})
+	}
+	return append(components,
+		componentStatus{name: "TiCDC", phase: tc.Status.TiCDC.Phase, statefulSet: tc.Status.TiCDC.StatefulSet},
+		componentStatus{name: "Pump", phase: tc.Status.Pump.Phase, statefulSet: tc.Status.Pump.StatefulSet},
+		componentStatus{name: "Drainer", phase: tc.Status.Drainer.Phase, statefulSet: tc.Status.Drainer.StatefulSet},
+	)
+}
+
+// updateComponentCondition sets the condition to True if any component matches, the reason
+// is the name of the first matched component followed by the suffix, e.g. TiKVUpgrading.
+// The reason changes with the component, so the message is kept up to date.
+func (u *tidbClusterConditionUpdater) updateComponentCondition(
+	tc *v1alpha1.TidbCluster,
+	components []componentStatus,
+	condType v1alpha1.TidbClusterConditionType,
+	suffix, messageFormat string,
+	falseReason, falseMessage string,
+	match func(componentStatus) bool,
+) {
+	cond := utiltidbcluster.NewTidbClusterCondition(condType, v1.ConditionFalse, falseReason, falseMessage)
+	for _, c := range components {
+		if match(c) {
+			cond = utiltidbcluster.NewTidbClusterCondition(condType, v1.ConditionTrue, c.name+suffix, fmt.Sprintf(messageFormat, c.name))
+			break
+		}
+	}
+	utiltidbcluster.SetTidbClusterCondition(&tc.Status, *cond)
+}
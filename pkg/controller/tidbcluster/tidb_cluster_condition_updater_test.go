@@ -273,6 +273,45 @@ func TestTidbClusterConditionUpdater_Ready(t *testing.T) {
 			wantReason:  utiltidbcluster.TiFlashStoreNotUp,
 			wantMessage: "TiFlash store(s) are not up",
 		},
+		{
+			name: "bootstrapping",
+			tc: &v1alpha1.TidbCluster{
+				Spec: v1alpha1.TidbClusterSpec{
+					BootstrapFrom: &v1alpha1.BootstrapFromSpec{
+						Backup: "backup",
+					},
+				},
+				Status: v1alpha1.TidbClusterStatus{
+					Bootstrap: &v1alpha1.BootstrapStatus{
+						Restore: "test-bootstrap",
+						Phase:   v1alpha1.RestoreRunning,
+					},
+				},
+			},
+			wantStatus:  v1.ConditionFalse,
+			wantReason:  utiltidbcluster.Bootstrapping,
+			wantMessage: "TiDB cluster is being restored from backup, the phase of restore test-bootstrap is Running",
+		},
+		{
+			name: "bootstrap failed",
+			tc: &v1alpha1.TidbCluster{
+				Spec: v1alpha1.TidbClusterSpec{
+					BootstrapFrom: &v1alpha1.BootstrapFromSpec{
+						Backup: "backup",
+					},
+				},
+				Status: v1alpha1.TidbClusterStatus{
+					Bootstrap: &v1alpha1.BootstrapStatus{
+						Restore: "test-bootstrap",
+						Phase:   v1alpha1.RestoreFailed,
+						Message: "restore test-bootstrap failed: access denied",
+					},
+				},
+			},
+			wantStatus:  v1.ConditionFalse,
+			wantReason:  utiltidbcluster.Bootstrapping,
+			wantMessage: "TiDB cluster failed to be restored from backup, restore test-bootstrap failed: access denied",
+		},
 		{
 			name: "all ready",
 			tc: &v1alpha1.TidbCluster{
@@ -360,3 +399,322 @@ func TestTidbClusterConditionUpdater_Ready(t *testing.T) {
 		})
 	}
 }
+
+func TestTidbClusterConditionUpdater_MaintenancePending(t *testing.T) {
+	pending := func() v1alpha1.TidbClusterStatus {
+		status := v1alpha1.TidbClusterStatus{}
+		utiltidbcluster.SetMaintenancePending(&status, "test-tikv upgrade")
+		return status
+	}
+	tests := []struct {
+		name       string
+		tc         *v1alpha1.TidbCluster
+		wantStatus v1.ConditionStatus
+	}{
+		{
+			name:       "no window",
+			tc:         &v1alpha1.TidbCluster{},
+			wantStatus: "",
+		},
+		{
+			name: "out of window",
+			tc: &v1alpha1.TidbCluster{
+				Spec: v1alpha1.TidbClusterSpec{
+					MaintenanceWindows: []v1alpha1.MaintenanceWindow{{Schedule: "0 0 1 1 *", Duration: "1m"}},
+				},
+				Status: pending(),
+			},
+			wantStatus: v1.ConditionTrue,
+		},
+		{
+			name: "out of window and nothing pending",
+			tc: &v1alpha1.TidbCluster{
+				Spec: v1alpha1.TidbClusterSpec{
+					MaintenanceWindows: []v1alpha1.MaintenanceWindow{{Schedule: "0 0 1 1 *", Duration: "1m"}},
+				},
+			},
+			wantStatus: v1.ConditionFalse,
+		},
+		{
+			name: "in window",
+			tc: &v1alpha1.TidbCluster{
+				Spec: v1alpha1.TidbClusterSpec{
+					MaintenanceWindows: []v1alpha1.MaintenanceWindow{{Schedule: "* * * * *", Duration: "1m"}},
+				},
+				Status: pending(),
+			},
+			wantStatus: v1.ConditionFalse,
+		},
+		{
+			name:       "windows removed",
+			tc:         &v1alpha1.TidbCluster{Status: pending()},
+			wantStatus: v1.ConditionFalse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conditionUpdater := &tidbClusterConditionUpdater{}
+			conditionUpdater.Update(tt.tc)
+			var status v1.ConditionStatus
+			if cond := utiltidbcluster.GetTidbClusterCondition(tt.tc.Status, v1alpha1.TidbClusterMaintenancePending); cond != nil {
+				status = cond.Status
+			}
+			if diff := cmp.Diff(tt.wantStatus, status); diff != "" {
+				t.Errorf("unexpected status (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestTidbClusterConditionUpdater_NodeDraining(t *testing.T) {
+	draining := map[string]v1alpha1.TiKVDrainingStore{
+		"1": {PodName: "test-tikv-0", StoreID: "1", NodeName: "node-1"},
+	}
+	drained := func() v1alpha1.TidbClusterStatus {
+		status := v1alpha1.TidbClusterStatus{}
+		cond := utiltidbcluster.NewTidbClusterCondition(v1alpha1.TidbClusterNodeDraining, v1.ConditionTrue, utiltidbcluster.StoresDraining, "")
+		utiltidbcluster.SetTidbClusterCondition(&status, *cond)
+		return status
+	}
+	tests := []struct {
+		name       string
+		tc         *v1alpha1.TidbCluster
+		wantStatus v1.ConditionStatus
+	}{
+		{
+			name:       "never drained",
+			tc:         &v1alpha1.TidbCluster{},
+			wantStatus: "",
+		},
+		{
+			name: "tikv draining",
+			tc: &v1alpha1.TidbCluster{
+				Status: v1alpha1.TidbClusterStatus{TiKV: v1alpha1.TiKVStatus{DrainingStores: draining}},
+			},
+			wantStatus: v1.ConditionTrue,
+		},
+		{
+			name: "tikv group draining",
+			tc: &v1alpha1.TidbCluster{
+				Status: v1alpha1.TidbClusterStatus{TiKV: v1alpha1.TiKVStatus{
+					Groups: map[string]v1alpha1.TiKVStatus{"group": {DrainingStores: draining}},
+				}},
+			},
+			wantStatus: v1.ConditionTrue,
+		},
+		{
+			name: "tiflash draining",
+			tc: &v1alpha1.TidbCluster{
+				Status: v1alpha1.TidbClusterStatus{TiFlash: v1alpha1.TiFlashStatus{DrainingStores: draining}},
+			},
+			wantStatus: v1.ConditionTrue,
+		},
+		{
+			name:       "drained",
+			tc:         &v1alpha1.TidbCluster{Status: drained()},
+			wantStatus: v1.ConditionFalse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conditionUpdater := &tidbClusterConditionUpdater{}
+			conditionUpdater.Update(tt.tc)
+			var status v1.ConditionStatus
+			if cond := utiltidbcluster.GetTidbClusterCondition(tt.tc.Status, v1alpha1.TidbClusterNodeDraining); cond != nil {
+				status = cond.Status
+			}
+			if diff := cmp.Diff(tt.wantStatus, status); diff != "" {
+				t.Errorf("unexpected status (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestTidbClusterConditionUpdater_VolumeResizing(t *testing.T) {
+	resized := func() v1alpha1.TidbClusterStatus {
+		status := v1alpha1.TidbClusterStatus{}
+		cond := utiltidbcluster.NewTidbClusterCondition(v1alpha1.TidbClusterVolumeResizing, v1.ConditionTrue, utiltidbcluster.VolumesResizing, "")
+		utiltidbcluster.SetTidbClusterCondition(&status, *cond)
+		return status
+	}
+	tests := []struct {
+		name       string
+		tc         *v1alpha1.TidbCluster
+		wantStatus v1.ConditionStatus
+	}{
+		{
+			name:       "never resized",
+			tc:         &v1alpha1.TidbCluster{},
+			wantStatus: "",
+		},
+		{
+			name: "resizing",
+			tc: &v1alpha1.TidbCluster{
+				Status: v1alpha1.TidbClusterStatus{ResizingPVCs: map[string]v1alpha1.PVCResizeStatus{
+					"tikv-test-tikv-0": {PodName: "test-tikv-0", Phase: v1alpha1.PVCResizing},
+				}},
+			},
+			wantStatus: v1.ConditionTrue,
+		},
+		{
+			name:       "resized",
+			tc:         &v1alpha1.TidbCluster{Status: resized()},
+			wantStatus: v1.ConditionFalse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conditionUpdater := &tidbClusterConditionUpdater{}
+			conditionUpdater.Update(tt.tc)
+			var status v1.ConditionStatus
+			if cond := utiltidbcluster.GetTidbClusterCondition(tt.tc.Status, v1alpha1.TidbClusterVolumeResizing); cond != nil {
+				status = cond.Status
+			}
+			if diff := cmp.Diff(tt.wantStatus, status); diff != "" {
+				t.Errorf("unexpected status (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestTidbClusterConditionUpdater_Components(t *testing.T) {
+	newTidbCluster := func() *v1alpha1.TidbCluster {
+		upToDate := &appsv1.StatefulSetStatus{CurrentRevision: "2", UpdateRevision: "2", ReadyReplicas: 3}
+		return &v1alpha1.TidbCluster{
+			Spec: v1alpha1.TidbClusterSpec{
+				PD:   &v1alpha1.PDSpec{Replicas: 3},
+				TiKV: &v1alpha1.TiKVSpec{Replicas: 1},
+				TiDB: &v1alpha1.TiDBSpec{Replicas: 2},
+			},
+			Status: v1alpha1.TidbClusterStatus{
+				PD: v1alpha1.PDStatus{
+					Members: map[string]v1alpha1.PDMember{
+						"pd-0": {Health: true},
+						"pd-1": {Health: true},
+						"pd-2": {Health: true},
+					},
+					StatefulSet: upToDate,
+				},
+				TiKV: v1alpha1.TiKVStatus{
+					Stores:      map[string]v1alpha1.TiKVStore{"1": {State: v1alpha1.TiKVStateUp}},
+					StatefulSet: upToDate,
+				},
+				TiDB: v1alpha1.TiDBStatus{
+					Members: map[string]v1alpha1.TiDBMember{
+						"tidb-0": {Health: true},
+						"tidb-1": {Health: true},
+					},
+					StatefulSet: upToDate,
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name        string
+		update      func(*v1alpha1.TidbCluster)
+		wantReasons map[v1alpha1.TidbClusterConditionType]string
+	}{
+		{
+			name: "all healthy",
+			wantReasons: map[v1alpha1.TidbClusterConditionType]string{
+				v1alpha1.TidbClusterPDAvailable:       utiltidbcluster.PDAllMembersHealthy,
+				v1alpha1.TidbClusterTiKVStoresHealthy: utiltidbcluster.TiKVAllStoresUp,
+				v1alpha1.TidbClusterTiDBAvailable:     utiltidbcluster.TiDBAllMembersHealthy,
+				v1alpha1.TidbClusterUpgrading:         utiltidbcluster.NoComponentsUpgrading,
+				v1alpha1.TidbClusterScaling:           utiltidbcluster.NoComponentsScaling,
+				v1alpha1.TidbClusterFailoverActive:    utiltidbcluster.NoFailoverActive,
+				v1alpha1.TidbClusterConfigOutOfSync:   utiltidbcluster.ConfigInSync,
+			},
+		},
+		{
+			name: "members unhealthy",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.PD.Members["pd-2"] = v1alpha1.PDMember{Health: false}
+				tc.Status.TiKV.Stores["1"] = v1alpha1.TiKVStore{State: v1alpha1.TiKVStateDown}
+				tc.Status.TiDB.Members["tidb-1"] = v1alpha1.TiDBMember{Health: false}
+			},
+			wantReasons: map[v1alpha1.TidbClusterConditionType]string{
+				v1alpha1.TidbClusterPDAvailable:       utiltidbcluster.PDUnhealthy,
+				v1alpha1.TidbClusterTiKVStoresHealthy: utiltidbcluster.TiKVStoreNotUp,
+				v1alpha1.TidbClusterTiDBAvailable:     utiltidbcluster.TiDBUnhealthy,
+			},
+		},
+		{
+			name: "members unavailable",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.PD.Members["pd-1"] = v1alpha1.PDMember{Health: false}
+				tc.Status.PD.Members["pd-2"] = v1alpha1.PDMember{Health: false}
+				tc.Status.TiDB.Members["tidb-0"] = v1alpha1.TiDBMember{Health: false}
+				tc.Status.TiDB.Members["tidb-1"] = v1alpha1.TiDBMember{Health: false}
+			},
+			wantReasons: map[v1alpha1.TidbClusterConditionType]string{
+				v1alpha1.TidbClusterPDAvailable:   utiltidbcluster.PDQuorumUnavailable,
+				v1alpha1.TidbClusterTiDBAvailable: utiltidbcluster.TiDBNoMemberHealthy,
+			},
+		},
+		{
+			name: "group members unhealthy",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiKVGroups = []v1alpha1.TiKVGroupSpec{{Name: "group", TiKVSpec: v1alpha1.TiKVSpec{Replicas: 1}}}
+				tc.Status.TiKV.Groups = map[string]v1alpha1.TiKVStatus{
+					"group": {Stores: map[string]v1alpha1.TiKVStore{"2": {State: v1alpha1.TiKVStateDown}}},
+				}
+				tc.Spec.TiDBGroups = []v1alpha1.TiDBGroupSpec{{Name: "group", TiDBSpec: v1alpha1.TiDBSpec{Replicas: 1}}}
+				tc.Status.TiDB.Groups = map[string]v1alpha1.TiDBStatus{
+					"group": {Members: map[string]v1alpha1.TiDBMember{"tidb-group-0": {Health: false}}},
+				}
+			},
+			wantReasons: map[v1alpha1.TidbClusterConditionType]string{
+				v1alpha1.TidbClusterTiKVStoresHealthy: utiltidbcluster.TiKVStoreNotUp,
+				v1alpha1.TidbClusterTiDBAvailable:     utiltidbcluster.TiDBUnhealthy,
+			},
+		},
+		{
+			name: "config restart pending",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiDB.Config = &v1alpha1.ConfigStatus{RestartRevision: "2", RestartPending: true}
+			},
+			wantReasons: map[v1alpha1.TidbClusterConditionType]string{
+				v1alpha1.TidbClusterConfigOutOfSync: "TiDBConfigOutOfSync",
+			},
+		},
+		{
+			name: "operations in progress",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiKV.Phase = v1alpha1.UpgradePhase
+				tc.Status.TiKV.StatefulSet = &appsv1.StatefulSetStatus{CurrentRevision: "2", UpdateRevision: "3"}
+				tc.Status.TiDB.Groups = map[string]v1alpha1.TiDBStatus{"group": {Phase: v1alpha1.ScalePhase}}
+				tc.Status.PD.FailureMembers = map[string]v1alpha1.PDFailureMember{"pd-2": {PodName: "pd-2"}}
+			},
+			wantReasons: map[v1alpha1.TidbClusterConditionType]string{
+				v1alpha1.TidbClusterUpgrading:       "TiKVUpgrading",
+				v1alpha1.TidbClusterScaling:         "TiDBScaling",
+				v1alpha1.TidbClusterFailoverActive:  "PDFailoverActive",
+				v1alpha1.TidbClusterConfigOutOfSync: "TiKVConfigOutOfSync",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := newTidbCluster()
+			if tt.update != nil {
+				tt.update(tc)
+			}
+			conditionUpdater := &tidbClusterConditionUpdater{}
+			conditionUpdater.Update(tc)
+			for condType, wantReason := range tt.wantReasons {
+				cond := utiltidbcluster.GetTidbClusterCondition(tc.Status, condType)
+				if cond == nil {
+					t.Fatalf("condition %s is not set", condType)
+				}
+				if diff := cmp.Diff(wantReason, cond.Reason); diff != "" {
+					t.Errorf("unexpected reason of %s (-want, +got): %s", condType, diff)
+				}
+			}
+		})
+	}
+}
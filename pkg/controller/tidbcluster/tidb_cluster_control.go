@@ -18,8 +18,11 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1/defaulting"
 	v1alpha1validation "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1/validation"
 	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
 	"github.com/pingcap/tidb-operator/pkg/manager"
 	"github.com/pingcap/tidb-operator/pkg/manager/member"
+	"github.com/pingcap/tidb-operator/pkg/metrics"
+	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
 	v1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	errorutils "k8s.io/apimachinery/pkg/util/errors"
@@ -48,10 +51,17 @@ func NewDefaultTidbClusterControl(
 	pvcCleaner member.PVCCleanerInterface,
 	pvcResizer member.PVCResizerInterface,
 	pumpMemberManager manager.Manager,
+	drainerMemberManager manager.Manager,
 	tiflashMemberManager manager.Manager,
 	ticdcMemberManager manager.Manager,
 	discoveryManager member.TidbDiscoveryManager,
 	tidbClusterStatusManager manager.Manager,
+	suspender member.TidbClusterSuspender,
+	bootstrapper member.TidbClusterBootstrapper,
+	planner member.TidbClusterPlanner,
+	nodeDrainer member.NodeDrainer,
+	localPVRecoverer member.LocalPVRecoverer,
+	memberReplacer member.MemberReplacer,
 	conditionUpdater TidbClusterConditionUpdater,
 	recorder record.EventRecorder) ControlInterface {
 	return &defaultTidbClusterControl{
@@ -65,10 +75,17 @@ func NewDefaultTidbClusterControl(
 		pvcCleaner:               pvcCleaner,
 		pvcResizer:               pvcResizer,
 		pumpMemberManager:        pumpMemberManager,
+		drainerMemberManager:     drainerMemberManager,
 		tiflashMemberManager:     tiflashMemberManager,
 		ticdcMemberManager:       ticdcMemberManager,
 		discoveryManager:         discoveryManager,
 		tidbClusterStatusManager: tidbClusterStatusManager,
+		suspender:                suspender,
+		bootstrapper:             bootstrapper,
+		planner:                  planner,
+		nodeDrainer:              nodeDrainer,
+		localPVRecoverer:         localPVRecoverer,
+		memberReplacer:           memberReplacer,
 		conditionUpdater:         conditionUpdater,
 		recorder:                 recorder,
 	}
@@ -85,10 +102,17 @@ type defaultTidbClusterControl struct {
 	pvcCleaner               member.PVCCleanerInterface
 	pvcResizer               member.PVCResizerInterface
 	pumpMemberManager        manager.Manager
+	drainerMemberManager     manager.Manager
 	tiflashMemberManager     manager.Manager
 	ticdcMemberManager       manager.Manager
 	discoveryManager         member.TidbDiscoveryManager
 	tidbClusterStatusManager manager.Manager
+	suspender                member.TidbClusterSuspender
+	bootstrapper             member.TidbClusterBootstrapper
+	planner                  member.TidbClusterPlanner
+	nodeDrainer              member.NodeDrainer
+	localPVRecoverer         member.LocalPVRecoverer
+	memberReplacer           member.MemberReplacer
 	conditionUpdater         TidbClusterConditionUpdater
 	recorder                 record.EventRecorder
 }
@@ -103,12 +127,38 @@ func (c *defaultTidbClusterControl) UpdateTidbCluster(tc *v1alpha1.TidbCluster)
 	var errs []error
 	oldStatus := tc.Status.DeepCopy()
 
-	if err := c.updateTidbCluster(tc); err != nil {
-		errs = append(errs, err)
-	}
+	if tc.PlanOnly() {
+		// the spec is not applied in plan mode, only the actions expected to be taken are published
+		plan, err := c.planner.Plan(tc)
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			tc.Status.Plan = plan
+		}
 
-	if err := c.conditionUpdater.Update(tc); err != nil {
-		errs = append(errs, err)
+		if err := c.observeTidbCluster(tc); err != nil {
+			errs = append(errs, err)
+		}
+		observeUpgradePhases(tc)
+
+		if err := c.conditionUpdater.Update(tc); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		tc.Status.Plan = nil
+
+		// the operations still waiting for the maintenance window are recorded again in the sync
+		maintenancePending := utiltidbcluster.ResetMaintenancePending(&tc.Status)
+		err := c.updateTidbCluster(tc)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		utiltidbcluster.RestoreMaintenancePending(&tc.Status, maintenancePending, err == nil)
+		observeUpgradePhases(tc)
+
+		if err := c.conditionUpdater.Update(tc); err != nil {
+			errs = append(errs, err)
+		}
 	}
 
 	if apiequality.Semantic.DeepEqual(&tc.Status, oldStatus) {
@@ -121,6 +171,31 @@ func (c *defaultTidbClusterControl) UpdateTidbCluster(tc *v1alpha1.TidbCluster)
 	return errorutils.NewAggregate(errs)
 }
 
+// observeUpgradePhases records the durations of the upgrade phases of the components
+func observeUpgradePhases(tc *v1alpha1.TidbCluster) {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	phases := map[string]v1alpha1.MemberPhase{
+		label.PDLabelVal:      tc.Status.PD.Phase,
+		label.TiKVLabelVal:    tc.Status.TiKV.Phase,
+		label.TiFlashLabelVal: tc.Status.TiFlash.Phase,
+		label.TiDBLabelVal:    tc.Status.TiDB.Phase,
+		label.TiCDCLabelVal:   tc.Status.TiCDC.Phase,
+		label.PumpLabelVal:    tc.Status.Pump.Phase,
+		label.DrainerLabelVal: tc.Status.Drainer.Phase,
+	}
+	// the groups are observed as components named by the component and the group
+	for _, group := range tc.Spec.TiKVGroups {
+		phases[label.TiKVLabelVal+"/"+group.Name] = tc.Status.TiKV.Groups[group.Name].Phase
+	}
+	for _, group := range tc.Spec.TiDBGroups {
+		phases[label.TiDBLabelVal+"/"+group.Name] = tc.Status.TiDB.Groups[group.Name].Phase
+	}
+	for component, phase := range phases {
+		metrics.ObserveUpgradePhase(ns, tcName, component, phase == v1alpha1.UpgradePhase)
+	}
+}
+
 func (c *defaultTidbClusterControl) validate(tc *v1alpha1.TidbCluster) bool {
 	errs := v1alpha1validation.ValidateTidbCluster(tc)
 	if len(errs) > 0 {
@@ -158,6 +233,19 @@ func (c *defaultTidbClusterControl) updateTidbCluster(tc *v1alpha1.TidbCluster)
 		return err
 	}
 
+	// scale the components to zero or back when the tidb cluster is suspended
+	// or resumed, the member managers are skipped to keep failover off until
+	// all the components are resumed
+	if suspended, err := c.suspender.Sync(tc); err != nil || suspended {
+		return err
+	}
+
+	// restore the backup data to a new tidb cluster once it is ready, the
+	// tidb service is not created until the restore completes
+	if err := c.bootstrapper.Sync(tc); err != nil {
+		return err
+	}
+
 	// works that should do to making the pd cluster current state match the desired state:
 	//   - create or update the pd service
 	//   - create or update the pd headless service
@@ -191,6 +279,11 @@ func (c *defaultTidbClusterControl) updateTidbCluster(tc *v1alpha1.TidbCluster)
 		return err
 	}
 
+	// syncing the drainer cluster
+	if err := c.drainerMemberManager.Sync(tc); err != nil {
+		return err
+	}
+
 	// works that should do to making the tidb cluster current state match the desired state:
 	//   - waiting for the tikv cluster available(at least one peer works)
 	//   - create or update tidb headless service
@@ -223,6 +316,25 @@ func (c *defaultTidbClusterControl) updateTidbCluster(tc *v1alpha1.TidbCluster)
 		return err
 	}
 
+	// moving the TiKV and TiFlash stores off the nodes being drained:
+	//   - evict the leaders of the stores on the nodes
+	//   - migrate the stores on the local volumes of the nodes to other nodes
+	if err := c.nodeDrainer.Sync(tc); err != nil {
+		return err
+	}
+
+	// recreating the PD members and TiKV stores whose local volumes are lost
+	// with their nodes on other nodes, only if the LocalPVRecovery feature is enabled
+	if err := c.localPVRecoverer.Sync(tc); err != nil {
+		return err
+	}
+
+	// recreating the PD members and TiKV stores whose pods are annotated to be
+	// replaced on fresh storage with the same ordinals
+	if err := c.memberReplacer.Sync(tc); err != nil {
+		return err
+	}
+
 	// syncing the labels from Pod to PVC and PV, these labels include:
 	//   - label.StoreIDLabelKey
 	//   - label.MemberIDLabelKey
@@ -252,6 +364,33 @@ func (c *defaultTidbClusterControl) updateTidbCluster(tc *v1alpha1.TidbCluster)
 	return c.tidbClusterStatusManager.Sync(tc)
 }
 
+// observeTidbCluster syncs the status of a tidb cluster without writing
+// anything else, so the status is kept up to date in plan mode
+func (c *defaultTidbClusterControl) observeTidbCluster(tc *v1alpha1.TidbCluster) error {
+	// the member managers only sync the status of a paused tidb cluster,
+	// the services, configmaps and statefulsets are left as they are
+	paused := *tc
+	paused.Spec.Paused = true
+	defer func() {
+		tc.Status = paused.Status
+	}()
+	for _, m := range []manager.Manager{
+		c.pdMemberManager,
+		c.tikvMemberManager,
+		c.pumpMemberManager,
+		c.drainerMemberManager,
+		c.tidbMemberManager,
+		c.tiflashMemberManager,
+		c.ticdcMemberManager,
+	} {
+		if err := m.Sync(&paused); err != nil {
+			return err
+		}
+	}
+
+	return c.tidbClusterStatusManager.Sync(&paused)
+}
+
 var _ ControlInterface = &defaultTidbClusterControl{}
 
 type FakeTidbClusterControlInterface struct {
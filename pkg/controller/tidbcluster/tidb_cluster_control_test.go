@@ -23,6 +23,7 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned/fake"
 	informers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions"
 	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
 	mm "github.com/pingcap/tidb-operator/pkg/manager/member"
 	"github.com/pingcap/tidb-operator/pkg/manager/meta"
 	apps "k8s.io/api/apps/v1"
@@ -48,7 +49,9 @@ func TestTidbClusterControlUpdateTidbCluster(t *testing.T) {
 		syncMetaManagerErr       bool
 		pvcCleanerErr            bool
 		updateTCStatusErr        bool
+		suspended                bool
 		errExpectFn              func(*GomegaWithT, error)
+		expectFn                 func(*GomegaWithT, *v1alpha1.TidbCluster)
 	}
 	testFn := func(test *testcase, t *testing.T) {
 		t.Log(test.name)
@@ -84,11 +87,17 @@ func TestTidbClusterControlUpdateTidbCluster(t *testing.T) {
 		if test.updateTCStatusErr {
 			tcUpdater.SetUpdateTidbClusterError(fmt.Errorf("update tidbcluster status error"), 0)
 		}
+		if test.suspended {
+			control.(*defaultTidbClusterControl).suspender.(*mm.FakeTidbClusterSuspender).SetSuspended(true)
+		}
 
 		err := control.UpdateTidbCluster(tc)
 		if test.errExpectFn != nil {
 			test.errExpectFn(g, err)
 		}
+		if test.expectFn != nil {
+			test.expectFn(g, tc)
+		}
 	}
 	tests := []testcase{
 		{
@@ -274,6 +283,63 @@ func TestTidbClusterControlUpdateTidbCluster(t *testing.T) {
 				g.Expect(err).NotTo(HaveOccurred())
 			},
 		},
+		{
+			name: "plan only",
+			update: func(cluster *v1alpha1.TidbCluster) {
+				cluster.Annotations = map[string]string{label.AnnPlanKey: label.AnnPlanVal}
+				cluster.Status.PD.Members = map[string]v1alpha1.PDMember{
+					"pd-0": {Name: "pd-0", Health: true},
+				}
+			},
+			suspended: true,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster) {
+				g.Expect(tc.Status.Plan).NotTo(BeNil())
+				// the status is still synced in plan mode
+				g.Expect(tc.Status.ClusterID).NotTo(BeEmpty())
+				g.Expect(tc.Spec.Paused).To(BeFalse())
+			},
+		},
+		{
+			name: "plan only with member manager sync error",
+			update: func(cluster *v1alpha1.TidbCluster) {
+				cluster.Annotations = map[string]string{label.AnnPlanKey: label.AnnPlanVal}
+			},
+			syncPDMemberManagerErr: true,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(strings.Contains(err.Error(), "pd member manager sync error")).To(Equal(true))
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster) {
+				g.Expect(tc.Status.Plan).NotTo(BeNil())
+			},
+		},
+		{
+			name: "plan only does not reclaim or clean anything",
+			update: func(cluster *v1alpha1.TidbCluster) {
+				cluster.Annotations = map[string]string{label.AnnPlanKey: label.AnnPlanVal}
+			},
+			syncReclaimPolicyErr: true,
+			orphanPodCleanerErr:  true,
+			syncMetaManagerErr:   true,
+			pvcCleanerErr:        true,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster) {
+				g.Expect(tc.Status.Plan).NotTo(BeNil())
+			},
+		},
+		{
+			name:                   "suspended",
+			syncPDMemberManagerErr: true,
+			suspended:              true,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+		},
 	}
 
 	for i := range tests {
@@ -317,6 +383,7 @@ func newFakeTidbClusterControl() (
 	orphanPodCleaner := mm.NewFakeOrphanPodsCleaner()
 	pvcCleaner := mm.NewFakePVCCleaner()
 	pumpMemberManager := mm.NewFakePumpMemberManager()
+	drainerMemberManager := mm.NewFakeDrainerMemberManager()
 	tiflashMemberManager := mm.NewFakeTiFlashMemberManager()
 	ticdcMemberManager := mm.NewFakeTiCDCMemberManager()
 	discoveryManager := mm.NewFakeDiscoveryManger()
@@ -333,10 +400,17 @@ func newFakeTidbClusterControl() (
 		pvcCleaner,
 		pvcResizer,
 		pumpMemberManager,
+		drainerMemberManager,
 		tiflashMemberManager,
 		ticdcMemberManager,
 		discoveryManager,
 		statusManager,
+		mm.NewFakeTidbClusterSuspender(),
+		mm.NewFakeTidbClusterBootstrapper(),
+		mm.NewFakeTidbClusterPlanner(),
+		mm.NewFakeNodeDrainer(),
+		mm.NewFakeLocalPVRecoverer(),
+		mm.NewFakeMemberReplacer(),
 		&tidbClusterConditionUpdater{},
 		recorder,
 	)
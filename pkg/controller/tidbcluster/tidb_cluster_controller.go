@@ -20,9 +20,13 @@ import (
 	perrors "github.com/pingcap/errors"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
 	mm "github.com/pingcap/tidb-operator/pkg/manager/member"
 	"github.com/pingcap/tidb-operator/pkg/manager/meta"
+	"github.com/pingcap/tidb-operator/pkg/metrics"
 	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -56,11 +60,18 @@ func NewController(deps *controller.Dependencies) *Controller {
 			mm.NewOrphanPodsCleaner(deps),
 			mm.NewRealPVCCleaner(deps),
 			mm.NewPVCResizer(deps),
-			mm.NewPumpMemberManager(deps),
+			mm.NewPumpMemberManager(deps, mm.NewPumpScaler(deps)),
+			mm.NewDrainerMemberManager(deps, mm.NewDrainerScaler(deps)),
 			mm.NewTiFlashMemberManager(deps, mm.NewTiFlashFailover(deps), mm.NewTiFlashScaler(deps), mm.NewTiFlashUpgrader(deps)),
-			mm.NewTiCDCMemberManager(deps),
+			mm.NewTiCDCMemberManager(deps, mm.NewTiCDCScaler(deps), mm.NewTiCDCUpgrader(deps)),
 			mm.NewTidbDiscoveryManager(deps),
 			mm.NewTidbClusterStatusManager(deps),
+			mm.NewTidbClusterSuspender(deps),
+			mm.NewTidbClusterBootstrapper(deps),
+			mm.NewTidbClusterPlanner(deps),
+			mm.NewNodeDrainer(deps),
+			mm.NewLocalPVRecoverer(deps),
+			mm.NewMemberReplacer(deps),
 			&tidbClusterConditionUpdater{},
 			deps.Recorder,
 		),
@@ -83,6 +94,10 @@ func NewController(deps *controller.Dependencies) *Controller {
 		},
 		DeleteFunc: c.deleteStatefulSet,
 	})
+	// the TiKV and TiFlash stores are moved off the nodes being drained
+	deps.KubeInformerFactory.Core().V1().Nodes().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: c.updateNode,
+	})
 
 	return c
 }
@@ -116,7 +131,10 @@ func (c *Controller) processNextWorkItem() bool {
 		return false
 	}
 	defer c.queue.Done(key)
-	if err := c.sync(key.(string)); err != nil {
+	startTime := time.Now()
+	err := c.sync(key.(string))
+	controller.ObserveReconcile("tidbcluster", key.(string), startTime, err)
+	if err != nil {
 		if perrors.Find(err, controller.IsRequeueError) != nil {
 			klog.Infof("TidbCluster: %v, still need sync: %v, requeuing", key.(string), err)
 		} else {
@@ -143,6 +161,8 @@ func (c *Controller) sync(key string) error {
 	tc, err := c.deps.TiDBClusterLister.TidbClusters(ns).Get(name)
 	if errors.IsNotFound(err) {
 		klog.Infof("TidbCluster has been deleted %v", key)
+		controller.ForgetReconcile("tidbcluster", key)
+		metrics.DeleteClusterMetrics(ns, name)
 		return nil
 	}
 	if err != nil {
@@ -240,6 +260,45 @@ func (c *Controller) deleteStatefulSet(obj interface{}) {
 	c.enqueueTidbCluster(tc)
 }
 
+// updateNode enqueues the tidbclusters with TiKV or TiFlash pods on the node when
+// the node is cordoned, uncordoned or its taints are changed
+func (c *Controller) updateNode(old, cur interface{}) {
+	oldNode := old.(*corev1.Node)
+	curNode := cur.(*corev1.Node)
+	if curNode.ResourceVersion == oldNode.ResourceVersion {
+		return
+	}
+	if oldNode.Spec.Unschedulable == curNode.Spec.Unschedulable && apiequality.Semantic.DeepEqual(oldNode.Spec.Taints, curNode.Spec.Taints) {
+		return
+	}
+
+	selector, err := label.New().Selector()
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	pods, err := c.deps.PodLister.List(selector)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to list pods on node %s: %v", curNode.Name, err))
+		return
+	}
+	for _, pod := range pods {
+		if pod.Spec.NodeName != curNode.Name {
+			continue
+		}
+		l := label.Label(pod.Labels)
+		if !l.IsTiKV() && !l.IsTiFlash() {
+			continue
+		}
+		tcName := pod.Labels[label.InstanceLabelKey]
+		if tcName == "" {
+			continue
+		}
+		klog.V(4).Infof("Node %s updated, TidbCluster: %s/%s", curNode.Name, pod.Namespace, tcName)
+		c.queue.Add(fmt.Sprintf("%s/%s", pod.Namespace, tcName))
+	}
+}
+
 // resolveTidbClusterFromSet returns the TidbCluster by a StatefulSet,
 // or nil if the StatefulSet could not be resolved to a matching TidbCluster
 // of the correct Kind.
@@ -22,6 +22,7 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -194,6 +195,78 @@ func TestTidbClusterControllerUpdateStatefulSet(t *testing.T) {
 	}
 }
 
+func TestTidbClusterControllerUpdateNode(t *testing.T) {
+	g := NewGomegaWithT(t)
+	type testcase struct {
+		name        string
+		updateNode  func(*corev1.Node)
+		podLabels   label.Label
+		expectedLen int
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log("test: ", test.name)
+
+		node1 := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", ResourceVersion: "1"}}
+		node2 := node1.DeepCopy()
+		node2.ResourceVersion = "2"
+		test.updateNode(node2)
+
+		fakeDeps := controller.NewFakeDependencies()
+		tcc := NewController(fakeDeps)
+		tcc.control = NewFakeTidbClusterControlInterface()
+		podIndexer := fakeDeps.KubeInformerFactory.Core().V1().Pods().Informer().GetIndexer()
+		err := podIndexer.Add(&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-tikv-0",
+				Namespace: corev1.NamespaceDefault,
+				Labels:    test.podLabels,
+			},
+			Spec: corev1.PodSpec{NodeName: node1.Name},
+		})
+		g.Expect(err).NotTo(HaveOccurred())
+		tcc.updateNode(node1, node2)
+		g.Expect(tcc.queue.Len()).To(Equal(test.expectedLen))
+	}
+
+	tests := []testcase{
+		{
+			name: "cordoned",
+			updateNode: func(node *corev1.Node) {
+				node.Spec.Unschedulable = true
+			},
+			podLabels:   label.New().Instance("test").TiKV(),
+			expectedLen: 1,
+		},
+		{
+			name: "tainted",
+			updateNode: func(node *corev1.Node) {
+				node.Spec.Taints = []corev1.Taint{{Key: "maintenance", Effect: corev1.TaintEffectNoSchedule}}
+			},
+			podLabels:   label.New().Instance("test").TiFlash(),
+			expectedLen: 1,
+		},
+		{
+			name:        "not drained",
+			updateNode:  func(node *corev1.Node) {},
+			podLabels:   label.New().Instance("test").TiKV(),
+			expectedLen: 0,
+		},
+		{
+			name: "no stores on the node",
+			updateNode: func(node *corev1.Node) {
+				node.Spec.Unschedulable = true
+			},
+			podLabels:   label.New().Instance("test").PD(),
+			expectedLen: 0,
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
 func TestTidbClusterControllerSync(t *testing.T) {
 	g := NewGomegaWithT(t)
 	type testcase struct {
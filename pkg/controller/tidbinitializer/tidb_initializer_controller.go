@@ -87,7 +87,10 @@ func (c *Controller) processNextWorkItem() bool {
 		return false
 	}
 	defer c.queue.Done(key)
-	if err := c.sync(key.(string)); err != nil {
+	startTime := time.Now()
+	err := c.sync(key.(string))
+	controller.ObserveReconcile("tidbinitializer", key.(string), startTime, err)
+	if err != nil {
 		if perrors.Find(err, controller.IsRequeueError) != nil {
 			klog.Infof("TiDBInitializer: %v, still need sync: %v, requeuing", key.(string), err)
 		} else {
@@ -113,6 +116,7 @@ func (c *Controller) sync(key string) error {
 	ti, err := c.deps.TiDBInitializerLister.TidbInitializers(ns).Get(name)
 	if errors.IsNotFound(err) {
 		klog.Infof("TiDBInitializer %v has been deleted", key)
+		controller.ForgetReconcile("tidbinitializer", key)
 		return nil
 	}
 	if err != nil {
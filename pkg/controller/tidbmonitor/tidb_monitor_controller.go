@@ -82,7 +82,10 @@ func (c *Controller) processNextWorkItem() bool {
 		return false
 	}
 	defer c.queue.Done(key)
-	if err := c.sync(key.(string)); err != nil {
+	startTime := time.Now()
+	err := c.sync(key.(string))
+	controller.ObserveReconcile("tidbmonitor", key.(string), startTime, err)
+	if err != nil {
 		if perrors.Find(err, controller.IsRequeueError) != nil {
 			klog.Infof("TidbMonitor: %v, still need sync: %v, requeuing", key.(string), err)
 		} else {
@@ -108,6 +111,7 @@ func (c *Controller) sync(key string) error {
 	tm, err := c.deps.TiDBMonitorLister.TidbMonitors(ns).Get(name)
 	if errors.IsNotFound(err) {
 		klog.Infof("TidbMonitor has been deleted %v", key)
+		controller.ForgetReconcile("tidbmonitor", key)
 		return nil
 	}
 	if err != nil {
@@ -0,0 +1,97 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	httputil "github.com/pingcap/tidb-operator/pkg/util/http"
+	"k8s.io/client-go/kubernetes"
+)
+
+// TiKVControlInterface is the interface that knows how to manage tikv peers through their status port
+type TiKVControlInterface interface {
+	// UpdateConfig updates the online changeable config items of the TiKV instance,
+	// the items are keyed by their dotted names, e.g. raftstore.sync-log
+	UpdateConfig(tc *v1alpha1.TidbCluster, ordinal int32, items map[string]interface{}) error
+}
+
+// defaultTiKVControl is default implementation of TiKVControlInterface.
+type defaultTiKVControl struct {
+	httpClient
+	// for unit test only
+	testURL string
+}
+
+// NewDefaultTiKVControl returns a defaultTiKVControl instance
+func NewDefaultTiKVControl(kubeCli kubernetes.Interface) *defaultTiKVControl {
+	return &defaultTiKVControl{httpClient: httpClient{kubeCli: kubeCli}}
+}
+
+func (c *defaultTiKVControl) UpdateConfig(tc *v1alpha1.TidbCluster, ordinal int32, items map[string]interface{}) error {
+	httpClient, err := c.getHTTPClient(tc)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/config", c.getBaseURL(tc, ordinal))
+	_, err = httputil.PostBodyOK(httpClient, url, bytes.NewBuffer(data))
+	return err
+}
+
+func (c *defaultTiKVControl) getBaseURL(tc *v1alpha1.TidbCluster, ordinal int32) string {
+	if c.testURL != "" {
+		return c.testURL
+	}
+
+	tcName := tc.GetName()
+	ns := tc.GetNamespace()
+	scheme := tc.Scheme()
+	hostName := fmt.Sprintf("%s-%d", TiKVGroupMemberName(tcName, tc.TiKVGroupName()), ordinal)
+
+	return fmt.Sprintf("%s://%s.%s.%s:20180", scheme, hostName, TiKVPeerMemberName(tcName), ns)
+}
+
+// FakeTiKVControl is a fake implementation of TiKVControlInterface.
+type FakeTiKVControl struct {
+	// UpdatedConfig records the config items updated, keyed by pod name
+	UpdatedConfig     map[string]map[string]interface{}
+	updateConfigError error
+}
+
+// NewFakeTiKVControl returns a FakeTiKVControl instance
+func NewFakeTiKVControl() *FakeTiKVControl {
+	return &FakeTiKVControl{UpdatedConfig: map[string]map[string]interface{}{}}
+}
+
+// SetUpdateConfigError sets the error returned by UpdateConfig
+func (c *FakeTiKVControl) SetUpdateConfigError(err error) {
+	c.updateConfigError = err
+}
+
+func (c *FakeTiKVControl) UpdateConfig(tc *v1alpha1.TidbCluster, ordinal int32, items map[string]interface{}) error {
+	if c.updateConfigError != nil {
+		return c.updateConfigError
+	}
+	podName := fmt.Sprintf("%s-%d", TiKVGroupMemberName(tc.GetName(), tc.TiKVGroupName()), ordinal)
+	c.UpdatedConfig[podName] = items
+	return nil
+}
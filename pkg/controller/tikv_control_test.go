@@ -0,0 +1,68 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestTiKVControlUpdateConfig(t *testing.T) {
+	g := NewGomegaWithT(t)
+	cases := []struct {
+		caseName string
+		failed   bool
+	}{
+		{
+			caseName: "UpdateConfig",
+			failed:   false,
+		},
+		{
+			caseName: "UpdateConfig failed",
+			failed:   true,
+		},
+	}
+	for _, c := range cases {
+		svc := getClientServer(func(w http.ResponseWriter, request *http.Request) {
+			g.Expect(request.Method).To(Equal("POST"), "check method")
+			g.Expect(request.URL.Path).To(Equal("/config"), "check url")
+			body, err := ioutil.ReadAll(request.Body)
+			g.Expect(err).NotTo(HaveOccurred())
+			items := map[string]interface{}{}
+			g.Expect(json.Unmarshal(body, &items)).To(Succeed())
+			g.Expect(items).To(HaveKeyWithValue("raftstore.sync-log", false))
+			if c.failed {
+				w.WriteHeader(http.StatusInternalServerError)
+			} else {
+				w.WriteHeader(http.StatusOK)
+			}
+		})
+		defer svc.Close()
+		fakeClient := &fake.Clientset{}
+		control := NewDefaultTiKVControl(fakeClient)
+		control.testURL = svc.URL
+		tc := getTidbCluster()
+		err := control.UpdateConfig(tc, 0, map[string]interface{}{"raftstore.sync-log": false})
+		if c.failed {
+			g.Expect(err).To(HaveOccurred(), c.caseName)
+		} else {
+			g.Expect(err).NotTo(HaveOccurred(), c.caseName)
+		}
+	}
+}
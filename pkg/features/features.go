@@ -31,6 +31,7 @@ var (
 		StableScheduling:    true,
 		AdvancedStatefulSet: false,
 		AutoScaling:         false,
+		LocalPVRecovery:     false,
 	}
 	// DefaultFeatureGate is a shared global FeatureGate.
 	DefaultFeatureGate FeatureGate = NewDefaultFeatureGate()
@@ -45,6 +46,10 @@ const (
 
 	// AutoScaling controls whether to use TidbClusterAutoScaler to auto scale-in/out pods
 	AutoScaling string = "AutoScaling"
+
+	// LocalPVRecovery controls whether to recreate the PD members and TiKV stores
+	// on other nodes when their local volumes are lost with the nodes
+	LocalPVRecovery string = "LocalPVRecovery"
 )
 
 type FeatureGate interface {
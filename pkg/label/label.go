@@ -37,6 +37,9 @@ const (
 
 	// NamespaceLabelKey is label key used in PV for easy querying
 	NamespaceLabelKey string = "app.kubernetes.io/namespace"
+	// TiDBServingLabelKey indicates whether a tidb pod is selected by the tidb
+	// service, it is set to false when draining the connections of the pod
+	TiDBServingLabelKey string = "tidb.pingcap.com/serving"
 	// UsedByLabelKey indicate where it is used. for example, tidb has two services,
 	// one for internal component access and the other for end-user
 	UsedByLabelKey string = "app.kubernetes.io/used-by"
@@ -62,6 +65,14 @@ const (
 	// BackupProtectionFinalizer is the name of finalizer on backups
 	BackupProtectionFinalizer string = "tidb.pingcap.com/backup-protection"
 
+	// TiCDCChangefeedFinalizer is the name of finalizer on ticdc changefeeds,
+	// it makes sure the changefeed is removed from TiCDC before the object is deleted
+	TiCDCChangefeedFinalizer string = "tidb.pingcap.com/ticdc-changefeed"
+
+	// PlacementRuleFinalizer is the name of finalizer on placement rules,
+	// it makes sure the rules are removed from PD before the object is deleted
+	PlacementRuleFinalizer string = "tidb.pingcap.com/placement-rule"
+
 	// AutoScalingGroupLabelKey describes the autoscaling group of the TiDB
 	AutoScalingGroupLabelKey = "tidb.pingcap.com/autoscaling-group"
 	// AutoInstanceLabelKey is label key used in autoscaling, it represents the autoscaler name
@@ -70,6 +81,13 @@ const (
 	AutoComponentLabelKey string = "tidb.pingcap.com/auto-component"
 	// BaseTCLabelKey is label key used for heterogeneous clusters to refer to its base TidbCluster
 	BaseTCLabelKey string = "tidb.pingcap.com/base-tc"
+	// TiKVGroupLabelKey is label key used to distinguish the TiKV groups of a TidbCluster
+	TiKVGroupLabelKey string = "tidb.pingcap.com/tikv-group"
+	// TiDBGroupLabelKey is label key used to distinguish the TiDB groups of a TidbCluster
+	TiDBGroupLabelKey string = "tidb.pingcap.com/tidb-group"
+	// TiDBDefaultGroupLabelKey is label key used to distinguish the pods of the
+	// default TiDB from the ones of the TiDB groups
+	TiDBDefaultGroupLabelKey string = "tidb.pingcap.com/tidb-default-group"
 
 	// AnnHATopologyKey defines the High availability topology key
 	AnnHATopologyKey = "pingcap.com/ha-topology-key"
@@ -97,11 +115,31 @@ const (
 	AnnEvictLeaderBeginTime = "tidb.pingcap.com/evictLeaderBeginTime"
 	// AnnStsLastSyncTimestamp is sts annotation key to indicate the last timestamp the operator sync the sts
 	AnnStsLastSyncTimestamp = "tidb.pingcap.com/sync-timestamp"
+	// AnnConfigRestartRevision is pod template annotation key to indicate the revision of the config
+	// which needs the pods to be rolled, it changes only when config items that can not be changed online are changed
+	AnnConfigRestartRevision = "tidb.pingcap.com/config-restart-revision"
+	// AnnSuspendedReplicas is sts annotation key to keep the replicas of the statefulset
+	// before it is scaled to zero for the hibernation of the tidb cluster
+	AnnSuspendedReplicas = "tidb.pingcap.com/suspended-replicas"
+	// AnnPlanKey is tc annotation key to indicate the spec should not be applied, only the
+	// actions expected to be taken are published in the status, the status of the
+	// components is still synced as if the tidb cluster is paused
+	AnnPlanKey = "tidb.pingcap.com/plan"
+	// AnnBootstrapFromHash is restore annotation key to keep the hash of the spec.bootstrapFrom
+	// of the tidb cluster the restore is created to bootstrap
+	AnnBootstrapFromHash = "tidb.pingcap.com/bootstrap-from-hash"
+	// AnnReplaceKey is pd and tikv pod annotation key to indicate the member should be
+	// deleted from the cluster and recreated on fresh storage with the same ordinal
+	AnnReplaceKey = "tidb.pingcap.com/replace"
 
 	// AnnForceUpgradeVal is tc annotation value to indicate whether force upgrade should be done
 	AnnForceUpgradeVal = "true"
+	// AnnPlanVal is tc annotation value to indicate the spec should not be applied
+	AnnPlanVal = "true"
 	// AnnSysctlInitVal is pod annotation value to indicate whether configuring sysctls with init container
 	AnnSysctlInitVal = "true"
+	// AnnReplaceVal is pod annotation value to indicate the member should be replaced
+	AnnReplaceVal = "true"
 
 	// AnnPDDeleteSlots is annotation key of pd delete slots.
 	AnnPDDeleteSlots = "pd.tidb.pingcap.com/delete-slots"
@@ -116,6 +154,29 @@ const (
 	// AnnDMWorkerDeleteSlots is annotation key of dm-worker delete slots.
 	AnnDMWorkerDeleteSlots = "dm-worker.tidb.pingcap.com/delete-slots"
 
+	// AnnPDUpgradeApproved is annotation key to approve the canary upgrade of pd
+	AnnPDUpgradeApproved = "pd.tidb.pingcap.com/upgrade-approved"
+	// AnnTiDBUpgradeApproved is annotation key to approve the canary upgrade of tidb
+	AnnTiDBUpgradeApproved = "tidb.tidb.pingcap.com/upgrade-approved"
+	// AnnTiKVUpgradeApproved is annotation key to approve the canary upgrade of tikv
+	AnnTiKVUpgradeApproved = "tikv.tidb.pingcap.com/upgrade-approved"
+
+	// AnnRestartedAt is tc annotation key to restart the pods of all the components gracefully,
+	// the pods are rolled through the upgraders whenever the value changes
+	AnnRestartedAt = "tidb.pingcap.com/restartedAt"
+	// AnnPDRestartedAt is tc annotation key to restart the pods of pd gracefully
+	AnnPDRestartedAt = "pd.tidb.pingcap.com/restartedAt"
+	// AnnTiKVRestartedAt is tc annotation key to restart the pods of tikv gracefully
+	AnnTiKVRestartedAt = "tikv.tidb.pingcap.com/restartedAt"
+	// AnnTiDBRestartedAt is tc annotation key to restart the pods of tidb gracefully
+	AnnTiDBRestartedAt = "tidb.tidb.pingcap.com/restartedAt"
+	// AnnTiFlashRestartedAt is tc annotation key to restart the pods of tiflash gracefully
+	AnnTiFlashRestartedAt = "tiflash.tidb.pingcap.com/restartedAt"
+	// AnnTiCDCRestartedAt is tc annotation key to restart the pods of ticdc gracefully
+	AnnTiCDCRestartedAt = "ticdc.tidb.pingcap.com/restartedAt"
+	// AnnPumpRestartedAt is tc annotation key to restart the pods of pump gracefully
+	AnnPumpRestartedAt = "pump.tidb.pingcap.com/restartedAt"
+
 	// AnnTiKVAutoScalingOutOrdinals describe the tikv pods' ordinal list which is created by auto-scaling out
 	AnnTiKVAutoScalingOutOrdinals = "tikv.tidb.pingcap.com/scale-out-ordinals"
 	// AnnTiDBAutoScalingOutOrdinals describe the tidb pods' ordinal list which is created by auto-scaling out
@@ -136,6 +197,8 @@ const (
 	TiCDCLabelVal string = "ticdc"
 	// PumpLabelVal is Pump label value
 	PumpLabelVal string = "pump"
+	// DrainerLabelVal is Drainer label value
+	DrainerLabelVal string = "drainer"
 	// DiscoveryLabelVal is Discovery label value
 	DiscoveryLabelVal string = "discovery"
 	// TiDBMonitorVal is Monitor label value
@@ -332,6 +395,16 @@ func (l Label) IsPump() bool {
 	return l[ComponentLabelKey] == PumpLabelVal
 }
 
+// Drainer assigns drainer to component key in label
+func (l Label) Drainer() Label {
+	return l.Component(DrainerLabelVal)
+}
+
+// IsDrainer returns whether label is a Drainer component
+func (l Label) IsDrainer() bool {
+	return l[ComponentLabelKey] == DrainerLabelVal
+}
+
 // DMMaster assigns dm-master to component key in label
 func (l Label) DMMaster() Label {
 	return l.Component(DMMasterLabelVal)
@@ -382,6 +455,18 @@ func (l Label) TiKV() Label {
 	return l.Component(TiKVLabelVal)
 }
 
+// TiKVGroup adds tikv group kv pair to label
+func (l Label) TiKVGroup(name string) Label {
+	l[TiKVGroupLabelKey] = name
+	return l
+}
+
+// TiDBGroup adds tidb group kv pair to label
+func (l Label) TiDBGroup(name string) Label {
+	l[TiDBGroupLabelKey] = name
+	return l
+}
+
 // IsTiKV returns whether label is a TiKV component
 func (l Label) IsTiKV() bool {
 	return l[ComponentLabelKey] == TiKVLabelVal
@@ -0,0 +1,370 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+)
+
+const (
+	// labelTopologyZone is the stable zone label of the nodes
+	labelTopologyZone = "topology.kubernetes.io/zone"
+)
+
+// canaryUpgrade gates the rolling upgrade of a component after the canary
+// pods are upgraded, watches the health of the canary pods during the bake
+// time and rolls them back if they become unhealthy before the upgrade is
+// approved.
+type canaryUpgrade struct {
+	deps       *controller.Dependencies
+	tc         *v1alpha1.TidbCluster
+	memberType v1alpha1.MemberType
+	strategy   *v1alpha1.CanaryUpgradeStrategy
+	// status points to the canary status field of the component status
+	status       **v1alpha1.CanaryUpgradeStatus
+	setStatus    *apps.StatefulSetStatus
+	annApproved  string
+	podName      func(ordinal int32) string
+	isPodHealthy func(ordinal int32) bool
+}
+
+func newPDCanaryUpgrade(deps *controller.Dependencies, tc *v1alpha1.TidbCluster) *canaryUpgrade {
+	tcName := tc.GetName()
+	return &canaryUpgrade{
+		deps:        deps,
+		tc:          tc,
+		memberType:  v1alpha1.PDMemberType,
+		strategy:    tc.BasePDSpec().CanaryUpgradeStrategy(),
+		status:      &tc.Status.PD.Canary,
+		setStatus:   tc.Status.PD.StatefulSet,
+		annApproved: label.AnnPDUpgradeApproved,
+		podName: func(ordinal int32) string {
+			return PdPodName(tcName, ordinal)
+		},
+		isPodHealthy: func(ordinal int32) bool {
+			member, exist := tc.Status.PD.Members[PdName(tcName, ordinal, tc.Namespace, tc.Spec.ClusterDomain)]
+			return exist && member.Health
+		},
+	}
+}
+
+func newTiKVCanaryUpgrade(deps *controller.Dependencies, tc *v1alpha1.TidbCluster) *canaryUpgrade {
+	return &canaryUpgrade{
+		deps:        deps,
+		tc:          tc,
+		memberType:  v1alpha1.TiKVMemberType,
+		strategy:    tc.BaseTiKVSpec().CanaryUpgradeStrategy(),
+		status:      &tc.Status.TiKV.Canary,
+		setStatus:   tc.Status.TiKV.StatefulSet,
+		annApproved: label.AnnTiKVUpgradeApproved,
+		podName: func(ordinal int32) string {
+			return tikvGroupPodName(tc, ordinal)
+		},
+		isPodHealthy: func(ordinal int32) bool {
+			podName := tikvGroupPodName(tc, ordinal)
+			for _, store := range tikvGroupStores(tc) {
+				if store.PodName == podName {
+					return store.State == v1alpha1.TiKVStateUp
+				}
+			}
+			return false
+		},
+	}
+}
+
+func newTiDBCanaryUpgrade(deps *controller.Dependencies, tc *v1alpha1.TidbCluster) *canaryUpgrade {
+	return &canaryUpgrade{
+		deps:        deps,
+		tc:          tc,
+		memberType:  v1alpha1.TiDBMemberType,
+		strategy:    tc.BaseTiDBSpec().CanaryUpgradeStrategy(),
+		status:      &tc.Status.TiDB.Canary,
+		setStatus:   tc.Status.TiDB.StatefulSet,
+		annApproved: label.AnnTiDBUpgradeApproved,
+		podName: func(ordinal int32) string {
+			return tidbGroupPodName(tc, ordinal)
+		},
+		isPodHealthy: func(ordinal int32) bool {
+			member, exist := tc.Status.TiDB.Members[tidbGroupPodName(tc, ordinal)]
+			return exist && member.Health
+		},
+	}
+}
+
+// keepRolledBack keeps the pod template of the statefulset unchanged if the
+// pod template which has been rolled back is applied again, it returns true
+// if the pod template is kept.
+func (c *canaryUpgrade) keepRolledBack(oldSet, newSet *apps.StatefulSet) (bool, error) {
+	status := *c.status
+	if status == nil || status.Phase != v1alpha1.CanaryUpgradePhaseRolledBack {
+		return false, nil
+	}
+	if c.strategy == nil {
+		// the canary upgrade is disabled, upgrade the pods anyway
+		*c.status = nil
+		return false, nil
+	}
+
+	hash, err := podTemplateHash(newSet)
+	if err != nil {
+		return false, err
+	}
+	if hash != status.FailedTemplateHash {
+		// the spec has been changed, begin a new upgrade
+		*c.status = nil
+		return false, nil
+	}
+
+	newSet.Spec.Template = oldSet.Spec.Template
+	newSet.Spec.UpdateStrategy = oldSet.Spec.UpdateStrategy
+	return true, nil
+}
+
+// watch checks the health of the canary pods which are baking or waiting for
+// the approval, it returns true if the canary pods are rolled back.
+func (c *canaryUpgrade) watch(oldSet, newSet *apps.StatefulSet, podOrdinals []int32) (bool, error) {
+	status := *c.status
+	if c.strategy == nil || status == nil || status.Revision != c.setStatus.UpdateRevision {
+		return false, nil
+	}
+	if status.Phase != v1alpha1.CanaryUpgradePhaseBaking && status.Phase != v1alpha1.CanaryUpgradePhaseWaitingForApproval {
+		return false, nil
+	}
+
+	ns := c.tc.GetNamespace()
+	var restarts int32
+	for _, ordinal := range podOrdinals {
+		podName := c.podName(ordinal)
+		pod, err := c.deps.PodLister.Pods(ns).Get(podName)
+		if err != nil {
+			return false, fmt.Errorf("canaryUpgrade.watch: failed to get pod %s for cluster %s/%s, error: %s", podName, ns, c.tc.GetName(), err)
+		}
+		if pod.Labels[apps.ControllerRevisionHashLabelKey] != status.Revision {
+			continue
+		}
+		if pod.Status.Phase != corev1.PodRunning || !c.isPodHealthy(ordinal) {
+			return c.rollback(oldSet, newSet, fmt.Sprintf("canary pod %s is unhealthy", podName))
+		}
+		restarts += podRestarts(pod)
+	}
+	if restarts > status.Restarts {
+		return c.rollback(oldSet, newSet, fmt.Sprintf("canary pods restarted %d times", restarts-status.Restarts))
+	}
+	return false, nil
+}
+
+// gate is called before the pod in the given ordinal is upgraded, it returns
+// a RequeueError if the upgrade should stop at the gate.
+func (c *canaryUpgrade) gate(podOrdinals []int32, upgraded []int32) error {
+	if c.strategy == nil {
+		*c.status = nil
+		return nil
+	}
+
+	ns := c.tc.GetNamespace()
+	tcName := c.tc.GetName()
+	revision := c.setStatus.UpdateRevision
+	status := *c.status
+	if status == nil || status.Revision != revision {
+		status = &v1alpha1.CanaryUpgradeStatus{
+			Phase:    v1alpha1.CanaryUpgradePhaseUpgrading,
+			Revision: revision,
+		}
+		*c.status = status
+	}
+
+	if status.Phase == v1alpha1.CanaryUpgradePhaseApproved {
+		return nil
+	}
+	if c.tc.Annotations[c.annApproved] == revision {
+		klog.Infof("tidbcluster: [%s/%s]'s %s canary upgrade of revision %s is approved", ns, tcName, c.memberType, revision)
+		status.Phase = v1alpha1.CanaryUpgradePhaseApproved
+		status.Message = ""
+		return nil
+	}
+
+	canaries, err := c.canaryCount(podOrdinals)
+	if err != nil {
+		return err
+	}
+	if int32(len(upgraded)) < canaries {
+		status.Phase = v1alpha1.CanaryUpgradePhaseUpgrading
+		return nil
+	}
+
+	// the upgraded pods have been checked to be healthy before reaching the gate
+	if status.BakeStartTime == nil {
+		var restarts int32
+		for _, ordinal := range upgraded {
+			pod, err := c.deps.PodLister.Pods(ns).Get(c.podName(ordinal))
+			if err != nil {
+				return fmt.Errorf("canaryUpgrade.gate: failed to get pod %s for cluster %s/%s, error: %s", c.podName(ordinal), ns, tcName, err)
+			}
+			restarts += podRestarts(pod)
+		}
+		now := metav1.Now()
+		status.Phase = v1alpha1.CanaryUpgradePhaseBaking
+		status.BakeStartTime = &now
+		status.Restarts = restarts
+		return controller.RequeueErrorf("tidbcluster: [%s/%s]'s %s canary pods are upgraded, begin baking", ns, tcName, c.memberType)
+	}
+
+	if time.Since(status.BakeStartTime.Time) < c.strategy.CanaryBakeTime() {
+		return controller.RequeueErrorf("tidbcluster: [%s/%s]'s %s canary pods are baking", ns, tcName, c.memberType)
+	}
+
+	status.Phase = v1alpha1.CanaryUpgradePhaseWaitingForApproval
+	status.Message = fmt.Sprintf("annotate the TidbCluster with %s=%s to continue the upgrade", c.annApproved, revision)
+	return controller.RequeueErrorf("tidbcluster: [%s/%s]'s %s canary upgrade is waiting for approval", ns, tcName, c.memberType)
+}
+
+// canaryCount returns the count of the canary pods, the pods are upgraded in
+// the descending order of the ordinals.
+func (c *canaryUpgrade) canaryCount(podOrdinals []int32) (int32, error) {
+	if !c.strategy.PerZone {
+		count := c.strategy.CanaryPods()
+		if count > int32(len(podOrdinals)) {
+			count = int32(len(podOrdinals))
+		}
+		return count, nil
+	}
+
+	zones := make([]string, 0, len(podOrdinals))
+	allZones := map[string]struct{}{}
+	for i := len(podOrdinals) - 1; i >= 0; i-- {
+		zone, err := c.podZone(c.podName(podOrdinals[i]))
+		if err != nil {
+			return 0, err
+		}
+		zones = append(zones, zone)
+		if zone != "" {
+			allZones[zone] = struct{}{}
+		}
+	}
+	if len(allZones) == 0 {
+		return 1, nil
+	}
+
+	upgradedZones := map[string]struct{}{}
+	for i, zone := range zones {
+		if zone != "" {
+			upgradedZones[zone] = struct{}{}
+		}
+		if len(upgradedZones) == len(allZones) {
+			return int32(i + 1), nil
+		}
+	}
+	return int32(len(zones)), nil
+}
+
+func (c *canaryUpgrade) podZone(podName string) (string, error) {
+	ns := c.tc.GetNamespace()
+	pod, err := c.deps.PodLister.Pods(ns).Get(podName)
+	if err != nil {
+		return "", fmt.Errorf("canaryUpgrade.podZone: failed to get pod %s for cluster %s/%s, error: %s", podName, ns, c.tc.GetName(), err)
+	}
+	if pod.Spec.NodeName == "" {
+		return "", nil
+	}
+	node, err := c.deps.NodeLister.Get(pod.Spec.NodeName)
+	if err != nil {
+		return "", fmt.Errorf("canaryUpgrade.podZone: failed to get node %s of pod %s/%s, error: %s", pod.Spec.NodeName, ns, podName, err)
+	}
+	if zone, ok := node.Labels[labelTopologyZone]; ok {
+		return zone, nil
+	}
+	return node.Labels[corev1.LabelZoneFailureDomain], nil
+}
+
+// rollback reverts the pod template of the statefulset to the current
+// revision, the canary pods which are not less than the partition are
+// recreated by the statefulset controller.
+func (c *canaryUpgrade) rollback(oldSet, newSet *apps.StatefulSet, reason string) (bool, error) {
+	ns := c.tc.GetNamespace()
+	tcName := c.tc.GetName()
+	status := *c.status
+	if !c.strategy.AutoRollbackEnabled() {
+		status.Message = fmt.Sprintf("%s, auto rollback is disabled", reason)
+		klog.Warningf("tidbcluster: [%s/%s]'s %s %s", ns, tcName, c.memberType, status.Message)
+		return false, nil
+	}
+
+	hash, err := podTemplateHash(newSet)
+	if err != nil {
+		return false, err
+	}
+	template, err := c.currentPodTemplate(oldSet)
+	if err != nil {
+		return false, err
+	}
+	newSet.Spec.Template = *template
+
+	msg := fmt.Sprintf("%s, roll back %s from revision %s to %s", reason, c.memberType, status.Revision, oldSet.Status.CurrentRevision)
+	*c.status = &v1alpha1.CanaryUpgradeStatus{
+		Phase:              v1alpha1.CanaryUpgradePhaseRolledBack,
+		Revision:           status.Revision,
+		FailedTemplateHash: hash,
+		Message:            msg,
+	}
+	klog.Warningf("tidbcluster: [%s/%s] %s", ns, tcName, msg)
+	c.deps.Recorder.Event(c.tc, corev1.EventTypeWarning, "CanaryRolledBack", msg)
+	return true, nil
+}
+
+// currentPodTemplate returns the pod template of the current revision of the statefulset
+func (c *canaryUpgrade) currentPodTemplate(set *apps.StatefulSet) (*corev1.PodTemplateSpec, error) {
+	ns := set.GetNamespace()
+	revisionName := set.Status.CurrentRevision
+	revision, err := c.deps.KubeClientset.AppsV1().ControllerRevisions(ns).Get(revisionName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("canaryUpgrade.currentPodTemplate: failed to get controller revision %s/%s, error: %s", ns, revisionName, err)
+	}
+	// the data of the revision is a patch of the statefulset, see
+	// https://github.com/kubernetes/kubernetes/blob/master/pkg/controller/statefulset/stateful_set_utils.go
+	patch := struct {
+		Spec struct {
+			Template corev1.PodTemplateSpec `json:"template"`
+		} `json:"spec"`
+	}{}
+	if err := json.Unmarshal(revision.Data.Raw, &patch); err != nil {
+		return nil, fmt.Errorf("canaryUpgrade.currentPodTemplate: failed to decode controller revision %s/%s, error: %s", ns, revisionName, err)
+	}
+	return &patch.Spec.Template, nil
+}
+
+func podTemplateHash(set *apps.StatefulSet) (string, error) {
+	data, err := json.Marshal(set.Spec.Template.Spec)
+	if err != nil {
+		return "", err
+	}
+	return v1alpha1.HashContents(data), nil
+}
+
+func podRestarts(pod *corev1.Pod) int32 {
+	var restarts int32
+	for _, status := range pod.Status.ContainerStatuses {
+		restarts += status.RestartCount
+	}
+	return restarts
+}
@@ -0,0 +1,361 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+)
+
+func TestTiDBUpgraderCanary(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name        string
+		strategy    *v1alpha1.CanaryUpgradeStrategy
+		changeFn    func(*v1alpha1.TidbCluster)
+		changePods  func([]*corev1.Pod)
+		errExpectFn func(*GomegaWithT, error)
+		expectFn    func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet)
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+		upgrader, _, podInformer := newTiDBUpgrader()
+		tidbUpgrader := upgrader.(*tidbUpgrader)
+		tc := newTidbClusterForTiDBUpgrader()
+		tc.Status.PD.Phase = v1alpha1.NormalPhase
+		tc.Status.TiKV.Phase = v1alpha1.NormalPhase
+		tc.Spec.TiDB.UpgradeStrategy = &v1alpha1.UpgradeStrategy{Canary: test.strategy}
+		if test.changeFn != nil {
+			test.changeFn(tc)
+		}
+		pods := getTiDBPods()
+		for _, pod := range pods {
+			pod.Status.Phase = corev1.PodRunning
+		}
+		if test.changePods != nil {
+			test.changePods(pods)
+		}
+		for _, pod := range pods {
+			podInformer.Informer().GetIndexer().Add(pod)
+		}
+
+		oldSet := newStatefulSetForTiDBUpgrader()
+		oldSet.Status.CurrentRevision = tc.Status.TiDB.StatefulSet.CurrentRevision
+		oldSet.Status.UpdateRevision = tc.Status.TiDB.StatefulSet.UpdateRevision
+		newSet := oldSet.DeepCopy()
+		SetStatefulSetLastAppliedConfigAnnotation(oldSet)
+		_, err := tidbUpgrader.deps.KubeClientset.AppsV1().ControllerRevisions(corev1.NamespaceDefault).Create(newControllerRevisionForCanary(oldSet))
+		g.Expect(err).NotTo(HaveOccurred())
+
+		err = upgrader.Upgrade(tc, oldSet, newSet)
+		test.errExpectFn(g, err)
+		test.expectFn(g, tc, newSet)
+	}
+
+	tests := []*testcase{
+		{
+			name:        "stop at the gate and begin baking",
+			strategy:    &v1alpha1.CanaryUpgradeStrategy{},
+			errExpectFn: errExpectRequeue,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(1)))
+				g.Expect(tc.Status.TiDB.Canary.Phase).To(Equal(v1alpha1.CanaryUpgradePhaseBaking))
+				g.Expect(tc.Status.TiDB.Canary.Revision).To(Equal("2"))
+				g.Expect(tc.Status.TiDB.Canary.BakeStartTime).NotTo(BeNil())
+			},
+		},
+		{
+			name:        "canary pods are not all upgraded",
+			strategy:    &v1alpha1.CanaryUpgradeStrategy{Pods: pointer.Int32Ptr(2)},
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(0)))
+				g.Expect(tc.Status.TiDB.Canary.Phase).To(Equal(v1alpha1.CanaryUpgradePhaseUpgrading))
+			},
+		},
+		{
+			name:     "wait for approval after baking",
+			strategy: &v1alpha1.CanaryUpgradeStrategy{BakeTime: pointer.StringPtr("1m")},
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiDB.Canary = newCanaryStatusForBaking(2 * time.Minute)
+			},
+			errExpectFn: errExpectRequeue,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(1)))
+				g.Expect(tc.Status.TiDB.Canary.Phase).To(Equal(v1alpha1.CanaryUpgradePhaseWaitingForApproval))
+			},
+		},
+		{
+			name:     "still baking",
+			strategy: &v1alpha1.CanaryUpgradeStrategy{BakeTime: pointer.StringPtr("10m")},
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiDB.Canary = newCanaryStatusForBaking(2 * time.Minute)
+			},
+			errExpectFn: errExpectRequeue,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(1)))
+				g.Expect(tc.Status.TiDB.Canary.Phase).To(Equal(v1alpha1.CanaryUpgradePhaseBaking))
+			},
+		},
+		{
+			name:     "approved",
+			strategy: &v1alpha1.CanaryUpgradeStrategy{},
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiDB.Canary = newCanaryStatusForBaking(2 * time.Minute)
+				tc.Annotations = map[string]string{label.AnnTiDBUpgradeApproved: "2"}
+			},
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(0)))
+				g.Expect(tc.Status.TiDB.Canary.Phase).To(Equal(v1alpha1.CanaryUpgradePhaseApproved))
+			},
+		},
+		{
+			name:     "approval of another revision",
+			strategy: &v1alpha1.CanaryUpgradeStrategy{},
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiDB.Canary = newCanaryStatusForBaking(2 * time.Minute)
+				tc.Annotations = map[string]string{label.AnnTiDBUpgradeApproved: "1"}
+			},
+			errExpectFn: errExpectRequeue,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(1)))
+			},
+		},
+		{
+			name:     "roll back unhealthy canary pods",
+			strategy: &v1alpha1.CanaryUpgradeStrategy{},
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiDB.Canary = newCanaryStatusForBaking(2 * time.Minute)
+				tc.Status.TiDB.Members[tidbPodName(upgradeTcName, 1)] = v1alpha1.TiDBMember{Name: tidbPodName(upgradeTcName, 1), Health: false}
+			},
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(1)))
+				g.Expect(newSet.Spec.Template.Spec.Containers[0].Image).To(Equal("tidb-current-image"))
+				g.Expect(tc.Status.TiDB.Canary.Phase).To(Equal(v1alpha1.CanaryUpgradePhaseRolledBack))
+				g.Expect(tc.Status.TiDB.Canary.FailedTemplateHash).NotTo(BeEmpty())
+			},
+		},
+		{
+			name:     "roll back restarted canary pods",
+			strategy: &v1alpha1.CanaryUpgradeStrategy{},
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiDB.Canary = newCanaryStatusForBaking(2 * time.Minute)
+			},
+			changePods: func(pods []*corev1.Pod) {
+				pods[1].Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "tidb", RestartCount: 1}}
+			},
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(newSet.Spec.Template.Spec.Containers[0].Image).To(Equal("tidb-current-image"))
+				g.Expect(tc.Status.TiDB.Canary.Phase).To(Equal(v1alpha1.CanaryUpgradePhaseRolledBack))
+			},
+		},
+		{
+			name:     "do not roll back if auto rollback is disabled",
+			strategy: &v1alpha1.CanaryUpgradeStrategy{AutoRollback: pointer.BoolPtr(false)},
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiDB.Canary = newCanaryStatusForBaking(2 * time.Minute)
+				tc.Status.TiDB.Members[tidbPodName(upgradeTcName, 1)] = v1alpha1.TiDBMember{Name: tidbPodName(upgradeTcName, 1), Health: false}
+			},
+			errExpectFn: errExpectRequeue,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(newSet.Spec.Template.Spec.Containers[0].Image).To(Equal("tidb-test-image"))
+				g.Expect(tc.Status.TiDB.Canary.Phase).To(Equal(v1alpha1.CanaryUpgradePhaseBaking))
+			},
+		},
+		{
+			name:     "keep the rolled back pod template",
+			strategy: &v1alpha1.CanaryUpgradeStrategy{},
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				hash, err := podTemplateHash(newStatefulSetForTiDBUpgrader())
+				g.Expect(err).NotTo(HaveOccurred())
+				tc.Status.TiDB.Phase = v1alpha1.NormalPhase
+				tc.Status.TiDB.Canary = &v1alpha1.CanaryUpgradeStatus{
+					Phase:              v1alpha1.CanaryUpgradePhaseRolledBack,
+					Revision:           "2",
+					FailedTemplateHash: hash,
+				}
+			},
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(1)))
+				g.Expect(tc.Status.TiDB.Phase).To(Equal(v1alpha1.NormalPhase))
+				g.Expect(tc.Status.TiDB.Canary.Phase).To(Equal(v1alpha1.CanaryUpgradePhaseRolledBack))
+			},
+		},
+		{
+			name:     "begin a new upgrade after the spec is changed",
+			strategy: &v1alpha1.CanaryUpgradeStrategy{},
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiDB.Canary = &v1alpha1.CanaryUpgradeStatus{
+					Phase:              v1alpha1.CanaryUpgradePhaseRolledBack,
+					Revision:           "2",
+					FailedTemplateHash: "another-template",
+				}
+			},
+			errExpectFn: errExpectRequeue,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(tc.Status.TiDB.Phase).To(Equal(v1alpha1.UpgradePhase))
+				g.Expect(tc.Status.TiDB.Canary.Phase).To(Equal(v1alpha1.CanaryUpgradePhaseBaking))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		testFn(test, t)
+	}
+}
+
+func TestCanaryCountPerZone(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tests := []struct {
+		name     string
+		zones    []string
+		expected int32
+	}{
+		{
+			name:     "one zone",
+			zones:    []string{"a", "a", "a"},
+			expected: 1,
+		},
+		{
+			name:     "every pod in a different zone",
+			zones:    []string{"a", "b", "c"},
+			expected: 3,
+		},
+		{
+			name:     "zones are covered by the pods with larger ordinals",
+			zones:    []string{"a", "a", "b", "a"},
+			expected: 2,
+		},
+		{
+			name:     "no zone labels",
+			zones:    []string{"", "", ""},
+			expected: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Log(tt.name)
+		fakeDeps := controller.NewFakeDependencies()
+		tc := newTidbClusterForTiDBUpgrader()
+		tc.Spec.TiDB.UpgradeStrategy = &v1alpha1.UpgradeStrategy{Canary: &v1alpha1.CanaryUpgradeStrategy{PerZone: true}}
+		podIndexer := fakeDeps.KubeInformerFactory.Core().V1().Pods().Informer().GetIndexer()
+		nodeIndexer := fakeDeps.KubeInformerFactory.Core().V1().Nodes().Informer().GetIndexer()
+		podOrdinals := []int32{}
+		for i, zone := range tt.zones {
+			ordinal := int32(i)
+			podOrdinals = append(podOrdinals, ordinal)
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   tidbPodName(tc.Name, ordinal),
+					Labels: map[string]string{},
+				},
+			}
+			if zone != "" {
+				node.Labels[labelTopologyZone] = zone
+			}
+			nodeIndexer.Add(node)
+			podIndexer.Add(&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      tidbPodName(tc.Name, ordinal),
+					Namespace: corev1.NamespaceDefault,
+				},
+				Spec: corev1.PodSpec{
+					NodeName: node.Name,
+				},
+			})
+		}
+
+		canary := newTiDBCanaryUpgrade(fakeDeps, tc)
+		count, err := canary.canaryCount(podOrdinals)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(count).To(Equal(tt.expected))
+	}
+}
+
+func newCanaryStatusForBaking(elapsed time.Duration) *v1alpha1.CanaryUpgradeStatus {
+	bakeStartTime := metav1.NewTime(time.Now().Add(-elapsed))
+	return &v1alpha1.CanaryUpgradeStatus{
+		Phase:         v1alpha1.CanaryUpgradePhaseBaking,
+		Revision:      "2",
+		BakeStartTime: &bakeStartTime,
+	}
+}
+
+func newControllerRevisionForCanary(set *apps.StatefulSet) *apps.ControllerRevision {
+	template := set.Spec.Template.DeepCopy()
+	template.Spec.Containers[0].Image = "tidb-current-image"
+	patch := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": template,
+		},
+	}
+	data, _ := json.Marshal(patch)
+	return &apps.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      set.Status.CurrentRevision,
+			Namespace: set.Namespace,
+		},
+		Data:     runtime.RawExtension{Raw: data},
+		Revision: 1,
+	}
+}
+
+func TestTiKVCanaryIsPodHealthy(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForPD()
+	tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+		"1": {ID: "1", PodName: "test-tikv-0", State: v1alpha1.TiKVStateUp},
+	}
+	tc.Status.TiKV.Groups = map[string]v1alpha1.TiKVStatus{
+		"hot": {Stores: map[string]v1alpha1.TiKVStore{
+			"2": {ID: "2", PodName: "test-tikv-hot-0", State: v1alpha1.TiKVStateUp},
+			"3": {ID: "3", PodName: "test-tikv-hot-1", State: v1alpha1.TiKVStateDown},
+		}},
+	}
+	canary := newTiKVCanaryUpgrade(controller.NewFakeDependencies(), tc)
+	g.Expect(canary.isPodHealthy(0)).To(BeTrue())
+	g.Expect(canary.isPodHealthy(1)).To(BeFalse())
+
+	t.Log("the stores of the group are looked up in the group status")
+	tc.Labels = map[string]string{label.TiKVGroupLabelKey: "hot"}
+	canary = newTiKVCanaryUpgrade(controller.NewFakeDependencies(), tc)
+	g.Expect(canary.isPodHealthy(0)).To(BeTrue())
+	g.Expect(canary.isPodHealthy(1)).To(BeFalse())
+
+	t.Log("the stores of the group view are its own stores")
+	tc.Labels = nil
+	gtc := tc.TiKVGroupCluster(&v1alpha1.TiKVGroupSpec{Name: "hot"})
+	canary = newTiKVCanaryUpgrade(controller.NewFakeDependencies(), gtc)
+	g.Expect(canary.isPodHealthy(0)).To(BeTrue())
+	g.Expect(canary.isPodHealthy(1)).To(BeFalse())
+}
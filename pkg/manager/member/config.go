@@ -14,12 +14,90 @@
 package member
 
 import (
+	"reflect"
+	"sort"
+	"strings"
+
 	perrors "github.com/pingcap/errors"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/label"
 	"github.com/pingcap/tidb-operator/pkg/util/toml"
+	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog"
+)
+
+// onlineConfigAllowList is the config items of a component that can be changed online,
+// an item is allowed if its dotted key is in keys or starts with one of the prefixes
+type onlineConfigAllowList struct {
+	keys     sets.String
+	prefixes []string
+}
+
+func (l onlineConfigAllowList) Has(key string) bool {
+	if l.keys.Has(key) {
+		return true
+	}
+	for _, prefix := range l.prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// pdOnlineConfig is the PD config items which can be updated through the config API
+	pdOnlineConfig = onlineConfigAllowList{
+		keys: sets.NewString(
+			"log.level",
+			"pd-server.use-region-storage",
+			"pd-server.max-gap-reset-ts",
+			"pd-server.key-type",
+			"pd-server.metric-storage",
+		),
+		prefixes: []string{"schedule.", "replication."},
+	}
+
+	// tikvOnlineConfig is the TiKV config items which can be updated through the status API
+	tikvOnlineConfig = onlineConfigAllowList{
+		keys: sets.NewString(
+			"rocksdb.max-background-jobs",
+			"rocksdb.max-open-files",
+			"rocksdb.compaction-readahead-size",
+			"rocksdb.bytes-per-sync",
+			"rocksdb.wal-bytes-per-sync",
+			"rocksdb.writable-file-max-buffer-size",
+			"storage.block-cache.capacity",
+			"backup.num-threads",
+		),
+		prefixes: []string{
+			"raftstore.",
+			"coprocessor.",
+			"pessimistic-txn.",
+			"gc.",
+			"split.",
+			"rocksdb.defaultcf.",
+			"rocksdb.writecf.",
+			"rocksdb.lockcf.",
+			"raftdb.defaultcf.",
+		},
+	}
+
+	// tidbOnlineSettings maps the TiDB config items to the settings of the status API
+	tidbOnlineSettings = map[string]string{
+		"log.level":               "log_level",
+		"check-mb4-value-in-utf8": "check_mb4_value_in_utf8",
+	}
+
+	// tidbOnlineConfig is the TiDB config items which can be updated through the status API
+	tidbOnlineConfig = onlineConfigAllowList{
+		keys: sets.StringKeySet(tidbOnlineSettings),
+	}
 )
 
 func updateConfigMap(old, new *corev1.ConfigMap) error {
@@ -83,3 +161,166 @@ func updateConfigMapIfNeed(
 
 	}
 }
+
+// flattenConfig flattens the nested tables of the config into the items keyed by their dotted names
+func flattenConfig(prefix string, config map[string]interface{}, items map[string]interface{}) {
+	for k, v := range config {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		if table, ok := v.(map[string]interface{}); ok {
+			flattenConfig(key, table, items)
+			continue
+		}
+		items[key] = v
+	}
+}
+
+// diffConfig returns the items changed from the old TOML config to the new one
+// keyed by their dotted names, the value of a removed item is nil
+func diffConfig(oldData, newData string) (map[string]interface{}, error) {
+	oldConfig := map[string]interface{}{}
+	if err := toml.Unmarshal([]byte(oldData), &oldConfig); err != nil {
+		return nil, err
+	}
+	newConfig := map[string]interface{}{}
+	if err := toml.Unmarshal([]byte(newData), &newConfig); err != nil {
+		return nil, err
+	}
+	oldItems := map[string]interface{}{}
+	flattenConfig("", oldConfig, oldItems)
+	newItems := map[string]interface{}{}
+	flattenConfig("", newConfig, newItems)
+
+	changed := map[string]interface{}{}
+	for k, v := range newItems {
+		if !reflect.DeepEqual(oldItems[k], v) {
+			changed[k] = v
+		}
+	}
+	for k := range oldItems {
+		if _, ok := newItems[k]; !ok {
+			changed[k] = nil
+		}
+	}
+	return changed, nil
+}
+
+// configRevision returns the digest of the config of the ConfigMap
+func configRevision(cm *corev1.ConfigMap) (string, error) {
+	sum, err := Sha256Sum(cm.Data["config-file"])
+	if err != nil {
+		return "", err
+	}
+	return sum[0:7], nil
+}
+
+// diffInUseConfig returns the items changed from the in-use ConfigMap to the desired one, the items
+// in the allow list are returned with their new values and the others are returned as restart keys
+func diffInUseConfig(
+	cmLister corelisters.ConfigMapLister,
+	inUseName string,
+	desired *corev1.ConfigMap,
+	allowList onlineConfigAllowList,
+) (map[string]interface{}, []string, error) {
+	if inUseName == "" {
+		return nil, nil, nil
+	}
+	existing, err := cmLister.ConfigMaps(desired.Namespace).Get(inUseName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, perrors.AddStack(err)
+	}
+	changed, err := diffConfig(existing.Data["config-file"], desired.Data["config-file"])
+	if err != nil {
+		return nil, nil, perrors.Annotatef(err, "diff config of configmap %s/%s", existing.Namespace, existing.Name)
+	}
+
+	online := map[string]interface{}{}
+	var restartKeys []string
+	for k, v := range changed {
+		if v != nil && allowList.Has(k) {
+			online[k] = v
+		} else {
+			restartKeys = append(restartKeys, k)
+		}
+	}
+	sort.Strings(restartKeys)
+	return online, restartKeys, nil
+}
+
+// applyConfigInPlace applies the items changed from the in-use ConfigMap online by apply if they are in
+// the allow list, and returns the new config status whose restart revision changes only when other items
+// are changed. If the items can not be applied online, they are applied by rolling the pods.
+func applyConfigInPlace(
+	cmLister corelisters.ConfigMapLister,
+	set *apps.StatefulSet,
+	inUseName string,
+	desired *corev1.ConfigMap,
+	status *v1alpha1.ConfigStatus,
+	allowList onlineConfigAllowList,
+	apply func(items map[string]interface{}) error,
+) (*v1alpha1.ConfigStatus, error) {
+	newStatus := &v1alpha1.ConfigStatus{}
+	if status != nil {
+		newStatus = status.DeepCopy()
+	}
+	// the status is preferred to keep the pending restart if the statefulset failed to be updated
+	if newStatus.RestartRevision == "" && set != nil {
+		newStatus.RestartRevision = set.Spec.Template.Annotations[label.AnnConfigRestartRevision]
+	}
+
+	revision, err := configRevision(desired)
+	if err != nil {
+		return nil, err
+	}
+	online, restartKeys, err := diffInUseConfig(cmLister, inUseName, desired, allowList)
+	if err != nil {
+		return nil, err
+	}
+	newStatus.Revision = revision
+	if len(online) == 0 && len(restartKeys) == 0 {
+		return newStatus, nil
+	}
+
+	onlineKeys := sets.StringKeySet(online).List()
+	if len(online) > 0 {
+		if err := apply(online); err != nil {
+			klog.Warningf("failed to apply config items %v of configmap %s/%s online, roll the pods instead: %v",
+				onlineKeys, desired.Namespace, desired.Name, err)
+			restartKeys = append(restartKeys, onlineKeys...)
+			sort.Strings(restartKeys)
+			onlineKeys = nil
+		}
+	}
+
+	now := metav1.Now()
+	newStatus.OnlineKeys = onlineKeys
+	newStatus.RestartKeys = restartKeys
+	newStatus.LastAppliedTime = &now
+	if len(restartKeys) > 0 {
+		newStatus.RestartRevision = revision
+	}
+	return newStatus, nil
+}
+
+// syncConfigRestartPending marks the config restart pending if the restart revision
+// is not in the pod template of the statefulset yet
+func syncConfigRestartPending(status *v1alpha1.ConfigStatus, set *apps.StatefulSet) {
+	if status == nil {
+		return
+	}
+	status.RestartPending = status.RestartRevision != "" && set.Spec.Template.Annotations[label.AnnConfigRestartRevision] != status.RestartRevision
+}
+
+// setConfigRestartAnnotation sets the restart revision of the config to the pod annotations,
+// so that the pods are rolled when it changes
+func setConfigRestartAnnotation(podAnnotations map[string]string, status *v1alpha1.ConfigStatus) {
+	if status == nil || status.RestartRevision == "" {
+		return
+	}
+	podAnnotations[label.AnnConfigRestartRevision] = status.RestartRevision
+}
@@ -14,10 +14,16 @@
 package member
 
 import (
+	"fmt"
 	"testing"
 
 	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestUpdateConfigMap(t *testing.T) {
@@ -132,3 +138,154 @@ func TestUpdateConfigMap(t *testing.T) {
 		testFn(&tests[i], t)
 	}
 }
+
+func TestDiffConfig(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	old := `
+[log]
+level = "info"
+
+[raftstore]
+sync-log = true
+raft-base-tick-interval = "1s"
+`
+	new := `
+[log]
+level = "warn"
+
+[raftstore]
+sync-log = false
+
+[storage]
+reserve-space = "1GB"
+`
+	changed, err := diffConfig(old, new)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(changed).To(Equal(map[string]interface{}{
+		"log.level":                         "warn",
+		"raftstore.sync-log":                false,
+		"raftstore.raft-base-tick-interval": nil,
+		"storage.reserve-space":             "1GB",
+	}))
+
+	changed, err = diffConfig(old, old)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(changed).To(BeEmpty())
+}
+
+func TestApplyConfigInPlace(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name        string
+		oldConfig   string
+		newConfig   string
+		status      *v1alpha1.ConfigStatus
+		annotation  string
+		applyErr    error
+		expectItems map[string]interface{}
+		expectFn    func(*GomegaWithT, *v1alpha1.ConfigStatus)
+	}
+
+	testFn := func(test *testcase) {
+		t.Log(test.name)
+
+		deps := controller.NewFakeDependencies()
+		existing := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "demo-tikv", Namespace: corev1.NamespaceDefault},
+			Data:       map[string]string{"config-file": test.oldConfig},
+		}
+		deps.LabelFilterKubeInformerFactory.Core().V1().ConfigMaps().Informer().GetIndexer().Add(existing)
+		desired := existing.DeepCopy()
+		desired.Data["config-file"] = test.newConfig
+		set := &apps.StatefulSet{}
+		if test.annotation != "" {
+			set.Spec.Template.Annotations = map[string]string{label.AnnConfigRestartRevision: test.annotation}
+		}
+
+		var items map[string]interface{}
+		status, err := applyConfigInPlace(deps.ConfigMapLister, set, existing.Name, desired, test.status, tikvOnlineConfig, func(i map[string]interface{}) error {
+			items = i
+			return test.applyErr
+		})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(items).To(Equal(test.expectItems))
+		test.expectFn(g, status)
+	}
+
+	tests := []testcase{
+		{
+			name:      "config not changed",
+			oldConfig: "[raftstore]\nsync-log = true\n",
+			newConfig: "[raftstore]\n  sync-log = true\n",
+			expectFn: func(g *GomegaWithT, status *v1alpha1.ConfigStatus) {
+				g.Expect(status.Revision).NotTo(BeEmpty())
+				g.Expect(status.RestartRevision).To(BeEmpty())
+				g.Expect(status.LastAppliedTime).To(BeNil())
+			},
+		},
+		{
+			name:        "online items changed",
+			oldConfig:   "[raftstore]\nsync-log = true\n",
+			newConfig:   "[raftstore]\nsync-log = false\n",
+			annotation:  "abcdefg",
+			expectItems: map[string]interface{}{"raftstore.sync-log": false},
+			expectFn: func(g *GomegaWithT, status *v1alpha1.ConfigStatus) {
+				g.Expect(status.OnlineKeys).To(Equal([]string{"raftstore.sync-log"}))
+				g.Expect(status.RestartKeys).To(BeEmpty())
+				g.Expect(status.RestartRevision).To(Equal("abcdefg"))
+				g.Expect(status.LastAppliedTime).NotTo(BeNil())
+			},
+		},
+		{
+			name:        "online and restart items changed",
+			oldConfig:   "[raftstore]\nsync-log = true\n",
+			newConfig:   "[raftstore]\nsync-log = false\n[storage]\nreserve-space = \"1GB\"\n",
+			status:      &v1alpha1.ConfigStatus{RestartRevision: "abcdefg"},
+			expectItems: map[string]interface{}{"raftstore.sync-log": false},
+			expectFn: func(g *GomegaWithT, status *v1alpha1.ConfigStatus) {
+				g.Expect(status.OnlineKeys).To(Equal([]string{"raftstore.sync-log"}))
+				g.Expect(status.RestartKeys).To(Equal([]string{"storage.reserve-space"}))
+				g.Expect(status.RestartRevision).To(Equal(status.Revision))
+			},
+		},
+		{
+			name:        "online items failed to be applied",
+			oldConfig:   "[raftstore]\nsync-log = true\n",
+			newConfig:   "[raftstore]\nsync-log = false\n",
+			applyErr:    fmt.Errorf("tikv is unavailable"),
+			expectItems: map[string]interface{}{"raftstore.sync-log": false},
+			expectFn: func(g *GomegaWithT, status *v1alpha1.ConfigStatus) {
+				g.Expect(status.OnlineKeys).To(BeEmpty())
+				g.Expect(status.RestartKeys).To(Equal([]string{"raftstore.sync-log"}))
+				g.Expect(status.RestartRevision).To(Equal(status.Revision))
+			},
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i])
+	}
+}
+
+func TestSyncConfigRestartPending(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	set := &apps.StatefulSet{}
+	set.Spec.Template.Annotations = map[string]string{label.AnnConfigRestartRevision: "1"}
+
+	syncConfigRestartPending(nil, set)
+
+	status := &v1alpha1.ConfigStatus{}
+	syncConfigRestartPending(status, set)
+	g.Expect(status.RestartPending).To(BeFalse())
+
+	status.RestartRevision = "2"
+	syncConfigRestartPending(status, set)
+	g.Expect(status.RestartPending).To(BeTrue())
+
+	set.Spec.Template.Annotations[label.AnnConfigRestartRevision] = "2"
+	syncConfigRestartPending(status, set)
+	g.Expect(status.RestartPending).To(BeFalse())
+}
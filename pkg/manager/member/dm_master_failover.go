@@ -19,6 +19,7 @@ import (
 
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/metrics"
 	"github.com/pingcap/tidb-operator/pkg/util"
 
 	apiv1 "k8s.io/api/core/v1"
@@ -131,6 +132,7 @@ func (f *masterFailover) tryToMarkAPeerAsFailure(dc *v1alpha1.DMCluster) error {
 
 		msg := fmt.Sprintf("dm-master member[%s] is unhealthy", masterMember.ID)
 		f.deps.Recorder.Event(dc, apiv1.EventTypeWarning, unHealthEventReason, fmt.Sprintf(unHealthEventMsgPattern, "dm-master", podName, msg))
+		metrics.ObserveFailover(ns, dc.GetName(), "dm-master")
 
 		// mark a peer member failed and return an error to skip reconciliation
 		// note that status of dm cluster will be updated always
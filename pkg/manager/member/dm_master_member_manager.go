@@ -203,6 +203,10 @@ func (m *masterMemberManager) syncMasterStatefulSetForDMCluster(dc *v1alpha1.DMC
 		return controller.RequeueErrorf("DMCluster: [%s/%s], waiting for dm-master cluster running", ns, dcName)
 	}
 
+	if err := recreateStatefulSetForStorage(m.deps, dc, newMasterSet, oldMasterSet); err != nil {
+		return err
+	}
+
 	// Force update takes precedence over scaling because force upgrade won't take effect when cluster gets stuck at scaling
 	if !dc.Status.Master.Synced && NeedForceUpgrade(dc.Annotations) {
 		dc.Status.Master.Phase = v1alpha1.UpgradePhase
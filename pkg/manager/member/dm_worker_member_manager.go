@@ -202,6 +202,10 @@ func (m *workerMemberManager) syncWorkerStatefulSetForDMCluster(dc *v1alpha1.DMC
 		return nil
 	}
 
+	if err := recreateStatefulSetForStorage(m.deps, dc, newSts, oldSts); err != nil {
+		return err
+	}
+
 	if err := m.scaler.Scale(dc, oldSts, newSts); err != nil {
 		return err
 	}
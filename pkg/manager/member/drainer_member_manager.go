@@ -0,0 +1,570 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	"github.com/pingcap/tidb-operator/pkg/manager"
+	"github.com/pingcap/tidb-operator/pkg/util"
+	"github.com/pingcap/tidb-operator/pkg/util/config"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/klog"
+)
+
+const (
+	defaultDrainerLogLevel        = "info"
+	defaultDrainerInitialCommitTS = "-1"
+	drainerCertVolumeMount        = "drainer-tls"
+	drainerCertPath               = "/var/lib/drainer-tls"
+)
+
+type drainerMemberManager struct {
+	deps   *controller.Dependencies
+	scaler Scaler
+}
+
+// NewDrainerMemberManager returns a controller to reconcile drainer clusters
+func NewDrainerMemberManager(deps *controller.Dependencies, scaler Scaler) manager.Manager {
+	return &drainerMemberManager{
+		deps:   deps,
+		scaler: scaler,
+	}
+}
+
+func (m *drainerMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
+	if tc.Spec.Drainer == nil {
+		return nil
+	}
+	if err := m.syncHeadlessService(tc); err != nil {
+		return err
+	}
+	return m.syncDrainerStatefulSetForTidbCluster(tc)
+}
+
+// syncDrainerStatefulSetForTidbCluster sync statefulset status of drainer to tidbcluster
+func (m *drainerMemberManager) syncDrainerStatefulSetForTidbCluster(tc *v1alpha1.TidbCluster) error {
+	oldDrainerSetTemp, err := m.deps.StatefulSetLister.StatefulSets(tc.Namespace).Get(controller.DrainerMemberName(tc.Name))
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("syncDrainerStatefulSetForTidbCluster: failed to get sts %s for cluster %s/%s, error: %s", controller.DrainerMemberName(tc.Name), tc.GetNamespace(), tc.GetName(), err)
+	}
+	notFound := errors.IsNotFound(err)
+	oldDrainerSet := oldDrainerSetTemp.DeepCopy()
+
+	if err := m.syncTiDBClusterStatus(tc, oldDrainerSet); err != nil {
+		klog.Errorf("failed to sync TidbCluster: [%s/%s]'s status, error: %v", tc.Namespace, tc.Name, err)
+		return err
+	}
+
+	if tc.Spec.Paused {
+		klog.V(4).Infof("tidb cluster %s/%s is paused, skip syncing for drainer statefulset", tc.GetNamespace(), tc.GetName())
+		return nil
+	}
+
+	cm, err := m.syncConfigMap(tc, oldDrainerSet)
+	if err != nil {
+		return err
+	}
+
+	newDrainerSet, err := getNewDrainerStatefulSet(tc, cm)
+	if err != nil {
+		return err
+	}
+	if notFound {
+		err = SetStatefulSetLastAppliedConfigAnnotation(newDrainerSet)
+		if err != nil {
+			return err
+		}
+		return m.deps.StatefulSetControl.CreateStatefulSet(tc, newDrainerSet)
+	}
+
+	// Wait for PD & TiKV upgrading done
+	if tc.Status.PD.Phase == v1alpha1.UpgradePhase || tc.TiKVUpgrading() {
+		return nil
+	}
+
+	if err := m.scaler.Scale(tc, oldDrainerSet, newDrainerSet); err != nil {
+		return err
+	}
+
+	if !templateEqual(newDrainerSet, oldDrainerSet) {
+		if _, err := waitForMaintenanceWindow(tc, oldDrainerSet, newDrainerSet); err != nil {
+			return err
+		}
+	}
+
+	return UpdateStatefulSet(m.deps.StatefulSetControl, tc, newDrainerSet, oldDrainerSet)
+}
+
+func (m *drainerMemberManager) syncTiDBClusterStatus(tc *v1alpha1.TidbCluster, set *apps.StatefulSet) error {
+	if set == nil {
+		// skip if not created yet
+		return nil
+	}
+
+	tc.Status.Drainer.StatefulSet = &set.Status
+
+	upgrading, err := m.drainerStatefulSetIsUpgrading(set, tc)
+	if err != nil {
+		return err
+	}
+	if upgrading {
+		tc.Status.Drainer.Phase = v1alpha1.UpgradePhase
+	} else {
+		tc.Status.Drainer.Phase = v1alpha1.NormalPhase
+	}
+
+	// failed to sync drainer members will not affect subsequent logic, just print the errors.
+	if err := m.syncDrainerMembers(tc); err != nil {
+		klog.Errorf("failed to sync TidbCluster: [%s/%s]'s drainer members, error: %v", tc.Namespace, tc.Name, err)
+	}
+
+	return nil
+}
+
+// syncDrainerMembers syncs the drainers registered in PD to the status,
+// the status of drainers is served by pumps.
+func (m *drainerMemberManager) syncDrainerMembers(tc *v1alpha1.TidbCluster) error {
+	if tc.Spec.Pump == nil {
+		klog.V(4).Infof("tidb cluster %s/%s has no pump, skip syncing drainer members", tc.GetNamespace(), tc.GetName())
+		return nil
+	}
+
+	drainers, err := m.deps.PumpControl.GetDrainers(tc)
+	if err != nil {
+		return err
+	}
+
+	members := map[string]v1alpha1.DrainerMember{}
+	for _, drainer := range drainers {
+		podName := drainer.PodName()
+		if !strings.HasPrefix(podName, controller.DrainerMemberName(tc.Name)+"-") {
+			// skip the drainers not managed by this cluster, e.g. created by helm
+			continue
+		}
+		members[podName] = v1alpha1.DrainerMember{
+			NodeID:       drainer.NodeID,
+			Host:         drainer.Host,
+			State:        drainer.State,
+			CheckpointTS: strconv.FormatInt(drainer.MaxCommitTS, 10),
+		}
+	}
+	tc.Status.Drainer.Members = members
+	return nil
+}
+
+func (m *drainerMemberManager) syncHeadlessService(tc *v1alpha1.TidbCluster) error {
+	if tc.Spec.Paused {
+		klog.V(4).Infof("tidb cluster %s/%s is paused, skip syncing for drainer headless service", tc.GetNamespace(), tc.GetName())
+		return nil
+	}
+
+	newSvc := getNewDrainerHeadlessService(tc)
+	oldSvc, err := m.deps.ServiceLister.Services(newSvc.Namespace).Get(newSvc.Name)
+	if errors.IsNotFound(err) {
+		err = controller.SetServiceLastAppliedConfigAnnotation(newSvc)
+		if err != nil {
+			return err
+		}
+		return m.deps.ServiceControl.CreateService(tc, newSvc)
+	}
+	if err != nil {
+		return fmt.Errorf("syncHeadlessService: failed to get svc %s/%s for cluster %s/%s, error %s", newSvc.Namespace, newSvc.Name, tc.GetNamespace(), tc.GetName(), err)
+	}
+
+	equal, err := controller.ServiceEqual(newSvc, oldSvc)
+	if err != nil {
+		return err
+	}
+	if !equal {
+		svc := *oldSvc
+		svc.Spec = newSvc.Spec
+		err = controller.SetServiceLastAppliedConfigAnnotation(&svc)
+		if err != nil {
+			return err
+		}
+		_, err = m.deps.ServiceControl.UpdateService(tc, &svc)
+		return err
+	}
+	return nil
+}
+
+func (m *drainerMemberManager) syncConfigMap(tc *v1alpha1.TidbCluster, set *apps.StatefulSet) (*corev1.ConfigMap, error) {
+	baseDrainerSpec, createDrainer := tc.BaseDrainerSpec()
+	if !createDrainer {
+		return nil, nil
+	}
+
+	newCm, err := getNewDrainerConfigMap(tc)
+	if err != nil {
+		return nil, err
+	}
+
+	var inUseName string
+	if set != nil {
+		inUseName = FindConfigMapVolume(&set.Spec.Template.Spec, func(name string) bool {
+			return strings.HasPrefix(name, controller.DrainerMemberName(tc.Name))
+		})
+	}
+
+	err = updateConfigMapIfNeed(m.deps.ConfigMapLister, baseDrainerSpec.ConfigUpdateStrategy(), inUseName, newCm)
+	if err != nil {
+		return nil, err
+	}
+	return m.deps.TypedControl.CreateOrUpdateConfigMap(tc, newCm)
+}
+
+func getNewDrainerHeadlessService(tc *v1alpha1.TidbCluster) *corev1.Service {
+	if tc.Spec.Drainer == nil {
+		return nil
+	}
+
+	objMeta, drainerLabel := getDrainerMeta(tc, controller.DrainerPeerMemberName)
+
+	return &corev1.Service{
+		ObjectMeta: objMeta,
+		Spec: corev1.ServiceSpec{
+			ClusterIP: "None",
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "drainer",
+					Port:       8249,
+					TargetPort: intstr.FromInt(8249),
+					Protocol:   corev1.ProtocolTCP,
+				},
+			},
+			Selector:                 drainerLabel,
+			PublishNotReadyAddresses: true,
+		},
+	}
+}
+
+// getNewDrainerConfigMap returns a configMap for drainer, the sink and the
+// checkpoint are rendered into the [syncer.to] and [syncer.to.checkpoint]
+// sections of the config
+func getNewDrainerConfigMap(tc *v1alpha1.TidbCluster) (*corev1.ConfigMap, error) {
+	_, createDrainer := tc.BaseDrainerSpec()
+	if !createDrainer {
+		return nil, nil
+	}
+	spec := tc.Spec.Drainer
+	objMeta, _ := getDrainerMeta(tc, controller.DrainerMemberName)
+
+	conf := config.New(map[string]interface{}{})
+	if spec.Config != nil && spec.Config.Inner() != nil {
+		conf = spec.Config.DeepCopy()
+	}
+
+	if spec.Sink != nil {
+		conf.Set("syncer.db-type", spec.Sink.Type)
+		if spec.Sink.Config != nil {
+			for k, v := range spec.Sink.Config.DeepCopy().Inner() {
+				conf.Set("syncer.to."+k, v)
+			}
+		}
+	}
+
+	if spec.Checkpoint != nil {
+		conf.Set("syncer.to.checkpoint.type", spec.Checkpoint.Type)
+		if spec.Checkpoint.Config != nil {
+			for k, v := range spec.Checkpoint.Config.DeepCopy().Inner() {
+				conf.Set("syncer.to.checkpoint."+k, v)
+			}
+		}
+	}
+
+	if tc.IsTLSClusterEnabled() {
+		conf.Set("security.ssl-ca", path.Join(drainerCertPath, corev1.ServiceAccountRootCAKey))
+		conf.Set("security.ssl-cert", path.Join(drainerCertPath, corev1.TLSCertKey))
+		conf.Set("security.ssl-key", path.Join(drainerCertPath, corev1.TLSPrivateKeyKey))
+	}
+
+	confText, err := conf.MarshalTOML()
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]string{
+		"drainer-config": string(confText),
+	}
+
+	objMeta.Name = controller.DrainerMemberName(tc.Name)
+
+	return &corev1.ConfigMap{
+		ObjectMeta: objMeta,
+		Data:       data,
+	}, nil
+}
+
+func getNewDrainerStatefulSet(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (*apps.StatefulSet, error) {
+	spec, ok := tc.BaseDrainerSpec()
+	if !ok {
+		return nil, nil
+	}
+	objMeta, drainerLabel := getDrainerMeta(tc, controller.DrainerMemberName)
+	replicas := tc.Spec.Drainer.Replicas
+	storageClass := tc.Spec.Drainer.StorageClassName
+	podAnnos := CombineAnnotations(controller.AnnProm(8249), spec.Annotations())
+	storageRequest, err := controller.ParseStorageRequest(tc.Spec.Drainer.Requests)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse storage request for drainer, tidbcluster %s/%s, error: %v", tc.Namespace, tc.Name, err)
+	}
+	startScript, err := getDrainerStartScript(tc)
+	if err != nil {
+		return nil, fmt.Errorf("cannot render start-script for drainer, tidbcluster %s/%s, error: %v", tc.Namespace, tc.Name, err)
+	}
+
+	var envs []corev1.EnvVar
+	if tc.Spec.Drainer.SetTimeZone != nil && *tc.Spec.Drainer.SetTimeZone {
+		envs = append(envs, corev1.EnvVar{
+			Name:  "TZ",
+			Value: tc.Spec.Timezone,
+		})
+	}
+	if spec.HostNetwork() {
+		// For backward compatibility, set HOSTNAME to POD_NAME in hostNetwork mode
+		envs = append(envs, corev1.EnvVar{
+			Name: "HOSTNAME",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: "metadata.name",
+				},
+			},
+		})
+	}
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      "data",
+			MountPath: "/data",
+		},
+		{
+			Name:      "config",
+			MountPath: "/etc/drainer",
+		},
+	}
+	if tc.IsTLSClusterEnabled() {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name: drainerCertVolumeMount, ReadOnly: true, MountPath: drainerCertPath,
+		})
+	}
+	containers := []corev1.Container{
+		{
+			Name:            "drainer",
+			Image:           *tc.DrainerImage(),
+			ImagePullPolicy: spec.ImagePullPolicy(),
+			Command: []string{
+				"/bin/sh",
+				"-c",
+				startScript,
+			},
+			Ports: []corev1.ContainerPort{{
+				Name:          "drainer",
+				ContainerPort: 8249,
+			}},
+			Resources:    controller.ContainerResource(tc.Spec.Drainer.ResourceRequirements),
+			Env:          util.AppendEnv(envs, spec.Env()),
+			VolumeMounts: volumeMounts,
+		},
+	}
+
+	volumes := []corev1.Volume{
+		{
+			Name: "config",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: cm.Name,
+					},
+					Items: []corev1.KeyToPath{
+						{
+							Key:  "drainer-config",
+							Path: "drainer.toml",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if tc.IsTLSClusterEnabled() {
+		volumes = append(volumes, corev1.Volume{
+			Name: drainerCertVolumeMount, VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: util.ClusterTLSSecretName(tc.Name, label.DrainerLabelVal),
+				},
+			},
+		})
+	}
+
+	volumeClaims := []corev1.PersistentVolumeClaim{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "data",
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{
+					corev1.ReadWriteOnce,
+				},
+				StorageClassName: storageClass,
+				Resources:        storageRequest,
+			},
+		},
+	}
+
+	serviceAccountName := tc.Spec.Drainer.ServiceAccount
+	if serviceAccountName == "" {
+		serviceAccountName = tc.Spec.ServiceAccount
+	}
+
+	podTemplate := corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: podAnnos,
+			Labels:      drainerLabel,
+		},
+		Spec: corev1.PodSpec{
+			Containers:         containers,
+			ServiceAccountName: serviceAccountName,
+			Volumes:            volumes,
+
+			Affinity:         spec.Affinity(),
+			Tolerations:      spec.Tolerations(),
+			NodeSelector:     spec.NodeSelector(),
+			SchedulerName:    spec.SchedulerName(),
+			SecurityContext:  spec.PodSecurityContext(),
+			HostNetwork:      spec.HostNetwork(),
+			DNSPolicy:        spec.DnsPolicy(),
+			ImagePullSecrets: spec.ImagePullSecrets(),
+		},
+	}
+
+	return &apps.StatefulSet{
+		ObjectMeta: objMeta,
+		Spec: apps.StatefulSetSpec{
+			Selector:    drainerLabel.LabelSelector(),
+			ServiceName: controller.DrainerPeerMemberName(tc.Name),
+			Replicas:    &replicas,
+
+			Template:             podTemplate,
+			VolumeClaimTemplates: volumeClaims,
+			UpdateStrategy: apps.StatefulSetUpdateStrategy{
+				Type: spec.StatefulSetUpdateStrategy(),
+			},
+		},
+	}, nil
+}
+
+func getDrainerMeta(tc *v1alpha1.TidbCluster, nameFunc func(string) string) (metav1.ObjectMeta, label.Label) {
+	instanceName := tc.GetInstanceName()
+	drainerLabel := label.New().Instance(instanceName).Drainer()
+
+	objMeta := metav1.ObjectMeta{
+		Name:            nameFunc(tc.Name),
+		Namespace:       tc.Namespace,
+		Labels:          drainerLabel,
+		OwnerReferences: []metav1.OwnerReference{controller.GetOwnerRef(tc)},
+	}
+	return objMeta, drainerLabel
+}
+
+func getDrainerStartScript(tc *v1alpha1.TidbCluster) (string, error) {
+	initialCommitTS := defaultDrainerInitialCommitTS
+	if tc.Spec.Drainer.InitialCommitTS != nil {
+		initialCommitTS = *tc.Spec.Drainer.InitialCommitTS
+	}
+
+	return RenderDrainerStartScript(&DrainerStartScriptModel{
+		Scheme:          tc.Scheme(),
+		ClusterName:     tc.Name,
+		LogLevel:        getDrainerLogLevel(tc),
+		InitialCommitTS: initialCommitTS,
+		ClusterDomain:   tc.Spec.ClusterDomain,
+		Namespace:       tc.GetNamespace(),
+	})
+}
+
+func getDrainerLogLevel(tc *v1alpha1.TidbCluster) string {
+	config := tc.Spec.Drainer.Config
+	if config == nil {
+		return defaultDrainerLogLevel
+	}
+
+	v := config.Get("log-level")
+	if v == nil {
+		return defaultDrainerLogLevel
+	}
+
+	logLevel, err := v.AsString()
+	if err != nil {
+		klog.Warning("error log-level for drainer: ", err)
+		return defaultDrainerLogLevel
+	}
+
+	return logLevel
+}
+
+func (m *drainerMemberManager) drainerStatefulSetIsUpgrading(set *apps.StatefulSet, tc *v1alpha1.TidbCluster) (bool, error) {
+	if statefulSetIsUpgrading(set) {
+		return true, nil
+	}
+	selector, err := label.New().
+		Instance(tc.GetInstanceName()).
+		Drainer().
+		Selector()
+	if err != nil {
+		return false, err
+	}
+	drainerPods, err := m.deps.PodLister.Pods(tc.GetNamespace()).List(selector)
+	if err != nil {
+		return false, fmt.Errorf("drainerStatefulSetIsUpgrading: failed to list pods for cluster %s/%s, selector %s, error: %s", tc.GetNamespace(), tc.GetName(), selector, err)
+	}
+	for _, pod := range drainerPods {
+		revisionHash, exist := pod.Labels[apps.ControllerRevisionHashLabelKey]
+		if !exist {
+			return false, nil
+		}
+		if revisionHash != tc.Status.Drainer.StatefulSet.UpdateRevision {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type FakeDrainerMemberManager struct {
+	err error
+}
+
+func NewFakeDrainerMemberManager() *FakeDrainerMemberManager {
+	return &FakeDrainerMemberManager{}
+}
+
+func (m *FakeDrainerMemberManager) SetSyncError(err error) {
+	m.err = err
+}
+
+func (m *FakeDrainerMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
+	if m.err != nil {
+		return m.err
+	}
+	return nil
+}
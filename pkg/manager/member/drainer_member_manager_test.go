@@ -0,0 +1,306 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/util/config"
+	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestDrainerMemberManagerSyncCreate(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type result struct {
+		sync   error
+		svc    *corev1.Service
+		getSvc error
+		set    *appsv1.StatefulSet
+		getSet error
+		cm     *corev1.ConfigMap
+		getCm  error
+	}
+
+	type testcase struct {
+		name           string
+		prepare        func(cluster *v1alpha1.TidbCluster)
+		errOnCreateSet bool
+		errOnCreateSvc bool
+		expectFn       func(*GomegaWithT, *result)
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+		tc := newTidbClusterForDrainer()
+		ns := tc.Namespace
+		tcName := tc.Name
+		if test.prepare != nil {
+			test.prepare(tc)
+		}
+
+		dmm, fakeDeps := newFakeDrainerMemberManager()
+
+		if test.errOnCreateSet {
+			fakeDeps.StatefulSetControl.(*controller.FakeStatefulSetControl).SetCreateStatefulSetError(errors.NewInternalError(fmt.Errorf("API server failed")), 0)
+		}
+		if test.errOnCreateSvc {
+			fakeDeps.ServiceControl.(*controller.FakeServiceControl).SetCreateServiceError(errors.NewInternalError(fmt.Errorf("API server failed")), 0)
+		}
+
+		syncErr := dmm.Sync(tc)
+		svc, getSvcErr := dmm.deps.ServiceLister.Services(ns).Get(controller.DrainerPeerMemberName(tcName))
+		set, getStsErr := dmm.deps.StatefulSetLister.StatefulSets(ns).Get(controller.DrainerMemberName(tcName))
+		cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: controller.DrainerMemberName(tcName)}}
+		key, err := client.ObjectKeyFromObject(cm)
+		g.Expect(err).To(Succeed())
+		getCmErr := fakeDeps.GenericControl.(*controller.FakeGenericControl).FakeCli.Get(context.TODO(), key, cm)
+		result := result{syncErr, svc, getSvcErr, set, getStsErr, cm, getCmErr}
+		test.expectFn(g, &result)
+	}
+
+	tests := []*testcase{
+		{
+			name: "basic",
+			expectFn: func(g *GomegaWithT, r *result) {
+				g.Expect(r.sync).To(Succeed())
+				g.Expect(r.getCm).To(Succeed())
+				g.Expect(r.getSet).To(Succeed())
+				g.Expect(r.getSvc).To(Succeed())
+				g.Expect(*r.set.Spec.Replicas).To(Equal(int32(1)))
+				g.Expect(r.set.Spec.Template.Spec.Containers[0].Command[2]).To(ContainSubstring("-initial-commit-ts=-1"))
+			},
+		},
+		{
+			name: "do not sync if drainer spec is nil",
+			prepare: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.Drainer = nil
+			},
+			expectFn: func(g *GomegaWithT, r *result) {
+				g.Expect(r.sync).To(Succeed())
+				g.Expect(r.getCm).NotTo(Succeed())
+				g.Expect(r.getSet).NotTo(Succeed())
+				g.Expect(r.getSvc).NotTo(Succeed())
+			},
+		},
+		{
+			name:           "error when create drainer statefulset",
+			errOnCreateSet: true,
+			expectFn: func(g *GomegaWithT, r *result) {
+				g.Expect(r.sync).NotTo(Succeed())
+				g.Expect(r.getSet).NotTo(Succeed())
+				g.Expect(r.getCm).To(Succeed())
+				g.Expect(r.getSvc).To(Succeed())
+			},
+		},
+		{
+			name:           "error when create drainer peer service",
+			errOnCreateSvc: true,
+			expectFn: func(g *GomegaWithT, r *result) {
+				g.Expect(r.sync).NotTo(Succeed())
+				g.Expect(r.getSet).NotTo(Succeed())
+				g.Expect(r.getCm).NotTo(Succeed())
+				g.Expect(r.getSvc).NotTo(Succeed())
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		testFn(tt, t)
+	}
+}
+
+func TestDrainerMemberManagerSyncMaintenanceWindow(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForDrainer()
+	dmm, _ := newFakeDrainerMemberManager()
+	g.Expect(dmm.Sync(tc)).To(Succeed())
+
+	tc.Spec.Drainer.Image = "drainer-test-image-2"
+	tc.Spec.MaintenanceWindows = []v1alpha1.MaintenanceWindow{{Schedule: "0 0 1 1 *", Duration: "1m"}}
+	g.Expect(dmm.Sync(tc)).To(Succeed())
+
+	set, err := dmm.deps.StatefulSetLister.StatefulSets(tc.Namespace).Get(controller.DrainerMemberName(tc.Name))
+	g.Expect(err).To(Succeed())
+	g.Expect(set.Spec.Template.Spec.Containers[0].Image).To(Equal("drainer-test-image"))
+	cond := utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.TidbClusterMaintenancePending)
+	g.Expect(cond).NotTo(BeNil())
+	g.Expect(cond.Status).To(Equal(corev1.ConditionTrue))
+	g.Expect(cond.Message).To(ContainSubstring(controller.DrainerMemberName(tc.Name)))
+}
+
+func TestGetNewDrainerConfigMap(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tests := []struct {
+		name     string
+		prepare  func(*v1alpha1.TidbCluster)
+		expected string
+	}{
+		{
+			name: "basic",
+			expected: `detect-interval = 10
+
+[syncer]
+  worker-count = 16
+`,
+		},
+		{
+			name: "sink and checkpoint",
+			prepare: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.Drainer.Sink = &v1alpha1.DrainerSinkSpec{
+					Type: "mysql",
+					Config: config.New(map[string]interface{}{
+						"host": "downstream",
+						"port": 3306,
+					}),
+				}
+				tc.Spec.Drainer.Checkpoint = &v1alpha1.DrainerCheckpointSpec{
+					Type: "tidb",
+					Config: config.New(map[string]interface{}{
+						"schema": "tidb_binlog",
+					}),
+				}
+			},
+			expected: `detect-interval = 10
+
+[syncer]
+  db-type = "mysql"
+  worker-count = 16
+  [syncer.to]
+    host = "downstream"
+    port = 3306
+    [syncer.to.checkpoint]
+      schema = "tidb_binlog"
+      type = "tidb"
+`,
+		},
+		{
+			name: "tls enabled",
+			prepare: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TLSCluster = &v1alpha1.TLSCluster{Enabled: true}
+			},
+			expected: `detect-interval = 10
+
+[security]
+  ssl-ca = "/var/lib/drainer-tls/ca.crt"
+  ssl-cert = "/var/lib/drainer-tls/tls.crt"
+  ssl-key = "/var/lib/drainer-tls/tls.key"
+
+[syncer]
+  worker-count = 16
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Log(tt.name)
+		tc := newTidbClusterForDrainer()
+		if tt.prepare != nil {
+			tt.prepare(tc)
+		}
+		cm, err := getNewDrainerConfigMap(tc)
+		g.Expect(err).To(Succeed())
+		g.Expect(cm.Name).To(Equal("test-drainer"))
+		g.Expect(cm.Data["drainer-config"]).To(Equal(tt.expected))
+		// the spec must not be changed by rendering
+		g.Expect(tc.Spec.Drainer.Config.Get("syncer.db-type")).To(BeNil())
+	}
+}
+
+func TestDrainerSyncTiDBClusterStatus(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForDrainer()
+	set := &appsv1.StatefulSet{
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: pointer.Int32Ptr(1),
+		},
+		Status: appsv1.StatefulSetStatus{
+			Replicas:        int32(1),
+			CurrentRevision: "drainer-v1",
+			UpdateRevision:  "drainer-v2",
+		},
+	}
+
+	dmm, fakeDeps := newFakeDrainerMemberManager()
+	pumpControl := fakeDeps.PumpControl.(*controller.FakePumpControl)
+	pumpControl.SetDrainer(&controller.BinlogNode{
+		NodeID:      "drainer-0",
+		Host:        "test-drainer-0.test-drainer:8249",
+		State:       v1alpha1.DrainerStateOnline,
+		MaxCommitTS: 42,
+	})
+	// drainer created by helm
+	pumpControl.SetDrainer(&controller.BinlogNode{
+		NodeID: "helm-drainer-0",
+		Host:   "test-helm-drainer-0.test-helm-drainer:8249",
+		State:  v1alpha1.DrainerStateOnline,
+	})
+
+	err := dmm.syncTiDBClusterStatus(tc, set)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tc.Status.Drainer.StatefulSet.Replicas).To(Equal(int32(1)))
+	g.Expect(tc.Status.Drainer.Phase).To(Equal(v1alpha1.UpgradePhase))
+	g.Expect(tc.Status.Drainer.Members).To(Equal(map[string]v1alpha1.DrainerMember{
+		"test-drainer-0": {
+			NodeID:       "drainer-0",
+			Host:         "test-drainer-0.test-drainer:8249",
+			State:        v1alpha1.DrainerStateOnline,
+			CheckpointTS: "42",
+		},
+	}))
+}
+
+func newFakeDrainerMemberManager() (*drainerMemberManager, *controller.Dependencies) {
+	fakeDeps := controller.NewFakeDependencies()
+	dmm := &drainerMemberManager{deps: fakeDeps, scaler: NewFakeDrainerScaler()}
+	return dmm, fakeDeps
+}
+
+func newTidbClusterForDrainer() *v1alpha1.TidbCluster {
+	tc := newTidbClusterForPump()
+	tc.Spec.Drainer = &v1alpha1.DrainerSpec{
+		ComponentSpec: v1alpha1.ComponentSpec{
+			Image: "drainer-test-image",
+		},
+		Config: config.New(map[string]interface{}{
+			"detect-interval": 10,
+			"syncer": map[string]interface{}{
+				"worker-count": 16,
+			},
+		}),
+		Replicas: 1,
+		ResourceRequirements: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceStorage: resource.MustParse("10Gi"),
+			},
+		},
+		StorageClassName: pointer.StringPtr("my-storage-class"),
+	}
+	return tc
+}
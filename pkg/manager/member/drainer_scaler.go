@@ -0,0 +1,143 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	apps "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
+)
+
+type drainerScaler struct {
+	generalScaler
+}
+
+// NewDrainerScaler returns a drainer Scaler
+func NewDrainerScaler(deps *controller.Dependencies) Scaler {
+	return &drainerScaler{generalScaler: generalScaler{deps: deps}}
+}
+
+func (s *drainerScaler) Scale(meta metav1.Object, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+	scaling, _, _, _ := scaleOne(oldSet, newSet)
+	if scaling > 0 {
+		return s.ScaleOut(meta, oldSet, newSet)
+	} else if scaling < 0 {
+		return s.ScaleIn(meta, oldSet, newSet)
+	}
+	return s.SyncAutoScalerAnn(meta, oldSet)
+}
+
+func (s *drainerScaler) ScaleOut(meta metav1.Object, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+	_, ordinal, replicas, deleteSlots := scaleOne(oldSet, newSet)
+	resetReplicas(newSet, oldSet)
+
+	klog.Infof("scaling out drainer statefulset %s/%s, ordinal: %d (replicas: %d, delete slots: %v)", oldSet.Namespace, oldSet.Name, ordinal, replicas, deleteSlots.List())
+	setReplicasAndDeleteSlots(newSet, replicas, deleteSlots)
+	return nil
+}
+
+// ScaleIn makes the drainer go offline before deleting the pod, so that its
+// registry in PD is not left online and pumps stop serving binlogs to it.
+func (s *drainerScaler) ScaleIn(meta metav1.Object, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+	tc, ok := meta.(*v1alpha1.TidbCluster)
+	if !ok {
+		return fmt.Errorf("drainerScaler.ScaleIn: failed to convert cluster %s/%s", meta.GetNamespace(), meta.GetName())
+	}
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	// we can only remove one member at a time when scaling in
+	_, ordinal, replicas, deleteSlots := scaleOne(oldSet, newSet)
+	resetReplicas(newSet, oldSet)
+
+	klog.Infof("scaling in drainer statefulset %s/%s, ordinal: %d (replicas: %d, delete slots: %v)", oldSet.Namespace, oldSet.Name, ordinal, replicas, deleteSlots.List())
+	if tc.Spec.Pump == nil {
+		// the drainers are registered through pumps, nothing to offline without pumps
+		klog.Infof("drainer scale in: tidb cluster %s/%s has no pump, delete drainer %d directly", ns, tcName, ordinal)
+		setReplicasAndDeleteSlots(newSet, replicas, deleteSlots)
+		return nil
+	}
+
+	podName := ordinalPodName(v1alpha1.DrainerMemberType, tcName, ordinal)
+	pod, err := s.deps.PodLister.Pods(ns).Get(podName)
+	if err != nil {
+		return fmt.Errorf("drainerScaler.ScaleIn: failed to get pod %s for cluster %s/%s, error: %s", podName, ns, tcName, err)
+	}
+
+	member, exist := tc.Status.Drainer.Members[podName]
+	if !exist {
+		// The drainer has never registered in PD, e.g. the pod is always pending,
+		// delete it directly to avoid blocking the subsequent operations.
+		if podutil.IsPodReady(pod) {
+			return controller.RequeueErrorf("drainer %s/%s is not found in PD, wait for its status to be synced", ns, podName)
+		}
+		klog.Infof("drainer scale in: drainer %s/%s is not ready and not found in PD, delete it directly", ns, podName)
+		setReplicasAndDeleteSlots(newSet, replicas, deleteSlots)
+		return nil
+	}
+
+	switch member.State {
+	case v1alpha1.DrainerStateOffline:
+		klog.Infof("drainer scale in: drainer %s/%s node %s is offline", ns, podName, member.NodeID)
+		setReplicasAndDeleteSlots(newSet, replicas, deleteSlots)
+		return nil
+	case v1alpha1.DrainerStateClosing:
+		return controller.RequeueErrorf("drainer %s/%s node %s is closing", ns, podName, member.NodeID)
+	default:
+		if err := s.deps.PumpControl.OfflineDrainer(tc, ordinal, member.NodeID); err != nil {
+			klog.Errorf("drainer scale in: failed to offline drainer %s/%s node %s, %v", ns, podName, member.NodeID, err)
+			return err
+		}
+		klog.Infof("drainer scale in: offline drainer %s/%s node %s successfully", ns, podName, member.NodeID)
+		return controller.RequeueErrorf("drainer %s/%s node %s is going offline, state: %s", ns, podName, member.NodeID, member.State)
+	}
+}
+
+func (s *drainerScaler) SyncAutoScalerAnn(meta metav1.Object, actual *apps.StatefulSet) error {
+	return nil
+}
+
+type fakeDrainerScaler struct{}
+
+// NewFakeDrainerScaler returns a fake drainer Scaler
+func NewFakeDrainerScaler() Scaler {
+	return &fakeDrainerScaler{}
+}
+
+func (s *fakeDrainerScaler) Scale(meta metav1.Object, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+	if *newSet.Spec.Replicas > *oldSet.Spec.Replicas {
+		return s.ScaleOut(meta, oldSet, newSet)
+	} else if *newSet.Spec.Replicas < *oldSet.Spec.Replicas {
+		return s.ScaleIn(meta, oldSet, newSet)
+	}
+	return nil
+}
+
+func (s *fakeDrainerScaler) ScaleOut(_ metav1.Object, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+	setReplicasAndDeleteSlots(newSet, *oldSet.Spec.Replicas+1, nil)
+	return nil
+}
+
+func (s *fakeDrainerScaler) ScaleIn(_ metav1.Object, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+	setReplicasAndDeleteSlots(newSet, *oldSet.Spec.Replicas-1, nil)
+	return nil
+}
+
+func (s *fakeDrainerScaler) SyncAutoScalerAnn(_ metav1.Object, actual *apps.StatefulSet) error {
+	return nil
+}
@@ -0,0 +1,163 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestDrainerScalerScaleIn(t *testing.T) {
+	g := NewGomegaWithT(t)
+	type testcase struct {
+		name        string
+		changeFn    func(*v1alpha1.TidbCluster)
+		podReady    bool
+		errExpectFn func(*GomegaWithT, error)
+		changed     bool
+		offlined    bool
+	}
+
+	testFn := func(test testcase, t *testing.T) {
+		t.Log(test.name)
+		tc := newTidbClusterForDrainerScale()
+		if test.changeFn != nil {
+			test.changeFn(tc)
+		}
+		oldSet := newStatefulSetForDrainerScale()
+		newSet := oldSet.DeepCopy()
+		newSet.Spec.Replicas = pointer.Int32Ptr(1)
+
+		fakeDeps := controller.NewFakeDependencies()
+		scaler := &drainerScaler{generalScaler: generalScaler{deps: fakeDeps}}
+		pumpControl := fakeDeps.PumpControl.(*controller.FakePumpControl)
+		podIndexer := fakeDeps.KubeInformerFactory.Core().V1().Pods().Informer().GetIndexer()
+		for i := int32(0); i < *oldSet.Spec.Replicas; i++ {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      ordinalPodName(v1alpha1.DrainerMemberType, tc.Name, i),
+					Namespace: corev1.NamespaceDefault,
+				},
+			}
+			if test.podReady {
+				pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+			}
+			podIndexer.Add(pod)
+		}
+		for _, member := range tc.Status.Drainer.Members {
+			pumpControl.SetDrainer(&controller.BinlogNode{NodeID: member.NodeID, Host: member.Host, State: member.State})
+		}
+
+		err := scaler.Scale(tc, oldSet, newSet)
+		test.errExpectFn(g, err)
+		if test.changed {
+			g.Expect(int(*newSet.Spec.Replicas)).To(Equal(1))
+		} else {
+			g.Expect(int(*newSet.Spec.Replicas)).To(Equal(2))
+		}
+		drainers, err := pumpControl.GetDrainers(tc)
+		g.Expect(err).NotTo(HaveOccurred())
+		for _, drainer := range drainers {
+			if drainer.NodeID == "drainer-1" && test.offlined {
+				g.Expect(drainer.State).To(Equal(v1alpha1.DrainerStateClosing))
+			}
+		}
+	}
+
+	setState := func(state string) func(*v1alpha1.TidbCluster) {
+		return func(tc *v1alpha1.TidbCluster) {
+			podName := ordinalPodName(v1alpha1.DrainerMemberType, tc.Name, 1)
+			member := tc.Status.Drainer.Members[podName]
+			member.State = state
+			tc.Status.Drainer.Members[podName] = member
+		}
+	}
+
+	tests := []testcase{
+		{
+			name:        "drainer is online",
+			errExpectFn: errExpectRequeue,
+			changed:     false,
+			offlined:    true,
+		},
+		{
+			name:        "drainer is closing",
+			changeFn:    setState(v1alpha1.DrainerStateClosing),
+			errExpectFn: errExpectRequeue,
+			changed:     false,
+		},
+		{
+			name:        "drainer is offline",
+			changeFn:    setState(v1alpha1.DrainerStateOffline),
+			errExpectFn: errExpectNil,
+			changed:     true,
+		},
+		{
+			name: "drainer is not registered and the pod is ready",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				delete(tc.Status.Drainer.Members, ordinalPodName(v1alpha1.DrainerMemberType, tc.Name, 1))
+			},
+			podReady:    true,
+			errExpectFn: errExpectRequeue,
+			changed:     false,
+		},
+		{
+			name: "no pump",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.Pump = nil
+			},
+			errExpectFn: errExpectNil,
+			changed:     true,
+		},
+	}
+
+	for _, test := range tests {
+		testFn(test, t)
+	}
+}
+
+func newStatefulSetForDrainerScale() *apps.StatefulSet {
+	return &apps.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      controller.DrainerMemberName(upgradeTcName),
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: apps.StatefulSetSpec{
+			Replicas: pointer.Int32Ptr(2),
+		},
+	}
+}
+
+func newTidbClusterForDrainerScale() *v1alpha1.TidbCluster {
+	tc := newTidbClusterForDrainer()
+	tc.Name = upgradeTcName
+	tc.Status.Drainer.Members = map[string]v1alpha1.DrainerMember{}
+	for i := int32(0); i < 2; i++ {
+		podName := ordinalPodName(v1alpha1.DrainerMemberType, tc.Name, i)
+		tc.Status.Drainer.Members[podName] = v1alpha1.DrainerMember{
+			NodeID: fmt.Sprintf("drainer-%d", i),
+			Host:   fmt.Sprintf("%s.%s:8249", podName, controller.DrainerPeerMemberName(tc.Name)),
+			State:  v1alpha1.DrainerStateOnline,
+		}
+	}
+	return tc
+}
@@ -0,0 +1,344 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/features"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog"
+)
+
+// LocalPVRecoverer recreates the PD members and TiKV stores whose local volumes are
+// lost with their nodes. A node is lost if it is NotReady or deleted for
+// --local-pv-recovery-period, the member or the store on it is deleted through PD
+// once PD confirms it is unhealthy, then the stale PVC and pod are deleted to let
+// the StatefulSet recreate the pod on another node. The TiKV stores are recovered one
+// at a time.
+type LocalPVRecoverer interface {
+	Sync(tc *v1alpha1.TidbCluster) error
+}
+
+type localPVRecoverer struct {
+	deps *controller.Dependencies
+}
+
+// NewLocalPVRecoverer returns a LocalPVRecoverer
+func NewLocalPVRecoverer(deps *controller.Dependencies) LocalPVRecoverer {
+	return &localPVRecoverer{deps: deps}
+}
+
+func (r *localPVRecoverer) Sync(tc *v1alpha1.TidbCluster) error {
+	if !features.DefaultFeatureGate.Enabled(features.LocalPVRecovery) || tc.Spec.Paused {
+		return nil
+	}
+
+	if tc.Spec.PD != nil && tc.Status.PD.Synced {
+		if err := r.recoverPDMembers(tc); err != nil {
+			return err
+		}
+	}
+	if tc.Spec.TiKV != nil && tc.Status.TiKV.Synced {
+		lost, err := r.recoverTiKVStores(tc, tc.Status.TiKV.Stores, tc.Status.TiKV.LostStores, tikvStoresRecovering(tc, ""))
+		tc.Status.TiKV.LostStores = lost
+		if err != nil {
+			return err
+		}
+	}
+	for i := range tc.Spec.TiKVGroups {
+		group := &tc.Spec.TiKVGroups[i]
+		status, ok := tc.Status.TiKV.Groups[group.Name]
+		if !ok || !status.Synced {
+			continue
+		}
+		lost, err := r.recoverTiKVStores(tc.TiKVGroupCluster(group), status.Stores, status.LostStores, tikvStoresRecovering(tc, group.Name))
+		status.LostStores = lost
+		tc.Status.TiKV.Groups[group.Name] = status
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *localPVRecoverer) recoverPDMembers(tc *v1alpha1.TidbCluster) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	for name, member := range tc.Status.PD.Members {
+		podName := strings.Split(name, ".")[0]
+		if _, ok := tc.Status.PD.LostMembers[podName]; ok || member.Health {
+			continue
+		}
+		nodeName, lost, err := r.lostNode(ns, podName)
+		if err != nil {
+			return err
+		}
+		if !lost {
+			continue
+		}
+		if tc.Status.PD.LostMembers == nil {
+			tc.Status.PD.LostMembers = map[string]v1alpha1.LostMember{}
+		}
+		tc.Status.PD.LostMembers[podName] = v1alpha1.LostMember{
+			PodName:   podName,
+			ID:        member.ID,
+			NodeName:  nodeName,
+			CreatedAt: metav1.Now(),
+		}
+		klog.Infof("local pv recovery: tidbcluster %s/%s, node %s of pd member %s is lost", ns, tcName, nodeName, podName)
+	}
+
+	for podName, lostMember := range tc.Status.PD.LostMembers {
+		if !lostMember.Deleted {
+			id, err := strconv.ParseUint(lostMember.ID, 10, 64)
+			if err != nil {
+				return err
+			}
+			if err := controller.GetPDClient(r.deps.PDControl, tc).DeleteMemberByID(id); err != nil {
+				klog.Errorf("local pv recovery: failed to delete pd member %d of pod %s/%s, %v", id, ns, podName, err)
+				return err
+			}
+			lostMember.Deleted = true
+			tc.Status.PD.LostMembers[podName] = lostMember
+			klog.Infof("local pv recovery: delete pd member %d of pod %s/%s successfully", id, ns, podName)
+			r.deps.Recorder.Eventf(tc, corev1.EventTypeWarning, "PDMemberDeleted", "%s(%d) deleted from cluster, node %s is lost", podName, id, lostMember.NodeName)
+		}
+
+		replaced, err := replacePodWithFreshStorage(r.deps, tc, controller.PDMemberName(tcName), podName, lostMember.CreatedAt)
+		if err != nil {
+			return err
+		}
+		if replaced {
+			delete(tc.Status.PD.LostMembers, podName)
+			klog.Infof("local pv recovery: pd member %s/%s is recreated on fresh storage", ns, podName)
+			r.deps.Recorder.Eventf(tc, corev1.EventTypeNormal, "PDMemberRecovered", "%s recreated on fresh storage", podName)
+		}
+	}
+	if len(tc.Status.PD.LostMembers) == 0 {
+		tc.Status.PD.LostMembers = nil
+	}
+	return nil
+}
+
+// recoverTiKVStores recovers the lost stores of the default TiKV or a TiKV group,
+// it returns the lost stores after recovering. No store is marked lost while the
+// stores of the others are being recovered.
+func (r *localPVRecoverer) recoverTiKVStores(tc *v1alpha1.TidbCluster, stores map[string]v1alpha1.TiKVStore, lostStores map[string]v1alpha1.LostMember, othersRecovering bool) (map[string]v1alpha1.LostMember, error) {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	if lostStores == nil {
+		lostStores = map[string]v1alpha1.LostMember{}
+	}
+	// the stores are recovered one at a time, the regions may lose the majority of
+	// their replicas if several stores are deleted at once
+	for _, storeID := range sets.StringKeySet(stores).List() {
+		if othersRecovering || len(lostStores) > 0 {
+			break
+		}
+		store := stores[storeID]
+		if store.State != v1alpha1.TiKVStateDown {
+			continue
+		}
+		nodeName, lost, err := r.lostNode(ns, store.PodName)
+		if err != nil {
+			return cleanLostMembers(lostStores), err
+		}
+		if !lost {
+			continue
+		}
+		lostStores[storeID] = v1alpha1.LostMember{
+			PodName:   store.PodName,
+			ID:        storeID,
+			NodeName:  nodeName,
+			CreatedAt: metav1.Now(),
+		}
+		klog.Infof("local pv recovery: tidbcluster %s/%s, node %s of tikv store %s of pod %s is lost", ns, tcName, nodeName, storeID, store.PodName)
+	}
+
+	for storeID, lostStore := range lostStores {
+		podName := lostStore.PodName
+		if !lostStore.Deleted {
+			id, err := strconv.ParseUint(storeID, 10, 64)
+			if err != nil {
+				return cleanLostMembers(lostStores), err
+			}
+			if err := controller.GetPDClient(r.deps.PDControl, tc).DeleteStore(id); err != nil {
+				klog.Errorf("local pv recovery: failed to delete store %d of pod %s/%s, %v", id, ns, podName, err)
+				return cleanLostMembers(lostStores), err
+			}
+			lostStore.Deleted = true
+			lostStores[storeID] = lostStore
+			klog.Infof("local pv recovery: delete store %d of pod %s/%s successfully", id, ns, podName)
+			r.deps.Recorder.Eventf(tc, corev1.EventTypeWarning, "TiKVStoreDeleted", "%s(%d) deleted from cluster, node %s is lost", podName, id, lostStore.NodeName)
+			continue
+		}
+
+		// the new store can not join with the address of the store until it is Tombstone
+		if _, exist := stores[storeID]; exist {
+			klog.Infof("local pv recovery: store %s of pod %s/%s is not Tombstone yet", storeID, ns, podName)
+			continue
+		}
+		replaced, err := replacePodWithFreshStorage(r.deps, tc, tikvMemberName(tc), podName, lostStore.CreatedAt)
+		if err != nil {
+			return cleanLostMembers(lostStores), err
+		}
+		if replaced {
+			delete(lostStores, storeID)
+			klog.Infof("local pv recovery: tikv pod %s/%s is recreated on fresh storage", ns, podName)
+			r.deps.Recorder.Eventf(tc, corev1.EventTypeNormal, "TiKVStoreRecovered", "%s recreated on fresh storage", podName)
+		}
+	}
+	return cleanLostMembers(lostStores), nil
+}
+
+// tikvStoresRecovering returns whether the stores of the default TiKV or the TiKV
+// groups other than the given group are being recovered, the empty group is the default TiKV
+func tikvStoresRecovering(tc *v1alpha1.TidbCluster, group string) bool {
+	if group != "" && len(tc.Status.TiKV.LostStores) > 0 {
+		return true
+	}
+	for name, status := range tc.Status.TiKV.Groups {
+		if name != group && len(status.LostStores) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// lostNode returns the node of the local volumes of the pod and whether the node
+// has been NotReady or deleted for the recovery period
+func (r *localPVRecoverer) lostNode(ns, podName string) (string, bool, error) {
+	pod, err := r.deps.PodLister.Pods(ns).Get(podName)
+	if errors.IsNotFound(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	var nodeName string
+	for _, vol := range pod.Spec.Volumes {
+		if vol.PersistentVolumeClaim == nil {
+			continue
+		}
+		pvc, err := r.deps.PVCLister.PersistentVolumeClaims(ns).Get(vol.PersistentVolumeClaim.ClaimName)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return "", false, err
+		}
+		if pvc.Spec.VolumeName == "" {
+			continue
+		}
+		pv, err := r.deps.PVLister.Get(pvc.Spec.VolumeName)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return "", false, err
+		}
+		if nodeName = localPVNodeName(pv); nodeName != "" {
+			break
+		}
+	}
+	if nodeName == "" {
+		return "", false, nil
+	}
+
+	period := r.deps.CLIConfig.LocalPVRecoveryPeriod
+	nodes, err := r.deps.NodeLister.List(labels.SelectorFromSet(labels.Set{corev1.LabelHostname: nodeName}))
+	if err != nil {
+		return "", false, err
+	}
+	if len(nodes) > 0 {
+		node := nodes[0]
+		since := node.CreationTimestamp
+		for _, cond := range node.Status.Conditions {
+			if cond.Type != corev1.NodeReady {
+				continue
+			}
+			if cond.Status == corev1.ConditionTrue {
+				return nodeName, false, nil
+			}
+			since = cond.LastTransitionTime
+		}
+		return nodeName, time.Now().After(since.Add(period)), nil
+	}
+
+	// the pods on the deleted node are deleted by the pod GC and recreated by
+	// the StatefulSet, they are pending as the volumes are bound to the node
+	if pod.Spec.NodeName != "" {
+		return nodeName, false, nil
+	}
+	since := pod.CreationTimestamp
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse {
+			since = cond.LastTransitionTime
+		}
+	}
+	return nodeName, time.Now().After(since.Add(period)), nil
+}
+
+// localPVNodeName returns the hostname of the node the local volume is on
+func localPVNodeName(pv *corev1.PersistentVolume) string {
+	if pv.Spec.Local == nil || pv.Spec.NodeAffinity == nil || pv.Spec.NodeAffinity.Required == nil {
+		return ""
+	}
+	for _, term := range pv.Spec.NodeAffinity.Required.NodeSelectorTerms {
+		for _, expr := range term.MatchExpressions {
+			if expr.Key == corev1.LabelHostname && expr.Operator == corev1.NodeSelectorOpIn && len(expr.Values) == 1 {
+				return expr.Values[0]
+			}
+		}
+	}
+	return ""
+}
+
+func cleanLostMembers(members map[string]v1alpha1.LostMember) map[string]v1alpha1.LostMember {
+	if len(members) == 0 {
+		return nil
+	}
+	return members
+}
+
+// FakeLocalPVRecoverer is a fake implementation of LocalPVRecoverer
+type FakeLocalPVRecoverer struct {
+	err error
+}
+
+// NewFakeLocalPVRecoverer returns a FakeLocalPVRecoverer
+func NewFakeLocalPVRecoverer() *FakeLocalPVRecoverer {
+	return &FakeLocalPVRecoverer{}
+}
+
+// SetSyncError sets the error returned by Sync
+func (r *FakeLocalPVRecoverer) SetSyncError(err error) {
+	r.err = err
+}
+
+func (r *FakeLocalPVRecoverer) Sync(_ *v1alpha1.TidbCluster) error {
+	return r.err
+}
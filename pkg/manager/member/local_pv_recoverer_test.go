@@ -0,0 +1,243 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/features"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestLocalPVRecovererSync(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	saved := features.DefaultFeatureGate.String()
+	defer features.DefaultFeatureGate.Set(saved) // reset features on exit
+
+	hoursAgo := func(hours int) metav1.Time {
+		return metav1.NewTime(time.Now().Add(-time.Duration(hours) * time.Hour))
+	}
+
+	type fixture struct {
+		deps      *controller.Dependencies
+		tc        *v1alpha1.TidbCluster
+		pdActions map[pdapi.ActionType]int
+		exist     func(podName, pvcName string) (bool, bool)
+	}
+
+	newFixture := func() *fixture {
+		f := &fixture{
+			deps:      controller.NewFakeDependencies(),
+			tc:        newTidbClusterForPD(),
+			pdActions: map[pdapi.ActionType]int{},
+		}
+		ns := f.tc.Namespace
+		informers := f.deps.KubeInformerFactory
+		podIndexer := informers.Core().V1().Pods().Informer().GetIndexer()
+		pvcIndexer := informers.Core().V1().PersistentVolumeClaims().Informer().GetIndexer()
+
+		for _, memberType := range []v1alpha1.MemberType{v1alpha1.PDMemberType, v1alpha1.TiKVMemberType} {
+			setName := fmt.Sprintf("%s-%s", f.tc.Name, memberType)
+			podName := ordinalPodName(memberType, f.tc.Name, 0)
+			pvcName := ordinalPVCName(memberType, setName, 0)
+			pvName := fmt.Sprintf("pv-%s", memberType)
+
+			informers.Apps().V1().StatefulSets().Informer().GetIndexer().Add(&apps.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: setName, Namespace: ns},
+				Spec: apps.StatefulSetSpec{
+					VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+						{ObjectMeta: metav1.ObjectMeta{Name: memberType.String()}},
+					},
+				},
+			})
+			// the pod is recreated by the StatefulSet and pending on the lost node
+			podIndexer.Add(&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: podName, Namespace: ns, CreationTimestamp: hoursAgo(3)},
+				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{{
+						Name: memberType.String(),
+						VolumeSource: corev1.VolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: pvcName},
+						},
+					}},
+				},
+				Status: corev1.PodStatus{
+					Conditions: []corev1.PodCondition{
+						{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, LastTransitionTime: hoursAgo(2)},
+					},
+				},
+			})
+			pvcIndexer.Add(&corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: pvcName, Namespace: ns, CreationTimestamp: hoursAgo(3)},
+				Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: pvName},
+			})
+			informers.Core().V1().PersistentVolumes().Informer().GetIndexer().Add(&corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: pvName},
+				Spec: corev1.PersistentVolumeSpec{
+					PersistentVolumeSource: corev1.PersistentVolumeSource{
+						Local: &corev1.LocalVolumeSource{Path: "/mnt/disks/1"},
+					},
+					NodeAffinity: &corev1.VolumeNodeAffinity{
+						Required: &corev1.NodeSelector{
+							NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+								MatchExpressions: []corev1.NodeSelectorRequirement{
+									{Key: corev1.LabelHostname, Operator: corev1.NodeSelectorOpIn, Values: []string{"node-1"}},
+								},
+							}},
+						},
+					},
+				},
+			})
+		}
+
+		f.tc.Status.PD.Synced = true
+		f.tc.Status.PD.Members = map[string]v1alpha1.PDMember{
+			"test-pd-0": {Name: "test-pd-0", ID: "10", Health: false},
+		}
+		f.tc.Status.TiKV.Synced = true
+		f.tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+			"1": {ID: "1", PodName: ordinalPodName(v1alpha1.TiKVMemberType, f.tc.Name, 0), State: v1alpha1.TiKVStateDown},
+		}
+
+		pdClient := controller.NewFakePDClient(f.deps.PDControl.(*pdapi.FakePDControl), f.tc)
+		for _, action := range []pdapi.ActionType{pdapi.DeleteMemberByIDActionType, pdapi.DeleteStoreActionType} {
+			actionType := action
+			pdClient.AddReaction(actionType, func(action *pdapi.Action) (interface{}, error) {
+				f.pdActions[actionType]++
+				return nil, nil
+			})
+		}
+
+		f.exist = func(podName, pvcName string) (bool, bool) {
+			_, podExist, _ := podIndexer.GetByKey(ns + "/" + podName)
+			_, pvcExist, _ := pvcIndexer.GetByKey(ns + "/" + pvcName)
+			return podExist, pvcExist
+		}
+		return f
+	}
+	addNode := func(f *fixture, ready corev1.ConditionStatus, since metav1.Time) {
+		f.deps.KubeInformerFactory.Core().V1().Nodes().Informer().GetIndexer().Add(&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "node-1",
+				Labels: map[string]string{corev1.LabelHostname: "node-1"},
+			},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{
+					{Type: corev1.NodeReady, Status: ready, LastTransitionTime: since},
+				},
+			},
+		})
+	}
+
+	t.Log("feature disabled")
+	features.DefaultFeatureGate.Set("LocalPVRecovery=false")
+	f := newFixture()
+	g.Expect(NewLocalPVRecoverer(f.deps).Sync(f.tc)).To(Succeed())
+	g.Expect(f.tc.Status.PD.LostMembers).To(BeNil())
+	g.Expect(f.tc.Status.TiKV.LostStores).To(BeNil())
+
+	features.DefaultFeatureGate.Set("LocalPVRecovery=true")
+
+	t.Log("node is ready")
+	f = newFixture()
+	addNode(f, corev1.ConditionTrue, hoursAgo(2))
+	g.Expect(NewLocalPVRecoverer(f.deps).Sync(f.tc)).To(Succeed())
+	g.Expect(f.tc.Status.PD.LostMembers).To(BeNil())
+	g.Expect(f.tc.Status.TiKV.LostStores).To(BeNil())
+
+	t.Log("node is NotReady within the recovery period")
+	f = newFixture()
+	addNode(f, corev1.ConditionFalse, metav1.Now())
+	g.Expect(NewLocalPVRecoverer(f.deps).Sync(f.tc)).To(Succeed())
+	g.Expect(f.tc.Status.PD.LostMembers).To(BeNil())
+	g.Expect(f.tc.Status.TiKV.LostStores).To(BeNil())
+
+	t.Log("node is NotReady for the recovery period")
+	f = newFixture()
+	addNode(f, corev1.ConditionUnknown, hoursAgo(1))
+	g.Expect(NewLocalPVRecoverer(f.deps).Sync(f.tc)).To(Succeed())
+	g.Expect(f.tc.Status.PD.LostMembers).To(HaveKey("test-pd-0"))
+	g.Expect(f.tc.Status.TiKV.LostStores).To(HaveKey("1"))
+
+	t.Log("pod is terminating on the NotReady node")
+	f = newFixture()
+	addNode(f, corev1.ConditionUnknown, hoursAgo(1))
+	podIndexer := f.deps.KubeInformerFactory.Core().V1().Pods().Informer().GetIndexer()
+	obj, _, _ := podIndexer.GetByKey(f.tc.Namespace + "/test-pd-0")
+	pod := obj.(*corev1.Pod).DeepCopy()
+	pod.Spec.NodeName = "node-1"
+	now := metav1.Now()
+	pod.DeletionTimestamp = &now
+	podIndexer.Update(pod)
+	g.Expect(NewLocalPVRecoverer(f.deps).Sync(f.tc)).To(Succeed())
+	g.Expect(f.tc.Status.PD.LostMembers["test-pd-0"].Deleted).To(BeTrue())
+	podExist, pvcExist := f.exist("test-pd-0", "pd-test-pd-0")
+	g.Expect(podExist).To(BeFalse())
+	g.Expect(pvcExist).To(BeFalse())
+
+	t.Log("tikv stores are recovered one at a time")
+	f = newFixture()
+	f.tc.Status.TiKV.Stores["2"] = v1alpha1.TiKVStore{ID: "2", PodName: ordinalPodName(v1alpha1.TiKVMemberType, f.tc.Name, 0), State: v1alpha1.TiKVStateDown}
+	g.Expect(NewLocalPVRecoverer(f.deps).Sync(f.tc)).To(Succeed())
+	g.Expect(f.tc.Status.TiKV.LostStores).To(HaveLen(1))
+	g.Expect(f.tc.Status.TiKV.LostStores).To(HaveKey("1"))
+	g.Expect(f.pdActions[pdapi.DeleteStoreActionType]).To(Equal(1))
+
+	t.Log("pd member is healthy")
+	f = newFixture()
+	f.tc.Status.PD.Members["test-pd-0"] = v1alpha1.PDMember{Name: "test-pd-0", ID: "10", Health: true}
+	g.Expect(NewLocalPVRecoverer(f.deps).Sync(f.tc)).To(Succeed())
+	g.Expect(f.tc.Status.PD.LostMembers).To(BeNil())
+
+	t.Log("node is deleted")
+	f = newFixture()
+	recoverer := NewLocalPVRecoverer(f.deps)
+	g.Expect(recoverer.Sync(f.tc)).To(Succeed())
+	g.Expect(f.pdActions[pdapi.DeleteMemberByIDActionType]).To(Equal(1))
+	g.Expect(f.pdActions[pdapi.DeleteStoreActionType]).To(Equal(1))
+	g.Expect(f.tc.Status.PD.LostMembers["test-pd-0"].Deleted).To(BeTrue())
+	g.Expect(f.tc.Status.PD.LostMembers["test-pd-0"].NodeName).To(Equal("node-1"))
+	g.Expect(f.tc.Status.TiKV.LostStores["1"].Deleted).To(BeTrue())
+
+	t.Log("pd pod and pvc are deleted, tikv store is not Tombstone yet")
+	podExist, pvcExist = f.exist("test-pd-0", "pd-test-pd-0")
+	g.Expect(podExist).To(BeFalse())
+	g.Expect(pvcExist).To(BeFalse())
+	podExist, pvcExist = f.exist("test-tikv-0", "tikv-test-tikv-0")
+	g.Expect(podExist).To(BeTrue())
+	g.Expect(pvcExist).To(BeTrue())
+
+	t.Log("tikv store is Tombstone")
+	delete(f.tc.Status.TiKV.Stores, "1")
+	g.Expect(recoverer.Sync(f.tc)).To(Succeed())
+	g.Expect(f.tc.Status.PD.LostMembers).To(BeNil())
+	podExist, pvcExist = f.exist("test-tikv-0", "tikv-test-tikv-0")
+	g.Expect(podExist).To(BeFalse())
+	g.Expect(pvcExist).To(BeFalse())
+	g.Expect(f.tc.Status.TiKV.LostStores).To(HaveKey("1"))
+
+	g.Expect(recoverer.Sync(f.tc)).To(Succeed())
+	g.Expect(f.tc.Status.TiKV.LostStores).To(BeNil())
+	g.Expect(f.pdActions[pdapi.DeleteMemberByIDActionType]).To(Equal(1))
+	g.Expect(f.pdActions[pdapi.DeleteStoreActionType]).To(Equal(1))
+}
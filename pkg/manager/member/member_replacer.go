@@ -0,0 +1,449 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	"github.com/pingcap/tidb-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+)
+
+// MemberReplacer replaces the PD members, TiKV and TiFlash stores and pumps whose pods
+// are annotated with tidb.pingcap.com/replace=true or whose volumes are not of the storage
+// classes of the volume claim templates, e.g. after the storage class in the spec is changed.
+// The leaders of a TiKV store are evicted before it is deleted.
+// The member or the store is deleted through PD and the pump is made offline, then the PVCs
+// and the pod are deleted to let the StatefulSet recreate the pod with the same ordinal on
+// fresh storage, it joins the cluster as a new member or store.
+// The members of a component are replaced one by one, a TiKV store is replaced only if the
+// number of Up stores is greater than max-replicas of PD.
+type MemberReplacer interface {
+	Sync(tc *v1alpha1.TidbCluster) error
+}
+
+type memberReplacer struct {
+	deps *controller.Dependencies
+}
+
+// NewMemberReplacer returns a MemberReplacer
+func NewMemberReplacer(deps *controller.Dependencies) MemberReplacer {
+	return &memberReplacer{deps: deps}
+}
+
+func (r *memberReplacer) Sync(tc *v1alpha1.TidbCluster) error {
+	if tc.Spec.Paused {
+		return nil
+	}
+
+	if tc.Spec.PD != nil && tc.Status.PD.Synced {
+		if err := r.replacePDMembers(tc); err != nil {
+			return err
+		}
+	}
+	if tc.Spec.TiKV != nil && tc.Status.TiKV.Synced {
+		replacing, blocked, err := r.replaceStores(tc, v1alpha1.TiKVMemberType, tikvMemberName(tc), tc.Status.TiKV.Stores, tc.Status.TiKV.ReplacingStores)
+		tc.Status.TiKV.ReplacingStores = replacing
+		tc.Status.TiKV.ReplaceBlockedReason = blocked
+		if err != nil {
+			return err
+		}
+	}
+	for i := range tc.Spec.TiKVGroups {
+		group := &tc.Spec.TiKVGroups[i]
+		status, ok := tc.Status.TiKV.Groups[group.Name]
+		if !ok || !status.Synced {
+			continue
+		}
+		gtc := tc.TiKVGroupCluster(group)
+		replacing, blocked, err := r.replaceStores(gtc, v1alpha1.TiKVMemberType, tikvMemberName(gtc), status.Stores, status.ReplacingStores)
+		status.ReplacingStores = replacing
+		status.ReplaceBlockedReason = blocked
+		tc.Status.TiKV.Groups[group.Name] = status
+		if err != nil {
+			return err
+		}
+	}
+	if tc.Spec.TiFlash != nil && tc.Status.TiFlash.Synced {
+		replacing, blocked, err := r.replaceStores(tc, v1alpha1.TiFlashMemberType, controller.TiFlashMemberName(tc.Name), tc.Status.TiFlash.Stores, tc.Status.TiFlash.ReplacingStores)
+		tc.Status.TiFlash.ReplacingStores = replacing
+		tc.Status.TiFlash.ReplaceBlockedReason = blocked
+		if err != nil {
+			return err
+		}
+	}
+	if tc.Spec.Pump != nil {
+		if err := r.replacePumpMembers(tc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *memberReplacer) replacePDMembers(tc *v1alpha1.TidbCluster) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	if len(tc.Status.PD.ReplacingMembers) == 0 {
+		var memberName string
+		for name := range tc.Status.PD.Members {
+			replace, err := r.replaceRequested(ns, controller.PDMemberName(tcName), strings.Split(name, ".")[0])
+			if err != nil {
+				return err
+			}
+			if replace {
+				memberName = name
+				break
+			}
+		}
+		if memberName == "" {
+			return nil
+		}
+		// keep the quorum of PD, a member is replaced only if the other members are healthy
+		for name, member := range tc.Status.PD.Members {
+			if name != memberName && !member.Health {
+				klog.Infof("member replacer: tidbcluster %s/%s, pd member %s is unhealthy, wait before replacing %s", ns, tcName, name, memberName)
+				return nil
+			}
+		}
+
+		podName := strings.Split(memberName, ".")[0]
+		if tc.Status.PD.Leader.Name == memberName || tc.Status.PD.Leader.Name == podName {
+			for name := range tc.Status.PD.Members {
+				if name == memberName {
+					continue
+				}
+				if err := controller.GetPDClient(r.deps.PDControl, tc).TransferPDLeader(name); err != nil {
+					klog.Errorf("member replacer: failed to transfer pd leader from %s/%s to %s, %v", ns, podName, name, err)
+					return err
+				}
+				klog.Infof("member replacer: tidbcluster %s/%s, transfer pd leader from %s to %s before replacing it", ns, tcName, podName, name)
+				return nil
+			}
+			return nil
+		}
+		tc.Status.PD.ReplacingMembers = map[string]v1alpha1.ReplacingMember{
+			podName: {
+				PodName:   podName,
+				ID:        tc.Status.PD.Members[memberName].ID,
+				CreatedAt: metav1.Now(),
+			},
+		}
+		klog.Infof("member replacer: tidbcluster %s/%s, begin replacing pd member %s", ns, tcName, podName)
+	}
+
+	for podName, member := range tc.Status.PD.ReplacingMembers {
+		if !member.Deleted {
+			id, err := strconv.ParseUint(member.ID, 10, 64)
+			if err != nil {
+				return err
+			}
+			if err := controller.GetPDClient(r.deps.PDControl, tc).DeleteMemberByID(id); err != nil {
+				klog.Errorf("member replacer: failed to delete pd member %d of pod %s/%s, %v", id, ns, podName, err)
+				return err
+			}
+			member.Deleted = true
+			tc.Status.PD.ReplacingMembers[podName] = member
+			klog.Infof("member replacer: delete pd member %d of pod %s/%s successfully", id, ns, podName)
+			r.deps.Recorder.Eventf(tc, corev1.EventTypeNormal, "PDMemberReplacing", "%s(%d) deleted from cluster to replace it", podName, id)
+		}
+
+		replaced, err := replacePodWithFreshStorage(r.deps, tc, controller.PDMemberName(tcName), podName, member.CreatedAt)
+		if err != nil {
+			return err
+		}
+		if replaced {
+			delete(tc.Status.PD.ReplacingMembers, podName)
+			klog.Infof("member replacer: pd member %s/%s is recreated on fresh storage", ns, podName)
+			r.deps.Recorder.Eventf(tc, corev1.EventTypeNormal, "PDMemberReplaced", "%s recreated on fresh storage", podName)
+		}
+	}
+	if len(tc.Status.PD.ReplacingMembers) == 0 {
+		tc.Status.PD.ReplacingMembers = nil
+	}
+	return nil
+}
+
+// replaceStores replaces the stores of the default TiKV, a TiKV group or TiFlash, it
+// returns the replacing stores after syncing and the reason why the store requested to
+// be replaced is blocked
+func (r *memberReplacer) replaceStores(tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType, setName string, stores map[string]v1alpha1.TiKVStore, replacing map[string]v1alpha1.ReplacingMember) (map[string]v1alpha1.ReplacingMember, string, error) {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	if len(replacing) == 0 {
+		replacing = map[string]v1alpha1.ReplacingMember{}
+		var storeID string
+		for id, store := range stores {
+			replace, err := r.replaceRequested(ns, setName, store.PodName)
+			if err != nil {
+				return nil, "", err
+			}
+			if replace {
+				storeID = id
+				break
+			}
+		}
+		if storeID == "" {
+			return nil, "", nil
+		}
+		// keep enough replicas of the regions, a store is replaced only if the other stores are Up
+		upStores := 0
+		for id, store := range stores {
+			if store.State == v1alpha1.TiKVStateUp {
+				upStores++
+				continue
+			}
+			if id != storeID {
+				blocked := fmt.Sprintf("store %s of pod %s is %s", id, store.PodName, store.State)
+				klog.Infof("member replacer: tidbcluster %s/%s, %s, wait before replacing store %s", ns, tcName, blocked, storeID)
+				return nil, blocked, nil
+			}
+		}
+		// the regions of the store are moved to the other Up stores before it is Tombstone
+		if memberType == v1alpha1.TiKVMemberType {
+			config, err := controller.GetPDClient(r.deps.PDControl, tc).GetConfig()
+			if err != nil {
+				return nil, "", err
+			}
+			if config.Replication != nil && config.Replication.MaxReplicas != nil && uint64(upStores) <= *config.Replication.MaxReplicas {
+				blocked := fmt.Sprintf("the number of Up stores %d is not greater than max-replicas %d", upStores, *config.Replication.MaxReplicas)
+				klog.Infof("member replacer: tidbcluster %s/%s, %s, wait before replacing store %s", ns, tcName, blocked, storeID)
+				return nil, blocked, nil
+			}
+		}
+		replacing[storeID] = v1alpha1.ReplacingMember{
+			PodName:   stores[storeID].PodName,
+			ID:        storeID,
+			CreatedAt: metav1.Now(),
+		}
+		klog.Infof("member replacer: tidbcluster %s/%s, begin replacing store %s of pod %s", ns, tcName, storeID, stores[storeID].PodName)
+	}
+
+	for storeID, store := range replacing {
+		podName := store.PodName
+		if !store.Deleted {
+			id, err := strconv.ParseUint(storeID, 10, 64)
+			if err != nil {
+				return cleanReplacingMembers(replacing), "", err
+			}
+			if memberType == v1alpha1.TiKVMemberType {
+				evicted, err := r.evictLeaders(tc, id, stores[storeID], podName)
+				if err != nil {
+					return cleanReplacingMembers(replacing), "", err
+				}
+				if !evicted {
+					klog.Infof("member replacer: wait for the leaders of store %d of pod %s/%s to be evicted", id, ns, podName)
+					continue
+				}
+			}
+			if err := controller.GetPDClient(r.deps.PDControl, tc).DeleteStore(id); err != nil {
+				klog.Errorf("member replacer: failed to delete store %d of pod %s/%s, %v", id, ns, podName, err)
+				return cleanReplacingMembers(replacing), "", err
+			}
+			// the store being deleted does not need the evict leader scheduler any more
+			if memberType == v1alpha1.TiKVMemberType {
+				if err := controller.GetPDClient(r.deps.PDControl, tc).EndEvictLeader(id); err != nil {
+					klog.Errorf("member replacer: failed to end evict leader of store %d of pod %s/%s, %v", id, ns, podName, err)
+					return cleanReplacingMembers(replacing), "", err
+				}
+			}
+			store.Deleted = true
+			replacing[storeID] = store
+			klog.Infof("member replacer: delete store %d of pod %s/%s successfully", id, ns, podName)
+			r.deps.Recorder.Eventf(tc, corev1.EventTypeNormal, "StoreReplacing", "%s(%d) deleted from cluster to replace it", podName, id)
+			continue
+		}
+
+		// the new store can not join with the address of the store until it is Tombstone
+		if _, exist := stores[storeID]; exist {
+			klog.Infof("member replacer: store %s of pod %s/%s is not Tombstone yet", storeID, ns, podName)
+			continue
+		}
+		replaced, err := replacePodWithFreshStorage(r.deps, tc, setName, podName, store.CreatedAt)
+		if err != nil {
+			return cleanReplacingMembers(replacing), "", err
+		}
+		if replaced {
+			delete(replacing, storeID)
+			klog.Infof("member replacer: pod %s/%s of store %s is recreated on fresh storage", ns, podName, storeID)
+			r.deps.Recorder.Eventf(tc, corev1.EventTypeNormal, "StoreReplaced", "%s recreated on fresh storage", podName)
+		}
+	}
+	return cleanReplacingMembers(replacing), "", nil
+}
+
+// evictLeaders evicts the leaders of the TiKV store before it is deleted, it returns true
+// once the store has no leaders or the eviction times out
+func (r *memberReplacer) evictLeaders(tc *v1alpha1.TidbCluster, storeID uint64, store v1alpha1.TiKVStore, podName string) (bool, error) {
+	pod, err := r.deps.PodLister.Pods(tc.GetNamespace()).Get(podName)
+	if errors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	u := &tikvUpgrader{deps: r.deps}
+	if _, evicting := pod.Annotations[EvictLeaderBeginTime]; !evicting {
+		return false, u.beginEvictLeader(tc, storeID, pod.DeepCopy())
+	}
+	return u.readyToUpgrade(pod, store, tc.TiKVEvictLeaderTimeout()), nil
+}
+
+func (r *memberReplacer) replacePumpMembers(tc *v1alpha1.TidbCluster) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	if len(tc.Status.Pump.ReplacingMembers) == 0 {
+		for podName, member := range tc.Status.Pump.Members {
+			if member.State != v1alpha1.PumpStateOnline {
+				continue
+			}
+			replace, err := r.replaceRequested(ns, controller.PumpMemberName(tcName), podName)
+			if err != nil {
+				return err
+			}
+			if !replace {
+				continue
+			}
+			tc.Status.Pump.ReplacingMembers = map[string]v1alpha1.ReplacingMember{
+				podName: {
+					PodName:   podName,
+					ID:        member.NodeID,
+					CreatedAt: metav1.Now(),
+				},
+			}
+			klog.Infof("member replacer: tidbcluster %s/%s, begin replacing pump %s", ns, tcName, podName)
+			break
+		}
+	}
+
+	for podName, member := range tc.Status.Pump.ReplacingMembers {
+		if !member.Deleted {
+			ordinal, err := util.GetOrdinalFromPodName(podName)
+			if err != nil {
+				return err
+			}
+			if err := r.deps.PumpControl.OfflinePump(tc, ordinal, member.ID); err != nil {
+				klog.Errorf("member replacer: failed to offline pump %s/%s node %s, %v", ns, podName, member.ID, err)
+				return err
+			}
+			member.Deleted = true
+			tc.Status.Pump.ReplacingMembers[podName] = member
+			klog.Infof("member replacer: offline pump %s/%s node %s successfully", ns, podName, member.ID)
+			r.deps.Recorder.Eventf(tc, corev1.EventTypeNormal, "PumpReplacing", "%s(%s) made offline to replace it", podName, member.ID)
+			continue
+		}
+
+		// the binlogs of the pump must be consumed by the drainers before its data is deleted,
+		// the new pump registers with the same node id once the pod is recreated
+		if status, exist := tc.Status.Pump.Members[podName]; exist && status.State != v1alpha1.PumpStateOffline {
+			pod, err := r.deps.PodLister.Pods(ns).Get(podName)
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			if err == nil && pod.CreationTimestamp.Before(&member.CreatedAt) {
+				klog.Infof("member replacer: pump %s/%s is %s, waiting for it to be offline", ns, podName, status.State)
+				continue
+			}
+		}
+		replaced, err := replacePodWithFreshStorage(r.deps, tc, controller.PumpMemberName(tcName), podName, member.CreatedAt)
+		if err != nil {
+			return err
+		}
+		if replaced {
+			delete(tc.Status.Pump.ReplacingMembers, podName)
+			klog.Infof("member replacer: pump %s/%s is recreated on fresh storage", ns, podName)
+			r.deps.Recorder.Eventf(tc, corev1.EventTypeNormal, "PumpReplaced", "%s recreated on fresh storage", podName)
+		}
+	}
+	if len(tc.Status.Pump.ReplacingMembers) == 0 {
+		tc.Status.Pump.ReplacingMembers = nil
+	}
+	return nil
+}
+
+// replaceRequested returns whether the pod is annotated to be replaced or any of its
+// volumes is not of the storage class of the volume claim template of the statefulset
+func (r *memberReplacer) replaceRequested(ns, setName, podName string) (bool, error) {
+	pod, err := r.deps.PodLister.Pods(ns).Get(podName)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if pod.Annotations[label.AnnReplaceKey] == label.AnnReplaceVal {
+		return true, nil
+	}
+
+	set, err := r.deps.StatefulSetLister.StatefulSets(ns).Get(setName)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	for _, tmpl := range set.Spec.VolumeClaimTemplates {
+		if tmpl.Spec.StorageClassName == nil {
+			continue
+		}
+		pvc, err := r.deps.PVCLister.PersistentVolumeClaims(ns).Get(fmt.Sprintf("%s-%s", tmpl.Name, podName))
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName != *tmpl.Spec.StorageClassName {
+			klog.Infof("member replacer: pvc %s/%s is not of storage class %s, replace pod %s", ns, pvc.Name, *tmpl.Spec.StorageClassName, podName)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func cleanReplacingMembers(members map[string]v1alpha1.ReplacingMember) map[string]v1alpha1.ReplacingMember {
+	if len(members) == 0 {
+		return nil
+	}
+	return members
+}
+
+// FakeMemberReplacer is a fake implementation of MemberReplacer
+type FakeMemberReplacer struct {
+	err error
+}
+
+// NewFakeMemberReplacer returns a FakeMemberReplacer
+func NewFakeMemberReplacer() *FakeMemberReplacer {
+	return &FakeMemberReplacer{}
+}
+
+// SetSyncError sets the error returned by Sync
+func (r *FakeMemberReplacer) SetSyncError(err error) {
+	r.err = err
+}
+
+func (r *FakeMemberReplacer) Sync(_ *v1alpha1.TidbCluster) error {
+	return r.err
+}
@@ -0,0 +1,314 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestMemberReplacerSync(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type fixture struct {
+		deps        *controller.Dependencies
+		tc          *v1alpha1.TidbCluster
+		pdActions   map[pdapi.ActionType]int
+		maxReplicas uint64
+		annotate    func(podName string)
+		exist       func(podName, pvcName string) (bool, bool)
+	}
+
+	newFixture := func() *fixture {
+		f := &fixture{
+			deps:        controller.NewFakeDependencies(),
+			tc:          newTidbClusterForPD(),
+			pdActions:   map[pdapi.ActionType]int{},
+			maxReplicas: 1,
+		}
+		ns := f.tc.Namespace
+		created := metav1.NewTime(time.Now().Add(-time.Hour))
+		informers := f.deps.KubeInformerFactory
+		podIndexer := informers.Core().V1().Pods().Informer().GetIndexer()
+		pvcIndexer := informers.Core().V1().PersistentVolumeClaims().Informer().GetIndexer()
+
+		for _, memberType := range []v1alpha1.MemberType{v1alpha1.PDMemberType, v1alpha1.TiKVMemberType} {
+			setName := fmt.Sprintf("%s-%s", f.tc.Name, memberType)
+			informers.Apps().V1().StatefulSets().Informer().GetIndexer().Add(&apps.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: setName, Namespace: ns},
+				Spec: apps.StatefulSetSpec{
+					VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+						{ObjectMeta: metav1.ObjectMeta{Name: memberType.String()}},
+					},
+				},
+			})
+			for ordinal := int32(0); ordinal < 2; ordinal++ {
+				podName := ordinalPodName(memberType, f.tc.Name, ordinal)
+				pvcName := ordinalPVCName(memberType, setName, ordinal)
+				podIndexer.Add(&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: podName, Namespace: ns, CreationTimestamp: created},
+					Spec: corev1.PodSpec{
+						Volumes: []corev1.Volume{{
+							Name: memberType.String(),
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: pvcName},
+							},
+						}},
+					},
+				})
+				pvcIndexer.Add(&corev1.PersistentVolumeClaim{
+					ObjectMeta: metav1.ObjectMeta{Name: pvcName, Namespace: ns, CreationTimestamp: created},
+				})
+			}
+		}
+
+		f.tc.Status.PD.Synced = true
+		f.tc.Status.PD.Leader = v1alpha1.PDMember{Name: "test-pd-0", ID: "10", Health: true}
+		f.tc.Status.PD.Members = map[string]v1alpha1.PDMember{
+			"test-pd-0": {Name: "test-pd-0", ID: "10", Health: true},
+			"test-pd-1": {Name: "test-pd-1", ID: "11", Health: true},
+		}
+		f.tc.Status.TiKV.Synced = true
+		f.tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+			"1": {ID: "1", PodName: "test-tikv-0", State: v1alpha1.TiKVStateUp},
+			"2": {ID: "2", PodName: "test-tikv-1", State: v1alpha1.TiKVStateUp},
+		}
+
+		pdClient := controller.NewFakePDClient(f.deps.PDControl.(*pdapi.FakePDControl), f.tc)
+		for _, action := range []pdapi.ActionType{pdapi.TransferPDLeaderActionType, pdapi.DeleteMemberByIDActionType, pdapi.DeleteStoreActionType,
+			pdapi.BeginEvictLeaderActionType, pdapi.EndEvictLeaderActionType} {
+			actionType := action
+			pdClient.AddReaction(actionType, func(action *pdapi.Action) (interface{}, error) {
+				f.pdActions[actionType]++
+				return nil, nil
+			})
+		}
+		pdClient.AddReaction(pdapi.GetConfigActionType, func(action *pdapi.Action) (interface{}, error) {
+			return &pdapi.PDConfigFromAPI{
+				Replication: &pdapi.PDReplicationConfig{MaxReplicas: &f.maxReplicas},
+			}, nil
+		})
+
+		f.annotate = func(podName string) {
+			obj, _, _ := podIndexer.GetByKey(ns + "/" + podName)
+			pod := obj.(*corev1.Pod).DeepCopy()
+			pod.Annotations = map[string]string{label.AnnReplaceKey: label.AnnReplaceVal}
+			podIndexer.Update(pod)
+		}
+		f.exist = func(podName, pvcName string) (bool, bool) {
+			_, podExist, _ := podIndexer.GetByKey(ns + "/" + podName)
+			_, pvcExist, _ := pvcIndexer.GetByKey(ns + "/" + pvcName)
+			return podExist, pvcExist
+		}
+		return f
+	}
+
+	t.Log("no pods are annotated")
+	f := newFixture()
+	g.Expect(NewMemberReplacer(f.deps).Sync(f.tc)).To(Succeed())
+	g.Expect(f.tc.Status.PD.ReplacingMembers).To(BeNil())
+	g.Expect(f.tc.Status.TiKV.ReplacingStores).To(BeNil())
+
+	t.Log("other pd members are unhealthy")
+	f = newFixture()
+	f.annotate("test-pd-0")
+	f.tc.Status.PD.Members["test-pd-1"] = v1alpha1.PDMember{Name: "test-pd-1", ID: "11", Health: false}
+	g.Expect(NewMemberReplacer(f.deps).Sync(f.tc)).To(Succeed())
+	g.Expect(f.tc.Status.PD.ReplacingMembers).To(BeNil())
+	g.Expect(f.pdActions[pdapi.TransferPDLeaderActionType]).To(Equal(0))
+
+	t.Log("transfer the pd leader before replacing it")
+	f = newFixture()
+	f.annotate("test-pd-0")
+	f.annotate("test-tikv-1")
+	f.tc.Status.TiKV.Stores["2"] = v1alpha1.TiKVStore{ID: "2", PodName: "test-tikv-1", State: v1alpha1.TiKVStateUp, LeaderCount: 3}
+	replacer := NewMemberReplacer(f.deps)
+	g.Expect(replacer.Sync(f.tc)).To(Succeed())
+	g.Expect(f.pdActions[pdapi.TransferPDLeaderActionType]).To(Equal(1))
+	g.Expect(f.tc.Status.PD.ReplacingMembers).To(BeNil())
+
+	t.Log("evict the leaders of the tikv store before deleting it")
+	g.Expect(f.pdActions[pdapi.BeginEvictLeaderActionType]).To(Equal(1))
+	g.Expect(f.pdActions[pdapi.DeleteStoreActionType]).To(Equal(0))
+	g.Expect(f.tc.Status.TiKV.ReplacingStores).To(HaveKey("2"))
+	g.Expect(f.tc.Status.TiKV.ReplacingStores["2"].Deleted).To(BeFalse())
+	g.Expect(replacer.Sync(f.tc)).To(Succeed())
+	g.Expect(f.pdActions[pdapi.BeginEvictLeaderActionType]).To(Equal(1))
+	g.Expect(f.pdActions[pdapi.DeleteStoreActionType]).To(Equal(0))
+
+	t.Log("delete the tikv store after its leaders are evicted")
+	f.tc.Status.TiKV.Stores["2"] = v1alpha1.TiKVStore{ID: "2", PodName: "test-tikv-1", State: v1alpha1.TiKVStateUp}
+	g.Expect(replacer.Sync(f.tc)).To(Succeed())
+	g.Expect(f.pdActions[pdapi.DeleteStoreActionType]).To(Equal(1))
+	g.Expect(f.pdActions[pdapi.EndEvictLeaderActionType]).To(Equal(1))
+	g.Expect(f.tc.Status.TiKV.ReplacingStores["2"].Deleted).To(BeTrue())
+
+	t.Log("delete the pd member, pod and pvc")
+	f.tc.Status.PD.Leader = f.tc.Status.PD.Members["test-pd-1"]
+	g.Expect(replacer.Sync(f.tc)).To(Succeed())
+	g.Expect(f.pdActions[pdapi.DeleteMemberByIDActionType]).To(Equal(1))
+	g.Expect(f.tc.Status.PD.ReplacingMembers["test-pd-0"].Deleted).To(BeTrue())
+	podExist, pvcExist := f.exist("test-pd-0", "pd-test-pd-0")
+	g.Expect(podExist).To(BeFalse())
+	g.Expect(pvcExist).To(BeFalse())
+
+	t.Log("tikv store is not Tombstone yet")
+	podExist, pvcExist = f.exist("test-tikv-1", "tikv-test-tikv-1")
+	g.Expect(podExist).To(BeTrue())
+	g.Expect(pvcExist).To(BeTrue())
+
+	t.Log("tikv store is Tombstone")
+	delete(f.tc.Status.TiKV.Stores, "2")
+	g.Expect(replacer.Sync(f.tc)).To(Succeed())
+	g.Expect(f.tc.Status.PD.ReplacingMembers).To(BeNil())
+	podExist, pvcExist = f.exist("test-tikv-1", "tikv-test-tikv-1")
+	g.Expect(podExist).To(BeFalse())
+	g.Expect(pvcExist).To(BeFalse())
+
+	g.Expect(replacer.Sync(f.tc)).To(Succeed())
+	g.Expect(f.tc.Status.TiKV.ReplacingStores).To(BeNil())
+	g.Expect(f.pdActions[pdapi.DeleteMemberByIDActionType]).To(Equal(1))
+	g.Expect(f.pdActions[pdapi.DeleteStoreActionType]).To(Equal(1))
+
+	t.Log("the storage class of the volumes is changed")
+	f = newFixture()
+	ns := f.tc.Namespace
+	setIndexer := f.deps.KubeInformerFactory.Apps().V1().StatefulSets().Informer().GetIndexer()
+	obj, _, _ := setIndexer.GetByKey(ns + "/test-tikv")
+	set := obj.(*apps.StatefulSet).DeepCopy()
+	set.Spec.VolumeClaimTemplates[0].Spec.StorageClassName = pointer.StringPtr("gp3")
+	setIndexer.Update(set)
+	pvcIndexer := f.deps.KubeInformerFactory.Core().V1().PersistentVolumeClaims().Informer().GetIndexer()
+	for _, pvcName := range []string{"tikv-test-tikv-0", "tikv-test-tikv-1"} {
+		obj, _, _ := pvcIndexer.GetByKey(ns + "/" + pvcName)
+		pvc := obj.(*corev1.PersistentVolumeClaim).DeepCopy()
+		pvc.Spec.StorageClassName = pointer.StringPtr("gp2")
+		pvcIndexer.Update(pvc)
+	}
+	obj, _, _ = pvcIndexer.GetByKey(ns + "/tikv-test-tikv-1")
+	pvc := obj.(*corev1.PersistentVolumeClaim).DeepCopy()
+	pvc.Spec.StorageClassName = pointer.StringPtr("gp3")
+	pvcIndexer.Update(pvc)
+
+	replacer = NewMemberReplacer(f.deps)
+	g.Expect(replacer.Sync(f.tc)).To(Succeed())
+	g.Expect(f.tc.Status.TiKV.ReplacingStores).To(HaveLen(1))
+	g.Expect(f.tc.Status.TiKV.ReplacingStores).To(HaveKey("1"))
+	g.Expect(f.tc.Status.PD.ReplacingMembers).To(BeNil())
+
+	t.Log("wait for the other stores to be Up")
+	f.tc.Status.TiKV.ReplacingStores = nil
+	f.tc.Status.TiKV.Stores["2"] = v1alpha1.TiKVStore{ID: "2", PodName: "test-tikv-1", State: v1alpha1.TiKVStateDown}
+	g.Expect(replacer.Sync(f.tc)).To(Succeed())
+	g.Expect(f.tc.Status.TiKV.ReplacingStores).To(BeNil())
+	g.Expect(f.tc.Status.TiKV.ReplaceBlockedReason).To(ContainSubstring("store 2 of pod test-tikv-1 is Down"))
+
+	t.Log("the number of Up stores is not greater than max-replicas")
+	f = newFixture()
+	f.maxReplicas = 2
+	f.annotate("test-tikv-1")
+	replacer = NewMemberReplacer(f.deps)
+	g.Expect(replacer.Sync(f.tc)).To(Succeed())
+	g.Expect(f.tc.Status.TiKV.ReplacingStores).To(BeNil())
+	g.Expect(f.tc.Status.TiKV.ReplaceBlockedReason).To(Equal("the number of Up stores 2 is not greater than max-replicas 2"))
+	g.Expect(f.pdActions[pdapi.DeleteStoreActionType]).To(Equal(0))
+
+	f.maxReplicas = 1
+	g.Expect(replacer.Sync(f.tc)).To(Succeed())
+	g.Expect(f.tc.Status.TiKV.ReplacingStores).To(HaveKey("2"))
+	g.Expect(f.tc.Status.TiKV.ReplaceBlockedReason).To(BeEmpty())
+	g.Expect(f.pdActions[pdapi.BeginEvictLeaderActionType]).To(Equal(1))
+	g.Expect(replacer.Sync(f.tc)).To(Succeed())
+	g.Expect(f.pdActions[pdapi.DeleteStoreActionType]).To(Equal(1))
+}
+
+func TestMemberReplacerSyncPump(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deps := controller.NewFakeDependencies()
+	tc := newTidbClusterForPD()
+	tc.Spec.Pump = &v1alpha1.PumpSpec{Replicas: 1}
+	ns := tc.Namespace
+	podName := ordinalPodName(v1alpha1.PumpMemberType, tc.Name, 0)
+	created := metav1.NewTime(time.Now().Add(-time.Hour))
+
+	informers := deps.KubeInformerFactory
+	podIndexer := informers.Core().V1().Pods().Informer().GetIndexer()
+	pvcIndexer := informers.Core().V1().PersistentVolumeClaims().Informer().GetIndexer()
+	informers.Apps().V1().StatefulSets().Informer().GetIndexer().Add(&apps.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: controller.PumpMemberName(tc.Name), Namespace: ns},
+		Spec: apps.StatefulSetSpec{
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+				{ObjectMeta: metav1.ObjectMeta{Name: "data"}},
+			},
+		},
+	})
+	podIndexer.Add(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              podName,
+			Namespace:         ns,
+			CreationTimestamp: created,
+			Annotations:       map[string]string{label.AnnReplaceKey: label.AnnReplaceVal},
+		},
+		Spec: corev1.PodSpec{
+			Volumes: []corev1.Volume{{
+				Name: "data",
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data-" + podName},
+				},
+			}},
+		},
+	})
+	pvcIndexer.Add(&corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data-" + podName, Namespace: ns, CreationTimestamp: created},
+	})
+
+	pumpControl := deps.PumpControl.(*controller.FakePumpControl)
+	pumpControl.SetPump(&controller.BinlogNode{NodeID: "pump-0:8250", State: v1alpha1.PumpStateOnline})
+	tc.Status.Pump.Members = map[string]v1alpha1.PumpMember{
+		podName: {NodeID: "pump-0:8250", State: v1alpha1.PumpStateOnline},
+	}
+
+	replacer := NewMemberReplacer(deps)
+	g.Expect(replacer.Sync(tc)).To(Succeed())
+	g.Expect(tc.Status.Pump.ReplacingMembers[podName].Deleted).To(BeTrue())
+
+	t.Log("wait for the pump to be offline")
+	tc.Status.Pump.Members[podName] = v1alpha1.PumpMember{NodeID: "pump-0:8250", State: v1alpha1.PumpStateClosing}
+	g.Expect(replacer.Sync(tc)).To(Succeed())
+	_, podExist, _ := podIndexer.GetByKey(ns + "/" + podName)
+	g.Expect(podExist).To(BeTrue())
+
+	t.Log("recreate the pod on fresh storage")
+	tc.Status.Pump.Members[podName] = v1alpha1.PumpMember{NodeID: "pump-0:8250", State: v1alpha1.PumpStateOffline}
+	g.Expect(replacer.Sync(tc)).To(Succeed())
+	_, podExist, _ = podIndexer.GetByKey(ns + "/" + podName)
+	g.Expect(podExist).To(BeFalse())
+	_, pvcExist, _ := pvcIndexer.GetByKey(ns + "/data-" + podName)
+	g.Expect(pvcExist).To(BeFalse())
+
+	g.Expect(replacer.Sync(tc)).To(Succeed())
+	g.Expect(tc.Status.Pump.ReplacingMembers).To(BeNil())
+}
@@ -0,0 +1,313 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+)
+
+// NodeDrainer moves the leaders of the TiKV and TiFlash stores off the nodes being
+// drained, a node is drained if it is unschedulable or has any of the taints set by
+// --node-drain-taints. The stores on the local volumes of the nodes are migrated to
+// other nodes by deleting the stores and recreating the pods on fresh storage.
+type NodeDrainer interface {
+	Sync(tc *v1alpha1.TidbCluster) error
+}
+
+type nodeDrainer struct {
+	deps *controller.Dependencies
+}
+
+// NewNodeDrainer returns a NodeDrainer
+func NewNodeDrainer(deps *controller.Dependencies) NodeDrainer {
+	return &nodeDrainer{deps: deps}
+}
+
+func (d *nodeDrainer) Sync(tc *v1alpha1.TidbCluster) error {
+	if tc.Spec.Paused {
+		return nil
+	}
+
+	if tc.Spec.TiKV != nil && tc.Status.TiKV.Synced {
+		draining, err := d.drainStores(tc, tikvMemberName(tc), tc.Status.TiKV.Stores, tc.Status.TiKV.DrainingStores)
+		tc.Status.TiKV.DrainingStores = draining
+		if err != nil {
+			return err
+		}
+	}
+	for i := range tc.Spec.TiKVGroups {
+		group := &tc.Spec.TiKVGroups[i]
+		status, ok := tc.Status.TiKV.Groups[group.Name]
+		if !ok || !status.Synced {
+			continue
+		}
+		gtc := tc.TiKVGroupCluster(group)
+		draining, err := d.drainStores(gtc, tikvMemberName(gtc), status.Stores, status.DrainingStores)
+		status.DrainingStores = draining
+		tc.Status.TiKV.Groups[group.Name] = status
+		if err != nil {
+			return err
+		}
+	}
+	if tc.Spec.TiFlash != nil && tc.Status.TiFlash.Synced {
+		draining, err := d.drainStores(tc, controller.TiFlashMemberName(tc.Name), tc.Status.TiFlash.Stores, tc.Status.TiFlash.DrainingStores)
+		tc.Status.TiFlash.DrainingStores = draining
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// drainStores evicts the leaders of the stores on the nodes being drained and
+// syncs the draining stores, it returns the draining stores after syncing
+func (d *nodeDrainer) drainStores(tc *v1alpha1.TidbCluster, setName string, stores map[string]v1alpha1.TiKVStore, draining map[string]v1alpha1.TiKVDrainingStore) (map[string]v1alpha1.TiKVDrainingStore, error) {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	if draining == nil {
+		draining = map[string]v1alpha1.TiKVDrainingStore{}
+	}
+
+	for storeID, store := range stores {
+		if _, ok := draining[storeID]; ok || store.State != v1alpha1.TiKVStateUp {
+			continue
+		}
+		nodeName, drained, err := d.storeNodeDrained(ns, store.PodName)
+		if err != nil {
+			return cleanDrainingStores(draining), err
+		}
+		if !drained {
+			continue
+		}
+		id, err := strconv.ParseUint(storeID, 10, 64)
+		if err != nil {
+			return cleanDrainingStores(draining), err
+		}
+		if err := controller.GetPDClient(d.deps.PDControl, tc).BeginEvictLeader(id); err != nil {
+			klog.Errorf("node drainer: failed to evict leaders of store %d of pod %s/%s, %v", id, ns, store.PodName, err)
+			return cleanDrainingStores(draining), err
+		}
+		draining[storeID] = v1alpha1.TiKVDrainingStore{
+			PodName:   store.PodName,
+			StoreID:   storeID,
+			NodeName:  nodeName,
+			CreatedAt: metav1.Now(),
+		}
+		klog.Infof("node drainer: tidbcluster %s/%s, node %s is being drained, evict leaders of store %d of pod %s", ns, tcName, nodeName, id, store.PodName)
+		d.deps.Recorder.Eventf(tc, corev1.EventTypeNormal, "NodeDraining", "node %s is being drained, evict leaders of %s(%d)", nodeName, store.PodName, id)
+	}
+
+	for storeID, drainingStore := range draining {
+		if err := d.syncDrainingStore(tc, setName, stores, draining, storeID, drainingStore); err != nil {
+			return cleanDrainingStores(draining), err
+		}
+	}
+	return cleanDrainingStores(draining), nil
+}
+
+func (d *nodeDrainer) syncDrainingStore(tc *v1alpha1.TidbCluster, setName string, stores map[string]v1alpha1.TiKVStore, draining map[string]v1alpha1.TiKVDrainingStore, storeID string, drainingStore v1alpha1.TiKVDrainingStore) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	podName := drainingStore.PodName
+
+	id, err := strconv.ParseUint(storeID, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	if !drainingStore.StoreDeleted {
+		store, exist := stores[storeID]
+		if !exist {
+			// the store is scaled in or failed over
+			delete(draining, storeID)
+			return nil
+		}
+
+		nodeName, drained, err := d.storeNodeDrained(ns, podName)
+		if err != nil {
+			return err
+		}
+		// the pod is pending after it is evicted from the node with local volumes
+		if nodeName != "" && (!drained || nodeName != drainingStore.NodeName) {
+			if err := controller.GetPDClient(d.deps.PDControl, tc).EndEvictLeader(id); err != nil {
+				klog.Errorf("node drainer: failed to end evicting leaders of store %d of pod %s/%s, %v", id, ns, podName, err)
+				return err
+			}
+			delete(draining, storeID)
+			klog.Infof("node drainer: tidbcluster %s/%s, pod %s is not on the node being drained, end evicting leaders of store %d", ns, tcName, podName, id)
+			return nil
+		}
+
+		local, err := d.onLocalVolumes(ns, podName)
+		if err != nil {
+			return err
+		}
+		// the pods on the network volumes are moved to other nodes by the drain
+		if !local {
+			return nil
+		}
+		if store.State == v1alpha1.TiKVStateUp && store.LeaderCount > 0 {
+			klog.Infof("node drainer: tidbcluster %s/%s, waiting for the leaders of store %d of pod %s to be evicted, leader count: %d", ns, tcName, id, podName, store.LeaderCount)
+			return nil
+		}
+		// migrate the stores one by one to keep enough replicas of the regions
+		for key, s := range draining {
+			if key != storeID && s.StoreDeleted {
+				return nil
+			}
+		}
+		if err := controller.GetPDClient(d.deps.PDControl, tc).DeleteStore(id); err != nil {
+			klog.Errorf("node drainer: failed to delete store %d of pod %s/%s, %v", id, ns, podName, err)
+			return err
+		}
+		drainingStore.StoreDeleted = true
+		draining[storeID] = drainingStore
+		klog.Infof("node drainer: tidbcluster %s/%s, delete store %d of pod %s to migrate it off node %s", ns, tcName, id, podName, drainingStore.NodeName)
+		d.deps.Recorder.Eventf(tc, corev1.EventTypeNormal, "StoreMigrating", "%s(%d) deleted to migrate it off node %s", podName, id, drainingStore.NodeName)
+		return nil
+	}
+
+	// the store is still Offline, wait for PD to move its regions away
+	if _, exist := stores[storeID]; exist {
+		klog.Infof("node drainer: tidbcluster %s/%s, store %d of pod %s is not Tombstone yet", ns, tcName, id, podName)
+		return nil
+	}
+
+	replaced, err := replacePodWithFreshStorage(d.deps, tc, setName, podName, drainingStore.CreatedAt)
+	if err != nil {
+		return err
+	}
+	if replaced {
+		delete(draining, storeID)
+		klog.Infof("node drainer: tidbcluster %s/%s, pod %s is migrated off node %s", ns, tcName, podName, drainingStore.NodeName)
+		d.deps.Recorder.Eventf(tc, corev1.EventTypeNormal, "StoreMigrated", "%s migrated off node %s", podName, drainingStore.NodeName)
+	}
+	return nil
+}
+
+// storeNodeDrained returns the node of the pod and whether the node is being
+// drained, the node name is empty if the pod is not scheduled
+func (d *nodeDrainer) storeNodeDrained(ns, podName string) (string, bool, error) {
+	pod, err := d.deps.PodLister.Pods(ns).Get(podName)
+	if errors.IsNotFound(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	nodeName := pod.Spec.NodeName
+	if nodeName == "" {
+		return "", false, nil
+	}
+	node, err := d.deps.NodeLister.Get(nodeName)
+	if errors.IsNotFound(err) {
+		return nodeName, false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return nodeName, nodeDrained(node, d.deps.CLIConfig.NodeDrainTaints), nil
+}
+
+// onLocalVolumes returns whether any volume of the pod is a local persistent volume
+func (d *nodeDrainer) onLocalVolumes(ns, podName string) (bool, error) {
+	pod, err := d.deps.PodLister.Pods(ns).Get(podName)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	for _, vol := range pod.Spec.Volumes {
+		if vol.PersistentVolumeClaim == nil {
+			continue
+		}
+		pvc, err := d.deps.PVCLister.PersistentVolumeClaims(ns).Get(vol.PersistentVolumeClaim.ClaimName)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		if pvc.Spec.VolumeName == "" {
+			continue
+		}
+		pv, err := d.deps.PVLister.Get(pvc.Spec.VolumeName)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		if pv.Spec.Local != nil || pv.Spec.HostPath != nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// nodeDrained returns whether the node is unschedulable or has any of the
+// comma separated taint keys
+func nodeDrained(node *corev1.Node, taintKeys string) bool {
+	if node.Spec.Unschedulable {
+		return true
+	}
+	for _, key := range strings.Split(taintKeys, ",") {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		for _, taint := range node.Spec.Taints {
+			if taint.Key == key {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func cleanDrainingStores(draining map[string]v1alpha1.TiKVDrainingStore) map[string]v1alpha1.TiKVDrainingStore {
+	if len(draining) == 0 {
+		return nil
+	}
+	return draining
+}
+
+// FakeNodeDrainer is a fake implementation of NodeDrainer
+type FakeNodeDrainer struct {
+	err error
+}
+
+// NewFakeNodeDrainer returns a FakeNodeDrainer
+func NewFakeNodeDrainer() *FakeNodeDrainer {
+	return &FakeNodeDrainer{}
+}
+
+// SetSyncError sets the error returned by Sync
+func (d *FakeNodeDrainer) SetSyncError(err error) {
+	d.err = err
+}
+
+func (d *FakeNodeDrainer) Sync(_ *v1alpha1.TidbCluster) error {
+	return d.err
+}
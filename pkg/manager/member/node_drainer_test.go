@@ -0,0 +1,185 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNodeDrainerSync(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type fixture struct {
+		deps        *controller.Dependencies
+		tc          *v1alpha1.TidbCluster
+		setNodes    func(unschedulable bool, taints ...corev1.Taint)
+		pdActions   map[pdapi.ActionType]int
+		podExist    func() bool
+		pvcExist    func() bool
+		drainingOne func() v1alpha1.TiKVDrainingStore
+	}
+
+	newFixture := func(local bool) *fixture {
+		f := &fixture{
+			deps:      controller.NewFakeDependencies(),
+			tc:        newTidbClusterForPD(),
+			pdActions: map[pdapi.ActionType]int{},
+		}
+		ns := f.tc.Namespace
+		setName := controller.TiKVMemberName(f.tc.Name)
+		podName := ordinalPodName(v1alpha1.TiKVMemberType, f.tc.Name, 0)
+		pvcName := ordinalPVCName(v1alpha1.TiKVMemberType, setName, 0)
+		created := metav1.NewTime(time.Now().Add(-time.Hour))
+
+		f.tc.Status.TiKV.Synced = true
+		f.tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+			"1": {ID: "1", PodName: podName, State: v1alpha1.TiKVStateUp, LeaderCount: 10},
+		}
+
+		informers := f.deps.KubeInformerFactory
+		informers.Apps().V1().StatefulSets().Informer().GetIndexer().Add(&apps.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: setName, Namespace: ns},
+			Spec: apps.StatefulSetSpec{
+				VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+					{ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.TiKVMemberType.String()}},
+				},
+			},
+		})
+		informers.Core().V1().Pods().Informer().GetIndexer().Add(&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: podName, Namespace: ns, CreationTimestamp: created},
+			Spec: corev1.PodSpec{
+				NodeName: "node-1",
+				Volumes: []corev1.Volume{{
+					Name: v1alpha1.TiKVMemberType.String(),
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: pvcName},
+					},
+				}},
+			},
+		})
+		informers.Core().V1().PersistentVolumeClaims().Informer().GetIndexer().Add(&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: pvcName, Namespace: ns, CreationTimestamp: created},
+			Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: "pv-1"},
+		})
+		pv := &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv-1"}}
+		if local {
+			pv.Spec.Local = &corev1.LocalVolumeSource{Path: "/mnt/disks/1"}
+		}
+		informers.Core().V1().PersistentVolumes().Informer().GetIndexer().Add(pv)
+
+		nodeIndexer := informers.Core().V1().Nodes().Informer().GetIndexer()
+		f.setNodes = func(unschedulable bool, taints ...corev1.Taint) {
+			nodeIndexer.Update(&corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+				Spec:       corev1.NodeSpec{Unschedulable: unschedulable, Taints: taints},
+			})
+		}
+		f.setNodes(false)
+
+		pdClient := controller.NewFakePDClient(f.deps.PDControl.(*pdapi.FakePDControl), f.tc)
+		for _, action := range []pdapi.ActionType{pdapi.BeginEvictLeaderActionType, pdapi.EndEvictLeaderActionType, pdapi.DeleteStoreActionType} {
+			actionType := action
+			pdClient.AddReaction(actionType, func(action *pdapi.Action) (interface{}, error) {
+				f.pdActions[actionType]++
+				return nil, nil
+			})
+		}
+
+		f.podExist = func() bool {
+			_, exist, _ := informers.Core().V1().Pods().Informer().GetIndexer().GetByKey(ns + "/" + podName)
+			return exist
+		}
+		f.pvcExist = func() bool {
+			_, exist, _ := informers.Core().V1().PersistentVolumeClaims().Informer().GetIndexer().GetByKey(ns + "/" + pvcName)
+			return exist
+		}
+		f.drainingOne = func() v1alpha1.TiKVDrainingStore {
+			g.Expect(f.tc.Status.TiKV.DrainingStores).To(HaveKey("1"))
+			return f.tc.Status.TiKV.DrainingStores["1"]
+		}
+		return f
+	}
+
+	t.Log("network volumes")
+	f := newFixture(false)
+	drainer := NewNodeDrainer(f.deps)
+
+	g.Expect(drainer.Sync(f.tc)).To(Succeed())
+	g.Expect(f.tc.Status.TiKV.DrainingStores).To(BeNil())
+	g.Expect(f.pdActions[pdapi.BeginEvictLeaderActionType]).To(Equal(0))
+
+	f.setNodes(true)
+	g.Expect(drainer.Sync(f.tc)).To(Succeed())
+	g.Expect(f.pdActions[pdapi.BeginEvictLeaderActionType]).To(Equal(1))
+	g.Expect(f.drainingOne().NodeName).To(Equal("node-1"))
+
+	f.tc.Status.TiKV.Stores["1"] = v1alpha1.TiKVStore{ID: "1", PodName: f.drainingOne().PodName, State: v1alpha1.TiKVStateUp}
+	g.Expect(drainer.Sync(f.tc)).To(Succeed())
+	g.Expect(f.pdActions[pdapi.DeleteStoreActionType]).To(Equal(0))
+	g.Expect(f.pdActions[pdapi.BeginEvictLeaderActionType]).To(Equal(1))
+
+	f.setNodes(false)
+	g.Expect(drainer.Sync(f.tc)).To(Succeed())
+	g.Expect(f.pdActions[pdapi.EndEvictLeaderActionType]).To(Equal(1))
+	g.Expect(f.tc.Status.TiKV.DrainingStores).To(BeNil())
+
+	t.Log("local volumes")
+	f = newFixture(true)
+	f.deps.CLIConfig.NodeDrainTaints = "example.com/maintenance, example.com/retired"
+	drainer = NewNodeDrainer(f.deps)
+
+	f.setNodes(false, corev1.Taint{Key: "example.com/other", Effect: corev1.TaintEffectNoSchedule})
+	g.Expect(drainer.Sync(f.tc)).To(Succeed())
+	g.Expect(f.tc.Status.TiKV.DrainingStores).To(BeNil())
+
+	f.setNodes(false, corev1.Taint{Key: "example.com/retired", Effect: corev1.TaintEffectNoSchedule})
+	g.Expect(drainer.Sync(f.tc)).To(Succeed())
+	g.Expect(f.pdActions[pdapi.BeginEvictLeaderActionType]).To(Equal(1))
+	g.Expect(f.pdActions[pdapi.DeleteStoreActionType]).To(Equal(0))
+
+	t.Log("delete the store after the leaders are evicted")
+	store := f.tc.Status.TiKV.Stores["1"]
+	store.LeaderCount = 0
+	f.tc.Status.TiKV.Stores["1"] = store
+	g.Expect(drainer.Sync(f.tc)).To(Succeed())
+	g.Expect(f.pdActions[pdapi.DeleteStoreActionType]).To(Equal(1))
+	g.Expect(f.drainingOne().StoreDeleted).To(BeTrue())
+
+	t.Log("wait for the store to be Tombstone")
+	store.State = v1alpha1.TiKVStateOffline
+	f.tc.Status.TiKV.Stores["1"] = store
+	g.Expect(drainer.Sync(f.tc)).To(Succeed())
+	g.Expect(f.pdActions[pdapi.DeleteStoreActionType]).To(Equal(1))
+	g.Expect(f.podExist()).To(BeTrue())
+
+	t.Log("recreate the pod on fresh storage")
+	delete(f.tc.Status.TiKV.Stores, "1")
+	g.Expect(drainer.Sync(f.tc)).To(Succeed())
+	g.Expect(f.podExist()).To(BeFalse())
+	g.Expect(f.pvcExist()).To(BeFalse())
+	g.Expect(f.drainingOne().StoreDeleted).To(BeTrue())
+
+	g.Expect(drainer.Sync(f.tc)).To(Succeed())
+	g.Expect(f.tc.Status.TiKV.DrainingStores).To(BeNil())
+	g.Expect(f.pdActions[pdapi.EndEvictLeaderActionType]).To(Equal(0))
+}
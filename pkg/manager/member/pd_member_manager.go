@@ -14,6 +14,7 @@
 package member
 
 import (
+	"encoding/json"
 	"fmt"
 	"path"
 	"path/filepath"
@@ -26,12 +27,14 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/label"
 	"github.com/pingcap/tidb-operator/pkg/manager"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
 	"github.com/pingcap/tidb-operator/pkg/util"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/klog"
 	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
@@ -84,7 +87,107 @@ func (m *pdMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
 	}
 
 	// Sync PD StatefulSet
-	return m.syncPDStatefulSetForTidbCluster(tc)
+	if err := m.syncPDStatefulSetForTidbCluster(tc); err != nil {
+		return err
+	}
+
+	return m.syncPDSchedulers(tc)
+}
+
+// syncPDSchedulers makes the schedulers declared in spec run in PD and removes
+// the ones added before but no longer declared, the evict-leader schedulers
+// added by the operator during upgrades are never touched
+func (m *pdMemberManager) syncPDSchedulers(tc *v1alpha1.TidbCluster) error {
+	if tc.Spec.Paused {
+		klog.V(4).Infof("tidb cluster %s/%s is paused, skip syncing for pd schedulers", tc.GetNamespace(), tc.GetName())
+		return nil
+	}
+	if len(tc.Spec.PD.Schedulers) == 0 && len(tc.Status.PD.Schedulers) == 0 {
+		return nil
+	}
+	if !tc.PDIsAvailable() {
+		return controller.RequeueErrorf("TidbCluster: [%s/%s], waiting for PD cluster running to sync schedulers", tc.GetNamespace(), tc.GetName())
+	}
+
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	pdCli := controller.GetPDClient(m.deps.PDControl, tc)
+	running, err := pdCli.GetSchedulers()
+	if err != nil {
+		return fmt.Errorf("syncPDSchedulers: failed to get schedulers for cluster %s/%s, error: %v", ns, tcName, err)
+	}
+	runningSet := sets.NewString(running...)
+
+	// record the schedulers as soon as they are added or removed,
+	// so they are not leaked when a later call fails
+	applied := sets.NewString(tc.Status.PD.Schedulers...)
+	defer func() {
+		tc.Status.PD.Schedulers = applied.List()
+	}()
+
+	declared := sets.NewString()
+	for _, scheduler := range tc.Spec.PD.Schedulers {
+		// evict-leader schedulers are owned by the operator
+		if pdapi.IsEvictLeaderScheduler(scheduler.Name) {
+			continue
+		}
+		declared.Insert(scheduler.Name)
+		if !runningSet.Has(scheduler.Name) {
+			args := map[string]interface{}{}
+			if scheduler.StoreID != nil {
+				args["store_id"] = *scheduler.StoreID
+			}
+			if err := pdCli.AddScheduler(scheduler.Name, args); err != nil {
+				return fmt.Errorf("syncPDSchedulers: failed to add scheduler %s for cluster %s/%s, error: %v", scheduler.Name, ns, tcName, err)
+			}
+			klog.Infof("scheduler %s is added for cluster %s/%s", scheduler.Name, ns, tcName)
+			// only the schedulers added here are removed when they are no
+			// longer declared, the ones already running are left to PD
+			applied.Insert(scheduler.Name)
+		}
+
+		if scheduler.Config == nil {
+			continue
+		}
+		current, err := pdCli.GetSchedulerConfig(scheduler.Name)
+		if err != nil {
+			return fmt.Errorf("syncPDSchedulers: failed to get config of scheduler %s for cluster %s/%s, error: %v", scheduler.Name, ns, tcName, err)
+		}
+		if changed := changedSchedulerConfig(current, scheduler.Config.Inner()); len(changed) > 0 {
+			if err := pdCli.SetSchedulerConfig(scheduler.Name, changed); err != nil {
+				return fmt.Errorf("syncPDSchedulers: failed to set config of scheduler %s for cluster %s/%s, error: %v", scheduler.Name, ns, tcName, err)
+			}
+			klog.Infof("config of scheduler %s is updated for cluster %s/%s: %v", scheduler.Name, ns, tcName, changed)
+		}
+	}
+
+	for _, name := range applied.Difference(declared).List() {
+		if err := pdCli.RemoveScheduler(name); err != nil {
+			return fmt.Errorf("syncPDSchedulers: failed to remove scheduler %s for cluster %s/%s, error: %v", name, ns, tcName, err)
+		}
+		klog.Infof("scheduler %s is removed for cluster %s/%s since it is no longer declared", name, ns, tcName)
+		applied.Delete(name)
+	}
+	return nil
+}
+
+// changedSchedulerConfig returns the declared config items which differ from
+// the current ones, the values are compared in their JSON form since PD
+// returns all numbers as float
+func changedSchedulerConfig(current, declared map[string]interface{}) map[string]interface{} {
+	changed := map[string]interface{}{}
+	for k, v := range declared {
+		want, err := json.Marshal(v)
+		if err != nil {
+			changed[k] = v
+			continue
+		}
+		got, err := json.Marshal(current[k])
+		if err != nil || string(got) != string(want) {
+			changed[k] = v
+		}
+	}
+	return changed
 }
 
 func (m *pdMemberManager) syncPDServiceForTidbCluster(tc *v1alpha1.TidbCluster) error {
@@ -212,6 +315,10 @@ func (m *pdMemberManager) syncPDStatefulSetForTidbCluster(tc *v1alpha1.TidbClust
 		return controller.RequeueErrorf("TidbCluster: [%s/%s], waiting for PD cluster running", ns, tcName)
 	}
 
+	if err := recreateStatefulSetForStorage(m.deps, tc, newPDSet, oldPDSet); err != nil {
+		return err
+	}
+
 	// Force update takes precedence over scaling because force upgrade won't take effect when cluster gets stuck at scaling
 	if !tc.Status.PD.Synced && NeedForceUpgrade(tc.Annotations) {
 		tc.Status.PD.Phase = v1alpha1.UpgradePhase
@@ -294,6 +401,7 @@ func (m *pdMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, set *a
 	tcName := tc.GetName()
 
 	tc.Status.PD.StatefulSet = &set.Status
+	syncConfigRestartPending(tc.Status.PD.Config, set)
 
 	upgrading, err := m.pdStatefulSetIsUpgrading(set, tc)
 	if err != nil {
@@ -421,6 +529,15 @@ func (m *pdMemberManager) syncPDConfigMap(tc *v1alpha1.TidbCluster, set *apps.St
 		})
 	}
 
+	if tc.BasePDSpec().ConfigUpdateStrategy() == v1alpha1.ConfigUpdateStrategyInPlace {
+		tc.Status.PD.Config, err = applyConfigInPlace(m.deps.ConfigMapLister, set, inUseName, newCm, tc.Status.PD.Config, pdOnlineConfig, func(items map[string]interface{}) error {
+			return controller.GetPDClient(m.deps.PDControl, tc).UpdateConfig(items)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	err = updateConfigMapIfNeed(m.deps.ConfigMapLister, tc.BasePDSpec().ConfigUpdateStrategy(), inUseName, newCm)
 	if err != nil {
 		return nil, err
@@ -679,6 +796,8 @@ func getNewPDSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (
 	pdLabel := label.New().Instance(instanceName).PD()
 	setName := controller.PDMemberName(tcName)
 	podAnnotations := CombineAnnotations(controller.AnnProm(2379), basePDSpec.Annotations())
+	setRestartedAtAnnotations(tc, v1alpha1.PDMemberType, podAnnotations)
+	setConfigRestartAnnotation(podAnnotations, tc.Status.PD.Config)
 	stsAnnotations := getStsAnnotations(tc.Annotations, label.PDLabelVal)
 
 	pdContainer := corev1.Container{
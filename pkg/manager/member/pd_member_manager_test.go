@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/label"
 	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	"github.com/pingcap/tidb-operator/pkg/util/config"
 	"github.com/pingcap/tidb-operator/pkg/util/toml"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -759,6 +760,90 @@ func TestPDMemberManagerSyncPDSts(t *testing.T) {
 	}
 }
 
+func TestPDMemberManagerSyncPDSchedulers(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForPD()
+	tc.Status.PD.Members = map[string]v1alpha1.PDMember{
+		"pd-0": {Name: "pd-0", Health: true},
+		"pd-1": {Name: "pd-1", Health: true},
+		"pd-2": {Name: "pd-2", Health: true},
+	}
+	tc.Status.PD.StatefulSet = &apps.StatefulSetStatus{ReadyReplicas: 3}
+	storeID := uint64(1)
+	tc.Spec.PD.Schedulers = []v1alpha1.PDSchedulerSpec{
+		{Name: "shuffle-leader-scheduler"},
+		{Name: "grant-leader-scheduler", StoreID: &storeID},
+		{
+			Name: "balance-hot-region-scheduler",
+			Config: config.New(map[string]interface{}{
+				"min-hot-byte-rate":   int64(100),
+				"src-tolerance-ratio": 1.2,
+			}),
+		},
+	}
+	// shuffle-region-scheduler was declared before and removed from the spec
+	tc.Status.PD.Schedulers = []string{"shuffle-region-scheduler"}
+
+	pmm, _, _ := newFakePDMemberManager()
+	pdClient := controller.NewFakePDClient(pmm.deps.PDControl.(*pdapi.FakePDControl), tc)
+	running := []string{"balance-hot-region-scheduler", "shuffle-region-scheduler", "evict-leader-scheduler-1"}
+	pdClient.AddReaction(pdapi.GetSchedulersActionType, func(action *pdapi.Action) (interface{}, error) {
+		return running, nil
+	})
+	added := map[string]map[string]interface{}{}
+	pdClient.AddReaction(pdapi.AddSchedulerActionType, func(action *pdapi.Action) (interface{}, error) {
+		added[action.Name] = action.Args
+		return nil, nil
+	})
+	var removed []string
+	pdClient.AddReaction(pdapi.RemoveSchedulerActionType, func(action *pdapi.Action) (interface{}, error) {
+		removed = append(removed, action.Name)
+		return nil, nil
+	})
+	pdClient.AddReaction(pdapi.GetSchedulerConfigActionType, func(action *pdapi.Action) (interface{}, error) {
+		// PD returns all numbers as float
+		return map[string]interface{}{"min-hot-byte-rate": float64(100), "src-tolerance-ratio": 1.05}, nil
+	})
+	configs := map[string]map[string]interface{}{}
+	pdClient.AddReaction(pdapi.SetSchedulerConfigActionType, func(action *pdapi.Action) (interface{}, error) {
+		configs[action.Name] = action.Args
+		return nil, nil
+	})
+
+	g.Expect(pmm.syncPDSchedulers(tc)).To(Succeed())
+	g.Expect(added).To(HaveLen(2))
+	g.Expect(added).To(HaveKey("shuffle-leader-scheduler"))
+	g.Expect(added["grant-leader-scheduler"]).To(Equal(map[string]interface{}{"store_id": uint64(1)}))
+	g.Expect(removed).To(Equal([]string{"shuffle-region-scheduler"}))
+	g.Expect(configs).To(Equal(map[string]map[string]interface{}{
+		"balance-hot-region-scheduler": {"src-tolerance-ratio": 1.2},
+	}))
+	// balance-hot-region-scheduler was running before it is declared
+	g.Expect(tc.Status.PD.Schedulers).To(Equal([]string{"grant-leader-scheduler", "shuffle-leader-scheduler"}))
+
+	// the schedulers not added by the operator are not removed
+	tc.Spec.PD.Schedulers = tc.Spec.PD.Schedulers[:2]
+	running = []string{"balance-hot-region-scheduler", "grant-leader-scheduler", "shuffle-leader-scheduler"}
+	removed = nil
+	g.Expect(pmm.syncPDSchedulers(tc)).To(Succeed())
+	g.Expect(removed).To(BeEmpty())
+	g.Expect(tc.Status.PD.Schedulers).To(Equal([]string{"grant-leader-scheduler", "shuffle-leader-scheduler"}))
+
+	// the schedulers added are recorded even if a later call fails
+	tc.Spec.PD.Schedulers = []v1alpha1.PDSchedulerSpec{{Name: "shuffle-region-scheduler"}}
+	running = nil
+	pdClient.AddReaction(pdapi.AddSchedulerActionType, func(action *pdapi.Action) (interface{}, error) {
+		return nil, nil
+	})
+	pdClient.AddReaction(pdapi.RemoveSchedulerActionType, func(action *pdapi.Action) (interface{}, error) {
+		return nil, fmt.Errorf("pd is unavailable")
+	})
+	tc.Status.PD.Schedulers = []string{"shuffle-leader-scheduler"}
+	g.Expect(pmm.syncPDSchedulers(tc)).NotTo(Succeed())
+	g.Expect(tc.Status.PD.Schedulers).To(Equal([]string{"shuffle-leader-scheduler", "shuffle-region-scheduler"}))
+}
+
 func newFakePDMemberManager() (*pdMemberManager, cache.Indexer, cache.Indexer) {
 	fakeDeps := controller.NewFakeDependencies()
 	podIndexer := fakeDeps.KubeInformerFactory.Core().V1().Pods().Informer().GetIndexer()
@@ -210,6 +210,10 @@ func (s *pdScaler) preCheckUpMembers(tc *v1alpha1.TidbCluster, podName string) b
 		upComponents += int(tc.Status.Pump.StatefulSet.Replicas)
 	}
 
+	if tc.Status.Drainer.StatefulSet != nil {
+		upComponents += int(tc.Status.Drainer.StatefulSet.Replicas)
+	}
+
 	if upComponents != 0 && tc.Spec.PD.Replicas == 0 {
 		errMsg := fmt.Sprintf("The PD is in use by TidbCluster [%s/%s], can't scale in PD, podname %s", tc.GetNamespace(), tc.GetName(), podName)
 		klog.Error(errMsg)
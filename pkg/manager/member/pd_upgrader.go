@@ -54,6 +54,14 @@ func (u *pdUpgrader) gracefulUpgrade(tc *v1alpha1.TidbCluster, oldSet *apps.Stat
 		newSet.Spec.Template.Spec = *podSpec
 		return nil
 	}
+	if wait, err := waitForMaintenanceWindow(tc, oldSet, newSet); err != nil || wait {
+		return err
+	}
+
+	canary := newPDCanaryUpgrade(u.deps, tc)
+	if kept, err := canary.keepRolledBack(oldSet, newSet); err != nil || kept {
+		return err
+	}
 
 	tc.Status.PD.Phase = v1alpha1.UpgradePhase
 	if !templateEqual(newSet, oldSet) {
@@ -76,6 +84,9 @@ func (u *pdUpgrader) gracefulUpgrade(tc *v1alpha1.TidbCluster, oldSet *apps.Stat
 
 	setUpgradePartition(newSet, *oldSet.Spec.UpdateStrategy.RollingUpdate.Partition)
 	podOrdinals := helper.GetPodOrdinals(*oldSet.Spec.Replicas, oldSet).List()
+	if rolledBack, err := canary.watch(oldSet, newSet, podOrdinals); err != nil || rolledBack {
+		return err
+	}
 	for _i := len(podOrdinals) - 1; _i >= 0; _i-- {
 		i := podOrdinals[_i]
 		podName := PdPodName(tcName, i)
@@ -96,6 +107,10 @@ func (u *pdUpgrader) gracefulUpgrade(tc *v1alpha1.TidbCluster, oldSet *apps.Stat
 			continue
 		}
 
+		if err := canary.gate(podOrdinals, podOrdinals[_i+1:]); err != nil {
+			return err
+		}
+
 		if u.deps.CLIConfig.PodWebhookEnabled {
 			setUpgradePartition(newSet, i)
 			return nil
@@ -16,6 +16,7 @@ package member
 import (
 	"fmt"
 	"path"
+	"strconv"
 	"strings"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
@@ -40,13 +41,15 @@ const (
 )
 
 type pumpMemberManager struct {
-	deps *controller.Dependencies
+	deps   *controller.Dependencies
+	scaler Scaler
 }
 
 // NewPumpMemberManager returns a controller to reconcile pump clusters
-func NewPumpMemberManager(deps *controller.Dependencies) manager.Manager {
+func NewPumpMemberManager(deps *controller.Dependencies, scaler Scaler) manager.Manager {
 	return &pumpMemberManager{
-		deps: deps,
+		deps:   deps,
+		scaler: scaler,
 	}
 }
 
@@ -96,11 +99,25 @@ func (m *pumpMemberManager) syncPumpStatefulSetForTidbCluster(tc *v1alpha1.TidbC
 		return m.deps.StatefulSetControl.CreateStatefulSet(tc, newPumpSet)
 	}
 
+	if err := recreateStatefulSetForStorage(m.deps, tc, newPumpSet, oldPumpSet); err != nil {
+		return err
+	}
+
 	// Wait for PD & TiKV upgrading done
-	if tc.Status.PD.Phase == v1alpha1.UpgradePhase || tc.Status.TiKV.Phase == v1alpha1.UpgradePhase {
+	if tc.Status.PD.Phase == v1alpha1.UpgradePhase || tc.TiKVUpgrading() {
 		return nil
 	}
 
+	if err := m.scaler.Scale(tc, oldPumpSet, newPumpSet); err != nil {
+		return err
+	}
+
+	if !templateEqual(newPumpSet, oldPumpSet) {
+		if _, err := waitForMaintenanceWindow(tc, oldPumpSet, newPumpSet); err != nil {
+			return err
+		}
+	}
+
 	return UpdateStatefulSet(m.deps.StatefulSetControl, tc, newPumpSet, oldPumpSet)
 }
 
@@ -116,19 +133,48 @@ func (m *pumpMemberManager) syncTiDBClusterStatus(tc *v1alpha1.TidbCluster, set
 	if err != nil {
 		return err
 	}
-	if upgrading {
+	// Scaling takes precedence over upgrading.
+	if set.Spec.Replicas != nil && tc.Spec.Pump.Replicas != *set.Spec.Replicas {
+		tc.Status.Pump.Phase = v1alpha1.ScalePhase
+	} else if upgrading {
 		tc.Status.Pump.Phase = v1alpha1.UpgradePhase
 	} else {
 		tc.Status.Pump.Phase = v1alpha1.NormalPhase
 	}
 
-	//TODO: support sync pump members from PD.
-	// pumpStatus := map[string]v1alpha1.PumpMember{}
-	// tc.Status.Pump.Members = pumpStatus
+	// failed to sync pump members will not affect subsequent logic, just print the errors.
+	if err := m.syncPumpMembers(tc); err != nil {
+		klog.Errorf("failed to sync TidbCluster: [%s/%s]'s pump members, error: %v", tc.Namespace, tc.Name, err)
+	}
 
 	return nil
 }
 
+// syncPumpMembers syncs the pumps registered in PD to the status
+func (m *pumpMemberManager) syncPumpMembers(tc *v1alpha1.TidbCluster) error {
+	pumps, err := m.deps.PumpControl.GetPumps(tc)
+	if err != nil {
+		return err
+	}
+
+	members := map[string]v1alpha1.PumpMember{}
+	for _, pump := range pumps {
+		podName := pump.PodName()
+		if !strings.HasPrefix(podName, controller.PumpMemberName(tc.Name)+"-") {
+			// skip the pumps of other clusters sharing the same pd
+			continue
+		}
+		members[podName] = v1alpha1.PumpMember{
+			NodeID:      pump.NodeID,
+			Host:        pump.Host,
+			State:       pump.State,
+			MaxCommitTS: strconv.FormatInt(pump.MaxCommitTS, 10),
+		}
+	}
+	tc.Status.Pump.Members = members
+	return nil
+}
+
 func (m *pumpMemberManager) syncHeadlessService(tc *v1alpha1.TidbCluster) error {
 	if tc.Spec.Paused {
 		klog.V(4).Infof("tikv cluster %s/%s is paused, skip syncing for pump headless service", tc.GetNamespace(), tc.GetName())
@@ -272,6 +318,7 @@ func getNewPumpStatefulSet(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (*app
 	replicas := tc.Spec.Pump.Replicas
 	storageClass := tc.Spec.Pump.StorageClassName
 	podAnnos := CombineAnnotations(controller.AnnProm(8250), spec.Annotations())
+	setRestartedAtAnnotations(tc, v1alpha1.PumpMemberType, podAnnos)
 	storageRequest, err := controller.ParseStorageRequest(tc.Spec.Pump.Requests)
 	if err != nil {
 		return nil, fmt.Errorf("cannot parse storage request for pump, tidbcluster %s/%s, error: %v", tc.Namespace, tc.Name, err)
@@ -255,7 +255,8 @@ func TestPumpMemberManagerSyncUpdate(t *testing.T) {
 				g.Expect(r.sync).To(Succeed())
 				g.Expect(r.svc.Spec.Ports[0].Port).NotTo(Equal(int32(8888)))
 				g.Expect(r.cm.Data["pump-config"]).To(ContainSubstring("stop-write-at-available-space"))
-				g.Expect(*r.set.Spec.Replicas).To(Equal(int32(5)))
+				// scale out one by one
+				g.Expect(*r.set.Spec.Replicas).To(Equal(int32(4)))
 			},
 		},
 		{
@@ -433,7 +434,7 @@ type pumpFakeControls struct {
 
 func newFakePumpMemberManager() (*pumpMemberManager, *pumpFakeControls, *pumpFakeIndexers) {
 	fakeDeps := controller.NewFakeDependencies()
-	pmm := &pumpMemberManager{deps: fakeDeps}
+	pmm := &pumpMemberManager{deps: fakeDeps, scaler: NewFakePumpScaler()}
 	controls := &pumpFakeControls{
 		svc:     fakeDeps.ServiceControl.(*controller.FakeServiceControl),
 		set:     fakeDeps.StatefulSetControl.(*controller.FakeStatefulSetControl),
@@ -0,0 +1,150 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	apps "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
+)
+
+type pumpScaler struct {
+	generalScaler
+}
+
+// NewPumpScaler returns a pump Scaler
+func NewPumpScaler(deps *controller.Dependencies) Scaler {
+	return &pumpScaler{generalScaler: generalScaler{deps: deps}}
+}
+
+func (s *pumpScaler) Scale(meta metav1.Object, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+	scaling, _, _, _ := scaleOne(oldSet, newSet)
+	if scaling > 0 {
+		return s.ScaleOut(meta, oldSet, newSet)
+	} else if scaling < 0 {
+		return s.ScaleIn(meta, oldSet, newSet)
+	}
+	return s.SyncAutoScalerAnn(meta, oldSet)
+}
+
+func (s *pumpScaler) ScaleOut(meta metav1.Object, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+	tc, ok := meta.(*v1alpha1.TidbCluster)
+	if !ok {
+		return fmt.Errorf("pumpScaler.ScaleOut: failed to convert cluster %s/%s", meta.GetNamespace(), meta.GetName())
+	}
+
+	_, ordinal, replicas, deleteSlots := scaleOne(oldSet, newSet)
+	resetReplicas(newSet, oldSet)
+
+	klog.Infof("scaling out pump statefulset %s/%s, ordinal: %d (replicas: %d, delete slots: %v)", oldSet.Namespace, oldSet.Name, ordinal, replicas, deleteSlots.List())
+	// the data of the offline pump must not be reused by the new one
+	_, err := s.deleteDeferDeletingPVC(tc, oldSet.GetName(), v1alpha1.PumpMemberType, ordinal)
+	if err != nil {
+		return err
+	}
+
+	setReplicasAndDeleteSlots(newSet, replicas, deleteSlots)
+	return nil
+}
+
+// ScaleIn makes the pump go offline before deleting the pod, so that TiDB
+// stops writing binlogs to it and drainers consume all of its binlogs.
+func (s *pumpScaler) ScaleIn(meta metav1.Object, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+	tc, ok := meta.(*v1alpha1.TidbCluster)
+	if !ok {
+		return fmt.Errorf("pumpScaler.ScaleIn: failed to convert cluster %s/%s", meta.GetNamespace(), meta.GetName())
+	}
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	// we can only remove one member at a time when scaling in
+	_, ordinal, replicas, deleteSlots := scaleOne(oldSet, newSet)
+	resetReplicas(newSet, oldSet)
+
+	klog.Infof("scaling in pump statefulset %s/%s, ordinal: %d (replicas: %d, delete slots: %v)", oldSet.Namespace, oldSet.Name, ordinal, replicas, deleteSlots.List())
+	podName := ordinalPodName(v1alpha1.PumpMemberType, tcName, ordinal)
+	pod, err := s.deps.PodLister.Pods(ns).Get(podName)
+	if err != nil {
+		return fmt.Errorf("pumpScaler.ScaleIn: failed to get pod %s for cluster %s/%s, error: %s", podName, ns, tcName, err)
+	}
+
+	member, exist := tc.Status.Pump.Members[podName]
+	if !exist {
+		// The pump has never registered in PD, e.g. the pod is always pending,
+		// delete it directly to avoid blocking the subsequent operations.
+		if podutil.IsPodReady(pod) {
+			return controller.RequeueErrorf("pump %s/%s is not found in PD, wait for its status to be synced", ns, podName)
+		}
+		klog.Infof("pump scale in: pump %s/%s is not ready and not found in PD, delete it directly", ns, podName)
+		setReplicasAndDeleteSlots(newSet, replicas, deleteSlots)
+		return nil
+	}
+
+	switch member.State {
+	case v1alpha1.PumpStateOffline:
+		klog.Infof("pump scale in: pump %s/%s node %s is offline", ns, podName, member.NodeID)
+		if err := s.updateDeferDeletingPVC(tc, v1alpha1.PumpMemberType, ordinal); err != nil {
+			return err
+		}
+		setReplicasAndDeleteSlots(newSet, replicas, deleteSlots)
+		return nil
+	case v1alpha1.PumpStateClosing:
+		return controller.RequeueErrorf("pump %s/%s node %s is closing", ns, podName, member.NodeID)
+	default:
+		if err := s.deps.PumpControl.OfflinePump(tc, ordinal, member.NodeID); err != nil {
+			klog.Errorf("pump scale in: failed to offline pump %s/%s node %s, %v", ns, podName, member.NodeID, err)
+			return err
+		}
+		klog.Infof("pump scale in: offline pump %s/%s node %s successfully", ns, podName, member.NodeID)
+		return controller.RequeueErrorf("pump %s/%s node %s is going offline, state: %s", ns, podName, member.NodeID, member.State)
+	}
+}
+
+func (s *pumpScaler) SyncAutoScalerAnn(meta metav1.Object, actual *apps.StatefulSet) error {
+	return nil
+}
+
+type fakePumpScaler struct{}
+
+// NewFakePumpScaler returns a fake pump Scaler
+func NewFakePumpScaler() Scaler {
+	return &fakePumpScaler{}
+}
+
+func (s *fakePumpScaler) Scale(meta metav1.Object, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+	if *newSet.Spec.Replicas > *oldSet.Spec.Replicas {
+		return s.ScaleOut(meta, oldSet, newSet)
+	} else if *newSet.Spec.Replicas < *oldSet.Spec.Replicas {
+		return s.ScaleIn(meta, oldSet, newSet)
+	}
+	return nil
+}
+
+func (s *fakePumpScaler) ScaleOut(_ metav1.Object, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+	setReplicasAndDeleteSlots(newSet, *oldSet.Spec.Replicas+1, nil)
+	return nil
+}
+
+func (s *fakePumpScaler) ScaleIn(_ metav1.Object, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+	setReplicasAndDeleteSlots(newSet, *oldSet.Spec.Replicas-1, nil)
+	return nil
+}
+
+func (s *fakePumpScaler) SyncAutoScalerAnn(_ metav1.Object, actual *apps.StatefulSet) error {
+	return nil
+}
@@ -0,0 +1,202 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/pointer"
+)
+
+func TestPumpScalerScaleOut(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForPumpScale()
+	oldSet := newStatefulSetForPumpScale()
+	newSet := oldSet.DeepCopy()
+	newSet.Spec.Replicas = pointer.Int32Ptr(5)
+
+	scaler, _, _, _ := newFakePumpScaler()
+	err := scaler.Scale(tc, oldSet, newSet)
+	g.Expect(err).NotTo(HaveOccurred())
+	// scale out one by one
+	g.Expect(int(*newSet.Spec.Replicas)).To(Equal(4))
+
+	// only TidbCluster is supported
+	newSet.Spec.Replicas = pointer.Int32Ptr(5)
+	err = scaler.ScaleOut(&v1alpha1.DMCluster{}, oldSet, newSet)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("failed to convert cluster"))
+}
+
+func TestPumpScalerScaleIn(t *testing.T) {
+	g := NewGomegaWithT(t)
+	type testcase struct {
+		name        string
+		changeFn    func(*v1alpha1.TidbCluster)
+		podReady    bool
+		errExpectFn func(*GomegaWithT, error)
+		changed     bool
+		offlined    bool
+	}
+
+	testFn := func(test testcase, t *testing.T) {
+		t.Log(test.name)
+		tc := newTidbClusterForPumpScale()
+		if test.changeFn != nil {
+			test.changeFn(tc)
+		}
+		oldSet := newStatefulSetForPumpScale()
+		newSet := oldSet.DeepCopy()
+		newSet.Spec.Replicas = pointer.Int32Ptr(2)
+
+		scaler, pumpControl, podIndexer, pvcIndexer := newFakePumpScaler()
+		for i := int32(0); i < *oldSet.Spec.Replicas; i++ {
+			podName := ordinalPodName(v1alpha1.PumpMemberType, tc.Name, i)
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      podName,
+					Namespace: corev1.NamespaceDefault,
+				},
+			}
+			if test.podReady {
+				pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+			}
+			podIndexer.Add(pod)
+			pvcLabels := label.New().Instance(tc.GetInstanceName()).Pump()
+			pvcLabels[label.AnnPodNameKey] = podName
+			pvcIndexer.Add(&corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf("data-%s", podName),
+					Namespace: corev1.NamespaceDefault,
+					Labels:    pvcLabels.Labels(),
+				},
+			})
+		}
+		for _, member := range tc.Status.Pump.Members {
+			pumpControl.SetPump(&controller.BinlogNode{NodeID: member.NodeID, Host: member.Host, State: member.State})
+		}
+
+		err := scaler.Scale(tc, oldSet, newSet)
+		test.errExpectFn(g, err)
+		if test.changed {
+			g.Expect(int(*newSet.Spec.Replicas)).To(Equal(2))
+		} else {
+			g.Expect(int(*newSet.Spec.Replicas)).To(Equal(3))
+		}
+		pumps, err := pumpControl.GetPumps(tc)
+		g.Expect(err).NotTo(HaveOccurred())
+		for _, pump := range pumps {
+			if pump.NodeID == "pump-2" && test.offlined {
+				g.Expect(pump.State).To(Equal(v1alpha1.PumpStateClosing))
+			}
+		}
+	}
+
+	setState := func(state string) func(*v1alpha1.TidbCluster) {
+		return func(tc *v1alpha1.TidbCluster) {
+			podName := ordinalPodName(v1alpha1.PumpMemberType, tc.Name, 2)
+			member := tc.Status.Pump.Members[podName]
+			member.State = state
+			tc.Status.Pump.Members[podName] = member
+		}
+	}
+
+	tests := []testcase{
+		{
+			name:        "pump is online",
+			errExpectFn: errExpectRequeue,
+			changed:     false,
+			offlined:    true,
+		},
+		{
+			name:        "pump is closing",
+			changeFn:    setState(v1alpha1.PumpStateClosing),
+			errExpectFn: errExpectRequeue,
+			changed:     false,
+		},
+		{
+			name:        "pump is offline",
+			changeFn:    setState(v1alpha1.PumpStateOffline),
+			errExpectFn: errExpectNil,
+			changed:     true,
+		},
+		{
+			name: "pump is not registered and the pod is not ready",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				delete(tc.Status.Pump.Members, ordinalPodName(v1alpha1.PumpMemberType, tc.Name, 2))
+			},
+			errExpectFn: errExpectNil,
+			changed:     true,
+		},
+		{
+			name: "pump is not registered and the pod is ready",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				delete(tc.Status.Pump.Members, ordinalPodName(v1alpha1.PumpMemberType, tc.Name, 2))
+			},
+			podReady:    true,
+			errExpectFn: errExpectRequeue,
+			changed:     false,
+		},
+	}
+
+	for _, test := range tests {
+		testFn(test, t)
+	}
+}
+
+func newFakePumpScaler() (*pumpScaler, *controller.FakePumpControl, cache.Indexer, cache.Indexer) {
+	fakeDeps := controller.NewFakeDependencies()
+	scaler := &pumpScaler{generalScaler: generalScaler{deps: fakeDeps}}
+	pumpControl := fakeDeps.PumpControl.(*controller.FakePumpControl)
+	podIndexer := fakeDeps.KubeInformerFactory.Core().V1().Pods().Informer().GetIndexer()
+	pvcIndexer := fakeDeps.KubeInformerFactory.Core().V1().PersistentVolumeClaims().Informer().GetIndexer()
+	return scaler, pumpControl, podIndexer, pvcIndexer
+}
+
+func newStatefulSetForPumpScale() *apps.StatefulSet {
+	return &apps.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      controller.PumpMemberName(upgradeTcName),
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: apps.StatefulSetSpec{
+			Replicas: pointer.Int32Ptr(3),
+		},
+	}
+}
+
+func newTidbClusterForPumpScale() *v1alpha1.TidbCluster {
+	tc := newTidbClusterForPump()
+	tc.Name = upgradeTcName
+	tc.Status.Pump.Members = map[string]v1alpha1.PumpMember{}
+	for i := int32(0); i < 3; i++ {
+		podName := ordinalPodName(v1alpha1.PumpMemberType, tc.Name, i)
+		tc.Status.Pump.Members[podName] = v1alpha1.PumpMember{
+			NodeID: fmt.Sprintf("pump-%d", i),
+			Host:   fmt.Sprintf("%s.%s:8250", podName, controller.PumpPeerMemberName(tc.Name)),
+			State:  v1alpha1.PumpStateOnline,
+		}
+	}
+	return tc
+}
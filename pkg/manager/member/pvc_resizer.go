@@ -16,18 +16,24 @@ package member
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/label"
 	"github.com/pingcap/tidb-operator/pkg/util"
+	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog"
+	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 )
 
 // PVCResizerInterface represents the interface of PVC Resizer.
@@ -40,21 +46,32 @@ import (
 // for every unmatched PVC (desiredCapacity != actualCapacity)
 //  if storageClass does not support VolumeExpansion, skip and continue
 //  if not patched, patch
+// for every component
+//  if all the pods are ready, restart the pod with the largest ordinal whose
+//  PVC is FileSystemResizePending, the PD leader is transferred and the TiKV
+//  leaders are evicted before the pod is restarted
 //
 // We patch all PVCs at the same time. For many cloud storage plugins (e.g.
 // AWS-EBS, GCE-PD), they support online file system expansion in latest
 // Kubernetes (1.15+).
 //
+// The `volumeClaimTemplates` of the statefulset can not be changed, so the
+// statefulset is deleted with its pods orphaned and recreated with the new
+// storage request by the member managers, new PVCs created by the statefulset
+// controller use the new storage request.
+//
+// If the feature `ExpandInUsePersistentVolumes` is not enabled or the volume
+// plugin does not support, the file system is resized after the pod referencing
+// the volume is recreated. Such pods are restarted one at a time, and only in
+// the maintenance window of the tidb cluster.
+//
+// The PVCs whose capacity is less than the storage request are recorded in
+// the status of the cluster until the resize is finished.
+//
 // Limitations:
 //
-// - Note that the current statfulset implementation does not allow
-//   `volumeClaimTemplates` to be changed, so new PVCs created by statefulset
-//   controller will use the old storage request.
 // - This is best effort, before statefulset volume resize feature (e.g.
 //   https://github.com/kubernetes/enhancements/pull/1848) to be implemented.
-// - If the feature `ExpandInUsePersistentVolumes` is not enabled or the volume
-//   plugin does not support, the pod referencing the volume must be deleted and
-//   recreated after the `FileSystemResizePending` condition becomes true.
 // - Shrinking volumes is not supported.
 //
 type PVCResizerInterface interface {
@@ -81,10 +98,24 @@ func (p *pvcResizer) Resize(tc *v1alpha1.TidbCluster) error {
 	if err != nil {
 		return err
 	}
+	// resizing the volumes may restart the pods, wait for the maintenance window
+	inWindow, err := utiltidbcluster.InMaintenanceWindow(tc, time.Now())
+	if err != nil {
+		return err
+	}
+	canPatch := func() bool {
+		if !inWindow {
+			utiltidbcluster.SetMaintenancePending(&tc.Status, "pvc resize")
+		}
+		return inWindow
+	}
+	r := &resizing{obj: tc, ns: tc.GetNamespace(), canPatch: canPatch, pvcs: map[string]v1alpha1.PVCResizeStatus{}}
 	// patch PD PVCs
 	if tc.Spec.PD != nil {
 		if storageRequest, ok := tc.Spec.PD.Requests[corev1.ResourceStorage]; ok {
-			err = p.patchPVCs(tc.GetNamespace(), selector.Add(*pdRequirement), storageRequest, "")
+			err = p.resizeComponent(r, selector.Add(*pdRequirement), storageRequest, "", func(pod *corev1.Pod) (bool, error) {
+				return p.transferPDLeader(tc, pod)
+			})
 			if err != nil {
 				return err
 			}
@@ -93,7 +124,23 @@ func (p *pvcResizer) Resize(tc *v1alpha1.TidbCluster) error {
 	// patch TiKV PVCs
 	if tc.Spec.TiKV != nil {
 		if storageRequest, ok := tc.Spec.TiKV.Requests[corev1.ResourceStorage]; ok {
-			err = p.patchPVCs(tc.GetNamespace(), selector.Add(*tikvRequirement), storageRequest, "")
+			err = p.resizeComponent(r, selector.Add(*tikvRequirement, *tikvDefaultGroupRequirement), storageRequest, "", func(pod *corev1.Pod) (bool, error) {
+				return p.evictTiKVLeaders(tc, pod)
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	// patch TiKV group PVCs
+	for i := range tc.Spec.TiKVGroups {
+		group := &tc.Spec.TiKVGroups[i]
+		if storageRequest, ok := group.Requests[corev1.ResourceStorage]; ok {
+			gtc := tc.TiKVGroupCluster(group)
+			groupRequirement := util.MustNewRequirement(label.TiKVGroupLabelKey, selection.Equals, []string{group.Name})
+			err = p.resizeComponent(r, selector.Add(*tikvRequirement, *groupRequirement), storageRequest, "", func(pod *corev1.Pod) (bool, error) {
+				return p.evictTiKVLeaders(gtc, pod)
+			})
 			if err != nil {
 				return err
 			}
@@ -104,7 +151,7 @@ func (p *pvcResizer) Resize(tc *v1alpha1.TidbCluster) error {
 		for i, claim := range tc.Spec.TiFlash.StorageClaims {
 			if storageRequest, ok := claim.Resources.Requests[corev1.ResourceStorage]; ok {
 				prefix := fmt.Sprintf("data%d", i)
-				err = p.patchPVCs(tc.GetNamespace(), selector.Add(*tiflashRequirement), storageRequest, prefix)
+				err = p.resizeComponent(r, selector.Add(*tiflashRequirement), storageRequest, prefix, nil)
 				if err != nil {
 					return err
 				}
@@ -114,12 +161,13 @@ func (p *pvcResizer) Resize(tc *v1alpha1.TidbCluster) error {
 	// patch Pump PVCs
 	if tc.Spec.Pump != nil {
 		if storageRequest, ok := tc.Spec.Pump.Requests[corev1.ResourceStorage]; ok {
-			err = p.patchPVCs(tc.GetNamespace(), selector.Add(*pumpRequirement), storageRequest, "")
+			err = p.resizeComponent(r, selector.Add(*pumpRequirement), storageRequest, "", nil)
 			if err != nil {
 				return err
 			}
 		}
 	}
+	tc.Status.ResizingPVCs = r.status()
 	return nil
 }
 
@@ -128,9 +176,10 @@ func (p *pvcResizer) ResizeDM(dc *v1alpha1.DMCluster) error {
 	if err != nil {
 		return err
 	}
+	r := &resizing{obj: dc, ns: dc.GetNamespace(), pvcs: map[string]v1alpha1.PVCResizeStatus{}}
 	// patch dm-master PVCs
 	if masterRs, err := resource.ParseQuantity(dc.Spec.Master.StorageSize); err == nil {
-		err = p.patchPVCs(dc.GetNamespace(), selector.Add(*dmMasterRequirement), masterRs, "")
+		err = p.resizeComponent(r, selector.Add(*dmMasterRequirement), masterRs, "", nil)
 		if err != nil {
 			return err
 		}
@@ -138,15 +187,168 @@ func (p *pvcResizer) ResizeDM(dc *v1alpha1.DMCluster) error {
 	// patch dm-worker PVCs
 	if dc.Spec.Worker != nil {
 		if workerRs, err := resource.ParseQuantity(dc.Spec.Worker.StorageSize); err == nil {
-			err = p.patchPVCs(dc.GetNamespace(), selector.Add(*dmWorkerRequirement), workerRs, "")
+			err = p.resizeComponent(r, selector.Add(*dmWorkerRequirement), workerRs, "", nil)
 			if err != nil {
 				return err
 			}
 		}
 	}
+	dc.Status.ResizingPVCs = r.status()
 	return nil
 }
 
+// resizing is the state of resizing the PVCs of a cluster in a sync
+type resizing struct {
+	obj runtime.Object
+	ns  string
+	// canPatch returns whether the PVCs can be patched and the pods can be
+	// restarted, nil means always
+	canPatch func() bool
+	// pvcs are the PVCs whose capacity is less than the storage request
+	pvcs map[string]v1alpha1.PVCResizeStatus
+	// restarting indicates a pod is restarting to resize the file system,
+	// no more pods are restarted in this sync
+	restarting bool
+}
+
+func (r *resizing) status() map[string]v1alpha1.PVCResizeStatus {
+	if len(r.pvcs) == 0 {
+		return nil
+	}
+	return r.pvcs
+}
+
+// resizeComponent patches the PVCs of a component and restarts its pods
+// waiting for the file system resize, prepareRestart returns whether the pod
+// is ready to be restarted, nil means always
+func (p *pvcResizer) resizeComponent(r *resizing, selector labels.Selector, storageRequest resource.Quantity, prefix string, prepareRestart func(*corev1.Pod) (bool, error)) error {
+	pvcs, err := p.patchPVCs(r.ns, selector, storageRequest, prefix, r.canPatch)
+	if err != nil {
+		return err
+	}
+	for name, status := range pvcs {
+		r.pvcs[name] = status
+	}
+	if r.restarting {
+		return nil
+	}
+	r.restarting, err = p.restartPodForFileSystemResize(r, selector, pvcs, prepareRestart)
+	return err
+}
+
+// restartPodForFileSystemResize deletes a pod whose PVCs are FileSystemResizePending to let
+// the StatefulSet recreate it, the pods are restarted one by one in descending ordinal and
+// only when all the pods of the component are ready. It returns true if a pod is restarting.
+func (p *pvcResizer) restartPodForFileSystemResize(r *resizing, selector labels.Selector, pvcs map[string]v1alpha1.PVCResizeStatus, prepareRestart func(*corev1.Pod) (bool, error)) (bool, error) {
+	pending := sets.NewString()
+	for _, status := range pvcs {
+		if status.Phase == v1alpha1.PVCFileSystemResizePending && status.PodName != "" {
+			pending.Insert(status.PodName)
+		}
+	}
+	if pending.Len() == 0 {
+		return false, nil
+	}
+
+	pods, err := p.deps.PodLister.Pods(r.ns).List(selector)
+	if err != nil {
+		return false, err
+	}
+	var candidate *corev1.Pod
+	var candidateOrdinal int32 = -1
+	found := sets.NewString()
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil || !podutil.IsPodReady(pod) {
+			klog.Infof("pod %s/%s is not ready, wait for it to restart the pods for the file system resize", pod.Namespace, pod.Name)
+			return true, nil
+		}
+		if !pending.Has(pod.Name) {
+			continue
+		}
+		found.Insert(pod.Name)
+		ordinal, err := util.GetOrdinalFromPodName(pod.Name)
+		if err != nil {
+			return false, err
+		}
+		if ordinal > candidateOrdinal {
+			candidate, candidateOrdinal = pod, ordinal
+		}
+	}
+	// the pods being recreated are not listed
+	if missing := pending.Difference(found); missing.Len() > 0 {
+		klog.Infof("pods %s/%v are being recreated, wait for them to restart the pods for the file system resize", r.ns, missing.List())
+		return true, nil
+	}
+	if r.canPatch != nil && !r.canPatch() {
+		klog.Infof("pod %s/%s is out of the maintenance windows, skip restarting it for the file system resize", candidate.Namespace, candidate.Name)
+		return false, nil
+	}
+	if prepareRestart != nil {
+		ready, err := prepareRestart(candidate)
+		if err != nil {
+			return true, err
+		}
+		if !ready {
+			klog.Infof("pod %s/%s is not ready to be restarted for the file system resize", candidate.Namespace, candidate.Name)
+			return true, nil
+		}
+	}
+	if err := p.deps.PodControl.DeletePod(r.obj, candidate); err != nil {
+		return false, err
+	}
+	klog.Infof("pod %s/%s is restarted to resize the file system of its volumes", candidate.Namespace, candidate.Name)
+	return true, nil
+}
+
+// transferPDLeader transfers the PD leader to another healthy member before the
+// pod of the leader is restarted, it returns true if the pod is not the leader
+func (p *pvcResizer) transferPDLeader(tc *v1alpha1.TidbCluster, pod *corev1.Pod) (bool, error) {
+	if strings.Split(tc.Status.PD.Leader.Name, ".")[0] != pod.Name {
+		return true, nil
+	}
+	for name, member := range tc.Status.PD.Members {
+		if strings.Split(name, ".")[0] == pod.Name || !member.Health {
+			continue
+		}
+		if err := controller.GetPDClient(p.deps.PDControl, tc).TransferPDLeader(name); err != nil {
+			klog.Errorf("pvc resizer: failed to transfer pd leader from %s/%s to %s, %v", pod.Namespace, pod.Name, name, err)
+			return false, err
+		}
+		klog.Infof("pvc resizer: transfer pd leader from %s/%s to %s before restarting it", pod.Namespace, pod.Name, name)
+		return false, nil
+	}
+	// no other healthy member to take the leadership
+	return true, nil
+}
+
+// evictTiKVLeaders evicts the leaders of the TiKV store before its pod is restarted
+// like the TiKV upgrader, it returns true once the leaders are evicted or the
+// eviction times out. tc is the group cluster for the pods of a TiKV group.
+func (p *pvcResizer) evictTiKVLeaders(tc *v1alpha1.TidbCluster, pod *corev1.Pod) (bool, error) {
+	for _, store := range tc.Status.TiKV.Stores {
+		if store.PodName != pod.Name {
+			continue
+		}
+		storeID, err := strconv.ParseUint(store.ID, 10, 64)
+		if err != nil {
+			return false, err
+		}
+		u := &tikvUpgrader{deps: p.deps}
+		if _, evicting := pod.Annotations[EvictLeaderBeginTime]; !evicting {
+			return false, u.beginEvictLeader(tc, storeID, pod.DeepCopy())
+		}
+		if !u.readyToUpgrade(pod, store, tc.TiKVEvictLeaderTimeout()) {
+			return false, nil
+		}
+		if err := controller.GetPDClient(p.deps.PDControl, tc).EndEvictLeader(storeID); err != nil {
+			klog.Errorf("pvc resizer: failed to end evict leader of store %d of pod %s/%s, %v", storeID, pod.Namespace, pod.Name, err)
+			return false, err
+		}
+		return true, nil
+	}
+	return true, nil
+}
+
 func (p *pvcResizer) isVolumeExpansionSupported(storageClassName string) (bool, error) {
 	sc, err := p.deps.StorageClassLister.Get(storageClassName)
 	if err != nil {
@@ -158,11 +360,13 @@ func (p *pvcResizer) isVolumeExpansionSupported(storageClassName string) (bool,
 	return *sc.AllowVolumeExpansion, nil
 }
 
-// patchPVCs patches PVCs filtered by selector and prefix.
-func (p *pvcResizer) patchPVCs(ns string, selector labels.Selector, storageRequest resource.Quantity, prefix string) error {
+// patchPVCs patches PVCs filtered by selector and prefix, a PVC is patched only
+// if canPatch is nil or returns true. It returns the progress of the PVCs whose
+// capacity is less than the storage request, keyed by PVC name.
+func (p *pvcResizer) patchPVCs(ns string, selector labels.Selector, storageRequest resource.Quantity, prefix string, canPatch func() bool) (map[string]v1alpha1.PVCResizeStatus, error) {
 	pvcs, err := p.deps.PVCLister.PersistentVolumeClaims(ns).List(selector)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	mergePatch, err := json.Marshal(map[string]interface{}{
 		"spec": map[string]interface{}{
@@ -174,8 +378,9 @@ func (p *pvcResizer) patchPVCs(ns string, selector labels.Selector, storageReque
 		},
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
+	resizing := map[string]v1alpha1.PVCResizeStatus{}
 	for _, pvc := range pvcs {
 		if !strings.HasPrefix(pvc.Name, prefix) {
 			continue
@@ -186,7 +391,7 @@ func (p *pvcResizer) patchPVCs(ns string, selector labels.Selector, storageReque
 		}
 		volumeExpansionSupported, err := p.isVolumeExpansionSupported(*pvc.Spec.StorageClassName)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		if currentRequest, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; !ok || storageRequest.Cmp(currentRequest) > 0 {
@@ -194,18 +399,51 @@ func (p *pvcResizer) patchPVCs(ns string, selector labels.Selector, storageReque
 				klog.Warningf("Storage Class %q used by PVC %s/%s does not support volume expansion, skipped", *pvc.Spec.StorageClassName, pvc.Namespace, pvc.Name)
 				continue
 			}
+			if canPatch != nil && !canPatch() {
+				klog.Infof("PVC %s/%s is out of the maintenance windows, skipped", pvc.Namespace, pvc.Name)
+				continue
+			}
 			_, err = p.deps.KubeClientset.CoreV1().PersistentVolumeClaims(pvc.Namespace).Patch(pvc.Name, types.MergePatchType, mergePatch)
 			if err != nil {
-				return err
+				return nil, err
 			}
 			klog.V(2).Infof("PVC %s/%s storage request is updated from %s to %s", pvc.Namespace, pvc.Name, currentRequest.String(), storageRequest.String())
 		} else if storageRequest.Cmp(currentRequest) < 0 {
 			klog.Warningf("PVC %s/%s/ storage request cannot be shrunk (%s to %s), skipped", pvc.Namespace, pvc.Name, currentRequest.String(), storageRequest.String())
+			continue
 		} else {
 			klog.V(4).Infof("PVC %s/%s storage request is already %s, skipped", pvc.Namespace, pvc.Name, storageRequest.String())
 		}
+
+		if status, ok := pvcResizeStatus(pvc, storageRequest); ok {
+			resizing[pvc.Name] = status
+		}
 	}
-	return nil
+	return resizing, nil
+}
+
+// pvcResizeStatus returns the progress of the PVC and whether its capacity
+// is less than the storage request
+func pvcResizeStatus(pvc *corev1.PersistentVolumeClaim, storageRequest resource.Quantity) (v1alpha1.PVCResizeStatus, bool) {
+	if pvc.Status.Phase != corev1.ClaimBound {
+		return v1alpha1.PVCResizeStatus{}, false
+	}
+	capacity := pvc.Status.Capacity[corev1.ResourceStorage]
+	if capacity.Cmp(storageRequest) >= 0 {
+		return v1alpha1.PVCResizeStatus{}, false
+	}
+	status := v1alpha1.PVCResizeStatus{
+		PodName:           pvc.Annotations[label.AnnPodNameKey],
+		CurrentCapacity:   capacity,
+		RequestedCapacity: storageRequest,
+		Phase:             v1alpha1.PVCResizing,
+	}
+	for _, cond := range pvc.Status.Conditions {
+		if cond.Type == corev1.PersistentVolumeClaimFileSystemResizePending && cond.Status == corev1.ConditionTrue {
+			status.Phase = v1alpha1.PVCFileSystemResizePending
+		}
+	}
+	return status, true
 }
 
 func NewPVCResizer(deps *controller.Dependencies) PVCResizerInterface {
@@ -15,13 +15,16 @@ package member
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	. "github.com/onsi/gomega"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/label"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
 	v1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -57,6 +60,12 @@ func newPVCWithStorage(name string, component string, storageClass, storageReque
 	return newFullPVC(name, component, storageClass, storageRequest, "tidb-cluster", "tc")
 }
 
+func newTiKVGroupPVCWithStorage(name string, group string, storageClass, storageRequest string) *v1.PersistentVolumeClaim {
+	pvc := newPVCWithStorage(name, label.TiKVLabelVal, storageClass, storageRequest)
+	pvc.Labels[label.TiKVGroupLabelKey] = group
+	return pvc
+}
+
 func newDMPVCWithStorage(name string, component string, storageClass, storageRequest string) *v1.PersistentVolumeClaim {
 	return newFullPVC(name, component, storageClass, storageRequest, "dm-cluster", "dc")
 }
@@ -143,6 +152,45 @@ func TestPVCResizer(t *testing.T) {
 				newPVCWithStorage("tikv-2", label.TiKVLabelVal, "sc", "2Gi"),
 			},
 		},
+		{
+			name: "resize TiKV and TiKV group PVCs with their own requests",
+			tc: &v1alpha1.TidbCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: v1.NamespaceDefault,
+					Name:      "tc",
+				},
+				Spec: v1alpha1.TidbClusterSpec{
+					TiKV: &v1alpha1.TiKVSpec{
+						ResourceRequirements: v1.ResourceRequirements{
+							Requests: v1.ResourceList{
+								v1.ResourceStorage: resource.MustParse("2Gi"),
+							},
+						},
+					},
+					TiKVGroups: []v1alpha1.TiKVGroupSpec{
+						{
+							Name: "group",
+							TiKVSpec: v1alpha1.TiKVSpec{
+								ResourceRequirements: v1.ResourceRequirements{
+									Requests: v1.ResourceList{
+										v1.ResourceStorage: resource.MustParse("3Gi"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			sc: newStorageClass("sc", true),
+			pvcs: []*v1.PersistentVolumeClaim{
+				newPVCWithStorage("tikv-0", label.TiKVLabelVal, "sc", "1Gi"),
+				newTiKVGroupPVCWithStorage("tikv-group-0", "group", "sc", "1Gi"),
+			},
+			wantPVCs: []*v1.PersistentVolumeClaim{
+				newPVCWithStorage("tikv-0", label.TiKVLabelVal, "sc", "2Gi"),
+				newTiKVGroupPVCWithStorage("tikv-group-0", "group", "sc", "3Gi"),
+			},
+		},
 		{
 			name: "resize TiFlash PVCs",
 			tc: &v1alpha1.TidbCluster{
@@ -430,3 +478,187 @@ func TestDMPVCResizer(t *testing.T) {
 		})
 	}
 }
+
+func TestPVCResizerFileSystemResize(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deps := controller.NewFakeDependencies()
+	tc := &v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: v1.NamespaceDefault,
+			Name:      "tc",
+		},
+		Spec: v1alpha1.TidbClusterSpec{
+			TiKV: &v1alpha1.TiKVSpec{
+				ResourceRequirements: v1.ResourceRequirements{
+					Requests: v1.ResourceList{
+						v1.ResourceStorage: resource.MustParse("2Gi"),
+					},
+				},
+			},
+		},
+	}
+	informers := deps.KubeInformerFactory
+	informers.Storage().V1().StorageClasses().Informer().GetIndexer().Add(newStorageClass("sc", true))
+	podIndexer := informers.Core().V1().Pods().Informer().GetIndexer()
+	for i, phase := range []v1alpha1.PVCResizePhase{v1alpha1.PVCFileSystemResizePending, v1alpha1.PVCResizing, v1alpha1.PVCFileSystemResizePending} {
+		podName := fmt.Sprintf("tc-tikv-%d", i)
+		pvc := newPVCWithStorage(fmt.Sprintf("tikv-%s", podName), label.TiKVLabelVal, "sc", "2Gi")
+		pvc.Annotations = map[string]string{label.AnnPodNameKey: podName}
+		pvc.Status = v1.PersistentVolumeClaimStatus{
+			Phase:    v1.ClaimBound,
+			Capacity: v1.ResourceList{v1.ResourceStorage: resource.MustParse("1Gi")},
+		}
+		if phase == v1alpha1.PVCFileSystemResizePending {
+			pvc.Status.Conditions = []v1.PersistentVolumeClaimCondition{
+				{Type: v1.PersistentVolumeClaimFileSystemResizePending, Status: v1.ConditionTrue},
+			}
+		}
+		informers.Core().V1().PersistentVolumeClaims().Informer().GetIndexer().Add(pvc)
+		podIndexer.Add(&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: v1.NamespaceDefault, Name: podName, Labels: pvc.Labels},
+			Status: v1.PodStatus{
+				Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}},
+			},
+		})
+	}
+	podExist := func(name string) bool {
+		_, exist, _ := podIndexer.GetByKey(v1.NamespaceDefault + "/" + name)
+		return exist
+	}
+	resizer := NewPVCResizer(deps)
+
+	t.Log("restart the pod with the largest ordinal")
+	g.Expect(resizer.Resize(tc)).To(Succeed())
+	g.Expect(tc.Status.ResizingPVCs).To(HaveLen(3))
+	g.Expect(tc.Status.ResizingPVCs["tikv-tc-tikv-1"].Phase).To(Equal(v1alpha1.PVCResizing))
+	g.Expect(tc.Status.ResizingPVCs["tikv-tc-tikv-2"].PodName).To(Equal("tc-tikv-2"))
+	g.Expect(tc.Status.ResizingPVCs["tikv-tc-tikv-2"].CurrentCapacity).To(Equal(resource.MustParse("1Gi")))
+	g.Expect(podExist("tc-tikv-2")).To(BeFalse())
+	g.Expect(podExist("tc-tikv-0")).To(BeTrue())
+
+	t.Log("wait for the pod to be recreated")
+	g.Expect(resizer.Resize(tc)).To(Succeed())
+	g.Expect(podExist("tc-tikv-0")).To(BeTrue())
+
+	t.Log("wait for the recreated pod to be ready")
+	pvcIndexer := informers.Core().V1().PersistentVolumeClaims().Informer().GetIndexer()
+	obj, _, _ := pvcIndexer.GetByKey(v1.NamespaceDefault + "/tikv-tc-tikv-2")
+	pvc := obj.(*v1.PersistentVolumeClaim).DeepCopy()
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: v1.NamespaceDefault, Name: "tc-tikv-2", Labels: pvc.Labels},
+		Status: v1.PodStatus{
+			Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionFalse}},
+		},
+	}
+	podIndexer.Add(pod)
+	g.Expect(resizer.Resize(tc)).To(Succeed())
+	g.Expect(podExist("tc-tikv-0")).To(BeTrue())
+
+	t.Log("restart the next pod")
+	pod.Status.Conditions[0].Status = v1.ConditionTrue
+	podIndexer.Update(pod)
+	pvc.Status.Capacity = v1.ResourceList{v1.ResourceStorage: resource.MustParse("2Gi")}
+	pvc.Status.Conditions = nil
+	pvcIndexer.Update(pvc)
+	g.Expect(resizer.Resize(tc)).To(Succeed())
+	g.Expect(tc.Status.ResizingPVCs).To(HaveLen(2))
+	g.Expect(tc.Status.ResizingPVCs).NotTo(HaveKey("tikv-tc-tikv-2"))
+	g.Expect(podExist("tc-tikv-0")).To(BeFalse())
+	g.Expect(podExist("tc-tikv-1")).To(BeTrue())
+}
+
+func TestPVCResizerFileSystemResizeLeaders(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deps := controller.NewFakeDependencies()
+	storage := v1.ResourceRequirements{
+		Requests: v1.ResourceList{
+			v1.ResourceStorage: resource.MustParse("2Gi"),
+		},
+	}
+	tc := &v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: v1.NamespaceDefault,
+			Name:      "tc",
+		},
+		Spec: v1alpha1.TidbClusterSpec{
+			PD:   &v1alpha1.PDSpec{ResourceRequirements: storage},
+			TiKV: &v1alpha1.TiKVSpec{ResourceRequirements: storage},
+		},
+	}
+	tc.Status.PD.Leader = v1alpha1.PDMember{Name: "tc-pd-0", Health: true}
+	tc.Status.PD.Members = map[string]v1alpha1.PDMember{
+		"tc-pd-0": {Name: "tc-pd-0", Health: true},
+		"tc-pd-1": {Name: "tc-pd-1", Health: true},
+	}
+	tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+		"1": {ID: "1", PodName: "tc-tikv-0", State: v1alpha1.TiKVStateUp, LeaderCount: 10},
+	}
+
+	informers := deps.KubeInformerFactory
+	informers.Storage().V1().StorageClasses().Informer().GetIndexer().Add(newStorageClass("sc", true))
+	podIndexer := informers.Core().V1().Pods().Informer().GetIndexer()
+	for _, podName := range []string{"tc-pd-0", "tc-tikv-0"} {
+		component := label.PDLabelVal
+		if podName == "tc-tikv-0" {
+			component = label.TiKVLabelVal
+		}
+		pvc := newPVCWithStorage(fmt.Sprintf("%s-%s", component, podName), component, "sc", "2Gi")
+		pvc.Annotations = map[string]string{label.AnnPodNameKey: podName}
+		pvc.Status = v1.PersistentVolumeClaimStatus{
+			Phase:    v1.ClaimBound,
+			Capacity: v1.ResourceList{v1.ResourceStorage: resource.MustParse("1Gi")},
+			Conditions: []v1.PersistentVolumeClaimCondition{
+				{Type: v1.PersistentVolumeClaimFileSystemResizePending, Status: v1.ConditionTrue},
+			},
+		}
+		informers.Core().V1().PersistentVolumeClaims().Informer().GetIndexer().Add(pvc)
+		podIndexer.Add(&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: v1.NamespaceDefault, Name: podName, Labels: pvc.Labels},
+			Status: v1.PodStatus{
+				Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}},
+			},
+		})
+	}
+	podExist := func(name string) bool {
+		_, exist, _ := podIndexer.GetByKey(v1.NamespaceDefault + "/" + name)
+		return exist
+	}
+
+	pdActions := map[pdapi.ActionType]int{}
+	pdClient := controller.NewFakePDClient(deps.PDControl.(*pdapi.FakePDControl), tc)
+	for _, action := range []pdapi.ActionType{pdapi.TransferPDLeaderActionType, pdapi.BeginEvictLeaderActionType, pdapi.EndEvictLeaderActionType} {
+		actionType := action
+		pdClient.AddReaction(actionType, func(action *pdapi.Action) (interface{}, error) {
+			pdActions[actionType]++
+			return nil, nil
+		})
+	}
+	resizer := NewPVCResizer(deps)
+
+	t.Log("transfer the pd leader before restarting the pod")
+	g.Expect(resizer.Resize(tc)).To(Succeed())
+	g.Expect(pdActions[pdapi.TransferPDLeaderActionType]).To(Equal(1))
+	g.Expect(podExist("tc-pd-0")).To(BeTrue())
+	g.Expect(pdActions[pdapi.BeginEvictLeaderActionType]).To(Equal(0))
+
+	t.Log("restart the pd pod after the leader is transferred")
+	tc.Status.PD.Leader = tc.Status.PD.Members["tc-pd-1"]
+	g.Expect(resizer.Resize(tc)).To(Succeed())
+	g.Expect(podExist("tc-pd-0")).To(BeFalse())
+
+	t.Log("evict the tikv leaders before restarting the pod")
+	tc.Spec.PD = nil
+	g.Expect(resizer.Resize(tc)).To(Succeed())
+	g.Expect(pdActions[pdapi.BeginEvictLeaderActionType]).To(Equal(1))
+	g.Expect(podExist("tc-tikv-0")).To(BeTrue())
+	g.Expect(resizer.Resize(tc)).To(Succeed())
+	g.Expect(podExist("tc-tikv-0")).To(BeTrue())
+
+	t.Log("restart the tikv pod after the leaders are evicted")
+	tc.Status.TiKV.Stores["1"] = v1alpha1.TiKVStore{ID: "1", PodName: "tc-tikv-0", State: v1alpha1.TiKVStateUp}
+	g.Expect(resizer.Resize(tc)).To(Succeed())
+	g.Expect(pdActions[pdapi.EndEvictLeaderActionType]).To(Equal(1))
+	g.Expect(podExist("tc-tikv-0")).To(BeFalse())
+}
@@ -336,6 +336,45 @@ func RenderPumpStartScript(model *PumpStartScriptModel) (string, error) {
 	return renderTemplateFunc(pumpStartScriptTpl, model)
 }
 
+// drainerStartScriptTpl is the template string of drainer start script
+// Note: changing this will cause a rolling-update of drainer cluster
+var drainerStartScriptTpl = template.Must(template.New("drainer-start-script").Parse(`set -euo pipefail
+
+/drainer \
+-L={{ .LogLevel }} \
+-pd-urls={{ .Scheme }}://{{ .ClusterName }}-pd:2379 \
+-addr=0.0.0.0:8249 \
+-advertise-addr=` + "`" + `echo ${HOSTNAME}` + "`" + `.{{ .ClusterName }}-drainer{{ .FormatDrainerZone }}:8249 \
+-config=/etc/drainer/drainer.toml \
+-initial-commit-ts={{ .InitialCommitTS }} \
+-data-dir=/data \
+-log-file=
+
+if [ $? == 0 ]; then
+    echo $(date -u +"[%Y/%m/%d %H:%M:%S.%3N %:z]") "drainer offline, please delete my pod"
+    tail -f /dev/null
+fi`))
+
+type DrainerStartScriptModel struct {
+	Scheme          string
+	ClusterName     string
+	LogLevel        string
+	InitialCommitTS string
+	Namespace       string
+	ClusterDomain   string
+}
+
+func (dssm *DrainerStartScriptModel) FormatDrainerZone() string {
+	if dssm.ClusterDomain != "" {
+		return fmt.Sprintf(".%s.svc.%s", dssm.Namespace, dssm.ClusterDomain)
+	}
+	return ""
+}
+
+func RenderDrainerStartScript(model *DrainerStartScriptModel) (string, error) {
+	return renderTemplateFunc(drainerStartScriptTpl, model)
+}
+
 // tidbInitStartScriptTpl is the template string of tidb initializer start script
 var tidbInitStartScriptTpl = template.Must(template.New("tidb-init-start-script").Parse(`import os, MySQLdb
 host = '{{ .ClusterName }}-tidb'
@@ -18,7 +18,6 @@ import (
 	"path"
 	"strings"
 
-	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/label"
@@ -43,13 +42,17 @@ const (
 // ticdcMemberManager implements manager.Manager.
 type ticdcMemberManager struct {
 	deps                     *controller.Dependencies
+	scaler                   Scaler
+	upgrader                 Upgrader
 	statefulSetIsUpgradingFn func(corelisters.PodLister, pdapi.PDControlInterface, *apps.StatefulSet, *v1alpha1.TidbCluster) (bool, error)
 }
 
 // NewTiCDCMemberManager returns a *ticdcMemberManager
-func NewTiCDCMemberManager(deps *controller.Dependencies) manager.Manager {
+func NewTiCDCMemberManager(deps *controller.Dependencies, scaler Scaler, upgrader Upgrader) manager.Manager {
 	m := &ticdcMemberManager{
-		deps: deps,
+		deps:     deps,
+		scaler:   scaler,
+		upgrader: upgrader,
 	}
 	m.statefulSetIsUpgradingFn = ticdcStatefulSetIsUpgrading
 	return m
@@ -114,9 +117,19 @@ func (m *ticdcMemberManager) syncStatefulSet(tc *v1alpha1.TidbCluster) error {
 		return nil
 	}
 
-	if tc.PDUpgrading() || tc.TiKVUpgrading() {
-		klog.Warningf("pd or tikv is upgrading, skipping upgrade ticdc")
-		return nil
+	// Scaling takes precedence over upgrading because:
+	// - if a capture fails in the upgrading, users may want to delete it or add
+	//   new replicas
+	// - it's ok to scale in the middle of upgrading (in statefulset controller
+	//   scaling takes precedence over upgrading too)
+	if err := m.scaler.Scale(tc, oldSts, newSts); err != nil {
+		return err
+	}
+
+	if !templateEqual(newSts, oldSts) || tc.Status.TiCDC.Phase == v1alpha1.UpgradePhase {
+		if err := m.upgrader.Upgrade(tc, oldSts, newSts); err != nil {
+			return err
+		}
 	}
 
 	return UpdateStatefulSet(m.deps.StatefulSetControl, tc, newSts, oldSts)
@@ -133,22 +146,33 @@ func (m *ticdcMemberManager) syncTiCDCStatus(tc *v1alpha1.TidbCluster, sts *apps
 	if err != nil {
 		return err
 	}
-	if upgrading {
+	// Scaling takes precedence over upgrading.
+	if tc.TiCDCDeployDesiredReplicas() != *sts.Spec.Replicas {
+		tc.Status.TiCDC.Phase = v1alpha1.ScalePhase
+	} else if upgrading {
 		tc.Status.TiCDC.Phase = v1alpha1.UpgradePhase
 	} else {
 		tc.Status.TiCDC.Phase = v1alpha1.NormalPhase
 	}
 
+	// The captures are listed from the ticdc cluster rather than the pods, so
+	// that the capture of a deleted pod is kept until it leaves the cluster.
+	captures, err := m.deps.CDCControl.GetCaptures(tc)
+	if err != nil {
+		tc.Status.TiCDC.Synced = false
+		return err
+	}
 	ticdcCaptures := map[string]v1alpha1.TiCDCCapture{}
-	for id := range helper.GetPodOrdinals(tc.Status.TiCDC.StatefulSet.Replicas, sts) {
-		podName := fmt.Sprintf("%s-%d", controller.TiCDCMemberName(tc.GetName()), id)
-		capture, err := m.deps.CDCControl.GetStatus(tc, int32(id))
-		if err != nil {
-			return err
+	for _, capture := range captures {
+		podName := capture.PodName()
+		if !strings.HasPrefix(podName, controller.TiCDCMemberName(tc.GetName())+"-") {
+			// skip the captures of other clusters sharing the same pd
+			continue
 		}
 		ticdcCaptures[podName] = v1alpha1.TiCDCCapture{
 			PodName: podName,
 			ID:      capture.ID,
+			IsOwner: capture.IsOwner,
 		}
 	}
 	tc.Status.TiCDC.Synced = true
@@ -233,6 +257,7 @@ func getNewTiCDCStatefulSet(tc *v1alpha1.TidbCluster) (*apps.StatefulSet, error)
 	ticdcLabel := labelTiCDC(tc)
 	stsName := controller.TiCDCMemberName(tcName)
 	podAnnotations := CombineAnnotations(controller.AnnProm(8301), baseTiCDCSpec.Annotations())
+	setRestartedAtAnnotations(tc, v1alpha1.TiCDCMemberType, podAnnotations)
 	stsAnnotations := getStsAnnotations(tc.Annotations, label.TiCDCLabelVal)
 	headlessSvcName := controller.TiCDCPeerMemberName(tcName)
 
@@ -336,6 +361,16 @@ func getNewTiCDCStatefulSet(tc *v1alpha1.TidbCluster) (*apps.StatefulSet, error)
 		}
 	}
 
+	updateStrategy := apps.StatefulSetUpdateStrategy{}
+	if baseTiCDCSpec.StatefulSetUpdateStrategy() == apps.OnDeleteStatefulSetStrategyType {
+		updateStrategy.Type = apps.OnDeleteStatefulSetStrategyType
+	} else {
+		updateStrategy.Type = apps.RollingUpdateStatefulSetStrategyType
+		updateStrategy.RollingUpdate = &apps.RollingUpdateStatefulSetStrategy{
+			Partition: pointer.Int32Ptr(tc.TiCDCDeployDesiredReplicas()),
+		}
+	}
+
 	ticdcSts := &apps.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            stsName,
@@ -356,9 +391,7 @@ func getNewTiCDCStatefulSet(tc *v1alpha1.TidbCluster) (*apps.StatefulSet, error)
 			},
 			ServiceName:         headlessSvcName,
 			PodManagementPolicy: apps.ParallelPodManagement,
-			UpdateStrategy: apps.StatefulSetUpdateStrategy{
-				Type: baseTiCDCSpec.StatefulSetUpdateStrategy(),
-			},
+			UpdateStrategy:      updateStrategy,
 		},
 	}
 	return ticdcSts, nil
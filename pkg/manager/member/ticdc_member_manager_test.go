@@ -166,7 +166,8 @@ func TestTiCDCMemberManagerSyncUpdate(t *testing.T) {
 			status:  v1alpha1.NormalPhase,
 			expectStatefulSetFn: func(g *GomegaWithT, set *apps.StatefulSet, err error) {
 				g.Expect(err).NotTo(HaveOccurred())
-				g.Expect(int(*set.Spec.Replicas)).To(Equal(5))
+				// scale out one by one
+				g.Expect(int(*set.Spec.Replicas)).To(Equal(4))
 			},
 		},
 		{
@@ -409,7 +410,9 @@ func TestTiCDCMemberManagerSyncTidbClusterStatus(t *testing.T) {
 func newFakeTiCDCMemberManager() (*ticdcMemberManager, *controller.FakeStatefulSetControl, *controller.FakeTiDBControl, *fakeIndexers) {
 	fakeDeps := controller.NewFakeDependencies()
 	tmm := &ticdcMemberManager{
-		deps: fakeDeps,
+		deps:     fakeDeps,
+		scaler:   NewFakeTiCDCScaler(),
+		upgrader: NewFakeTiCDCUpgrader(),
 	}
 	tmm.statefulSetIsUpgradingFn = ticdcStatefulSetIsUpgrading
 	indexers := &fakeIndexers{
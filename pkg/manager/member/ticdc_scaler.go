@@ -0,0 +1,126 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+
+	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	apps "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+)
+
+type ticdcScaler struct {
+	generalScaler
+}
+
+// NewTiCDCScaler returns a ticdc Scaler
+func NewTiCDCScaler(deps *controller.Dependencies) Scaler {
+	return &ticdcScaler{generalScaler: generalScaler{deps: deps}}
+}
+
+func (s *ticdcScaler) Scale(meta metav1.Object, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+	scaling, _, _, _ := scaleOne(oldSet, newSet)
+	if scaling > 0 {
+		return s.ScaleOut(meta, oldSet, newSet)
+	} else if scaling < 0 {
+		return s.ScaleIn(meta, oldSet, newSet)
+	}
+	return s.SyncAutoScalerAnn(meta, oldSet)
+}
+
+func (s *ticdcScaler) ScaleOut(meta metav1.Object, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+	_, ordinal, replicas, deleteSlots := scaleOne(oldSet, newSet)
+	resetReplicas(newSet, oldSet)
+
+	klog.Infof("scaling out ticdc statefulset %s/%s, ordinal: %d (replicas: %d, delete slots: %v)", oldSet.Namespace, oldSet.Name, ordinal, replicas, deleteSlots.List())
+	setReplicasAndDeleteSlots(newSet, replicas, deleteSlots)
+	return nil
+}
+
+// ScaleIn removes one capture at a time, the capture resigns the ownership and
+// moves its tables to other captures before the pod is deleted.
+func (s *ticdcScaler) ScaleIn(meta metav1.Object, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+	tc, ok := meta.(*v1alpha1.TidbCluster)
+	if !ok {
+		return fmt.Errorf("ticdcScaler.ScaleIn: failed to convert cluster %s/%s", meta.GetNamespace(), meta.GetName())
+	}
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	// we can only remove one member at a time when scaling in
+	_, ordinal, replicas, deleteSlots := scaleOne(oldSet, newSet)
+	resetReplicas(newSet, oldSet)
+
+	klog.Infof("scaling in ticdc statefulset %s/%s, ordinal: %d (replicas: %d, delete slots: %v)", oldSet.Namespace, oldSet.Name, ordinal, replicas, deleteSlots.List())
+
+	// the captures of the pods deleted in the previous rounds must leave the
+	// ticdc cluster before we move on, otherwise the tables may be scheduled to them
+	podOrdinals := helper.GetPodOrdinals(*oldSet.Spec.Replicas, oldSet)
+	for podName := range tc.Status.TiCDC.Captures {
+		exist := false
+		for id := range podOrdinals {
+			if ticdcPodName(tcName, id) == podName {
+				exist = true
+				break
+			}
+		}
+		if !exist {
+			return controller.RequeueErrorf("ticdc scale in: capture of the deleted pod %s/%s is still in the ticdc cluster", ns, podName)
+		}
+	}
+
+	if err := gracefulShutdownTiCDC(s.deps, tc, ordinal, "scale in"); err != nil {
+		return err
+	}
+
+	setReplicasAndDeleteSlots(newSet, replicas, deleteSlots)
+	return nil
+}
+
+func (s *ticdcScaler) SyncAutoScalerAnn(meta metav1.Object, actual *apps.StatefulSet) error {
+	return nil
+}
+
+type fakeTiCDCScaler struct{}
+
+// NewFakeTiCDCScaler returns a fake ticdc Scaler
+func NewFakeTiCDCScaler() Scaler {
+	return &fakeTiCDCScaler{}
+}
+
+func (s *fakeTiCDCScaler) Scale(meta metav1.Object, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+	if *newSet.Spec.Replicas > *oldSet.Spec.Replicas {
+		return s.ScaleOut(meta, oldSet, newSet)
+	} else if *newSet.Spec.Replicas < *oldSet.Spec.Replicas {
+		return s.ScaleIn(meta, oldSet, newSet)
+	}
+	return nil
+}
+
+func (s *fakeTiCDCScaler) ScaleOut(_ metav1.Object, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+	setReplicasAndDeleteSlots(newSet, *oldSet.Spec.Replicas+1, nil)
+	return nil
+}
+
+func (s *fakeTiCDCScaler) ScaleIn(_ metav1.Object, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+	setReplicasAndDeleteSlots(newSet, *oldSet.Spec.Replicas-1, nil)
+	return nil
+}
+
+func (s *fakeTiCDCScaler) SyncAutoScalerAnn(_ metav1.Object, actual *apps.StatefulSet) error {
+	return nil
+}
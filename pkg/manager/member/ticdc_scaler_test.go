@@ -0,0 +1,167 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/pointer"
+)
+
+func TestTiCDCScalerScaleOut(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForTiCDCScale()
+	oldSet := newStatefulSetForTiCDCScale()
+	newSet := oldSet.DeepCopy()
+	newSet.Spec.Replicas = pointer.Int32Ptr(5)
+
+	scaler, _, _ := newFakeTiCDCScaler()
+	err := scaler.Scale(tc, oldSet, newSet)
+	g.Expect(err).NotTo(HaveOccurred())
+	// scale out one by one
+	g.Expect(int(*newSet.Spec.Replicas)).To(Equal(4))
+}
+
+func TestTiCDCScalerScaleIn(t *testing.T) {
+	g := NewGomegaWithT(t)
+	type testcase struct {
+		name        string
+		changeFn    func(*v1alpha1.TidbCluster)
+		changeCDC   func(*controller.FakeTiCDCControl)
+		errExpectFn func(*GomegaWithT, error)
+		changed     bool
+	}
+
+	testFn := func(test testcase, t *testing.T) {
+		t.Log(test.name)
+		tc := newTidbClusterForTiCDCScale()
+		if test.changeFn != nil {
+			test.changeFn(tc)
+		}
+		oldSet := newStatefulSetForTiCDCScale()
+		newSet := oldSet.DeepCopy()
+		newSet.Spec.Replicas = pointer.Int32Ptr(2)
+
+		scaler, cdcControl, podIndexer := newFakeTiCDCScaler()
+		for i := int32(0); i < *oldSet.Spec.Replicas; i++ {
+			podIndexer.Add(&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      ticdcPodName(tc.Name, i),
+					Namespace: corev1.NamespaceDefault,
+				},
+			})
+		}
+		cdcControl.SetCaptures(getTiCDCCaptures(tc))
+		if test.changeCDC != nil {
+			test.changeCDC(cdcControl)
+		}
+
+		err := scaler.Scale(tc, oldSet, newSet)
+		test.errExpectFn(g, err)
+		if test.changed {
+			g.Expect(int(*newSet.Spec.Replicas)).To(Equal(2))
+		} else {
+			g.Expect(int(*newSet.Spec.Replicas)).To(Equal(3))
+		}
+	}
+
+	tests := []testcase{
+		{
+			name:        "normal",
+			errExpectFn: errExpectNil,
+			changed:     true,
+		},
+		{
+			name: "capture is not in the ticdc cluster",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				delete(tc.Status.TiCDC.Captures, ticdcPodName(tc.Name, 2))
+			},
+			errExpectFn: errExpectNil,
+			changed:     true,
+		},
+		{
+			name: "capture is the owner",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				capture := tc.Status.TiCDC.Captures[ticdcPodName(tc.Name, 2)]
+				capture.IsOwner = true
+				tc.Status.TiCDC.Captures[ticdcPodName(tc.Name, 2)] = capture
+			},
+			errExpectFn: errExpectRequeue,
+			changed:     false,
+		},
+		{
+			name: "capture still has tables",
+			changeCDC: func(cdcControl *controller.FakeTiCDCControl) {
+				cdcControl.SetTableCount("capture-2", 10)
+			},
+			errExpectFn: errExpectRequeue,
+			changed:     false,
+		},
+		{
+			name: "capture of a deleted pod is still in the ticdc cluster",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				podName := ticdcPodName(tc.Name, 3)
+				tc.Status.TiCDC.Captures[podName] = v1alpha1.TiCDCCapture{PodName: podName, ID: "capture-3"}
+			},
+			errExpectFn: errExpectRequeue,
+			changed:     false,
+		},
+	}
+
+	for _, test := range tests {
+		testFn(test, t)
+	}
+}
+
+func newFakeTiCDCScaler() (*ticdcScaler, *controller.FakeTiCDCControl, cache.Indexer) {
+	fakeDeps := controller.NewFakeDependencies()
+	scaler := &ticdcScaler{generalScaler: generalScaler{deps: fakeDeps}}
+	cdcControl := fakeDeps.CDCControl.(*controller.FakeTiCDCControl)
+	podIndexer := fakeDeps.KubeInformerFactory.Core().V1().Pods().Informer().GetIndexer()
+	return scaler, cdcControl, podIndexer
+}
+
+func newStatefulSetForTiCDCScale() *apps.StatefulSet {
+	return &apps.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      controller.TiCDCMemberName(upgradeTcName),
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: apps.StatefulSetSpec{
+			Replicas: pointer.Int32Ptr(3),
+		},
+	}
+}
+
+func newTidbClusterForTiCDCScale() *v1alpha1.TidbCluster {
+	tc := newTidbClusterForTiCDCUpgrader()
+	tc.Status.TiCDC.Captures = map[string]v1alpha1.TiCDCCapture{}
+	for i := int32(0); i < 3; i++ {
+		podName := ticdcPodName(tc.Name, i)
+		tc.Status.TiCDC.Captures[podName] = v1alpha1.TiCDCCapture{
+			PodName: podName,
+			ID:      fmt.Sprintf("capture-%d", i),
+		}
+	}
+	return tc
+}
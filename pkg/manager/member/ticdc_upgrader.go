@@ -0,0 +1,195 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog"
+)
+
+const (
+	// TiCDCGracefulShutdownBeginTime is the key of the begin time of gracefully shutting down a ticdc capture
+	TiCDCGracefulShutdownBeginTime = "ticdcGracefulShutdownBeginTime"
+)
+
+type ticdcUpgrader struct {
+	deps *controller.Dependencies
+}
+
+// NewTiCDCUpgrader returns a ticdc Upgrader
+func NewTiCDCUpgrader(deps *controller.Dependencies) Upgrader {
+	return &ticdcUpgrader{
+		deps: deps,
+	}
+}
+
+func (u *ticdcUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	if tc.PDUpgrading() || tc.TiKVUpgrading() || tc.TiCDCScaling() {
+		klog.Infof("TidbCluster: [%s/%s]'s pd status is %s, tikv status is %s, ticdc status is %s, can not upgrade ticdc",
+			ns, tcName, tc.Status.PD.Phase, tc.Status.TiKV.Phase, tc.Status.TiCDC.Phase)
+		_, podSpec, err := GetLastAppliedConfig(oldSet)
+		if err != nil {
+			return err
+		}
+		newSet.Spec.Template.Spec = *podSpec
+		return nil
+	}
+	if wait, err := waitForMaintenanceWindow(tc, oldSet, newSet); err != nil || wait {
+		return err
+	}
+
+	if !tc.Status.TiCDC.Synced {
+		return fmt.Errorf("tidbcluster: [%s/%s]'s ticdc status sync failed, can not to be upgraded", ns, tcName)
+	}
+
+	tc.Status.TiCDC.Phase = v1alpha1.UpgradePhase
+	if !templateEqual(newSet, oldSet) {
+		return nil
+	}
+
+	if tc.Status.TiCDC.StatefulSet.UpdateRevision == tc.Status.TiCDC.StatefulSet.CurrentRevision {
+		return nil
+	}
+
+	if oldSet.Spec.UpdateStrategy.Type == apps.OnDeleteStatefulSetStrategyType || oldSet.Spec.UpdateStrategy.RollingUpdate == nil {
+		// Manually bypass tidb-operator to modify statefulset directly, such as modify ticdc statefulset's RollingUpdate strategy to OnDelete strategy,
+		// or set RollingUpdate to nil, skip tidb-operator's rolling update logic in order to speed up the upgrade in the test environment occasionally.
+		// If we encounter this situation, we will let the native statefulset controller do the upgrade completely, which may be unsafe for upgrading ticdc.
+		// Therefore, in the production environment, we should try to avoid modifying the ticdc statefulset update strategy directly.
+		newSet.Spec.UpdateStrategy = oldSet.Spec.UpdateStrategy
+		klog.Warningf("tidbcluster: [%s/%s] ticdc statefulset %s UpdateStrategy has been modified manually", ns, tcName, oldSet.GetName())
+		return nil
+	}
+
+	setUpgradePartition(newSet, *oldSet.Spec.UpdateStrategy.RollingUpdate.Partition)
+	podOrdinals := helper.GetPodOrdinals(*oldSet.Spec.Replicas, oldSet).List()
+	for _i := len(podOrdinals) - 1; _i >= 0; _i-- {
+		i := podOrdinals[_i]
+		podName := ticdcPodName(tcName, i)
+		pod, err := u.deps.PodLister.Pods(ns).Get(podName)
+		if err != nil {
+			return fmt.Errorf("ticdcUpgrader.Upgrade: failed to get pod %s for cluster %s/%s, error: %s", podName, ns, tcName, err)
+		}
+		revision, exist := pod.Labels[apps.ControllerRevisionHashLabelKey]
+		if !exist {
+			return controller.RequeueErrorf("tidbcluster: [%s/%s]'s ticdc pod: [%s] has no label: %s", ns, tcName, podName, apps.ControllerRevisionHashLabelKey)
+		}
+
+		if revision == tc.Status.TiCDC.StatefulSet.UpdateRevision {
+			if pod.Status.Phase != corev1.PodRunning {
+				return controller.RequeueErrorf("tidbcluster: [%s/%s]'s upgraded ticdc pod: [%s] is not running", ns, tcName, podName)
+			}
+			if _, exist := tc.Status.TiCDC.Captures[podName]; !exist {
+				return controller.RequeueErrorf("tidbcluster: [%s/%s]'s upgraded ticdc pod: [%s] has not joined the ticdc cluster", ns, tcName, podName)
+			}
+			continue
+		}
+
+		return u.upgradeTiCDCPod(tc, i, newSet)
+	}
+
+	return nil
+}
+
+func (u *ticdcUpgrader) upgradeTiCDCPod(tc *v1alpha1.TidbCluster, ordinal int32, newSet *apps.StatefulSet) error {
+	if err := gracefulShutdownTiCDC(u.deps, tc, ordinal, "upgrade"); err != nil {
+		return err
+	}
+	setUpgradePartition(newSet, ordinal)
+	return nil
+}
+
+// gracefulShutdownTiCDC resigns the ownership and drains the tables of the capture
+// in the given ordinal, it returns nil when the pod is safe to be deleted.
+// The pod is deleted anyway if it can not be shut down gracefully within the timeout.
+func gracefulShutdownTiCDC(deps *controller.Dependencies, tc *v1alpha1.TidbCluster, ordinal int32, action string) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	podName := ticdcPodName(tcName, ordinal)
+
+	if _, exist := tc.Status.TiCDC.Captures[podName]; !exist {
+		klog.Infof("ticdc %s: capture of %s/%s is not in the ticdc cluster, skip graceful shutdown", action, ns, podName)
+		return nil
+	}
+
+	pod, err := deps.PodLister.Pods(ns).Get(podName)
+	if err != nil {
+		return fmt.Errorf("gracefulShutdownTiCDC: failed to get pod %s for cluster %s/%s, error: %s", podName, ns, tcName, err)
+	}
+
+	beginTimeStr, exist := pod.Annotations[TiCDCGracefulShutdownBeginTime]
+	if !exist {
+		pod = pod.DeepCopy()
+		if pod.Annotations == nil {
+			pod.Annotations = map[string]string{}
+		}
+		beginTimeStr = time.Now().Format(time.RFC3339)
+		pod.Annotations[TiCDCGracefulShutdownBeginTime] = beginTimeStr
+		if _, err := deps.PodControl.UpdatePod(tc, pod); err != nil {
+			klog.Errorf("ticdc %s: failed to set pod %s/%s annotation %s to %s, %v",
+				action, ns, podName, TiCDCGracefulShutdownBeginTime, beginTimeStr, err)
+			return err
+		}
+		klog.Infof("ticdc %s: begin graceful shutdown of capture %s/%s", action, ns, podName)
+	}
+
+	beginTime, err := time.Parse(time.RFC3339, beginTimeStr)
+	if err != nil {
+		klog.Errorf("parse annotation:[%s] of pod %s/%s to time failed.", TiCDCGracefulShutdownBeginTime, ns, podName)
+	} else if time.Now().After(beginTime.Add(tc.TiCDCGracefulShutdownTimeout())) {
+		klog.Warningf("ticdc %s: graceful shutdown of capture %s/%s timed out, delete the pod anyway", action, ns, podName)
+		return nil
+	}
+
+	resigned, err := deps.CDCControl.ResignOwner(tc, ordinal)
+	if err != nil {
+		return fmt.Errorf("ticdc %s: failed to resign the ownership of capture %s/%s, error: %v", action, ns, podName, err)
+	}
+	if resigned {
+		return controller.RequeueErrorf("ticdc %s: capture %s/%s has resigned the ownership, wait for a new owner", action, ns, podName)
+	}
+
+	tableCount, err := deps.CDCControl.DrainCapture(tc, ordinal)
+	if err != nil {
+		return fmt.Errorf("ticdc %s: failed to drain capture %s/%s, error: %v", action, ns, podName, err)
+	}
+	if tableCount > 0 {
+		return controller.RequeueErrorf("ticdc %s: capture %s/%s still has %d tables, wait for draining", action, ns, podName, tableCount)
+	}
+
+	klog.Infof("ticdc %s: capture %s/%s has been drained", action, ns, podName)
+	return nil
+}
+
+type fakeTiCDCUpgrader struct{}
+
+// NewFakeTiCDCUpgrader returns a fake ticdc upgrader
+func NewFakeTiCDCUpgrader() Upgrader {
+	return &fakeTiCDCUpgrader{}
+}
+
+func (u *fakeTiCDCUpgrader) Upgrade(tc *v1alpha1.TidbCluster, _ *apps.StatefulSet, _ *apps.StatefulSet) error {
+	tc.Status.TiCDC.Phase = v1alpha1.UpgradePhase
+	return nil
+}
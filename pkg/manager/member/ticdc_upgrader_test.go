@@ -0,0 +1,308 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	podinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestTiCDCUpgrader_Upgrade(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name         string
+		changeFn     func(*v1alpha1.TidbCluster)
+		changePods   func(pods []*corev1.Pod)
+		changeCDC    func(*controller.FakeTiCDCControl)
+		changeNewSet func(*apps.StatefulSet)
+		errorExpect  bool
+		expectFn     func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet, podInformer podinformers.PodInformer)
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+		upgrader, cdcControl, podInformer := newTiCDCUpgrader()
+		tc := newTidbClusterForTiCDCUpgrader()
+		if test.changeFn != nil {
+			test.changeFn(tc)
+		}
+		pods := getTiCDCPods()
+		if test.changePods != nil {
+			test.changePods(pods)
+		}
+		for _, pod := range pods {
+			podInformer.Informer().GetIndexer().Add(pod)
+		}
+		cdcControl.SetCaptures(getTiCDCCaptures(tc))
+		if test.changeCDC != nil {
+			test.changeCDC(cdcControl)
+		}
+
+		oldSet := newStatefulSetForTiCDCUpgrader()
+		newSet := oldSet.DeepCopy()
+		SetStatefulSetLastAppliedConfigAnnotation(oldSet)
+		if test.changeNewSet != nil {
+			test.changeNewSet(newSet)
+		}
+
+		err := upgrader.Upgrade(tc, oldSet, newSet)
+		if test.errorExpect {
+			g.Expect(err).To(HaveOccurred())
+		} else {
+			g.Expect(err).NotTo(HaveOccurred())
+		}
+		test.expectFn(g, tc, newSet, podInformer)
+	}
+
+	tests := []*testcase{
+		{
+			name: "normal",
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet, _ podinformers.PodInformer) {
+				g.Expect(tc.Status.TiCDC.Phase).To(Equal(v1alpha1.UpgradePhase))
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(0)))
+			},
+		},
+		{
+			name: "pd is upgrading",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.PD.Phase = v1alpha1.UpgradePhase
+			},
+			changeNewSet: func(set *apps.StatefulSet) {
+				set.Spec.Template.Spec.Containers[0].Image = "ticdc-test-image:new"
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet, _ podinformers.PodInformer) {
+				g.Expect(tc.Status.TiCDC.Phase).To(Equal(v1alpha1.NormalPhase))
+				g.Expect(newSet.Spec.Template.Spec.Containers[0].Image).To(Equal("ticdc-test-image"))
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(1)))
+			},
+		},
+		{
+			name: "ticdc status is not synced",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiCDC.Synced = false
+			},
+			errorExpect: true,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet, _ podinformers.PodInformer) {
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(1)))
+			},
+		},
+		{
+			name: "upgraded pod has not joined the ticdc cluster",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				delete(tc.Status.TiCDC.Captures, ticdcPodName(upgradeTcName, 1))
+			},
+			errorExpect: true,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet, _ podinformers.PodInformer) {
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(1)))
+			},
+		},
+		{
+			name: "resign the owner before upgrading",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				capture := tc.Status.TiCDC.Captures[ticdcPodName(upgradeTcName, 0)]
+				capture.IsOwner = true
+				tc.Status.TiCDC.Captures[ticdcPodName(upgradeTcName, 0)] = capture
+			},
+			errorExpect: true,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet, podInformer podinformers.PodInformer) {
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(1)))
+				pod, err := podInformer.Lister().Pods(corev1.NamespaceDefault).Get(ticdcPodName(upgradeTcName, 0))
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(pod.Annotations).To(HaveKey(TiCDCGracefulShutdownBeginTime))
+			},
+		},
+		{
+			name: "wait for draining the tables",
+			changeCDC: func(cdcControl *controller.FakeTiCDCControl) {
+				cdcControl.SetTableCount("capture-0", 3)
+			},
+			errorExpect: true,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet, _ podinformers.PodInformer) {
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(1)))
+			},
+		},
+		{
+			name: "graceful shutdown timed out",
+			changePods: func(pods []*corev1.Pod) {
+				pods[0].Annotations = map[string]string{
+					TiCDCGracefulShutdownBeginTime: time.Now().Add(-time.Hour).Format(time.RFC3339),
+				}
+			},
+			changeCDC: func(cdcControl *controller.FakeTiCDCControl) {
+				cdcControl.SetTableCount("capture-0", 3)
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet, _ podinformers.PodInformer) {
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(0)))
+			},
+		},
+		{
+			name: "failed to drain the capture",
+			changeCDC: func(cdcControl *controller.FakeTiCDCControl) {
+				cdcControl.SetError(fmt.Errorf("ticdc is unavailable"))
+			},
+			errorExpect: true,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet, _ podinformers.PodInformer) {
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(1)))
+			},
+		},
+	}
+
+	for i := range tests {
+		testFn(tests[i], t)
+	}
+}
+
+func newTiCDCUpgrader() (Upgrader, *controller.FakeTiCDCControl, podinformers.PodInformer) {
+	fakeDeps := controller.NewFakeDependencies()
+	upgrader := &ticdcUpgrader{fakeDeps}
+	cdcControl := fakeDeps.CDCControl.(*controller.FakeTiCDCControl)
+	podInformer := fakeDeps.KubeInformerFactory.Core().V1().Pods()
+	return upgrader, cdcControl, podInformer
+}
+
+func newStatefulSetForTiCDCUpgrader() *apps.StatefulSet {
+	return &apps.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      controller.TiCDCMemberName(upgradeTcName),
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: apps.StatefulSetSpec{
+			Replicas: pointer.Int32Ptr(2),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:  "ticdc",
+							Image: "ticdc-test-image",
+						},
+					},
+				},
+			},
+			UpdateStrategy: apps.StatefulSetUpdateStrategy{Type: apps.RollingUpdateStatefulSetStrategyType,
+				RollingUpdate: &apps.RollingUpdateStatefulSetStrategy{
+					Partition: pointer.Int32Ptr(1),
+				},
+			},
+		},
+		Status: apps.StatefulSetStatus{
+			CurrentRevision: "1",
+			UpdateRevision:  "2",
+			ReadyReplicas:   2,
+			Replicas:        2,
+			CurrentReplicas: 1,
+			UpdatedReplicas: 1,
+		},
+	}
+}
+
+func newTidbClusterForTiCDCUpgrader() *v1alpha1.TidbCluster {
+	return &v1alpha1.TidbCluster{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "TidbCluster",
+			APIVersion: "pingcap.com/v1alpha1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      upgradeTcName,
+			Namespace: corev1.NamespaceDefault,
+			UID:       types.UID(upgradeTcName),
+		},
+		Spec: v1alpha1.TidbClusterSpec{
+			TiCDC: &v1alpha1.TiCDCSpec{
+				ComponentSpec: v1alpha1.ComponentSpec{
+					Image: "ticdc-test-image",
+				},
+				Replicas: 2,
+			},
+		},
+		Status: v1alpha1.TidbClusterStatus{
+			PD:   v1alpha1.PDStatus{Phase: v1alpha1.NormalPhase},
+			TiKV: v1alpha1.TiKVStatus{Phase: v1alpha1.NormalPhase},
+			TiCDC: v1alpha1.TiCDCStatus{
+				Synced: true,
+				Phase:  v1alpha1.NormalPhase,
+				StatefulSet: &apps.StatefulSetStatus{
+					CurrentReplicas: 1,
+					UpdatedReplicas: 1,
+					CurrentRevision: "1",
+					UpdateRevision:  "2",
+					Replicas:        2,
+				},
+				Captures: map[string]v1alpha1.TiCDCCapture{
+					ticdcPodName(upgradeTcName, 0): {
+						PodName: ticdcPodName(upgradeTcName, 0),
+						ID:      "capture-0",
+					},
+					ticdcPodName(upgradeTcName, 1): {
+						PodName: ticdcPodName(upgradeTcName, 1),
+						ID:      "capture-1",
+					},
+				},
+			},
+		},
+	}
+}
+
+func getTiCDCPods() []*corev1.Pod {
+	lc := label.New().Instance(upgradeInstanceName).TiCDC().Labels()
+	lc[apps.ControllerRevisionHashLabelKey] = "1"
+	lu := label.New().Instance(upgradeInstanceName).TiCDC().Labels()
+	lu[apps.ControllerRevisionHashLabelKey] = "2"
+	pods := []*corev1.Pod{
+		{
+			TypeMeta: metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ticdcPodName(upgradeTcName, 0),
+				Namespace: corev1.NamespaceDefault,
+				Labels:    lc,
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+		{
+			TypeMeta: metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ticdcPodName(upgradeTcName, 1),
+				Namespace: corev1.NamespaceDefault,
+				Labels:    lu,
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+	}
+	return pods
+}
+
+// getTiCDCCaptures returns the captures reported by ticdc according to the status
+func getTiCDCCaptures(tc *v1alpha1.TidbCluster) []*controller.CaptureInfo {
+	captures := []*controller.CaptureInfo{}
+	for _, capture := range tc.Status.TiCDC.Captures {
+		captures = append(captures, &controller.CaptureInfo{
+			ID:            capture.ID,
+			IsOwner:       capture.IsOwner,
+			AdvertiseAddr: fmt.Sprintf("%s.%s.%s.svc:8301", capture.PodName, controller.TiCDCPeerMemberName(tc.Name), tc.Namespace),
+		})
+	}
+	return captures
+}
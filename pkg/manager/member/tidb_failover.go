@@ -19,6 +19,7 @@ import (
 
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/metrics"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog"
@@ -80,6 +81,7 @@ func (f *tidbFailover) Failover(tc *v1alpha1.TidbCluster) error {
 			}
 			msg := fmt.Sprintf("tidb[%s] is unhealthy", tidbMember.Name)
 			f.deps.Recorder.Event(tc, corev1.EventTypeWarning, unHealthEventReason, fmt.Sprintf(unHealthEventMsgPattern, "tidb", tidbMember.Name, msg))
+			metrics.ObserveFailover(tc.GetNamespace(), tc.GetName(), "tidb")
 			break
 		}
 	}
@@ -26,10 +26,13 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/label"
 	"github.com/pingcap/tidb-operator/pkg/manager"
 	"github.com/pingcap/tidb-operator/pkg/util"
+	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	corelisters "k8s.io/client-go/listers/core/v1"
@@ -70,8 +73,8 @@ func NewTiDBMemberManager(deps *controller.Dependencies, tidbUpgrader Upgrader,
 }
 
 func (m *tidbMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
-	// If tikv is not specified return
-	if tc.Spec.TiDB == nil {
+	// If tidb is not specified return
+	if tc.Spec.TiDB == nil && len(tc.Spec.TiDBGroups) == 0 {
 		return nil
 	}
 
@@ -91,6 +94,17 @@ func (m *tidbMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
 		return err
 	}
 
+	if tc.Spec.TiDB != nil {
+		if err := m.syncTiDB(tc); err != nil {
+			return err
+		}
+	}
+	return m.syncTiDBGroups(tc)
+}
+
+// syncTiDB syncs the service and the statefulset of the default TiDB or of
+// a TiDB group view returned by TidbCluster.TiDBGroupCluster
+func (m *tidbMemberManager) syncTiDB(tc *v1alpha1.TidbCluster) error {
 	// Sync TiDB Service before syncing TiDB StatefulSet
 	if err := m.syncTiDBService(tc); err != nil {
 		return err
@@ -106,6 +120,32 @@ func (m *tidbMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
 	return m.syncTiDBStatefulSetForTidbCluster(tc)
 }
 
+// syncTiDBGroups syncs the TiDB groups one by one, all the groups share the
+// peer service with the default TiDB
+func (m *tidbMemberManager) syncTiDBGroups(tc *v1alpha1.TidbCluster) error {
+	// drop the status of the groups removed from spec
+	groups := map[string]v1alpha1.TiDBStatus{}
+	for _, group := range tc.Spec.TiDBGroups {
+		if status, ok := tc.Status.TiDB.Groups[group.Name]; ok {
+			groups[group.Name] = status
+		}
+	}
+	tc.Status.TiDB.Groups = groups
+
+	for i := range tc.Spec.TiDBGroups {
+		group := &tc.Spec.TiDBGroups[i]
+		gtc := tc.TiDBGroupCluster(group)
+		err := m.syncTiDB(gtc)
+		groups[group.Name] = gtc.Status.TiDB
+		// the upgrade of the group may wait for the maintenance window
+		utiltidbcluster.MergeConditions(&tc.Status, gtc.Status.Conditions)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (m *tidbMemberManager) checkTLSClientCert(tc *v1alpha1.TidbCluster) error {
 	ns := tc.Namespace
 	secretName := tlsClientSecretName(tc)
@@ -173,9 +213,9 @@ func (m *tidbMemberManager) syncTiDBStatefulSetForTidbCluster(tc *v1alpha1.TidbC
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
 
-	oldTiDBSetTemp, err := m.deps.StatefulSetLister.StatefulSets(ns).Get(controller.TiDBMemberName(tcName))
+	oldTiDBSetTemp, err := m.deps.StatefulSetLister.StatefulSets(ns).Get(tidbMemberName(tc))
 	if err != nil && !errors.IsNotFound(err) {
-		return fmt.Errorf("syncTiDBStatefulSetForTidbCluster: failed to get sts %s for cluster %s/%s, error: %s", controller.TiDBMemberName(tcName), ns, tcName, err)
+		return fmt.Errorf("syncTiDBStatefulSetForTidbCluster: failed to get sts %s for cluster %s/%s, error: %s", tidbMemberName(tc), ns, tcName, err)
 	}
 	setNotExist := errors.IsNotFound(err)
 
@@ -189,6 +229,10 @@ func (m *tidbMemberManager) syncTiDBStatefulSetForTidbCluster(tc *v1alpha1.TidbC
 		return nil
 	}
 
+	if err := m.restoreDrainedTiDBPods(tc); err != nil {
+		return err
+	}
+
 	cm, err := m.syncTiDBConfigMap(tc, oldTiDBSet)
 	if err != nil {
 		return err
@@ -218,6 +262,15 @@ func (m *tidbMemberManager) syncTiDBStatefulSetForTidbCluster(tc *v1alpha1.TidbC
 		}
 	}
 
+	if scaling, ordinal, replicas, deleteSlots := scaleOne(oldTiDBSet, newTiDBSet); scaling < 0 && tc.TiDBDrainEnabled() {
+		// scale in one by one to drain the connections of the pods
+		resetReplicas(newTiDBSet, oldTiDBSet)
+		if err := drainTiDBConnections(m.deps, tc, ordinal, "scale in"); err != nil {
+			return err
+		}
+		setReplicasAndDeleteSlots(newTiDBSet, replicas, deleteSlots)
+	}
+
 	if !templateEqual(newTiDBSet, oldTiDBSet) || tc.Status.TiDB.Phase == v1alpha1.UpgradePhase {
 		if err := m.tidbUpgrader.Upgrade(tc, oldTiDBSet, newTiDBSet); err != nil {
 			return err
@@ -236,7 +289,7 @@ func (m *tidbMemberManager) shouldRecover(tc *v1alpha1.TidbCluster) bool {
 	// Note that failover pods may fail (e.g. lack of resources) and we don't care
 	// about them because we're going to delete them.
 	for ordinal := range tc.TiDBStsDesiredOrdinals(true) {
-		name := fmt.Sprintf("%s-%d", controller.TiDBMemberName(tc.GetName()), ordinal)
+		name := fmt.Sprintf("%s-%d", tidbMemberName(tc), ordinal)
 		pod, err := m.deps.PodLister.Pods(tc.Namespace).Get(name)
 		if err != nil {
 			klog.Errorf("pod %s/%s does not exist: %v", tc.Namespace, name, err)
@@ -253,11 +306,70 @@ func (m *tidbMemberManager) shouldRecover(tc *v1alpha1.TidbCluster) bool {
 	return true
 }
 
+// restoreDrainedTiDBPods adds the drained pods back to the tidb service if
+// the upgrading or scaling in has been reverted before they are deleted
+func (m *tidbMemberManager) restoreDrainedTiDBPods(tc *v1alpha1.TidbCluster) error {
+	if tc.Status.TiDB.Phase != v1alpha1.NormalPhase {
+		return nil
+	}
+
+	ns := tc.GetNamespace()
+	desiredOrdinals := tc.TiDBStsDesiredOrdinals(false)
+	for ordinal := range desiredOrdinals {
+		podName := tidbGroupPodName(tc, ordinal)
+		pod, err := m.deps.PodLister.Pods(ns).Get(podName)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("restoreDrainedTiDBPods: failed to get pod %s for cluster %s/%s, error: %s", podName, ns, tc.GetName(), err)
+		}
+		if _, exist := pod.Annotations[TiDBDrainBeginTime]; !exist || pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		pod = pod.DeepCopy()
+		delete(pod.Annotations, TiDBDrainBeginTime)
+		if tc.TiDBDrainEnabled() {
+			pod.Labels[label.TiDBServingLabelKey] = "true"
+		} else {
+			delete(pod.Labels, label.TiDBServingLabelKey)
+		}
+		if _, err := m.deps.PodControl.UpdatePod(tc, pod); err != nil {
+			return err
+		}
+		klog.Infof("tidb pod %s/%s is added back to the tidb service", ns, podName)
+	}
+	return nil
+}
+
+// tidbPodsLabeled returns whether all the tidb pods have the given label
+func (m *tidbMemberManager) tidbPodsLabeled(tc *v1alpha1.TidbCluster, key string) (bool, error) {
+	selector, err := tidbPodSelector(tc)
+	if err != nil {
+		return false, err
+	}
+	pods, err := m.deps.PodLister.Pods(tc.GetNamespace()).List(selector)
+	if err != nil {
+		return false, fmt.Errorf("tidbPodsLabeled: failed to list pods for cluster %s/%s, selector %s, error: %s", tc.GetNamespace(), tc.GetName(), selector, err)
+	}
+	for _, pod := range pods {
+		if _, exist := pod.Labels[key]; !exist {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
 func (m *tidbMemberManager) syncTiDBService(tc *v1alpha1.TidbCluster) error {
 	if tc.Spec.Paused {
 		klog.V(4).Infof("tidb cluster %s/%s is paused, skip syncing for tidb service", tc.GetNamespace(), tc.GetName())
 		return nil
 	}
+	if tc.Bootstrapping() {
+		klog.V(4).Infof("tidb cluster %s/%s is being bootstrapped from backup, skip syncing for tidb service", tc.GetNamespace(), tc.GetName())
+		return nil
+	}
 
 	newSvc := getNewTiDBServiceOrNil(tc)
 	// TODO: delete tidb service if user remove the service spec deliberately
@@ -265,6 +377,31 @@ func (m *tidbMemberManager) syncTiDBService(tc *v1alpha1.TidbCluster) error {
 		return nil
 	}
 
+	if tc.TiDBDrainEnabled() {
+		// The pods created before the drain is enabled have no serving label,
+		// select the serving pods only after all the pods are labeled.
+		labeled, err := m.tidbPodsLabeled(tc, label.TiDBServingLabelKey)
+		if err != nil {
+			return err
+		}
+		if labeled {
+			newSvc.Spec.Selector[label.TiDBServingLabelKey] = "true"
+		}
+	}
+
+	if isDefaultTiDBOfGroups(tc) {
+		// The pods of the default TiDB created before the groups are added have
+		// no default group label, exclude the pods of the groups only after all
+		// the pods are labeled.
+		labeled, err := m.tidbPodsLabeled(tc, label.TiDBDefaultGroupLabelKey)
+		if err != nil {
+			return err
+		}
+		if labeled {
+			newSvc.Spec.Selector[label.TiDBDefaultGroupLabelKey] = "true"
+		}
+	}
+
 	ns := newSvc.Namespace
 
 	oldSvcTmp, err := m.deps.ServiceLister.Services(ns).Get(newSvc.Name)
@@ -327,12 +464,21 @@ func (m *tidbMemberManager) syncTiDBConfigMap(tc *v1alpha1.TidbCluster, set *app
 	var inUseName string
 	if set != nil {
 		inUseName = FindConfigMapVolume(&set.Spec.Template.Spec, func(name string) bool {
-			return strings.HasPrefix(name, controller.TiDBMemberName(tc.Name))
+			return strings.HasPrefix(name, tidbMemberName(tc))
 		})
 	}
 
 	klog.V(3).Info("get tidb in use config map name: ", inUseName)
 
+	if tc.BaseTiDBSpec().ConfigUpdateStrategy() == v1alpha1.ConfigUpdateStrategyInPlace {
+		tc.Status.TiDB.Config, err = applyConfigInPlace(m.deps.ConfigMapLister, set, inUseName, newCm, tc.Status.TiDB.Config, tidbOnlineConfig, func(items map[string]interface{}) error {
+			return m.updateTiDBSettings(tc, items)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	err = updateConfigMapIfNeed(m.deps.ConfigMapLister, tc.BaseTiDBSpec().ConfigUpdateStrategy(), inUseName, newCm)
 	if err != nil {
 		return nil, err
@@ -340,6 +486,37 @@ func (m *tidbMemberManager) syncTiDBConfigMap(tc *v1alpha1.TidbCluster, set *app
 	return m.deps.TypedControl.CreateOrUpdateConfigMap(tc, newCm)
 }
 
+// updateTiDBSettings updates the config items of the healthy members online,
+// the other members load the config from the ConfigMap when they restart
+func (m *tidbMemberManager) updateTiDBSettings(tc *v1alpha1.TidbCluster, items map[string]interface{}) error {
+	settings := map[string]string{}
+	for k, v := range items {
+		switch value := v.(type) {
+		case bool:
+			if value {
+				settings[tidbOnlineSettings[k]] = "1"
+			} else {
+				settings[tidbOnlineSettings[k]] = "0"
+			}
+		default:
+			settings[tidbOnlineSettings[k]] = fmt.Sprintf("%v", value)
+		}
+	}
+	for _, member := range tc.Status.TiDB.Members {
+		if !member.Health {
+			continue
+		}
+		ordinal, err := util.GetOrdinalFromPodName(member.Name)
+		if err != nil {
+			return err
+		}
+		if err := m.deps.TiDBControl.UpdateSettings(tc, ordinal, settings); err != nil {
+			return fmt.Errorf("update settings of tidb %s failed: %v", member.Name, err)
+		}
+	}
+	return nil
+}
+
 func getTiDBConfigMap(tc *v1alpha1.TidbCluster) (*corev1.ConfigMap, error) {
 	config := tc.Spec.TiDB.Config
 	if config == nil {
@@ -384,9 +561,8 @@ func getTiDBConfigMap(tc *v1alpha1.TidbCluster) (*corev1.ConfigMap, error) {
 		"config-file":    string(confText),
 		"startup-script": startScript,
 	}
-	name := controller.TiDBMemberName(tc.Name)
-	instanceName := tc.GetInstanceName()
-	tidbLabels := label.New().Instance(instanceName).TiDB().Labels()
+	name := tidbMemberName(tc)
+	tidbLabels := labelTiDB(tc).Labels()
 
 	cm := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -409,10 +585,8 @@ func getNewTiDBServiceOrNil(tc *v1alpha1.TidbCluster) *corev1.Service {
 	}
 
 	ns := tc.Namespace
-	tcName := tc.Name
-	instanceName := tc.GetInstanceName()
-	tidbSelector := label.New().Instance(instanceName).TiDB()
-	svcName := controller.TiDBMemberName(tcName)
+	tidbSelector := labelTiDB(tc)
+	svcName := tidbMemberName(tc)
 	tidbLabels := tidbSelector.Copy().UsedByEndUser().Labels()
 	portName := "mysql-client"
 	if svcSpec.PortName != nil {
@@ -505,7 +679,6 @@ func getNewTiDBSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 	tcName := tc.GetName()
 	headlessSvcName := controller.TiDBPeerMemberName(tcName)
 	baseTiDBSpec := tc.BaseTiDBSpec()
-	instanceName := tc.GetInstanceName()
 	tidbConfigMap := controller.MemberConfigMapName(tc, v1alpha1.TiDBMemberType)
 	if cm != nil {
 		tidbConfigMap = cm.Name
@@ -725,8 +898,17 @@ func getNewTiDBSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 		podSpec.DNSPolicy = corev1.DNSClusterFirstWithHostNet
 	}
 
-	tidbLabel := label.New().Instance(instanceName).TiDB()
+	tidbLabel := labelTiDB(tc)
+	podLabels := tidbLabel.Copy()
+	if tc.TiDBDrainEnabled() {
+		podLabels[label.TiDBServingLabelKey] = "true"
+	}
+	if isDefaultTiDBOfGroups(tc) {
+		podLabels[label.TiDBDefaultGroupLabelKey] = "true"
+	}
 	podAnnotations := CombineAnnotations(controller.AnnProm(10080), baseTiDBSpec.Annotations())
+	setRestartedAtAnnotations(tc, v1alpha1.TiDBMemberType, podAnnotations)
+	setConfigRestartAnnotation(podAnnotations, tc.Status.TiDB.Config)
 	stsAnnotations := getStsAnnotations(tc.Annotations, label.TiDBLabelVal)
 
 	updateStrategy := apps.StatefulSetUpdateStrategy{}
@@ -741,7 +923,7 @@ func getNewTiDBSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 
 	tidbSet := &apps.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:            controller.TiDBMemberName(tcName),
+			Name:            tidbMemberName(tc),
 			Namespace:       ns,
 			Labels:          tidbLabel.Labels(),
 			Annotations:     stsAnnotations,
@@ -752,7 +934,7 @@ func getNewTiDBSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 			Selector: tidbLabel.LabelSelector(),
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels:      tidbLabel.Labels(),
+					Labels:      podLabels.Labels(),
 					Annotations: podAnnotations,
 				},
 				Spec: podSpec,
@@ -774,6 +956,7 @@ func (m *tidbMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, set
 	}
 
 	tc.Status.TiDB.StatefulSet = &set.Status
+	syncConfigRestartPending(tc.Status.TiDB.Config, set)
 
 	upgrading, err := m.tidbStatefulSetIsUpgradingFn(m.deps.PodLister, set, tc)
 	if err != nil {
@@ -781,7 +964,7 @@ func (m *tidbMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, set
 	}
 	if tc.TiDBStsDesiredReplicas() != *set.Spec.Replicas {
 		tc.Status.TiDB.Phase = v1alpha1.ScalePhase
-	} else if upgrading && tc.Status.TiKV.Phase != v1alpha1.UpgradePhase &&
+	} else if upgrading && !tc.TiKVUpgrading() &&
 		tc.Status.PD.Phase != v1alpha1.UpgradePhase && tc.Status.Pump.Phase != v1alpha1.UpgradePhase {
 		tc.Status.TiDB.Phase = v1alpha1.UpgradePhase
 	} else {
@@ -790,7 +973,7 @@ func (m *tidbMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, set
 
 	tidbStatus := map[string]v1alpha1.TiDBMember{}
 	for id := range helper.GetPodOrdinals(tc.Status.TiDB.StatefulSet.Replicas, set) {
-		name := fmt.Sprintf("%s-%d", controller.TiDBMemberName(tc.GetName()), id)
+		name := fmt.Sprintf("%s-%d", tidbMemberName(tc), id)
 		health, err := m.deps.TiDBControl.GetHealth(tc, int32(id))
 		if err != nil {
 			return err
@@ -827,14 +1010,44 @@ func (m *tidbMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, set
 	return nil
 }
 
+// tidbDefaultGroupRequirement selects the TiDB pods not of any group
+var tidbDefaultGroupRequirement = util.MustNewRequirement(label.TiDBGroupLabelKey, selection.DoesNotExist, nil)
+
+// tidbPodSelector returns the selector of the TiDB pods of the group tc is
+// synced for. The selector of the default TiDB StatefulSet matches the pods of
+// the groups too and is immutable, so the pods of the groups are excluded here.
+func tidbPodSelector(tc *v1alpha1.TidbCluster) (labels.Selector, error) {
+	selector, err := labelTiDB(tc).Selector()
+	if err != nil {
+		return nil, err
+	}
+	if tc.TiDBGroupName() == "" {
+		selector = selector.Add(*tidbDefaultGroupRequirement)
+	}
+	return selector, nil
+}
+
+// isDefaultTiDBOfGroups returns whether tc is synced for the default TiDB of a
+// TidbCluster with TiDB groups, whose pods are labeled to be told apart from
+// the pods of the groups by the Service
+func isDefaultTiDBOfGroups(tc *v1alpha1.TidbCluster) bool {
+	return tc.TiDBGroupName() == "" && len(tc.Spec.TiDBGroups) > 0
+}
+
+func labelTiDB(tc *v1alpha1.TidbCluster) label.Label {
+	instanceName := tc.GetInstanceName()
+	l := label.New().Instance(instanceName).TiDB()
+	if group := tc.TiDBGroupName(); group != "" {
+		l = l.TiDBGroup(group)
+	}
+	return l
+}
+
 func tidbStatefulSetIsUpgrading(podLister corelisters.PodLister, set *apps.StatefulSet, tc *v1alpha1.TidbCluster) (bool, error) {
 	if statefulSetIsUpgrading(set) {
 		return true, nil
 	}
-	selector, err := label.New().
-		Instance(tc.GetInstanceName()).
-		TiDB().
-		Selector()
+	selector, err := tidbPodSelector(tc)
 	if err != nil {
 		return false, err
 	}
@@ -33,6 +33,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	corelisters "k8s.io/client-go/listers/core/v1"
@@ -785,6 +786,52 @@ func TestTiDBMemberManagerSyncTidbService(t *testing.T) {
 				g.Expect(svc.Spec.HealthCheckNodePort).To(Equal(int32(8888)))
 			},
 		},
+		{
+			name: "Select serving pods when drain is enabled",
+			prepare: func(tc *v1alpha1.TidbCluster, indexers *fakeIndexers) {
+				tc.Spec.TiDB.Service = &v1alpha1.TiDBServiceSpec{
+					ServiceSpec: v1alpha1.ServiceSpec{
+						Type: corev1.ServiceTypeClusterIP,
+					},
+				}
+				tc.Spec.TiDB.Drain = &v1alpha1.TiDBDrainSpec{Enabled: true}
+				podLabels := label.New().Instance(tc.GetInstanceName()).TiDB().Labels()
+				podLabels[label.TiDBServingLabelKey] = "true"
+				_ = indexers.pod.Add(&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      tidbPodName(tc.Name, 0),
+						Namespace: corev1.NamespaceDefault,
+						Labels:    podLabels,
+					},
+				})
+			},
+			expectFn: func(g *GomegaWithT, err error, svc *corev1.Service) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(svc.Spec.Selector).To(HaveKeyWithValue(label.TiDBServingLabelKey, "true"))
+			},
+		},
+		{
+			name: "Do not select serving pods until all pods are labeled",
+			prepare: func(tc *v1alpha1.TidbCluster, indexers *fakeIndexers) {
+				tc.Spec.TiDB.Service = &v1alpha1.TiDBServiceSpec{
+					ServiceSpec: v1alpha1.ServiceSpec{
+						Type: corev1.ServiceTypeClusterIP,
+					},
+				}
+				tc.Spec.TiDB.Drain = &v1alpha1.TiDBDrainSpec{Enabled: true}
+				_ = indexers.pod.Add(&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      tidbPodName(tc.Name, 0),
+						Namespace: corev1.NamespaceDefault,
+						Labels:    label.New().Instance(tc.GetInstanceName()).TiDB().Labels(),
+					},
+				})
+			},
+			expectFn: func(g *GomegaWithT, err error, svc *corev1.Service) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(svc.Spec.Selector).NotTo(HaveKey(label.TiDBServingLabelKey))
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -804,6 +851,87 @@ type fakeIndexers struct {
 	ti     cache.Indexer
 }
 
+func TestTiDBMemberManagerSyncTiDBGroups(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForTiDB()
+	tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+		"tikv-0": {PodName: "tikv-0", State: v1alpha1.TiKVStateUp},
+	}
+	tc.Status.TiKV.StatefulSet = &apps.StatefulSetStatus{ReadyReplicas: 1}
+	tc.Spec.TiDBGroups = []v1alpha1.TiDBGroupSpec{
+		{Name: "olap", TiDBSpec: *tc.Spec.TiDB.DeepCopy()},
+	}
+	tc.Spec.TiDBGroups[0].Replicas = 2
+	tc.Spec.TiDBGroups[0].Service = &v1alpha1.TiDBServiceSpec{
+		ServiceSpec: v1alpha1.ServiceSpec{Type: corev1.ServiceTypeClusterIP},
+	}
+	tc.Spec.TiDB.Service = &v1alpha1.TiDBServiceSpec{
+		ServiceSpec: v1alpha1.ServiceSpec{Type: corev1.ServiceTypeClusterIP},
+	}
+	tc.Status.TiDB.Groups = map[string]v1alpha1.TiDBStatus{
+		"removed": {},
+	}
+	ns := tc.Namespace
+
+	tmm, _, _, indexers := newFakeTiDBMemberManager()
+	// a pod of the default TiDB created before the groups are added
+	defaultPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-tidb-0",
+			Namespace: ns,
+			Labels:    label.New().Instance(tc.GetInstanceName()).TiDB().Labels(),
+		},
+	}
+	g.Expect(indexers.pod.Add(defaultPod)).To(Succeed())
+	g.Expect(tmm.Sync(tc)).To(Succeed())
+
+	defaultSet, err := tmm.deps.StatefulSetLister.StatefulSets(ns).Get(controller.TiDBMemberName(tc.Name))
+	g.Expect(err).NotTo(HaveOccurred())
+	// the selector of the default TiDB StatefulSet is kept, the pods of the
+	// groups are excluded from the pods of the default TiDB by the operator
+	g.Expect(defaultSet.Spec.Selector).To(Equal(labelTiDB(tc).LabelSelector()))
+	selector, err := tidbPodSelector(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(selector.Matches(labels.Set(labelTiDB(tc.TiDBGroupCluster(&tc.Spec.TiDBGroups[0]))))).To(BeFalse())
+	g.Expect(defaultSet.Spec.Template.Labels[label.TiDBDefaultGroupLabelKey]).To(Equal("true"))
+	defaultSvc, err := tmm.deps.ServiceLister.Services(ns).Get(controller.TiDBMemberName(tc.Name))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(defaultSvc.Spec.Selector).NotTo(HaveKey(label.TiDBDefaultGroupLabelKey))
+
+	set, err := tmm.deps.StatefulSetLister.StatefulSets(ns).Get("test-tidb-olap")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(*set.Spec.Replicas).To(Equal(int32(2)))
+	g.Expect(set.Spec.Selector.MatchLabels[label.TiDBGroupLabelKey]).To(Equal("olap"))
+	g.Expect(set.Spec.Template.Labels[label.TiDBGroupLabelKey]).To(Equal("olap"))
+	g.Expect(set.Spec.ServiceName).To(Equal(controller.TiDBPeerMemberName(tc.Name)))
+
+	svc, err := tmm.deps.ServiceLister.Services(ns).Get("test-tidb-olap")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(svc.Spec.Selector[label.TiDBGroupLabelKey]).To(Equal("olap"))
+
+	g.Expect(tc.Status.TiDB.Groups).To(HaveKey("olap"))
+	g.Expect(tc.Status.TiDB.Groups).NotTo(HaveKey("removed"))
+	g.Expect(tc.Status.TiDB.Groups["olap"].StatefulSet).NotTo(BeNil())
+
+	// the default TiDB Service excludes the pods of the groups after all
+	// the pods of the default TiDB are labeled
+	defaultPod.Labels[label.TiDBDefaultGroupLabelKey] = "true"
+	g.Expect(indexers.pod.Update(defaultPod)).To(Succeed())
+	g.Expect(indexers.pod.Add(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-tidb-olap-0",
+			Namespace: ns,
+			Labels:    label.New().Instance(tc.GetInstanceName()).TiDB().TiDBGroup("olap").Labels(),
+		},
+	})).To(Succeed())
+	g.Expect(indexers.svc.Add(defaultSvc)).To(Succeed())
+	g.Expect(tmm.syncTiDBService(tc)).To(Succeed())
+	defaultSvc, err = tmm.deps.ServiceLister.Services(ns).Get(controller.TiDBMemberName(tc.Name))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(defaultSvc.Spec.Selector[label.TiDBDefaultGroupLabelKey]).To(Equal("true"))
+}
+
 func newFakeTiDBMemberManager() (*tidbMemberManager, *controller.FakeStatefulSetControl, *controller.FakeTiDBControl, *fakeIndexers) {
 	fakeDeps := controller.NewFakeDependencies()
 	tmm := &tidbMemberManager{
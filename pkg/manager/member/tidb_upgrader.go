@@ -15,14 +15,24 @@ package member
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
 	apps "k8s.io/api/apps/v1"
 	"k8s.io/klog"
 )
 
+const (
+	// TiDBDrainBeginTime is the key of the begin time of draining the connections of a tidb pod
+	TiDBDrainBeginTime = "tidbDrainBeginTime"
+	// MaxResignDDLOwnerCount is the max retry count of resigning the ddl owner
+	// of a tidb pod before it is upgraded anyway
+	MaxResignDDLOwnerCount = 3
+)
+
 type tidbUpgrader struct {
 	deps *controller.Dependencies
 }
@@ -45,7 +55,7 @@ func (u *tidbUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulSe
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
 
-	if tc.Status.PD.Phase == v1alpha1.UpgradePhase || tc.Status.TiKV.Phase == v1alpha1.UpgradePhase ||
+	if tc.Status.PD.Phase == v1alpha1.UpgradePhase || tc.TiKVUpgrading() ||
 		tc.Status.Pump.Phase == v1alpha1.UpgradePhase || tc.TiDBScaling() {
 		klog.Infof("TidbCluster: [%s/%s]'s pd status is %s, tikv status is %s, pump status is %s,"+
 			"tidb status is %s, can not upgrade tidb", ns, tcName, tc.Status.PD.Phase, tc.Status.TiKV.Phase,
@@ -57,6 +67,14 @@ func (u *tidbUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulSe
 		newSet.Spec.Template.Spec = *podSpec
 		return nil
 	}
+	if wait, err := waitForMaintenanceWindow(tc, oldSet, newSet); err != nil || wait {
+		return err
+	}
+
+	canary := newTiDBCanaryUpgrade(u.deps, tc)
+	if kept, err := canary.keepRolledBack(oldSet, newSet); err != nil || kept {
+		return err
+	}
 
 	tc.Status.TiDB.Phase = v1alpha1.UpgradePhase
 	if !templateEqual(newSet, oldSet) {
@@ -79,9 +97,12 @@ func (u *tidbUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulSe
 
 	setUpgradePartition(newSet, *oldSet.Spec.UpdateStrategy.RollingUpdate.Partition)
 	podOrdinals := helper.GetPodOrdinals(*oldSet.Spec.Replicas, oldSet).List()
+	if rolledBack, err := canary.watch(oldSet, newSet, podOrdinals); err != nil || rolledBack {
+		return err
+	}
 	for _i := len(podOrdinals) - 1; _i >= 0; _i-- {
 		i := podOrdinals[_i]
-		podName := tidbPodName(tcName, i)
+		podName := tidbGroupPodName(tc, i)
 		pod, err := u.deps.PodLister.Pods(ns).Get(podName)
 		if err != nil {
 			return fmt.Errorf("tidbUpgrader.Upgrade: failed to get pods %s for cluster %s/%s, error: %s", podName, ns, tcName, err)
@@ -97,6 +118,10 @@ func (u *tidbUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulSe
 			}
 			continue
 		}
+
+		if err := canary.gate(podOrdinals, podOrdinals[_i+1:]); err != nil {
+			return err
+		}
 		return u.upgradeTiDBPod(tc, i, newSet)
 	}
 
@@ -104,10 +129,89 @@ func (u *tidbUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulSe
 }
 
 func (u *tidbUpgrader) upgradeTiDBPod(tc *v1alpha1.TidbCluster, ordinal int32, newSet *apps.StatefulSet) error {
+	if err := drainTiDBConnections(u.deps, tc, ordinal, "upgrade"); err != nil {
+		return err
+	}
+	podName := tidbGroupPodName(tc, ordinal)
+	if member, exist := tc.Status.TiDB.Members[podName]; exist && member.Health {
+		notOwner, err := u.deps.TiDBControl.ResignDDLOwner(tc, ordinal)
+		if err != nil && tc.Status.TiDB.ResignDDLOwnerRetryCount < MaxResignDDLOwnerCount {
+			klog.Errorf("tidb upgrade: failed to resign ddl owner of %s/%s, retry count: %d, %v", tc.GetNamespace(), podName, tc.Status.TiDB.ResignDDLOwnerRetryCount, err)
+			tc.Status.TiDB.ResignDDLOwnerRetryCount++
+			return err
+		}
+		if err == nil && !notOwner {
+			klog.Infof("tidb upgrade: resign ddl owner of %s/%s successfully", tc.GetNamespace(), podName)
+		}
+	}
+	tc.Status.TiDB.ResignDDLOwnerRetryCount = 0
 	setUpgradePartition(newSet, ordinal)
 	return nil
 }
 
+// drainTiDBConnections removes the tidb pod in the given ordinal from the
+// endpoints of the tidb service and waits for its connections to be closed,
+// it returns nil when the pod is safe to be deleted.
+// The pod is deleted anyway if the connections are not closed within the timeout.
+func drainTiDBConnections(deps *controller.Dependencies, tc *v1alpha1.TidbCluster, ordinal int32, action string) error {
+	if !tc.TiDBDrainEnabled() {
+		return nil
+	}
+
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	podName := tidbGroupPodName(tc, ordinal)
+
+	if member, exist := tc.Status.TiDB.Members[podName]; !exist || !member.Health {
+		klog.Infof("tidb %s: tidb %s/%s is not healthy, skip draining the connections", action, ns, podName)
+		return nil
+	}
+
+	pod, err := deps.PodLister.Pods(ns).Get(podName)
+	if err != nil {
+		return fmt.Errorf("drainTiDBConnections: failed to get pod %s for cluster %s/%s, error: %s", podName, ns, tcName, err)
+	}
+
+	beginTimeStr, exist := pod.Annotations[TiDBDrainBeginTime]
+	if !exist {
+		pod = pod.DeepCopy()
+		if pod.Annotations == nil {
+			pod.Annotations = map[string]string{}
+		}
+		if pod.Labels == nil {
+			pod.Labels = map[string]string{}
+		}
+		beginTimeStr = time.Now().Format(time.RFC3339)
+		pod.Annotations[TiDBDrainBeginTime] = beginTimeStr
+		pod.Labels[label.TiDBServingLabelKey] = "false"
+		if _, err := deps.PodControl.UpdatePod(tc, pod); err != nil {
+			klog.Errorf("tidb %s: failed to remove pod %s/%s from the tidb service, %v", action, ns, podName, err)
+			return err
+		}
+		// give the endpoints some time to be updated before counting the connections
+		return controller.RequeueErrorf("tidb %s: pod %s/%s is removed from the tidb service, begin draining the connections", action, ns, podName)
+	}
+
+	beginTime, err := time.Parse(time.RFC3339, beginTimeStr)
+	if err != nil {
+		klog.Errorf("parse annotation:[%s] of pod %s/%s to time failed.", TiDBDrainBeginTime, ns, podName)
+	} else if time.Now().After(beginTime.Add(tc.TiDBDrainTimeout())) {
+		klog.Warningf("tidb %s: draining the connections of %s/%s timed out, delete the pod anyway", action, ns, podName)
+		return nil
+	}
+
+	count, err := deps.TiDBControl.GetConnectionCount(tc, ordinal)
+	if err != nil {
+		return fmt.Errorf("tidb %s: failed to get the connection count of %s/%s, error: %v", action, ns, podName, err)
+	}
+	if count > 0 {
+		return controller.RequeueErrorf("tidb %s: %s/%s still has %d connections, wait for draining", action, ns, podName, count)
+	}
+
+	klog.Infof("tidb %s: the connections of %s/%s have been drained", action, ns, podName)
+	return nil
+}
+
 type fakeTiDBUpgrader struct{}
 
 // NewFakeTiDBUpgrader returns a fake tidb upgrader
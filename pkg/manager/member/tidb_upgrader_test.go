@@ -14,7 +14,9 @@
 package member
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
@@ -36,13 +38,17 @@ func TestTiDBUpgrader_Upgrade(t *testing.T) {
 		changeFn                func(*v1alpha1.TidbCluster)
 		getLastAppliedConfigErr bool
 		errorExpect             bool
+		resignDDLOwnerFailed    bool
 		changeOldSet            func(set *apps.StatefulSet)
 		expectFn                func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet)
 	}
 
 	testFn := func(test *testcase, t *testing.T) {
 		t.Log(test.name)
-		upgrader, _, podInformer := newTiDBUpgrader()
+		upgrader, tidbControl, podInformer := newTiDBUpgrader()
+		if test.resignDDLOwnerFailed {
+			tidbControl.SetResignDDLOwnerError(fmt.Errorf("resign ddl owner failed"))
+		}
 		tc := newTidbClusterForTiDBUpgrader()
 		if test.changeFn != nil {
 			test.changeFn(tc)
@@ -85,6 +91,32 @@ func TestTiDBUpgrader_Upgrade(t *testing.T) {
 				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(0)))
 			},
 		},
+		{
+			name: "resign ddl owner failed",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.PD.Phase = v1alpha1.NormalPhase
+				tc.Status.TiKV.Phase = v1alpha1.NormalPhase
+			},
+			resignDDLOwnerFailed: true,
+			errorExpect:          true,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(tc.Status.TiDB.ResignDDLOwnerRetryCount).To(Equal(int32(1)))
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(1)))
+			},
+		},
+		{
+			name: "resign ddl owner failed too many times",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.PD.Phase = v1alpha1.NormalPhase
+				tc.Status.TiKV.Phase = v1alpha1.NormalPhase
+				tc.Status.TiDB.ResignDDLOwnerRetryCount = MaxResignDDLOwnerCount
+			},
+			resignDDLOwnerFailed: true,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(tc.Status.TiDB.ResignDDLOwnerRetryCount).To(Equal(int32(0)))
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(0)))
+			},
+		},
 		{
 			name: "modify oldSet update strategy to OnDelete",
 			changeFn: func(tc *v1alpha1.TidbCluster) {
@@ -316,3 +348,82 @@ func getTiDBPods() []*corev1.Pod {
 	}
 	return pods
 }
+
+func TestTiDBUpgraderDrain(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name        string
+		annotations map[string]string
+		connections int
+		errExpectFn func(*GomegaWithT, error)
+		expectFn    func(g *GomegaWithT, pod *corev1.Pod, newSet *apps.StatefulSet)
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+		upgrader, tidbControl, podInformer := newTiDBUpgrader()
+		tc := newTidbClusterForTiDBUpgrader()
+		tc.Status.PD.Phase = v1alpha1.NormalPhase
+		tc.Status.TiKV.Phase = v1alpha1.NormalPhase
+		tc.Spec.TiDB.Drain = &v1alpha1.TiDBDrainSpec{Enabled: true, Timeout: pointer.StringPtr("1m")}
+		pods := getTiDBPods()
+		pods[0].Annotations = test.annotations
+		for _, pod := range pods {
+			podInformer.Informer().GetIndexer().Add(pod)
+		}
+		tidbControl.SetConnectionCount(tidbPodName(upgradeTcName, 0), test.connections)
+
+		oldSet := newStatefulSetForTiDBUpgrader()
+		newSet := oldSet.DeepCopy()
+		SetStatefulSetLastAppliedConfigAnnotation(oldSet)
+
+		err := upgrader.Upgrade(tc, oldSet, newSet)
+		test.errExpectFn(g, err)
+		pod, err := podInformer.Lister().Pods(corev1.NamespaceDefault).Get(tidbPodName(upgradeTcName, 0))
+		g.Expect(err).NotTo(HaveOccurred())
+		test.expectFn(g, pod, newSet)
+	}
+
+	tests := []*testcase{
+		{
+			name:        "begin draining",
+			errExpectFn: errExpectRequeue,
+			expectFn: func(g *GomegaWithT, pod *corev1.Pod, newSet *apps.StatefulSet) {
+				g.Expect(pod.Labels[label.TiDBServingLabelKey]).To(Equal("false"))
+				g.Expect(pod.Annotations).To(HaveKey(TiDBDrainBeginTime))
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(1)))
+			},
+		},
+		{
+			name:        "connections are not drained",
+			annotations: map[string]string{TiDBDrainBeginTime: time.Now().Format(time.RFC3339)},
+			connections: 3,
+			errExpectFn: errExpectRequeue,
+			expectFn: func(g *GomegaWithT, pod *corev1.Pod, newSet *apps.StatefulSet) {
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(1)))
+			},
+		},
+		{
+			name:        "connections are drained",
+			annotations: map[string]string{TiDBDrainBeginTime: time.Now().Format(time.RFC3339)},
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, pod *corev1.Pod, newSet *apps.StatefulSet) {
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(0)))
+			},
+		},
+		{
+			name:        "draining timed out",
+			annotations: map[string]string{TiDBDrainBeginTime: time.Now().Add(-2 * time.Minute).Format(time.RFC3339)},
+			connections: 3,
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, pod *corev1.Pod, newSet *apps.StatefulSet) {
+				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(0)))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		testFn(test, t)
+	}
+}
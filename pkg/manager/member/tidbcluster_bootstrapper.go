@@ -0,0 +1,278 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	"github.com/pingcap/tidb-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+)
+
+// TidbClusterBootstrapper restores the backup data specified in spec.bootstrapFrom
+// to a new TidbCluster through a Restore once the cluster is ready
+type TidbClusterBootstrapper interface {
+	Sync(tc *v1alpha1.TidbCluster) error
+}
+
+type tidbClusterBootstrapper struct {
+	deps *controller.Dependencies
+}
+
+// NewTidbClusterBootstrapper returns a TidbClusterBootstrapper
+func NewTidbClusterBootstrapper(deps *controller.Dependencies) TidbClusterBootstrapper {
+	return &tidbClusterBootstrapper{deps: deps}
+}
+
+func (b *tidbClusterBootstrapper) Sync(tc *v1alpha1.TidbCluster) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	if tc.Spec.BootstrapFrom == nil {
+		return nil
+	}
+	if tc.Status.Bootstrap == nil {
+		// only the cluster without any data can be bootstrapped
+		_, err := b.deps.StatefulSetLister.StatefulSets(ns).Get(controller.PDMemberName(tcName))
+		if err == nil {
+			klog.V(4).Infof("tidbcluster %s/%s already exists, skip bootstrapping it from backup", ns, tcName)
+			return nil
+		}
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		tc.Status.Bootstrap = &v1alpha1.BootstrapStatus{}
+	}
+	if !tc.Bootstrapping() {
+		return nil
+	}
+
+	restoreName := bootstrapRestoreName(tcName)
+	restore, err := b.deps.RestoreLister.Restores(ns).Get(restoreName)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if errors.IsNotFound(err) {
+		// the data is restored after all the components are ready
+		if !bootstrapClusterReady(tc) {
+			klog.V(4).Infof("tidbcluster %s/%s is not ready, waiting to bootstrap it from backup", ns, tcName)
+			return nil
+		}
+		restore, err = b.newRestore(tc)
+		if err != nil {
+			return err
+		}
+		if restore == nil {
+			return nil
+		}
+		if _, err := b.deps.RestoreControl.CreateRestore(tc, restore); err != nil {
+			return err
+		}
+		klog.Infof("tidbcluster %s/%s, restore %s is created to bootstrap the cluster", ns, tcName, restoreName)
+	}
+
+	if v1alpha1.IsRestoreFailed(restore) {
+		hash, err := bootstrapFromHash(tc)
+		if err != nil {
+			return err
+		}
+		// the failed Restore is kept until it is deleted or the bootstrapFrom
+		// spec is changed, then a new Restore is created to retry
+		if restore.Annotations[label.AnnBootstrapFromHash] != hash {
+			if err := b.deps.RestoreControl.DeleteRestore(tc, restore); err != nil {
+				return err
+			}
+			klog.Infof("tidbcluster %s/%s, failed restore %s is deleted since bootstrapFrom is changed", ns, tcName, restoreName)
+			tc.Status.Bootstrap = &v1alpha1.BootstrapStatus{}
+			return nil
+		}
+		_, condition := v1alpha1.GetRestoreCondition(&restore.Status, v1alpha1.RestoreFailed)
+		message := fmt.Sprintf("restore %s failed: %s", restoreName, condition.Message)
+		if tc.Status.Bootstrap.Message != message {
+			b.deps.Recorder.Event(tc, corev1.EventTypeWarning, "BootstrapFailed", message)
+		}
+		tc.Status.Bootstrap.Message = message
+	} else {
+		tc.Status.Bootstrap.Message = ""
+	}
+
+	tc.Status.Bootstrap.Restore = restoreName
+	tc.Status.Bootstrap.Phase = restore.Status.Phase
+	if restore.Status.Phase == v1alpha1.RestoreComplete {
+		tc.Status.Bootstrap.TimeCompleted = restore.Status.TimeCompleted
+		klog.Infof("tidbcluster %s/%s is bootstrapped from restore %s", ns, tcName, restoreName)
+	}
+	return nil
+}
+
+// newRestore returns the Restore to bootstrap the tidb cluster, nil is returned
+// if the Backup is not completed yet
+func (b *tidbClusterBootstrapper) newRestore(tc *v1alpha1.TidbCluster) (*v1alpha1.Restore, error) {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	from := tc.Spec.BootstrapFrom
+
+	spec := v1alpha1.RestoreSpec{}
+	if from.Restore != nil {
+		spec = *from.Restore.DeepCopy()
+	}
+	if from.Backup != "" {
+		backup, err := b.deps.BackupLister.Backups(ns).Get(from.Backup)
+		if err != nil {
+			return nil, fmt.Errorf("tidbcluster %s/%s, get backup %s failed: %v", ns, tcName, from.Backup, err)
+		}
+		if backup.Status.Phase != v1alpha1.BackupComplete {
+			klog.Infof("tidbcluster %s/%s, waiting for backup %s to complete to bootstrap the cluster", ns, tcName, from.Backup)
+			return nil, nil
+		}
+		setRestoreSpecFromBackup(&spec, backup)
+	}
+
+	if spec.BR != nil {
+		spec.BR.Cluster = tcName
+		spec.BR.ClusterNamespace = ns
+	}
+	spec.To = bootstrapAccessConfig(tc, spec.To)
+
+	hash, err := bootstrapFromHash(tc)
+	if err != nil {
+		return nil, err
+	}
+	return &v1alpha1.Restore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            bootstrapRestoreName(tcName),
+			Namespace:       ns,
+			Annotations:     map[string]string{label.AnnBootstrapFromHash: hash},
+			OwnerReferences: []metav1.OwnerReference{controller.GetOwnerRef(tc)},
+		},
+		Spec: spec,
+	}, nil
+}
+
+// setRestoreSpecFromBackup sets the storage location and the tool of the backup
+// to the restore spec, the items set in the restore spec are kept
+func setRestoreSpecFromBackup(spec *v1alpha1.RestoreSpec, backup *v1alpha1.Backup) {
+	spec.StorageProvider = *backup.Spec.StorageProvider.DeepCopy()
+	spec.Type = backup.Spec.Type
+	if len(spec.TableFilter) == 0 {
+		spec.TableFilter = backup.Spec.TableFilter
+	}
+	if spec.ServiceAccount == "" {
+		spec.ServiceAccount = backup.Spec.ServiceAccount
+	}
+	if len(spec.ImagePullSecrets) == 0 {
+		spec.ImagePullSecrets = backup.Spec.ImagePullSecrets
+	}
+	spec.UseKMS = spec.UseKMS || backup.Spec.UseKMS
+
+	if backup.Spec.BR != nil {
+		if spec.BR == nil {
+			spec.BR = backup.Spec.BR.DeepCopy()
+			// only used to back up the history version
+			spec.BR.TimeAgo = ""
+		}
+		if spec.ToolImage == "" {
+			spec.ToolImage = backup.Spec.ToolImage
+		}
+		return
+	}
+
+	// the data exported by dumpling is imported by lightning from the backup path
+	spec.BR = nil
+	switch {
+	case spec.S3 != nil:
+		spec.S3.Path = backup.Status.BackupPath
+	case spec.Gcs != nil:
+		spec.Gcs.Path = backup.Status.BackupPath
+	}
+	if spec.StorageSize == "" {
+		spec.StorageSize = backup.Spec.StorageSize
+	}
+	if spec.StorageClassName == nil {
+		spec.StorageClassName = backup.Spec.StorageClassName
+	}
+}
+
+// bootstrapAccessConfig returns the access config of the TiDB of the tidb cluster,
+// the user and the password secret in the restore template are kept
+func bootstrapAccessConfig(tc *v1alpha1.TidbCluster, to *v1alpha1.TiDBAccessConfig) *v1alpha1.TiDBAccessConfig {
+	config := &v1alpha1.TiDBAccessConfig{}
+	if to != nil {
+		config = to.DeepCopy()
+	}
+	// the TiDB service is not created until the cluster is bootstrapped
+	config.Host = fmt.Sprintf("%s.%s", controller.TiDBPeerMemberName(tc.Name), tc.Namespace)
+	config.Port = 4000
+	if config.User == "" {
+		config.User = "root"
+	}
+	if config.TLSClientSecretName == nil && tc.Spec.TiDB != nil && tc.Spec.TiDB.IsTLSClientEnabled() {
+		secretName := util.TiDBClientTLSSecretName(tc.Name)
+		config.TLSClientSecretName = &secretName
+	}
+	return config
+}
+
+func bootstrapClusterReady(tc *v1alpha1.TidbCluster) bool {
+	if tc.Spec.PD != nil && !tc.PDAllMembersReady() {
+		return false
+	}
+	if tc.Spec.TiKV != nil && !tc.TiKVAllStoresReady() {
+		return false
+	}
+	if tc.Spec.TiDB != nil && !tc.TiDBAllMembersReady() {
+		return false
+	}
+	return true
+}
+
+// bootstrapFromHash returns the hash of spec.bootstrapFrom, the failed Restore
+// created for a different spec.bootstrapFrom is recreated
+func bootstrapFromHash(tc *v1alpha1.TidbCluster) (string, error) {
+	b, err := json.Marshal(tc.Spec.BootstrapFrom)
+	if err != nil {
+		return "", err
+	}
+	return v1alpha1.HashContents(b), nil
+}
+
+func bootstrapRestoreName(tcName string) string {
+	return fmt.Sprintf("%s-bootstrap", tcName)
+}
+
+// FakeTidbClusterBootstrapper is a fake implementation of TidbClusterBootstrapper
+type FakeTidbClusterBootstrapper struct {
+	err error
+}
+
+// NewFakeTidbClusterBootstrapper returns a FakeTidbClusterBootstrapper
+func NewFakeTidbClusterBootstrapper() *FakeTidbClusterBootstrapper {
+	return &FakeTidbClusterBootstrapper{}
+}
+
+// SetSyncError sets the error returned by Sync
+func (b *FakeTidbClusterBootstrapper) SetSyncError(err error) {
+	b.err = err
+}
+
+func (b *FakeTidbClusterBootstrapper) Sync(_ *v1alpha1.TidbCluster) error {
+	return b.err
+}
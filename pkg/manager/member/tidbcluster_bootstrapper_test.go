@@ -0,0 +1,220 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTidbClusterBootstrapperSync(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name     string
+		prepare  func(tc *v1alpha1.TidbCluster, deps *controller.Dependencies)
+		expectFn func(*GomegaWithT, *v1alpha1.TidbCluster, *v1alpha1.Restore)
+	}
+
+	newBackup := func(br bool, phase v1alpha1.BackupConditionType) *v1alpha1.Backup {
+		backup := &v1alpha1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: metav1.NamespaceDefault},
+			Spec: v1alpha1.BackupSpec{
+				StorageProvider: v1alpha1.StorageProvider{
+					S3: &v1alpha1.S3StorageProvider{Bucket: "bucket", Prefix: "prefix"},
+				},
+				StorageSize: "10Gi",
+			},
+			Status: v1alpha1.BackupStatus{
+				BackupPath: "s3://bucket/prefix/backup-1",
+				Phase:      phase,
+			},
+		}
+		if br {
+			backup.Spec.BR = &v1alpha1.BRConfig{Cluster: "prod", ClusterNamespace: "prod", TimeAgo: "1h"}
+		}
+		return backup
+	}
+	addBackup := func(backup *v1alpha1.Backup) func(*v1alpha1.TidbCluster, *controller.Dependencies) {
+		return func(tc *v1alpha1.TidbCluster, deps *controller.Dependencies) {
+			deps.InformerFactory.Pingcap().V1alpha1().Backups().Informer().GetIndexer().Add(backup)
+		}
+	}
+
+	addFailedRestore := func(changed bool) func(*v1alpha1.TidbCluster, *controller.Dependencies) {
+		return func(tc *v1alpha1.TidbCluster, deps *controller.Dependencies) {
+			tc.Status.Bootstrap = &v1alpha1.BootstrapStatus{Restore: "test-bootstrap", Phase: v1alpha1.RestoreRunning}
+			hash, err := bootstrapFromHash(tc)
+			g.Expect(err).NotTo(HaveOccurred())
+			if changed {
+				tc.Spec.BootstrapFrom.Restore.To.SecretName = "new-secret"
+			}
+			restore := &v1alpha1.Restore{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-bootstrap",
+					Namespace:   tc.Namespace,
+					Annotations: map[string]string{label.AnnBootstrapFromHash: hash},
+				},
+				Status: v1alpha1.RestoreStatus{Phase: v1alpha1.RestoreFailed},
+			}
+			v1alpha1.UpdateRestoreCondition(&restore.Status, &v1alpha1.RestoreCondition{
+				Type:    v1alpha1.RestoreFailed,
+				Status:  corev1.ConditionTrue,
+				Message: "access denied",
+			})
+			deps.InformerFactory.Pingcap().V1alpha1().Restores().Informer().GetIndexer().Add(restore)
+		}
+	}
+
+	testFn := func(test *testcase) {
+		t.Log(test.name)
+
+		deps := controller.NewFakeDependencies()
+		tc := newTidbClusterForPD()
+		tc.Spec.PD.Replicas = 1
+		tc.Spec.TiKV = nil
+		tc.Spec.TiDB = nil
+		tc.Spec.BootstrapFrom = &v1alpha1.BootstrapFromSpec{
+			Backup: "backup",
+			Restore: &v1alpha1.RestoreSpec{
+				To: &v1alpha1.TiDBAccessConfig{SecretName: "secret"},
+			},
+		}
+		tc.Status.PD.Members = map[string]v1alpha1.PDMember{"test-pd-0": {Health: true}}
+		if test.prepare != nil {
+			test.prepare(tc, deps)
+		}
+
+		err := NewTidbClusterBootstrapper(deps).Sync(tc)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		restore, err := deps.RestoreLister.Restores(tc.Namespace).Get("test-bootstrap")
+		if errors.IsNotFound(err) {
+			restore = nil
+		} else {
+			g.Expect(err).NotTo(HaveOccurred())
+		}
+		test.expectFn(g, tc, restore)
+	}
+
+	tests := []testcase{
+		{
+			name: "existing cluster",
+			prepare: func(tc *v1alpha1.TidbCluster, deps *controller.Dependencies) {
+				deps.KubeInformerFactory.Apps().V1().StatefulSets().Informer().GetIndexer().Add(&apps.StatefulSet{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-pd", Namespace: tc.Namespace},
+				})
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, restore *v1alpha1.Restore) {
+				g.Expect(tc.Status.Bootstrap).To(BeNil())
+				g.Expect(tc.Bootstrapping()).To(BeFalse())
+				g.Expect(restore).To(BeNil())
+			},
+		},
+		{
+			name: "cluster is not ready",
+			prepare: func(tc *v1alpha1.TidbCluster, deps *controller.Dependencies) {
+				tc.Status.PD.Members = nil
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, restore *v1alpha1.Restore) {
+				g.Expect(tc.Bootstrapping()).To(BeTrue())
+				g.Expect(tc.Status.Bootstrap.Restore).To(BeEmpty())
+				g.Expect(restore).To(BeNil())
+			},
+		},
+		{
+			name:    "backup is not complete",
+			prepare: addBackup(newBackup(true, v1alpha1.BackupRunning)),
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, restore *v1alpha1.Restore) {
+				g.Expect(tc.Bootstrapping()).To(BeTrue())
+				g.Expect(restore).To(BeNil())
+			},
+		},
+		{
+			name:    "restore from br backup",
+			prepare: addBackup(newBackup(true, v1alpha1.BackupComplete)),
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, restore *v1alpha1.Restore) {
+				g.Expect(tc.Bootstrapping()).To(BeTrue())
+				g.Expect(tc.Status.Bootstrap.Restore).To(Equal("test-bootstrap"))
+				g.Expect(restore).NotTo(BeNil())
+				g.Expect(restore.OwnerReferences[0].Name).To(Equal(tc.Name))
+				g.Expect(restore.Spec.BR.Cluster).To(Equal(tc.Name))
+				g.Expect(restore.Spec.BR.ClusterNamespace).To(Equal(tc.Namespace))
+				g.Expect(restore.Spec.BR.TimeAgo).To(BeEmpty())
+				g.Expect(restore.Spec.S3.Prefix).To(Equal("prefix"))
+				g.Expect(restore.Spec.To).To(Equal(&v1alpha1.TiDBAccessConfig{
+					Host:       "test-tidb-peer.default",
+					Port:       4000,
+					User:       "root",
+					SecretName: "secret",
+				}))
+			},
+		},
+		{
+			name:    "restore from dumpling backup",
+			prepare: addBackup(newBackup(false, v1alpha1.BackupComplete)),
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, restore *v1alpha1.Restore) {
+				g.Expect(restore).NotTo(BeNil())
+				g.Expect(restore.Spec.BR).To(BeNil())
+				g.Expect(restore.Spec.S3.Path).To(Equal("s3://bucket/prefix/backup-1"))
+				g.Expect(restore.Spec.StorageSize).To(Equal("10Gi"))
+			},
+		},
+		{
+			name: "restore completed",
+			prepare: func(tc *v1alpha1.TidbCluster, deps *controller.Dependencies) {
+				tc.Status.Bootstrap = &v1alpha1.BootstrapStatus{Restore: "test-bootstrap", Phase: v1alpha1.RestoreRunning}
+				deps.InformerFactory.Pingcap().V1alpha1().Restores().Informer().GetIndexer().Add(&v1alpha1.Restore{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-bootstrap", Namespace: tc.Namespace},
+					Status:     v1alpha1.RestoreStatus{Phase: v1alpha1.RestoreComplete},
+				})
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, restore *v1alpha1.Restore) {
+				g.Expect(tc.Status.Bootstrap.Phase).To(Equal(v1alpha1.RestoreComplete))
+				g.Expect(tc.Bootstrapping()).To(BeFalse())
+			},
+		},
+		{
+			name:    "restore failed",
+			prepare: addFailedRestore(false),
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, restore *v1alpha1.Restore) {
+				g.Expect(tc.Bootstrapping()).To(BeTrue())
+				g.Expect(tc.Status.Bootstrap.Phase).To(Equal(v1alpha1.RestoreFailed))
+				g.Expect(tc.Status.Bootstrap.Message).To(ContainSubstring("access denied"))
+				g.Expect(restore).NotTo(BeNil())
+			},
+		},
+		{
+			name:    "restore failed and bootstrapFrom changed",
+			prepare: addFailedRestore(true),
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, restore *v1alpha1.Restore) {
+				g.Expect(tc.Bootstrapping()).To(BeTrue())
+				g.Expect(tc.Status.Bootstrap.Phase).To(BeEmpty())
+				g.Expect(restore).To(BeNil())
+			},
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i])
+	}
+}
@@ -0,0 +1,322 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// TidbClusterPlanner makes the plan of the actions the member managers are
+// expected to take for the spec of a TidbCluster, nothing is changed
+type TidbClusterPlanner interface {
+	Plan(tc *v1alpha1.TidbCluster) (*v1alpha1.TidbClusterPlan, error)
+}
+
+type tidbClusterPlanner struct {
+	deps *controller.Dependencies
+}
+
+// NewTidbClusterPlanner returns a TidbClusterPlanner
+func NewTidbClusterPlanner(deps *controller.Dependencies) TidbClusterPlanner {
+	return &tidbClusterPlanner{deps: deps}
+}
+
+// componentPlanner builds the desired ConfigMap and statefulset of a component
+type componentPlanner struct {
+	memberType v1alpha1.MemberType
+	setName    string
+	strategy   v1alpha1.ConfigUpdateStrategy
+	// allowList is the config items can be changed online, nil if the component
+	// does not support changing config online
+	allowList    *onlineConfigAllowList
+	newConfigMap func() (*corev1.ConfigMap, error)
+	newSet       func(cm *corev1.ConfigMap) (*apps.StatefulSet, error)
+}
+
+func (p *tidbClusterPlanner) Plan(tc *v1alpha1.TidbCluster) (*v1alpha1.TidbClusterPlan, error) {
+	// the builders may modify the spec, e.g. the TLS config items
+	tc = tc.DeepCopy()
+
+	var planners []componentPlanner
+	if tc.Spec.PD != nil {
+		planners = append(planners, pdPlanner(tc))
+	}
+	if tc.Spec.TiKV != nil {
+		planners = append(planners, tikvPlanner(tc))
+	}
+	for i := range tc.Spec.TiKVGroups {
+		planners = append(planners, tikvPlanner(tc.TiKVGroupCluster(&tc.Spec.TiKVGroups[i])))
+	}
+	if tc.Spec.TiDB != nil {
+		planners = append(planners, tidbPlanner(tc))
+	}
+	for i := range tc.Spec.TiDBGroups {
+		planners = append(planners, tidbPlanner(tc.TiDBGroupCluster(&tc.Spec.TiDBGroups[i])))
+	}
+	if tc.Spec.TiFlash != nil {
+		planners = append(planners, componentPlanner{
+			memberType: v1alpha1.TiFlashMemberType,
+			setName:    controller.TiFlashMemberName(tc.Name),
+			strategy:   tc.BaseTiFlashSpec().ConfigUpdateStrategy(),
+			newConfigMap: func() (*corev1.ConfigMap, error) {
+				return getTiFlashConfigMap(tc)
+			},
+			newSet: func(cm *corev1.ConfigMap) (*apps.StatefulSet, error) {
+				return getNewStatefulSet(tc, cm)
+			},
+		})
+	}
+	if tc.Spec.TiCDC != nil {
+		planners = append(planners, componentPlanner{
+			memberType: v1alpha1.TiCDCMemberType,
+			setName:    controller.TiCDCMemberName(tc.Name),
+			newConfigMap: func() (*corev1.ConfigMap, error) {
+				return nil, nil
+			},
+			newSet: func(cm *corev1.ConfigMap) (*apps.StatefulSet, error) {
+				return getNewTiCDCStatefulSet(tc)
+			},
+		})
+	}
+	if basePumpSpec, ok := tc.BasePumpSpec(); ok {
+		planners = append(planners, componentPlanner{
+			memberType: v1alpha1.PumpMemberType,
+			setName:    controller.PumpMemberName(tc.Name),
+			strategy:   basePumpSpec.ConfigUpdateStrategy(),
+			newConfigMap: func() (*corev1.ConfigMap, error) {
+				return getNewPumpConfigMap(tc)
+			},
+			newSet: func(cm *corev1.ConfigMap) (*apps.StatefulSet, error) {
+				return getNewPumpStatefulSet(tc, cm)
+			},
+		})
+	}
+
+	plan := &v1alpha1.TidbClusterPlan{
+		ObservedGeneration: tc.Generation,
+	}
+	for _, planner := range planners {
+		componentPlan, err := p.planComponent(tc, planner)
+		if err != nil {
+			return nil, fmt.Errorf("plan %s for tidbcluster %s/%s failed: %v", planner.setName, tc.Namespace, tc.Name, err)
+		}
+		if len(componentPlan.Actions) > 0 {
+			plan.Components = append(plan.Components, *componentPlan)
+		}
+	}
+
+	// keep the generated time of an unchanged plan, otherwise the status
+	// would be updated in every sync
+	if old := tc.Status.Plan; old != nil && old.ObservedGeneration == plan.ObservedGeneration &&
+		apiequality.Semantic.DeepEqual(old.Components, plan.Components) {
+		plan.GeneratedTime = old.GeneratedTime
+	} else {
+		plan.GeneratedTime = metav1.Now()
+	}
+	return plan, nil
+}
+
+func pdPlanner(tc *v1alpha1.TidbCluster) componentPlanner {
+	return componentPlanner{
+		memberType: v1alpha1.PDMemberType,
+		setName:    controller.PDMemberName(tc.Name),
+		strategy:   tc.BasePDSpec().ConfigUpdateStrategy(),
+		allowList:  &pdOnlineConfig,
+		newConfigMap: func() (*corev1.ConfigMap, error) {
+			if tc.Spec.PD.Config == nil {
+				return nil, nil
+			}
+			return getPDConfigMap(tc)
+		},
+		newSet: func(cm *corev1.ConfigMap) (*apps.StatefulSet, error) {
+			return getNewPDSetForTidbCluster(tc, cm)
+		},
+	}
+}
+
+func tikvPlanner(tc *v1alpha1.TidbCluster) componentPlanner {
+	return componentPlanner{
+		memberType: v1alpha1.TiKVMemberType,
+		setName:    tikvMemberName(tc),
+		strategy:   tc.BaseTiKVSpec().ConfigUpdateStrategy(),
+		allowList:  &tikvOnlineConfig,
+		newConfigMap: func() (*corev1.ConfigMap, error) {
+			if tc.Spec.TiKV.Config == nil {
+				return nil, nil
+			}
+			return getTikVConfigMap(tc)
+		},
+		newSet: func(cm *corev1.ConfigMap) (*apps.StatefulSet, error) {
+			return getNewTiKVSetForTidbCluster(tc, cm)
+		},
+	}
+}
+
+func tidbPlanner(tc *v1alpha1.TidbCluster) componentPlanner {
+	return componentPlanner{
+		memberType: v1alpha1.TiDBMemberType,
+		setName:    tidbMemberName(tc),
+		strategy:   tc.BaseTiDBSpec().ConfigUpdateStrategy(),
+		allowList:  &tidbOnlineConfig,
+		newConfigMap: func() (*corev1.ConfigMap, error) {
+			if tc.Spec.TiDB.Config == nil {
+				return nil, nil
+			}
+			return getTiDBConfigMap(tc)
+		},
+		newSet: func(cm *corev1.ConfigMap) (*apps.StatefulSet, error) {
+			return getNewTiDBSetForTidbCluster(tc, cm), nil
+		},
+	}
+}
+
+// planComponent compares the desired statefulset of the component with the existing one
+// the same way as the member managers do, and returns the actions expected to be taken
+func (p *tidbClusterPlanner) planComponent(tc *v1alpha1.TidbCluster, planner componentPlanner) (*v1alpha1.ComponentPlan, error) {
+	plan := &v1alpha1.ComponentPlan{
+		Component:   planner.memberType,
+		StatefulSet: planner.setName,
+	}
+
+	oldSet, err := p.deps.StatefulSetLister.StatefulSets(tc.Namespace).Get(planner.setName)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	if errors.IsNotFound(err) {
+		oldSet = nil
+	}
+
+	cm, err := planner.newConfigMap()
+	if err != nil {
+		return nil, err
+	}
+	var onlineKeys, restartKeys []string
+	if cm != nil {
+		var inUseName string
+		if oldSet != nil {
+			inUseName = FindConfigMapVolume(&oldSet.Spec.Template.Spec, func(name string) bool {
+				return strings.HasPrefix(name, planner.setName)
+			})
+		}
+		if planner.strategy == v1alpha1.ConfigUpdateStrategyInPlace && planner.allowList != nil {
+			var online map[string]interface{}
+			online, restartKeys, err = diffInUseConfig(p.deps.ConfigMapLister, inUseName, cm, *planner.allowList)
+			if err != nil {
+				return nil, err
+			}
+			onlineKeys = sets.StringKeySet(online).List()
+		}
+		if err := updateConfigMapIfNeed(p.deps.ConfigMapLister, planner.strategy, inUseName, cm); err != nil {
+			return nil, err
+		}
+	}
+
+	newSet, err := planner.newSet(cm)
+	if err != nil {
+		return nil, err
+	}
+	if len(restartKeys) > 0 {
+		revision, err := configRevision(cm)
+		if err != nil {
+			return nil, err
+		}
+		if newSet.Spec.Template.Annotations == nil {
+			newSet.Spec.Template.Annotations = map[string]string{}
+		}
+		newSet.Spec.Template.Annotations[label.AnnConfigRestartRevision] = revision
+	}
+
+	newOrdinals := helper.GetPodOrdinals(*newSet.Spec.Replicas, newSet)
+	if oldSet == nil {
+		plan.Actions = append(plan.Actions, v1alpha1.PlanAction{
+			Type: v1alpha1.PlanActionCreate,
+			Pods: planPodNames(planner.setName, newOrdinals),
+		})
+		return plan, nil
+	}
+
+	oldOrdinals := helper.GetPodOrdinals(*oldSet.Spec.Replicas, oldSet)
+	if ordinals := newOrdinals.Difference(oldOrdinals); ordinals.Len() > 0 {
+		plan.Actions = append(plan.Actions, v1alpha1.PlanAction{
+			Type: v1alpha1.PlanActionScaleOut,
+			Pods: planPodNames(planner.setName, ordinals),
+		})
+	}
+	if ordinals := oldOrdinals.Difference(newOrdinals); ordinals.Len() > 0 {
+		plan.Actions = append(plan.Actions, v1alpha1.PlanAction{
+			Type: v1alpha1.PlanActionScaleIn,
+			Pods: planPodNames(planner.setName, ordinals),
+		})
+	}
+	if len(onlineKeys) > 0 {
+		plan.Actions = append(plan.Actions, v1alpha1.PlanAction{
+			Type:       v1alpha1.PlanActionReconfigure,
+			Pods:       planPodNames(planner.setName, oldOrdinals.Intersection(newOrdinals)),
+			ConfigKeys: onlineKeys,
+		})
+	}
+	if !templateEqual(newSet, oldSet) {
+		plan.Actions = append(plan.Actions, v1alpha1.PlanAction{
+			Type:       v1alpha1.PlanActionRestart,
+			Pods:       planPodNames(planner.setName, oldOrdinals.Intersection(newOrdinals)),
+			ConfigKeys: restartKeys,
+		})
+	}
+	return plan, nil
+}
+
+func planPodNames(setName string, ordinals sets.Int32) []string {
+	var names []string
+	for _, ordinal := range ordinals.List() {
+		names = append(names, fmt.Sprintf("%s-%d", setName, ordinal))
+	}
+	return names
+}
+
+// FakeTidbClusterPlanner is a fake implementation of TidbClusterPlanner
+type FakeTidbClusterPlanner struct {
+	plan *v1alpha1.TidbClusterPlan
+	err  error
+}
+
+// NewFakeTidbClusterPlanner returns a FakeTidbClusterPlanner
+func NewFakeTidbClusterPlanner() *FakeTidbClusterPlanner {
+	return &FakeTidbClusterPlanner{plan: &v1alpha1.TidbClusterPlan{}}
+}
+
+// SetPlan sets the plan returned by Plan
+func (p *FakeTidbClusterPlanner) SetPlan(plan *v1alpha1.TidbClusterPlan) {
+	p.plan = plan
+}
+
+// SetPlanError sets the error returned by Plan
+func (p *FakeTidbClusterPlanner) SetPlanError(err error) {
+	p.err = err
+}
+
+func (p *FakeTidbClusterPlanner) Plan(_ *v1alpha1.TidbCluster) (*v1alpha1.TidbClusterPlan, error) {
+	return p.plan, p.err
+}
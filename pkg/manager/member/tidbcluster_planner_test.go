@@ -0,0 +1,189 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTidbClusterPlannerPlan(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name     string
+		existing func(tc *v1alpha1.TidbCluster, deps *controller.Dependencies)
+		update   func(tc *v1alpha1.TidbCluster)
+		expectFn func(*GomegaWithT, map[v1alpha1.MemberType]v1alpha1.ComponentPlan)
+	}
+
+	testFn := func(test *testcase) {
+		t.Log(test.name)
+
+		deps := controller.NewFakeDependencies()
+		tc := newTidbClusterForPD()
+		tc.Spec.TiKV.Config = v1alpha1.NewTiKVConfig()
+		tc.Spec.TiKV.Config.Set("raftstore.sync-log", true)
+		if test.existing != nil {
+			test.existing(tc.DeepCopy(), deps)
+		}
+		if test.update != nil {
+			test.update(tc)
+		}
+
+		plan, err := NewTidbClusterPlanner(deps).Plan(tc)
+		g.Expect(err).NotTo(HaveOccurred())
+		components := map[v1alpha1.MemberType]v1alpha1.ComponentPlan{}
+		for _, component := range plan.Components {
+			components[component.Component] = component
+		}
+		test.expectFn(g, components)
+	}
+
+	addSets := func(tc *v1alpha1.TidbCluster, deps *controller.Dependencies) {
+		setIndexer := deps.KubeInformerFactory.Apps().V1().StatefulSets().Informer().GetIndexer()
+		pdSet, err := getNewPDSetForTidbCluster(tc, nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(SetStatefulSetLastAppliedConfigAnnotation(pdSet)).To(Succeed())
+		setIndexer.Add(pdSet)
+
+		cm, err := getTikVConfigMap(tc)
+		g.Expect(err).NotTo(HaveOccurred())
+		deps.LabelFilterKubeInformerFactory.Core().V1().ConfigMaps().Informer().GetIndexer().Add(cm)
+		tikvSet, err := getNewTiKVSetForTidbCluster(tc, cm)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(SetStatefulSetLastAppliedConfigAnnotation(tikvSet)).To(Succeed())
+		setIndexer.Add(tikvSet)
+
+		tidbSet := getNewTiDBSetForTidbCluster(tc, nil)
+		g.Expect(SetStatefulSetLastAppliedConfigAnnotation(tidbSet)).To(Succeed())
+		setIndexer.Add(tidbSet)
+	}
+
+	tests := []testcase{
+		{
+			name: "create cluster",
+			expectFn: func(g *GomegaWithT, components map[v1alpha1.MemberType]v1alpha1.ComponentPlan) {
+				g.Expect(components).To(HaveLen(3))
+				g.Expect(components[v1alpha1.PDMemberType].Actions).To(Equal([]v1alpha1.PlanAction{
+					{Type: v1alpha1.PlanActionCreate, Pods: []string{"test-pd-0", "test-pd-1", "test-pd-2"}},
+				}))
+				g.Expect(components[v1alpha1.TiKVMemberType].Actions[0].Type).To(Equal(v1alpha1.PlanActionCreate))
+			},
+		},
+		{
+			name:     "nothing changed",
+			existing: addSets,
+			expectFn: func(g *GomegaWithT, components map[v1alpha1.MemberType]v1alpha1.ComponentPlan) {
+				g.Expect(components).To(BeEmpty())
+			},
+		},
+		{
+			name:     "scale out and upgrade pd",
+			existing: addSets,
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.PD.Replicas = 4
+				tc.Spec.PD.Image = "pd-test-image-2"
+			},
+			expectFn: func(g *GomegaWithT, components map[v1alpha1.MemberType]v1alpha1.ComponentPlan) {
+				g.Expect(components).To(HaveLen(1))
+				g.Expect(components[v1alpha1.PDMemberType].Actions).To(Equal([]v1alpha1.PlanAction{
+					{Type: v1alpha1.PlanActionScaleOut, Pods: []string{"test-pd-3"}},
+					{Type: v1alpha1.PlanActionRestart, Pods: []string{"test-pd-0", "test-pd-1", "test-pd-2"}},
+				}))
+			},
+		},
+		{
+			name:     "scale in tikv",
+			existing: addSets,
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiKV.Replicas = 2
+			},
+			expectFn: func(g *GomegaWithT, components map[v1alpha1.MemberType]v1alpha1.ComponentPlan) {
+				g.Expect(components).To(HaveLen(1))
+				g.Expect(components[v1alpha1.TiKVMemberType].Actions).To(Equal([]v1alpha1.PlanAction{
+					{Type: v1alpha1.PlanActionScaleIn, Pods: []string{"test-tikv-2"}},
+				}))
+			},
+		},
+		{
+			name:     "reconfigure tikv online",
+			existing: addSets,
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiKV.Config.Set("raftstore.sync-log", false)
+			},
+			expectFn: func(g *GomegaWithT, components map[v1alpha1.MemberType]v1alpha1.ComponentPlan) {
+				g.Expect(components).To(HaveLen(1))
+				g.Expect(components[v1alpha1.TiKVMemberType].Actions).To(Equal([]v1alpha1.PlanAction{
+					{
+						Type:       v1alpha1.PlanActionReconfigure,
+						Pods:       []string{"test-tikv-0", "test-tikv-1", "test-tikv-2"},
+						ConfigKeys: []string{"raftstore.sync-log"},
+					},
+				}))
+			},
+		},
+		{
+			name:     "reconfigure tikv with restart",
+			existing: addSets,
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiKV.Config.Set("storage.reserve-space", "1GB")
+			},
+			expectFn: func(g *GomegaWithT, components map[v1alpha1.MemberType]v1alpha1.ComponentPlan) {
+				g.Expect(components).To(HaveLen(1))
+				g.Expect(components[v1alpha1.TiKVMemberType].Actions).To(Equal([]v1alpha1.PlanAction{
+					{
+						Type:       v1alpha1.PlanActionRestart,
+						Pods:       []string{"test-tikv-0", "test-tikv-1", "test-tikv-2"},
+						ConfigKeys: []string{"storage.reserve-space"},
+					},
+				}))
+			},
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i])
+	}
+}
+
+func TestTidbClusterPlannerGeneratedTime(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deps := controller.NewFakeDependencies()
+	tc := newTidbClusterForPD()
+	planner := NewTidbClusterPlanner(deps)
+
+	plan, err := planner.Plan(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(plan.GeneratedTime.IsZero()).To(BeFalse())
+
+	// the generated time is kept if the plan is unchanged
+	generatedTime := metav1.NewTime(plan.GeneratedTime.Add(-time.Hour))
+	plan.GeneratedTime = generatedTime
+	tc.Status.Plan = plan
+	plan, err = planner.Plan(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(plan.GeneratedTime).To(Equal(generatedTime))
+
+	tc.Spec.PD.Replicas = 5
+	plan, err = planner.Plan(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(plan.GeneratedTime).NotTo(Equal(generatedTime))
+}
@@ -0,0 +1,276 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"strconv"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+	"k8s.io/utils/pointer"
+)
+
+// TidbClusterSuspender scales the components of a TidbCluster to zero when
+// spec.suspend is set and scales them back when it is unset
+type TidbClusterSuspender interface {
+	// Sync suspends or resumes the components of the tidb cluster, it returns
+	// true if the member managers should not sync the tidb cluster
+	Sync(tc *v1alpha1.TidbCluster) (bool, error)
+}
+
+type tidbClusterSuspender struct {
+	deps *controller.Dependencies
+}
+
+// NewTidbClusterSuspender returns a TidbClusterSuspender
+func NewTidbClusterSuspender(deps *controller.Dependencies) TidbClusterSuspender {
+	return &tidbClusterSuspender{deps: deps}
+}
+
+// suspendComponent is the statefulsets of a component, the statefulsets of
+// the TiKV and TiDB groups belong to TiKV and TiDB
+type suspendComponent struct {
+	memberType v1alpha1.MemberType
+	sets       []suspendSet
+}
+
+type suspendSet struct {
+	name string
+	// replicas is used when the replicas before suspended are not recorded
+	replicas int32
+}
+
+func (s *tidbClusterSuspender) Sync(tc *v1alpha1.TidbCluster) (bool, error) {
+	if tc.Spec.Suspend {
+		return true, s.suspend(tc)
+	}
+	if tc.Status.Suspend == nil {
+		return false, nil
+	}
+	if err := s.resume(tc); err != nil {
+		return true, err
+	}
+	return false, nil
+}
+
+// suspend scales the components to zero in the reverse order of the dependencies,
+// a component is scaled only after the components depending on it are gone
+func (s *tidbClusterSuspender) suspend(tc *v1alpha1.TidbCluster) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	components := suspendComponents(tc)
+	for i := len(components) - 1; i >= 0; i-- {
+		component := components[i]
+		setSuspendPhase(tc, v1alpha1.SuspendPhaseSuspending, component.memberType)
+
+		done := true
+		for _, target := range component.sets {
+			set, err := s.deps.StatefulSetLister.StatefulSets(ns).Get(target.name)
+			if errors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			if set.Spec.Replicas == nil || *set.Spec.Replicas != 0 {
+				newSet := set.DeepCopy()
+				if newSet.Annotations == nil {
+					newSet.Annotations = map[string]string{}
+				}
+				if _, ok := newSet.Annotations[label.AnnSuspendedReplicas]; !ok {
+					replicas := int32(1)
+					if set.Spec.Replicas != nil {
+						replicas = *set.Spec.Replicas
+					}
+					newSet.Annotations[label.AnnSuspendedReplicas] = strconv.Itoa(int(replicas))
+				}
+				newSet.Spec.Replicas = pointer.Int32Ptr(0)
+				klog.Infof("tidbcluster %s/%s is suspended, scale statefulset %s to 0", ns, tcName, target.name)
+				if _, err := s.deps.StatefulSetControl.UpdateStatefulSet(tc, newSet); err != nil {
+					return err
+				}
+				done = false
+				continue
+			}
+			if set.Status.Replicas != 0 {
+				done = false
+			}
+		}
+		if !done {
+			return controller.RequeueErrorf("tidbcluster: [%s/%s], waiting for %s to be suspended", ns, tcName, component.memberType)
+		}
+	}
+
+	setSuspendPhase(tc, v1alpha1.SuspendPhaseSuspended, "")
+	return nil
+}
+
+// resume scales the components back in the order of the dependencies,
+// a component is scaled only after the components it depends on are ready
+func (s *tidbClusterSuspender) resume(tc *v1alpha1.TidbCluster) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	for _, component := range suspendComponents(tc) {
+		setSuspendPhase(tc, v1alpha1.SuspendPhaseResuming, component.memberType)
+
+		done := true
+		for _, target := range component.sets {
+			set, err := s.deps.StatefulSetLister.StatefulSets(ns).Get(target.name)
+			if errors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			// the annotation may be lost when the update of the statefulset is
+			// retried on conflict, the desired replicas are used in that case
+			val, ok := set.Annotations[label.AnnSuspendedReplicas]
+			if ok || (set.Spec.Replicas != nil && *set.Spec.Replicas == 0 && target.replicas > 0) {
+				replicas := target.replicas
+				if ok {
+					if r, err := strconv.ParseInt(val, 10, 32); err == nil {
+						replicas = int32(r)
+					} else {
+						klog.Warningf("tidbcluster %s/%s, invalid annotation %s=%q of statefulset %s", ns, tcName, label.AnnSuspendedReplicas, val, target.name)
+					}
+				}
+				newSet := set.DeepCopy()
+				delete(newSet.Annotations, label.AnnSuspendedReplicas)
+				newSet.Spec.Replicas = pointer.Int32Ptr(replicas)
+				klog.Infof("tidbcluster %s/%s is resumed, scale statefulset %s to %d", ns, tcName, target.name, replicas)
+				if _, err := s.deps.StatefulSetControl.UpdateStatefulSet(tc, newSet); err != nil {
+					return err
+				}
+				done = false
+				continue
+			}
+			if set.Spec.Replicas != nil && set.Status.ReadyReplicas < *set.Spec.Replicas {
+				done = false
+			}
+		}
+		if !done {
+			return controller.RequeueErrorf("tidbcluster: [%s/%s], waiting for %s to be resumed", ns, tcName, component.memberType)
+		}
+	}
+
+	tc.Status.Suspend = nil
+	return nil
+}
+
+// suspendComponents returns the components of the tidb cluster in the order
+// of the dependencies
+func suspendComponents(tc *v1alpha1.TidbCluster) []suspendComponent {
+	var components []suspendComponent
+	if tc.Spec.PD != nil {
+		components = append(components, suspendComponent{
+			memberType: v1alpha1.PDMemberType,
+			sets:       []suspendSet{{name: controller.PDMemberName(tc.Name), replicas: tc.PDStsDesiredReplicas()}},
+		})
+	}
+
+	tikv := suspendComponent{memberType: v1alpha1.TiKVMemberType}
+	if tc.Spec.TiKV != nil {
+		tikv.sets = append(tikv.sets, suspendSet{name: tikvMemberName(tc), replicas: tc.TiKVStsDesiredReplicas()})
+	}
+	for i := range tc.Spec.TiKVGroups {
+		gtc := tc.TiKVGroupCluster(&tc.Spec.TiKVGroups[i])
+		tikv.sets = append(tikv.sets, suspendSet{name: tikvMemberName(gtc), replicas: gtc.TiKVStsDesiredReplicas()})
+	}
+	if len(tikv.sets) > 0 {
+		components = append(components, tikv)
+	}
+
+	if tc.Spec.TiFlash != nil {
+		components = append(components, suspendComponent{
+			memberType: v1alpha1.TiFlashMemberType,
+			sets:       []suspendSet{{name: controller.TiFlashMemberName(tc.Name), replicas: tc.TiFlashStsDesiredReplicas()}},
+		})
+	}
+	if tc.Spec.Pump != nil {
+		components = append(components, suspendComponent{
+			memberType: v1alpha1.PumpMemberType,
+			sets:       []suspendSet{{name: controller.PumpMemberName(tc.Name), replicas: tc.Spec.Pump.Replicas}},
+		})
+	}
+	if tc.Spec.Drainer != nil {
+		components = append(components, suspendComponent{
+			memberType: v1alpha1.DrainerMemberType,
+			sets:       []suspendSet{{name: controller.DrainerMemberName(tc.Name), replicas: tc.Spec.Drainer.Replicas}},
+		})
+	}
+
+	tidb := suspendComponent{memberType: v1alpha1.TiDBMemberType}
+	if tc.Spec.TiDB != nil {
+		tidb.sets = append(tidb.sets, suspendSet{name: tidbMemberName(tc), replicas: tc.TiDBStsDesiredReplicas()})
+	}
+	for i := range tc.Spec.TiDBGroups {
+		gtc := tc.TiDBGroupCluster(&tc.Spec.TiDBGroups[i])
+		tidb.sets = append(tidb.sets, suspendSet{name: tidbMemberName(gtc), replicas: gtc.TiDBStsDesiredReplicas()})
+	}
+	if len(tidb.sets) > 0 {
+		components = append(components, tidb)
+	}
+
+	if tc.Spec.TiCDC != nil {
+		components = append(components, suspendComponent{
+			memberType: v1alpha1.TiCDCMemberType,
+			sets:       []suspendSet{{name: controller.TiCDCMemberName(tc.Name), replicas: tc.Spec.TiCDC.Replicas}},
+		})
+	}
+	return components
+}
+
+func setSuspendPhase(tc *v1alpha1.TidbCluster, phase v1alpha1.SuspendPhase, memberType v1alpha1.MemberType) {
+	if tc.Status.Suspend != nil && tc.Status.Suspend.Phase == phase && tc.Status.Suspend.Component == memberType {
+		return
+	}
+	tc.Status.Suspend = &v1alpha1.SuspendStatus{
+		Phase:              phase,
+		Component:          memberType,
+		LastTransitionTime: metav1.Now(),
+	}
+}
+
+// FakeTidbClusterSuspender is a fake implementation of TidbClusterSuspender
+type FakeTidbClusterSuspender struct {
+	suspended bool
+	err       error
+}
+
+// NewFakeTidbClusterSuspender returns a FakeTidbClusterSuspender
+func NewFakeTidbClusterSuspender() *FakeTidbClusterSuspender {
+	return &FakeTidbClusterSuspender{}
+}
+
+// SetSuspended sets whether the member managers should be skipped
+func (s *FakeTidbClusterSuspender) SetSuspended(suspended bool) {
+	s.suspended = suspended
+}
+
+// SetSyncError sets the error returned by Sync
+func (s *FakeTidbClusterSuspender) SetSyncError(err error) {
+	s.err = err
+}
+
+func (s *FakeTidbClusterSuspender) Sync(_ *v1alpha1.TidbCluster) (bool, error) {
+	return s.suspended, s.err
+}
@@ -0,0 +1,201 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	apps "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestTidbClusterSuspenderSync(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deps := controller.NewFakeDependencies()
+	setIndexer := deps.KubeInformerFactory.Apps().V1().StatefulSets().Informer().GetIndexer()
+	suspender := NewTidbClusterSuspender(deps)
+
+	tc := newTidbClusterForPD()
+	setNames := map[v1alpha1.MemberType]string{
+		v1alpha1.PDMemberType:   controller.PDMemberName(tc.Name),
+		v1alpha1.TiKVMemberType: controller.TiKVMemberName(tc.Name),
+		v1alpha1.TiDBMemberType: controller.TiDBMemberName(tc.Name),
+	}
+	for _, name := range setNames {
+		setIndexer.Add(&apps.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: tc.Namespace},
+			Spec:       apps.StatefulSetSpec{Replicas: pointer.Int32Ptr(3)},
+			Status:     apps.StatefulSetStatus{Replicas: 3, ReadyReplicas: 3},
+		})
+	}
+	getSet := func(memberType v1alpha1.MemberType) *apps.StatefulSet {
+		set, err := deps.StatefulSetLister.StatefulSets(tc.Namespace).Get(setNames[memberType])
+		g.Expect(err).NotTo(HaveOccurred())
+		return set
+	}
+	setStatus := func(memberType v1alpha1.MemberType, replicas int32) {
+		set := getSet(memberType).DeepCopy()
+		set.Status.Replicas = replicas
+		set.Status.ReadyReplicas = replicas
+		setIndexer.Update(set)
+	}
+
+	t.Log("not suspended")
+	skip, err := suspender.Sync(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(skip).To(BeFalse())
+	g.Expect(tc.Status.Suspend).To(BeNil())
+
+	t.Log("suspend tidb first")
+	tc.Spec.Suspend = true
+	skip, err = suspender.Sync(tc)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(skip).To(BeTrue())
+	g.Expect(tc.Status.Suspend.Phase).To(Equal(v1alpha1.SuspendPhaseSuspending))
+	g.Expect(tc.Status.Suspend.Component).To(Equal(v1alpha1.TiDBMemberType))
+	g.Expect(*getSet(v1alpha1.TiDBMemberType).Spec.Replicas).To(Equal(int32(0)))
+	g.Expect(getSet(v1alpha1.TiDBMemberType).Annotations[label.AnnSuspendedReplicas]).To(Equal("3"))
+	g.Expect(*getSet(v1alpha1.TiKVMemberType).Spec.Replicas).To(Equal(int32(3)))
+
+	t.Log("wait for the tidb pods to be deleted")
+	_, err = suspender.Sync(tc)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(*getSet(v1alpha1.TiKVMemberType).Spec.Replicas).To(Equal(int32(3)))
+
+	t.Log("suspend tikv after tidb")
+	setStatus(v1alpha1.TiDBMemberType, 0)
+	_, err = suspender.Sync(tc)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(tc.Status.Suspend.Component).To(Equal(v1alpha1.TiKVMemberType))
+	g.Expect(*getSet(v1alpha1.TiKVMemberType).Spec.Replicas).To(Equal(int32(0)))
+	g.Expect(*getSet(v1alpha1.PDMemberType).Spec.Replicas).To(Equal(int32(3)))
+
+	t.Log("suspended")
+	setStatus(v1alpha1.TiKVMemberType, 0)
+	_, err = suspender.Sync(tc)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	setStatus(v1alpha1.PDMemberType, 0)
+	skip, err = suspender.Sync(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(skip).To(BeTrue())
+	g.Expect(tc.Status.Suspend.Phase).To(Equal(v1alpha1.SuspendPhaseSuspended))
+
+	t.Log("resume pd first")
+	tc.Spec.Suspend = false
+	skip, err = suspender.Sync(tc)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(skip).To(BeTrue())
+	g.Expect(tc.Status.Suspend.Phase).To(Equal(v1alpha1.SuspendPhaseResuming))
+	g.Expect(tc.Status.Suspend.Component).To(Equal(v1alpha1.PDMemberType))
+	g.Expect(*getSet(v1alpha1.PDMemberType).Spec.Replicas).To(Equal(int32(3)))
+	g.Expect(getSet(v1alpha1.PDMemberType).Annotations).NotTo(HaveKey(label.AnnSuspendedReplicas))
+	g.Expect(*getSet(v1alpha1.TiKVMemberType).Spec.Replicas).To(Equal(int32(0)))
+
+	t.Log("resume tikv after pd is ready")
+	setStatus(v1alpha1.PDMemberType, 3)
+	_, err = suspender.Sync(tc)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(tc.Status.Suspend.Component).To(Equal(v1alpha1.TiKVMemberType))
+	g.Expect(*getSet(v1alpha1.TiKVMemberType).Spec.Replicas).To(Equal(int32(3)))
+	g.Expect(*getSet(v1alpha1.TiDBMemberType).Spec.Replicas).To(Equal(int32(0)))
+
+	t.Log("resume tidb with the desired replicas if the annotation is lost")
+	setStatus(v1alpha1.TiKVMemberType, 3)
+	tidbSet := getSet(v1alpha1.TiDBMemberType).DeepCopy()
+	tidbSet.Annotations = nil
+	setIndexer.Update(tidbSet)
+	tc.Spec.TiDB.Replicas = 2
+	_, err = suspender.Sync(tc)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(*getSet(v1alpha1.TiDBMemberType).Spec.Replicas).To(Equal(int32(2)))
+
+	t.Log("resumed")
+	setStatus(v1alpha1.TiDBMemberType, 2)
+	skip, err = suspender.Sync(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(skip).To(BeFalse())
+	g.Expect(tc.Status.Suspend).To(BeNil())
+}
+
+func TestTidbClusterSuspenderSyncDrainer(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deps := controller.NewFakeDependencies()
+	setIndexer := deps.KubeInformerFactory.Apps().V1().StatefulSets().Informer().GetIndexer()
+	suspender := NewTidbClusterSuspender(deps)
+
+	tc := newTidbClusterForPD()
+	tc.Spec.PD = nil
+	tc.Spec.TiKV = nil
+	tc.Spec.TiDB = nil
+	tc.Spec.Pump = &v1alpha1.PumpSpec{Replicas: 2}
+	tc.Spec.Drainer = &v1alpha1.DrainerSpec{Replicas: 1}
+	setNames := map[v1alpha1.MemberType]string{
+		v1alpha1.PumpMemberType:    controller.PumpMemberName(tc.Name),
+		v1alpha1.DrainerMemberType: controller.DrainerMemberName(tc.Name),
+	}
+	for _, name := range setNames {
+		setIndexer.Add(&apps.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: tc.Namespace},
+			Spec:       apps.StatefulSetSpec{Replicas: pointer.Int32Ptr(1)},
+			Status:     apps.StatefulSetStatus{Replicas: 1, ReadyReplicas: 1},
+		})
+	}
+	getSet := func(memberType v1alpha1.MemberType) *apps.StatefulSet {
+		set, err := deps.StatefulSetLister.StatefulSets(tc.Namespace).Get(setNames[memberType])
+		g.Expect(err).NotTo(HaveOccurred())
+		return set
+	}
+	setStatus := func(memberType v1alpha1.MemberType, replicas int32) {
+		set := getSet(memberType).DeepCopy()
+		set.Status.Replicas = replicas
+		set.Status.ReadyReplicas = replicas
+		setIndexer.Update(set)
+	}
+
+	t.Log("suspend drainer before pump")
+	tc.Spec.Suspend = true
+	_, err := suspender.Sync(tc)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(tc.Status.Suspend.Component).To(Equal(v1alpha1.DrainerMemberType))
+	g.Expect(*getSet(v1alpha1.DrainerMemberType).Spec.Replicas).To(Equal(int32(0)))
+	g.Expect(*getSet(v1alpha1.PumpMemberType).Spec.Replicas).To(Equal(int32(1)))
+
+	setStatus(v1alpha1.DrainerMemberType, 0)
+	_, err = suspender.Sync(tc)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(tc.Status.Suspend.Component).To(Equal(v1alpha1.PumpMemberType))
+	g.Expect(*getSet(v1alpha1.PumpMemberType).Spec.Replicas).To(Equal(int32(0)))
+
+	t.Log("resume drainer after pump")
+	setStatus(v1alpha1.PumpMemberType, 0)
+	tc.Spec.Suspend = false
+	_, err = suspender.Sync(tc)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(tc.Status.Suspend.Component).To(Equal(v1alpha1.PumpMemberType))
+	g.Expect(*getSet(v1alpha1.PumpMemberType).Spec.Replicas).To(Equal(int32(1)))
+	g.Expect(*getSet(v1alpha1.DrainerMemberType).Spec.Replicas).To(Equal(int32(0)))
+
+	setStatus(v1alpha1.PumpMemberType, 1)
+	_, err = suspender.Sync(tc)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(tc.Status.Suspend.Component).To(Equal(v1alpha1.DrainerMemberType))
+	g.Expect(*getSet(v1alpha1.DrainerMemberType).Spec.Replicas).To(Equal(int32(1)))
+}
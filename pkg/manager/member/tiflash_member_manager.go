@@ -203,6 +203,10 @@ func (m *tiflashMemberManager) syncStatefulSet(tc *v1alpha1.TidbCluster) error {
 		return nil
 	}
 
+	if err := recreateStatefulSetForStorage(m.deps, tc, newSet, oldSet); err != nil {
+		return err
+	}
+
 	if _, err := m.setStoreLabelsForTiFlash(tc); err != nil {
 		return err
 	}
@@ -416,6 +420,7 @@ func getNewStatefulSet(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (*apps.St
 	setName := controller.TiFlashMemberName(tcName)
 	podAnnotations := CombineAnnotations(controller.AnnProm(8234), baseTiFlashSpec.Annotations())
 	podAnnotations = CombineAnnotations(controller.AnnAdditionalProm("tiflash.proxy", 20292), podAnnotations)
+	setRestartedAtAnnotations(tc, v1alpha1.TiFlashMemberType, podAnnotations)
 	stsAnnotations := getStsAnnotations(tc.Annotations, label.TiFlashLabelVal)
 	capacity := controller.TiKVCapacity(tc.Spec.TiFlash.Limits)
 	headlessSvcName := controller.TiFlashPeerMemberName(tcName)
@@ -689,6 +694,7 @@ func (m *tiflashMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, s
 	tc.Status.TiFlash.Stores = stores
 	tc.Status.TiFlash.PeerStores = peerStores
 	tc.Status.TiFlash.TombstoneStores = tombstoneStores
+	tc.Status.TiFlash.TotalCapacity, tc.Status.TiFlash.UsedCapacity, tc.Status.TiFlash.AvailableCapacity = storesCapacity(stores)
 	tc.Status.TiFlash.Image = ""
 	c := filterContainer(set, "tiflash")
 	if c != nil {
@@ -712,6 +718,11 @@ func (m *tiflashMemberManager) getTiFlashStore(store *pdapi.StoreInfo) *v1alpha1
 		LeaderCount:       int32(store.Status.LeaderCount),
 		State:             store.Store.StateName,
 		LastHeartbeatTime: metav1.Time{Time: store.Status.LastHeartbeatTS},
+		RegionCount:       int32(store.Status.RegionCount),
+		Capacity:          storeSizeQuantity(store.Status.Capacity),
+		Available:         storeSizeQuantity(store.Status.Available),
+		UsedSize:          storeSizeQuantity(store.Status.UsedSize),
+		Labels:            storeLabels(store.Store.GetLabels()),
 	}
 }
 
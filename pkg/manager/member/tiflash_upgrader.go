@@ -32,7 +32,7 @@ func NewTiFlashUpgrader(deps *controller.Dependencies) Upgrader {
 
 func (tku *tiflashUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
 	//  Wait for PD, TiKV and TiDB to finish upgrade
-	if tc.Status.PD.Phase == v1alpha1.UpgradePhase || tc.Status.TiKV.Phase == v1alpha1.UpgradePhase ||
+	if tc.Status.PD.Phase == v1alpha1.UpgradePhase || tc.TiKVUpgrading() ||
 		tc.Status.TiDB.Phase == v1alpha1.UpgradePhase {
 		_, podSpec, err := GetLastAppliedConfig(oldSet)
 		if err != nil {
@@ -41,7 +41,8 @@ func (tku *tiflashUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.State
 		newSet.Spec.Template.Spec = *podSpec
 		return nil
 	}
-	return nil
+	_, err := waitForMaintenanceWindow(tc, oldSet, newSet)
+	return err
 }
 
 type fakeTiFlashUpgrader struct{}
@@ -15,10 +15,13 @@ package member
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/metrics"
 	"github.com/pingcap/tidb-operator/pkg/util"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -84,10 +87,75 @@ func (f *tikvFailover) Failover(tc *v1alpha1.TidbCluster) error {
 				}
 				msg := fmt.Sprintf("store[%s] is Down", store.ID)
 				f.deps.Recorder.Event(tc, corev1.EventTypeWarning, unHealthEventReason, fmt.Sprintf(unHealthEventMsgPattern, "tikv", podName, msg))
+				metrics.ObserveFailover(ns, tcName, "tikv")
 			}
 		}
 	}
 
+	if tc.TiKVFailoverMode() == v1alpha1.TiKVFailoverModeReplaceInPlace {
+		return f.tryToReplaceFailureStores(tc)
+	}
+	return nil
+}
+
+// tryToReplaceFailureStores deletes the failure stores through PD, and once
+// they become Tombstone, deletes the pods and PVCs to let the StatefulSet
+// recreate the same ordinals on fresh storage. Only one store is replaced
+// at a time, the others wait until the one being replaced is done.
+func (f *tikvFailover) tryToReplaceFailureStores(tc *v1alpha1.TidbCluster) error {
+	if !tc.Status.TiKV.Synced {
+		return fmt.Errorf("tikv failover: TidbCluster %s/%s tikv status is not synced, can't replace failure stores", tc.GetNamespace(), tc.GetName())
+	}
+	keys := make([]string, 0, len(tc.Status.TiKV.FailureStores))
+	for key, failureStore := range tc.Status.TiKV.FailureStores {
+		if failureStore.StoreDeleted {
+			// continue the replacement in progress
+			return f.tryToReplaceFailureStore(tc, key, failureStore)
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	sort.Strings(keys)
+	return f.tryToReplaceFailureStore(tc, keys[0], tc.Status.TiKV.FailureStores[keys[0]])
+}
+
+func (f *tikvFailover) tryToReplaceFailureStore(tc *v1alpha1.TidbCluster, key string, failureStore v1alpha1.TiKVFailureStore) error {
+	ns := tc.GetNamespace()
+	podName := failureStore.PodName
+
+	if !failureStore.StoreDeleted {
+		storeID, err := strconv.ParseUint(failureStore.StoreID, 10, 64)
+		if err != nil {
+			return err
+		}
+		if err := controller.GetPDClient(f.deps.PDControl, tc).DeleteStore(storeID); err != nil {
+			klog.Errorf("tikv failover: failed to delete store %d of pod %s/%s, %v", storeID, ns, podName, err)
+			return err
+		}
+		failureStore.StoreDeleted = true
+		tc.Status.TiKV.FailureStores[key] = failureStore
+		klog.Infof("tikv failover: delete store %d of pod %s/%s successfully", storeID, ns, podName)
+		f.deps.Recorder.Eventf(tc, corev1.EventTypeWarning, "TiKVStoreDeleted", "%s(%d) deleted from cluster", podName, storeID)
+		return nil
+	}
+
+	// the store is still Offline, wait for PD to move its regions away
+	if _, exist := tc.Status.TiKV.Stores[failureStore.StoreID]; exist {
+		klog.Infof("tikv failover: store %s of pod %s/%s is not Tombstone yet", failureStore.StoreID, ns, podName)
+		return nil
+	}
+
+	replaced, err := replacePodWithFreshStorage(f.deps, tc, tikvMemberName(tc), podName, failureStore.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("tryToReplaceFailureStore: %v", err)
+	}
+	if replaced {
+		delete(tc.Status.TiKV.FailureStores, key)
+		klog.Infof("tikv failover: pod %s/%s is replaced with fresh storage", ns, podName)
+		f.deps.Recorder.Eventf(tc, corev1.EventTypeNormal, "TiKVStoreReplaced", "%s replaced with fresh storage", podName)
+	}
 	return nil
 }
 
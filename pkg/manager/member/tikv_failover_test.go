@@ -20,6 +20,9 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 )
@@ -302,3 +305,214 @@ func TestTiKVFailoverFailover(t *testing.T) {
 		})
 	}
 }
+
+func TestTiKVFailoverReplaceInPlace(t *testing.T) {
+	failureTime := metav1.Time{Time: time.Now().Add(-10 * time.Minute)}
+	oldTime := metav1.Time{Time: failureTime.Add(-time.Hour)}
+	newTime := metav1.Time{Time: failureTime.Add(time.Minute)}
+
+	tests := []struct {
+		name     string
+		update   func(*v1alpha1.TidbCluster)
+		podTime  *metav1.Time
+		pvcTime  *metav1.Time
+		err      bool
+		expectFn func(g *GomegaWithT, tc *v1alpha1.TidbCluster, storeDeleted bool, podExist, pvcExist bool)
+	}{
+		{
+			name: "mark failure store and delete it through PD",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+					"1": {
+						ID:                 "1",
+						State:              v1alpha1.TiKVStateDown,
+						PodName:            "test-tikv-1",
+						LastTransitionTime: metav1.Time{Time: time.Now().Add(-70 * time.Minute)},
+					},
+				}
+			},
+			podTime: &oldTime,
+			pvcTime: &oldTime,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, storeDeleted bool, podExist, pvcExist bool) {
+				g.Expect(len(tc.Status.TiKV.FailureStores)).To(Equal(1))
+				g.Expect(tc.Status.TiKV.FailureStores["1"].StoreDeleted).To(BeTrue())
+				g.Expect(storeDeleted).To(BeTrue())
+				g.Expect(podExist).To(BeTrue())
+				g.Expect(pvcExist).To(BeTrue())
+				g.Expect(tc.TiKVStsDesiredReplicas()).To(Equal(int32(3)))
+			},
+		},
+		{
+			name: "store is not tombstone yet",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+					"1": {ID: "1", State: v1alpha1.TiKVStateOffline, PodName: "test-tikv-1"},
+				}
+				tc.Status.TiKV.FailureStores = map[string]v1alpha1.TiKVFailureStore{
+					"1": {PodName: "test-tikv-1", StoreID: "1", CreatedAt: failureTime, StoreDeleted: true},
+				}
+			},
+			podTime: &oldTime,
+			pvcTime: &oldTime,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, storeDeleted bool, podExist, pvcExist bool) {
+				g.Expect(len(tc.Status.TiKV.FailureStores)).To(Equal(1))
+				g.Expect(storeDeleted).To(BeFalse())
+				g.Expect(podExist).To(BeTrue())
+				g.Expect(pvcExist).To(BeTrue())
+			},
+		},
+		{
+			name: "store is tombstone, delete pod and pvc",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiKV.TombstoneStores = map[string]v1alpha1.TiKVStore{
+					"1": {ID: "1", State: v1alpha1.TiKVStateTombstone, PodName: "test-tikv-1"},
+				}
+				tc.Status.TiKV.FailureStores = map[string]v1alpha1.TiKVFailureStore{
+					"1": {PodName: "test-tikv-1", StoreID: "1", CreatedAt: failureTime, StoreDeleted: true},
+				}
+			},
+			podTime: &oldTime,
+			pvcTime: &oldTime,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, storeDeleted bool, podExist, pvcExist bool) {
+				g.Expect(len(tc.Status.TiKV.FailureStores)).To(Equal(1))
+				g.Expect(podExist).To(BeFalse())
+				g.Expect(pvcExist).To(BeFalse())
+			},
+		},
+		{
+			name: "new pod reuses old pvc",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiKV.FailureStores = map[string]v1alpha1.TiKVFailureStore{
+					"1": {PodName: "test-tikv-1", StoreID: "1", CreatedAt: failureTime, StoreDeleted: true},
+				}
+			},
+			podTime: &newTime,
+			pvcTime: &oldTime,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, storeDeleted bool, podExist, pvcExist bool) {
+				g.Expect(len(tc.Status.TiKV.FailureStores)).To(Equal(1))
+				g.Expect(podExist).To(BeFalse())
+				g.Expect(pvcExist).To(BeFalse())
+			},
+		},
+		{
+			name: "pod is recreated on fresh storage",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiKV.FailureStores = map[string]v1alpha1.TiKVFailureStore{
+					"1": {PodName: "test-tikv-1", StoreID: "1", CreatedAt: failureTime, StoreDeleted: true},
+				}
+			},
+			podTime: &newTime,
+			pvcTime: &newTime,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, storeDeleted bool, podExist, pvcExist bool) {
+				g.Expect(len(tc.Status.TiKV.FailureStores)).To(Equal(0))
+				g.Expect(podExist).To(BeTrue())
+				g.Expect(pvcExist).To(BeTrue())
+			},
+		},
+		{
+			name: "replace one failure store at a time",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiKV.FailureStores = map[string]v1alpha1.TiKVFailureStore{
+					"1": {PodName: "test-tikv-1", StoreID: "1", CreatedAt: failureTime},
+					"2": {PodName: "test-tikv-2", StoreID: "2", CreatedAt: failureTime},
+				}
+			},
+			podTime: &oldTime,
+			pvcTime: &oldTime,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, storeDeleted bool, podExist, pvcExist bool) {
+				g.Expect(storeDeleted).To(BeTrue())
+				g.Expect(tc.Status.TiKV.FailureStores["1"].StoreDeleted).To(BeTrue())
+				g.Expect(tc.Status.TiKV.FailureStores["2"].StoreDeleted).To(BeFalse())
+			},
+		},
+		{
+			name: "wait for the failure store being replaced",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+					"2": {ID: "2", State: v1alpha1.TiKVStateOffline, PodName: "test-tikv-2"},
+				}
+				tc.Status.TiKV.FailureStores = map[string]v1alpha1.TiKVFailureStore{
+					"1": {PodName: "test-tikv-1", StoreID: "1", CreatedAt: failureTime},
+					"2": {PodName: "test-tikv-2", StoreID: "2", CreatedAt: failureTime, StoreDeleted: true},
+				}
+			},
+			podTime: &oldTime,
+			pvcTime: &oldTime,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, storeDeleted bool, podExist, pvcExist bool) {
+				g.Expect(storeDeleted).To(BeFalse())
+				g.Expect(tc.Status.TiKV.FailureStores["1"].StoreDeleted).To(BeFalse())
+				g.Expect(podExist).To(BeTrue())
+			},
+		},
+		{
+			name: "tikv status is not synced",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiKV.Synced = false
+				tc.Status.TiKV.FailureStores = map[string]v1alpha1.TiKVFailureStore{
+					"1": {PodName: "test-tikv-1", StoreID: "1", CreatedAt: failureTime},
+				}
+			},
+			err: true,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, storeDeleted bool, podExist, pvcExist bool) {
+				g.Expect(storeDeleted).To(BeFalse())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			tc := newTidbClusterForPD()
+			tc.Spec.TiKV.Replicas = 3
+			tc.Spec.TiKV.MaxFailoverCount = pointer.Int32Ptr(3)
+			tc.Spec.TiKV.FailoverMode = v1alpha1.TiKVFailoverModeReplaceInPlace
+			tc.Status.TiKV.Synced = true
+			tt.update(tc)
+
+			fakeDeps := controller.NewFakeDependencies()
+			fakeDeps.CLIConfig.TiKVFailoverPeriod = 1 * time.Hour
+			tikvFailover := &tikvFailover{deps: fakeDeps}
+
+			setName := controller.TiKVMemberName(tc.GetName())
+			set := &apps.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: setName, Namespace: tc.GetNamespace()},
+				Spec: apps.StatefulSetSpec{
+					VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+						{ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.TiKVMemberType.String()}},
+					},
+				},
+			}
+			fakeDeps.KubeInformerFactory.Apps().V1().StatefulSets().Informer().GetIndexer().Add(set)
+			podIndexer := fakeDeps.KubeInformerFactory.Core().V1().Pods().Informer().GetIndexer()
+			pvcIndexer := fakeDeps.KubeInformerFactory.Core().V1().PersistentVolumeClaims().Informer().GetIndexer()
+			podName := ordinalPodName(v1alpha1.TiKVMemberType, tc.GetName(), 1)
+			pvcName := ordinalPVCName(v1alpha1.TiKVMemberType, setName, 1)
+			if tt.podTime != nil {
+				podIndexer.Add(&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: podName, Namespace: tc.GetNamespace(), CreationTimestamp: *tt.podTime},
+				})
+			}
+			if tt.pvcTime != nil {
+				pvcIndexer.Add(&corev1.PersistentVolumeClaim{
+					ObjectMeta: metav1.ObjectMeta{Name: pvcName, Namespace: tc.GetNamespace(), CreationTimestamp: *tt.pvcTime},
+				})
+			}
+
+			storeDeleted := false
+			pdClient := controller.NewFakePDClient(fakeDeps.PDControl.(*pdapi.FakePDControl), tc)
+			pdClient.AddReaction(pdapi.DeleteStoreActionType, func(action *pdapi.Action) (interface{}, error) {
+				storeDeleted = true
+				return nil, nil
+			})
+
+			err := tikvFailover.Failover(tc)
+			if tt.err {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			_, podExist, _ := podIndexer.GetByKey(tc.GetNamespace() + "/" + podName)
+			_, pvcExist, _ := pvcIndexer.GetByKey(tc.GetNamespace() + "/" + pvcName)
+			tt.expectFn(g, tc, storeDeleted, podExist, pvcExist)
+		})
+	}
+}
@@ -28,10 +28,13 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/manager"
 	"github.com/pingcap/tidb-operator/pkg/pdapi"
 	"github.com/pingcap/tidb-operator/pkg/util"
+	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	corelisters "k8s.io/client-go/listers/core/v1"
@@ -47,7 +50,7 @@ const (
 	tikvClusterCertPath = "/var/lib/tikv-tls"
 
 	//find a better way to manage store only managed by tikv in Operator
-	tikvStoreLimitPattern = `%s-tikv-\d+\.%s-tikv-peer\.%s\.svc%s\:\d+`
+	tikvStoreLimitPattern = `%s-\d+\.%s-tikv-peer\.%s\.svc%s\:\d+`
 )
 
 // tikvMemberManager implements manager.Manager.
@@ -83,7 +86,7 @@ type SvcConfig struct {
 // Sync fulfills the manager.Manager interface
 func (m *tikvMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
 	// If tikv is not specified return
-	if tc.Spec.TiKV == nil {
+	if tc.Spec.TiKV == nil && len(tc.Spec.TiKVGroups) == 0 {
 		return nil
 	}
 
@@ -108,7 +111,71 @@ func (m *tikvMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
 			return err
 		}
 	}
-	return m.syncStatefulSetForTidbCluster(tc)
+	if tc.Spec.TiKV != nil {
+		upgradingGroup, upgrading := tikvUpgradingGroup(tc)
+		if err := m.syncStatefulSetForTidbCluster(tc, upgrading && upgradingGroup != ""); err != nil {
+			return err
+		}
+	}
+	return m.syncTiKVGroups(tc)
+}
+
+// tikvUpgradingGroup returns the group of the TiKV StatefulSet being upgraded,
+// the group is empty for the default TiKV. The upgrades of the TiKV StatefulSets
+// are serialized, otherwise the stores of several groups holding the peers of
+// the same region may be restarted at the same time. The default TiKV goes
+// first, then the groups in the order of spec.
+func tikvUpgradingGroup(tc *v1alpha1.TidbCluster) (string, bool) {
+	if tc.Spec.TiKV != nil && tc.Status.TiKV.Phase == v1alpha1.UpgradePhase {
+		return "", true
+	}
+	for _, group := range tc.Spec.TiKVGroups {
+		if tc.Status.TiKV.Groups[group.Name].Phase == v1alpha1.UpgradePhase {
+			return group.Name, true
+		}
+	}
+	return "", false
+}
+
+// syncTiKVGroups syncs the TiKV groups one by one, all the groups share the
+// peer service with the default TiKV
+func (m *tikvMemberManager) syncTiKVGroups(tc *v1alpha1.TidbCluster) error {
+	// drop the status of the groups removed from spec
+	groups := map[string]v1alpha1.TiKVStatus{}
+	for _, group := range tc.Spec.TiKVGroups {
+		if status, ok := tc.Status.TiKV.Groups[group.Name]; ok {
+			groups[group.Name] = status
+		}
+	}
+	tc.Status.TiKV.Groups = groups
+
+	var err error
+	for i := range tc.Spec.TiKVGroups {
+		group := &tc.Spec.TiKVGroups[i]
+		upgradingGroup, upgrading := tikvUpgradingGroup(tc)
+		gtc := tc.TiKVGroupCluster(group)
+		err = m.syncStatefulSetForTidbCluster(gtc, upgrading && upgradingGroup != group.Name)
+		groups[group.Name] = gtc.Status.TiKV
+		// the upgrade of the group may wait for the maintenance window
+		utiltidbcluster.MergeConditions(&tc.Status, gtc.Status.Conditions)
+		if err != nil {
+			break
+		}
+	}
+	if len(groups) > 0 {
+		// the capacity of the cluster includes the stores of the groups
+		stores := map[string]v1alpha1.TiKVStore{}
+		for id, store := range tc.Status.TiKV.Stores {
+			stores[id] = store
+		}
+		for _, status := range groups {
+			for id, store := range status.Stores {
+				stores[id] = store
+			}
+		}
+		tc.Status.TiKV.TotalCapacity, tc.Status.TiKV.UsedCapacity, tc.Status.TiKV.AvailableCapacity = storesCapacity(stores)
+	}
+	return err
 }
 
 func (m *tikvMemberManager) syncServiceForTidbCluster(tc *v1alpha1.TidbCluster, svcConfig SvcConfig) error {
@@ -155,13 +222,15 @@ func (m *tikvMemberManager) syncServiceForTidbCluster(tc *v1alpha1.TidbCluster,
 	return nil
 }
 
-func (m *tikvMemberManager) syncStatefulSetForTidbCluster(tc *v1alpha1.TidbCluster) error {
+// syncStatefulSetForTidbCluster syncs the TiKV StatefulSet of the group tc is
+// synced for, the upgrade is held if another TiKV StatefulSet is being upgraded
+func (m *tikvMemberManager) syncStatefulSetForTidbCluster(tc *v1alpha1.TidbCluster, upgradeHeld bool) error {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
 
-	oldSetTmp, err := m.deps.StatefulSetLister.StatefulSets(ns).Get(controller.TiKVMemberName(tcName))
+	oldSetTmp, err := m.deps.StatefulSetLister.StatefulSets(ns).Get(tikvMemberName(tc))
 	if err != nil && !errors.IsNotFound(err) {
-		return fmt.Errorf("syncStatefulSetForTidbCluster: failed to get sts %s for cluster %s/%s, error: %s", controller.TiKVMemberName(tcName), ns, tcName, err)
+		return fmt.Errorf("syncStatefulSetForTidbCluster: failed to get sts %s for cluster %s/%s, error: %s", tikvMemberName(tc), ns, tcName, err)
 	}
 	setNotExist := errors.IsNotFound(err)
 
@@ -187,6 +256,7 @@ func (m *tikvMemberManager) syncStatefulSetForTidbCluster(tc *v1alpha1.TidbClust
 	}
 	if len(tc.Status.TiKV.FailureStores) > 0 &&
 		tc.Spec.TiKV.RecoverFailover &&
+		tc.TiKVFailoverMode() == v1alpha1.TiKVFailoverModeAddReplica &&
 		shouldRecover(tc, label.TiKVLabelVal, m.deps.PodLister) {
 		m.failover.Recover(tc)
 	}
@@ -208,6 +278,10 @@ func (m *tikvMemberManager) syncStatefulSetForTidbCluster(tc *v1alpha1.TidbClust
 		return nil
 	}
 
+	if err := recreateStatefulSetForStorage(m.deps, tc, newSet, oldSet); err != nil {
+		return err
+	}
+
 	if _, err := m.setStoreLabelsForTiKV(tc); err != nil {
 		return err
 	}
@@ -224,8 +298,11 @@ func (m *tikvMemberManager) syncStatefulSetForTidbCluster(tc *v1alpha1.TidbClust
 	// Perform failover logic if necessary. Note that this will only update
 	// TidbCluster status. The actual scaling performs in next sync loop (if a
 	// new replica needs to be added).
+	// In ReplaceInPlace mode, the failure stores are replaced by deleting the
+	// pods, so failover must go on while the pods are being recreated.
 	if m.deps.CLIConfig.AutoFailover && tc.Spec.TiKV.MaxFailoverCount != nil {
-		if tc.TiKVAllPodsStarted() && !tc.TiKVAllStoresReady() {
+		replacing := tc.TiKVFailoverMode() == v1alpha1.TiKVFailoverModeReplaceInPlace && len(tc.Status.TiKV.FailureStores) > 0
+		if replacing || (tc.TiKVAllPodsStarted() && !tc.TiKVAllStoresReady()) {
 			if err := m.failover.Failover(tc); err != nil {
 				return err
 			}
@@ -233,7 +310,15 @@ func (m *tikvMemberManager) syncStatefulSetForTidbCluster(tc *v1alpha1.TidbClust
 	}
 
 	if !templateEqual(newSet, oldSet) || tc.Status.TiKV.Phase == v1alpha1.UpgradePhase {
-		if err := m.upgrader.Upgrade(tc, oldSet, newSet); err != nil {
+		if upgradeHeld {
+			klog.Infof("tikv statefulset %s/%s waits for the upgrade of another tikv statefulset", ns, oldSet.GetName())
+			_, podSpec, err := GetLastAppliedConfig(oldSet)
+			if err != nil {
+				return err
+			}
+			newSet.Spec.Template.Spec = *podSpec
+			newSet.Spec.UpdateStrategy = oldSet.Spec.UpdateStrategy
+		} else if err := m.upgrader.Upgrade(tc, oldSet, newSet); err != nil {
 			return err
 		}
 	}
@@ -254,10 +339,19 @@ func (m *tikvMemberManager) syncTiKVConfigMap(tc *v1alpha1.TidbCluster, set *app
 	var inUseName string
 	if set != nil {
 		inUseName = FindConfigMapVolume(&set.Spec.Template.Spec, func(name string) bool {
-			return strings.HasPrefix(name, controller.TiKVMemberName(tc.Name))
+			return strings.HasPrefix(name, tikvMemberName(tc))
 		})
 	}
 
+	if tc.BaseTiKVSpec().ConfigUpdateStrategy() == v1alpha1.ConfigUpdateStrategyInPlace {
+		tc.Status.TiKV.Config, err = applyConfigInPlace(m.deps.ConfigMapLister, set, inUseName, newCm, tc.Status.TiKV.Config, tikvOnlineConfig, func(items map[string]interface{}) error {
+			return m.updateTiKVConfig(tc, items)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	err = updateConfigMapIfNeed(m.deps.ConfigMapLister, tc.BaseTiKVSpec().ConfigUpdateStrategy(), inUseName, newCm)
 	if err != nil {
 		return nil, err
@@ -265,6 +359,24 @@ func (m *tikvMemberManager) syncTiKVConfigMap(tc *v1alpha1.TidbCluster, set *app
 	return m.deps.TypedControl.CreateOrUpdateConfigMap(tc, newCm)
 }
 
+// updateTiKVConfig updates the config items of the up stores online,
+// the other stores load the config from the ConfigMap when they restart
+func (m *tikvMemberManager) updateTiKVConfig(tc *v1alpha1.TidbCluster, items map[string]interface{}) error {
+	for _, store := range tc.Status.TiKV.Stores {
+		if store.State != v1alpha1.TiKVStateUp {
+			continue
+		}
+		ordinal, err := util.GetOrdinalFromPodName(store.PodName)
+		if err != nil {
+			return err
+		}
+		if err := m.deps.TiKVControl.UpdateConfig(tc, ordinal, items); err != nil {
+			return fmt.Errorf("update config of tikv %s failed: %v", store.PodName, err)
+		}
+	}
+	return nil
+}
+
 func getNewServiceForTidbCluster(tc *v1alpha1.TidbCluster, svcConfig SvcConfig) *corev1.Service {
 	ns := tc.Namespace
 	tcName := tc.Name
@@ -421,8 +533,10 @@ func getNewTiKVSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap)
 	}
 
 	tikvLabel := labelTiKV(tc)
-	setName := controller.TiKVMemberName(tcName)
+	setName := tikvMemberName(tc)
 	podAnnotations := CombineAnnotations(controller.AnnProm(20180), baseTiKVSpec.Annotations())
+	setRestartedAtAnnotations(tc, v1alpha1.TiKVMemberType, podAnnotations)
+	setConfigRestartAnnotation(podAnnotations, tc.Status.TiKV.Config)
 	stsAnnotations := getStsAnnotations(tc.Annotations, label.TiKVLabelVal)
 	capacity := controller.TiKVCapacity(tc.Spec.TiKV.Limits)
 	headlessSvcName := controller.TiKVPeerMemberName(tcName)
@@ -600,10 +714,9 @@ func getTikVConfigMap(tc *v1alpha1.TidbCluster) (*corev1.ConfigMap, error) {
 	if err != nil {
 		return nil, err
 	}
-	instanceName := tc.GetInstanceName()
-	tikvLabel := label.New().Instance(instanceName).TiKV().Labels()
+	tikvLabel := labelTiKV(tc).Labels()
 	cm.ObjectMeta = metav1.ObjectMeta{
-		Name:            controller.TiKVMemberName(tc.Name),
+		Name:            tikvMemberName(tc),
 		Namespace:       tc.Namespace,
 		Labels:          tikvLabel,
 		OwnerReferences: []metav1.OwnerReference{controller.GetOwnerRef(tc)},
@@ -612,9 +725,30 @@ func getTikVConfigMap(tc *v1alpha1.TidbCluster) (*corev1.ConfigMap, error) {
 	return cm, nil
 }
 
+// tikvDefaultGroupRequirement selects the TiKV pods and PVCs not of any group
+var tikvDefaultGroupRequirement = util.MustNewRequirement(label.TiKVGroupLabelKey, selection.DoesNotExist, nil)
+
+// tikvPodSelector returns the selector of the TiKV pods of the group tc is
+// synced for. The selector of the default TiKV StatefulSet matches the pods of
+// the groups too and is immutable, so the pods of the groups are excluded here.
+func tikvPodSelector(tc *v1alpha1.TidbCluster) (labels.Selector, error) {
+	selector, err := labelTiKV(tc).Selector()
+	if err != nil {
+		return nil, err
+	}
+	if tc.TiKVGroupName() == "" {
+		selector = selector.Add(*tikvDefaultGroupRequirement)
+	}
+	return selector, nil
+}
+
 func labelTiKV(tc *v1alpha1.TidbCluster) label.Label {
 	instanceName := tc.GetInstanceName()
-	return label.New().Instance(instanceName).TiKV()
+	l := label.New().Instance(instanceName).TiKV()
+	if group := tc.TiKVGroupName(); group != "" {
+		l = l.TiKVGroup(group)
+	}
+	return l
 }
 
 func (m *tikvMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, set *apps.StatefulSet) error {
@@ -623,6 +757,7 @@ func (m *tikvMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, set
 		return nil
 	}
 	tc.Status.TiKV.StatefulSet = &set.Status
+	syncConfigRestartPending(tc.Status.TiKV.Config, set)
 	upgrading, err := m.statefulSetIsUpgradingFn(m.deps.PodLister, m.deps.PDControl, set, tc)
 	if err != nil {
 		return err
@@ -650,7 +785,7 @@ func (m *tikvMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, set
 		return err
 	}
 
-	pattern, err := regexp.Compile(fmt.Sprintf(tikvStoreLimitPattern, tc.Name, tc.Name, tc.Namespace, controller.FormatClusterDomainForRegex(tc.Spec.ClusterDomain)))
+	pattern, err := regexp.Compile(fmt.Sprintf(tikvStoreLimitPattern, tikvMemberName(tc), tc.Name, tc.Namespace, controller.FormatClusterDomainForRegex(tc.Spec.ClusterDomain)))
 	if err != nil {
 		return err
 	}
@@ -708,6 +843,7 @@ func (m *tikvMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, set
 	tc.Status.TiKV.Stores = stores
 	tc.Status.TiKV.PeerStores = peerStores
 	tc.Status.TiKV.TombstoneStores = tombstoneStores
+	tc.Status.TiKV.TotalCapacity, tc.Status.TiKV.UsedCapacity, tc.Status.TiKV.AvailableCapacity = storesCapacity(stores)
 	tc.Status.TiKV.Image = ""
 	c := filterContainer(set, "tikv")
 	if c != nil {
@@ -731,6 +867,11 @@ func getTiKVStore(store *pdapi.StoreInfo) *v1alpha1.TiKVStore {
 		LeaderCount:       int32(store.Status.LeaderCount),
 		State:             store.Store.StateName,
 		LastHeartbeatTime: metav1.Time{Time: store.Status.LastHeartbeatTS},
+		RegionCount:       int32(store.Status.RegionCount),
+		Capacity:          storeSizeQuantity(store.Status.Capacity),
+		Available:         storeSizeQuantity(store.Status.Available),
+		UsedSize:          storeSizeQuantity(store.Status.UsedSize),
+		Labels:            storeLabels(store.Store.GetLabels()),
 	}
 }
 
@@ -755,7 +896,7 @@ func (m *tikvMemberManager) setStoreLabelsForTiKV(tc *v1alpha1.TidbCluster) (int
 		return setCount, nil
 	}
 
-	pattern, err := regexp.Compile(fmt.Sprintf(tikvStoreLimitPattern, tc.Name, tc.Name, tc.Namespace, controller.FormatClusterDomainForRegex(tc.Spec.ClusterDomain)))
+	pattern, err := regexp.Compile(fmt.Sprintf(tikvStoreLimitPattern, tikvMemberName(tc), tc.Name, tc.Namespace, controller.FormatClusterDomainForRegex(tc.Spec.ClusterDomain)))
 	if err != nil {
 		return -1, err
 	}
@@ -844,7 +985,7 @@ func tikvStatefulSetIsUpgrading(podLister corelisters.PodLister, pdControl pdapi
 		return true, nil
 	}
 	instanceName := tc.GetInstanceName()
-	selector, err := label.New().Instance(instanceName).TiKV().Selector()
+	selector, err := tikvPodSelector(tc)
 	if err != nil {
 		return false, err
 	}
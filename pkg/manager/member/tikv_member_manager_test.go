@@ -33,6 +33,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
@@ -1548,6 +1549,71 @@ func TestTiKVMemberManagerSyncTidbClusterStatus(t *testing.T) {
 				g.Expect(tc.Status.TiKV.Synced).To(BeTrue())
 			},
 		},
+		{
+			name: "store capacity and labels",
+			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1alpha1.TidbCluster) (bool, error) {
+				return false, nil
+			},
+			storeInfo: &pdapi.StoresInfo{
+				Stores: []*pdapi.StoreInfo{
+					{
+						Store: &pdapi.MetaStore{
+							Store: &metapb.Store{
+								Id:      333,
+								Address: fmt.Sprintf("%s-tikv-0.%s-tikv-peer.%s.svc:20160", "test", "test", "default"),
+								Labels: []*metapb.StoreLabel{
+									{
+										Key:   "zone",
+										Value: "z1",
+									},
+								},
+							},
+							StateName: "Up",
+						},
+						Status: &pdapi.StoreStatus{
+							Capacity:        100 << 30,
+							Available:       60 << 30,
+							UsedSize:        30 << 30,
+							RegionCount:     1000,
+							LastHeartbeatTS: time.Now(),
+						},
+					},
+					{
+						Store: &pdapi.MetaStore{
+							Store: &metapb.Store{
+								Id:      334,
+								Address: fmt.Sprintf("%s-tikv-1.%s-tikv-peer.%s.svc:20160", "test", "test", "default"),
+							},
+							StateName: "Up",
+						},
+						Status: &pdapi.StoreStatus{
+							Capacity:        100 << 30,
+							Available:       80 << 30,
+							UsedSize:        10 << 30,
+							RegionCount:     800,
+							LastHeartbeatTS: time.Now(),
+						},
+					},
+				},
+			},
+			tombstoneStoreInfo: &pdapi.StoresInfo{
+				Stores: []*pdapi.StoreInfo{},
+			},
+			errExpectFn: errExpectNil,
+			tcExpectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster) {
+				g.Expect(len(tc.Status.TiKV.Stores)).To(Equal(2))
+				store := tc.Status.TiKV.Stores["333"]
+				g.Expect(store.RegionCount).To(Equal(int32(1000)))
+				g.Expect(store.Capacity.String()).To(Equal("100Gi"))
+				g.Expect(store.Available.String()).To(Equal("60Gi"))
+				g.Expect(store.UsedSize.String()).To(Equal("30Gi"))
+				g.Expect(store.Labels).To(Equal(map[string]string{"zone": "z1"}))
+				g.Expect(tc.Status.TiKV.Stores["334"].Labels).To(BeNil())
+				g.Expect(tc.Status.TiKV.TotalCapacity.String()).To(Equal("200Gi"))
+				g.Expect(tc.Status.TiKV.AvailableCapacity.String()).To(Equal("140Gi"))
+				g.Expect(tc.Status.TiKV.UsedCapacity.String()).To(Equal("40Gi"))
+			},
+		},
 	}
 
 	for i := range tests {
@@ -1556,6 +1622,106 @@ func TestTiKVMemberManagerSyncTidbClusterStatus(t *testing.T) {
 	}
 }
 
+func TestTiKVMemberManagerSyncTiKVGroups(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForPD()
+	tc.Status.PD.Members = map[string]v1alpha1.PDMember{
+		"pd-0": {Name: "pd-0", Health: true},
+		"pd-1": {Name: "pd-1", Health: true},
+		"pd-2": {Name: "pd-2", Health: true},
+	}
+	tc.Status.PD.StatefulSet = &apps.StatefulSetStatus{ReadyReplicas: 3}
+	tc.Spec.TiKVGroups = []v1alpha1.TiKVGroupSpec{
+		{Name: "hot", TiKVSpec: *tc.Spec.TiKV.DeepCopy()},
+	}
+	tc.Spec.TiKVGroups[0].Replicas = 2
+	tc.Status.TiKV.Groups = map[string]v1alpha1.TiKVStatus{
+		"removed": {Synced: true},
+	}
+	ns := tc.Namespace
+
+	tkmm, _, _, pdClient, _, _ := newFakeTiKVMemberManager(tc)
+	pdClient.AddReaction(pdapi.GetConfigActionType, func(action *pdapi.Action) (interface{}, error) {
+		return &pdapi.PDConfigFromAPI{Replication: &pdapi.PDReplicationConfig{}}, nil
+	})
+	newStore := func(id uint64, podName string) *pdapi.StoreInfo {
+		return &pdapi.StoreInfo{
+			Store: &pdapi.MetaStore{
+				Store: &metapb.Store{
+					Id:      id,
+					Address: fmt.Sprintf("%s.%s-tikv-peer.%s.svc:20160", podName, tc.Name, ns),
+				},
+				StateName: v1alpha1.TiKVStateUp,
+			},
+			Status: &pdapi.StoreStatus{
+				Capacity:        100 << 30,
+				Available:       60 << 30,
+				UsedSize:        30 << 30,
+				LastHeartbeatTS: time.Now(),
+			},
+		}
+	}
+	pdClient.AddReaction(pdapi.GetStoresActionType, func(action *pdapi.Action) (interface{}, error) {
+		return &pdapi.StoresInfo{Stores: []*pdapi.StoreInfo{
+			newStore(1, "test-tikv-0"),
+			newStore(2, "test-tikv-hot-0"),
+		}}, nil
+	})
+	pdClient.AddReaction(pdapi.GetTombStoneStoresActionType, func(action *pdapi.Action) (interface{}, error) {
+		return &pdapi.StoresInfo{Stores: []*pdapi.StoreInfo{}}, nil
+	})
+
+	// the first round creates the statefulsets
+	g.Expect(tkmm.Sync(tc)).To(Succeed())
+	_, err := tkmm.deps.StatefulSetLister.StatefulSets(ns).Get(controller.TiKVMemberName(tc.Name))
+	g.Expect(err).NotTo(HaveOccurred())
+	set, err := tkmm.deps.StatefulSetLister.StatefulSets(ns).Get("test-tikv-hot")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(*set.Spec.Replicas).To(Equal(int32(2)))
+	g.Expect(set.Spec.Selector.MatchLabels[label.TiKVGroupLabelKey]).To(Equal("hot"))
+	g.Expect(set.Spec.Template.Labels[label.TiKVGroupLabelKey]).To(Equal("hot"))
+	g.Expect(set.Spec.ServiceName).To(Equal(controller.TiKVPeerMemberName(tc.Name)))
+	g.Expect(tc.Status.TiKV.Groups).To(HaveKey("hot"))
+	g.Expect(tc.Status.TiKV.Groups).NotTo(HaveKey("removed"))
+
+	// the second round syncs the stores of each group
+	g.Expect(tkmm.Sync(tc)).To(Succeed())
+	g.Expect(tc.Status.TiKV.Stores).To(HaveKey("1"))
+	g.Expect(tc.Status.TiKV.Stores).NotTo(HaveKey("2"))
+	group := tc.Status.TiKV.Groups["hot"]
+	g.Expect(group.Synced).To(BeTrue())
+	g.Expect(group.Stores).To(HaveKey("2"))
+	g.Expect(group.Stores).NotTo(HaveKey("1"))
+	g.Expect(group.Groups).To(BeNil())
+	g.Expect(group.TotalCapacity.String()).To(Equal("100Gi"))
+	// the capacity of the cluster includes the stores of the groups
+	g.Expect(tc.Status.TiKV.TotalCapacity.String()).To(Equal("200Gi"))
+	g.Expect(tc.Status.TiKV.AvailableCapacity.String()).To(Equal("120Gi"))
+	g.Expect(tc.Status.TiKV.UsedCapacity.String()).To(Equal("60Gi"))
+
+	// the selector of the default TiKV StatefulSet is kept, the pods of the
+	// groups are excluded from the pods of the default TiKV by the operator
+	set, err = tkmm.deps.StatefulSetLister.StatefulSets(ns).Get(controller.TiKVMemberName(tc.Name))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(set.Spec.Selector).To(Equal(labelTiKV(tc).LabelSelector()))
+	selector, err := tikvPodSelector(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(selector.Matches(labels.Set(labelTiKV(tc.TiKVGroupCluster(&tc.Spec.TiKVGroups[0]))))).To(BeFalse())
+	g.Expect(selector.Matches(labels.Set(labelTiKV(tc)))).To(BeTrue())
+
+	// the upgrades of the default TiKV and the groups are serialized
+	tc.Spec.TiKV.Image = "tikv-test-image:v2"
+	tc.Spec.TiKVGroups[0].Image = "tikv-test-image:v2"
+	g.Expect(tkmm.Sync(tc)).To(Succeed())
+	g.Expect(tc.Status.TiKV.Phase).To(Equal(v1alpha1.UpgradePhase))
+	g.Expect(tc.Status.TiKV.Groups["hot"].Phase).NotTo(Equal(v1alpha1.UpgradePhase))
+	g.Expect(tc.TiKVUpgrading()).To(BeTrue())
+	set, err = tkmm.deps.StatefulSetLister.StatefulSets(ns).Get("test-tikv-hot")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(set.Spec.Template.Spec.Containers[0].Image).To(Equal("tikv-test-image"))
+}
+
 func newFakeTiKVMemberManager(tc *v1alpha1.TidbCluster) (
 	*tikvMemberManager, *controller.FakeStatefulSetControl,
 	*controller.FakeServiceControl, *pdapi.FakePDClient, cache.Indexer, cache.Indexer) {
@@ -63,7 +63,7 @@ func (s *tikvScaler) ScaleOut(meta metav1.Object, oldSet *apps.StatefulSet, newS
 	var pvcName string
 	switch meta.(type) {
 	case *v1alpha1.TidbCluster:
-		pvcName = fmt.Sprintf("tikv-%s-tikv-%d", meta.GetName(), ordinal)
+		pvcName = ordinalPVCName(v1alpha1.TiKVMemberType, oldSet.GetName(), ordinal)
 	default:
 		return fmt.Errorf("tikv.ScaleOut, failed to convert cluster %s/%s", meta.GetNamespace(), meta.GetName())
 	}
@@ -93,9 +93,9 @@ func (s *tikvScaler) ScaleIn(meta metav1.Object, oldSet *apps.StatefulSet, newSe
 	// We need remove member from cluster before reducing statefulset replicas
 	var podName string
 
-	switch meta.(type) {
+	switch meta := meta.(type) {
 	case *v1alpha1.TidbCluster:
-		podName = ordinalPodName(v1alpha1.TiKVMemberType, tcName, ordinal)
+		podName = tikvGroupPodName(meta, ordinal)
 	default:
 		return fmt.Errorf("tikvScaler.ScaleIn: failed to convert cluster %s/%s", meta.GetNamespace(), meta.GetName())
 	}
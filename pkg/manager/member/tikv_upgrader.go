@@ -52,7 +52,9 @@ func (u *tikvUpgrader) Upgrade(meta metav1.Object, oldSet *apps.StatefulSet, new
 	ns := meta.GetNamespace()
 	tcName := meta.GetName()
 
+	var tc *v1alpha1.TidbCluster
 	var status *v1alpha1.TiKVStatus
+	var canary *canaryUpgrade
 	switch meta := meta.(type) {
 	case *v1alpha1.TidbCluster:
 		if meta.Status.PD.Phase == v1alpha1.UpgradePhase || meta.TiKVScaling() {
@@ -65,7 +67,12 @@ func (u *tikvUpgrader) Upgrade(meta metav1.Object, oldSet *apps.StatefulSet, new
 			newSet.Spec.Template.Spec = *podSpec
 			return nil
 		}
+		if wait, err := waitForMaintenanceWindow(meta, oldSet, newSet); err != nil || wait {
+			return err
+		}
+		tc = meta
 		status = &meta.Status.TiKV
+		canary = newTiKVCanaryUpgrade(u.deps, meta)
 	default:
 		return fmt.Errorf("cluster[%s/%s] failed to upgrading tikv due to converting", meta.GetNamespace(), meta.GetName())
 	}
@@ -74,6 +81,10 @@ func (u *tikvUpgrader) Upgrade(meta metav1.Object, oldSet *apps.StatefulSet, new
 		return fmt.Errorf("cluster: [%s/%s]'s tikv status sync failed, can not to be upgraded", ns, tcName)
 	}
 
+	if kept, err := canary.keepRolledBack(oldSet, newSet); err != nil || kept {
+		return err
+	}
+
 	status.Phase = v1alpha1.UpgradePhase
 	if !templateEqual(newSet, oldSet) {
 		return nil
@@ -95,13 +106,16 @@ func (u *tikvUpgrader) Upgrade(meta metav1.Object, oldSet *apps.StatefulSet, new
 
 	setUpgradePartition(newSet, *oldSet.Spec.UpdateStrategy.RollingUpdate.Partition)
 	podOrdinals := helper.GetPodOrdinals(*oldSet.Spec.Replicas, oldSet).List()
+	if rolledBack, err := canary.watch(oldSet, newSet, podOrdinals); err != nil || rolledBack {
+		return err
+	}
 	for _i := len(podOrdinals) - 1; _i >= 0; _i-- {
 		i := podOrdinals[_i]
-		store := u.getStoreByOrdinal(meta.GetName(), *status, i)
+		store := u.getStoreByOrdinal(tc, *status, i)
 		if store == nil {
 			continue
 		}
-		podName := TikvPodName(tcName, i)
+		podName := tikvGroupPodName(tc, i)
 		pod, err := u.deps.PodLister.Pods(ns).Get(podName)
 		if err != nil {
 			return fmt.Errorf("tikvUpgrader.Upgrade: failed to get pods %s for cluster %s/%s, error: %s", podName, ns, tcName, err)
@@ -123,6 +137,10 @@ func (u *tikvUpgrader) Upgrade(meta metav1.Object, oldSet *apps.StatefulSet, new
 			continue
 		}
 
+		if err := canary.gate(podOrdinals, podOrdinals[_i+1:]); err != nil {
+			return err
+		}
+
 		if u.deps.CLIConfig.PodWebhookEnabled {
 			setUpgradePartition(newSet, i)
 			return nil
@@ -140,7 +158,7 @@ func (u *tikvUpgrader) Upgrade(meta metav1.Object, oldSet *apps.StatefulSet, new
 func (u *tikvUpgrader) upgradeTiKVPod(tc *v1alpha1.TidbCluster, ordinal int32, newSet *apps.StatefulSet) error {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
-	upgradePodName := TikvPodName(tcName, ordinal)
+	upgradePodName := tikvGroupPodName(tc, ordinal)
 	upgradePod, err := u.deps.PodLister.Pods(ns).Get(upgradePodName)
 	if err != nil {
 		return fmt.Errorf("upgradeTiKVPod: failed to get pods %s for cluster %s/%s, error: %s", upgradePodName, ns, tcName, err)
@@ -221,7 +239,7 @@ func (u *tikvUpgrader) endEvictLeader(tc *v1alpha1.TidbCluster, ordinal int32) e
 	if u.deps.CLIConfig.TestMode {
 		time.Sleep(5 * time.Second)
 	}
-	store := u.getStoreByOrdinal(tc.GetName(), tc.Status.TiKV, ordinal)
+	store := u.getStoreByOrdinal(tc, tc.Status.TiKV, ordinal)
 	storeID, err := strconv.ParseUint(store.ID, 10, 64)
 	if err != nil {
 		return err
@@ -241,8 +259,8 @@ func (u *tikvUpgrader) endEvictLeader(tc *v1alpha1.TidbCluster, ordinal int32) e
 	return nil
 }
 
-func (u *tikvUpgrader) getStoreByOrdinal(name string, status v1alpha1.TiKVStatus, ordinal int32) *v1alpha1.TiKVStore {
-	podName := TikvPodName(name, ordinal)
+func (u *tikvUpgrader) getStoreByOrdinal(tc *v1alpha1.TidbCluster, status v1alpha1.TiKVStatus, ordinal int32) *v1alpha1.TiKVStore {
+	podName := tikvGroupPodName(tc, ordinal)
 	for _, store := range status.Stores {
 		if store.PodName == podName {
 			return &store
@@ -18,17 +18,22 @@ import (
 	"encoding/json"
 	"fmt"
 	"path"
+	"time"
 
 	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
+	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/label"
 	"github.com/pingcap/tidb-operator/pkg/util"
+	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
 	"github.com/pingcap/tidb-operator/pkg/util/toml"
+	"github.com/tikv/pd/pkg/typeutil"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -113,17 +118,238 @@ func templateEqual(new *apps.StatefulSet, old *apps.StatefulSet) bool {
 			klog.Errorf("unmarshal PodTemplate: [%s/%s]'s applied config failed,error: %v", old.GetNamespace(), old.GetName(), err)
 			return false
 		}
+		// the pods are rolled when the config items that can not be changed online are
+		// changed or the restart of the pods is requested
+		for _, key := range restartAnnotationKeys() {
+			if oldStsSpec.Template.Annotations[key] != new.Spec.Template.Annotations[key] {
+				return false
+			}
+		}
 		return apiequality.Semantic.DeepEqual(oldStsSpec.Template.Spec, new.Spec.Template.Spec)
 	}
 	return false
 }
 
+// waitForMaintenanceWindow keeps the pods of the statefulset from being recreated if the
+// tidb cluster is out of its maintenance windows: the new pod template is not applied and
+// the partition of the rolling upgrade in progress is kept. It returns true if the upgrade
+// should wait for the maintenance window.
+func waitForMaintenanceWindow(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) (bool, error) {
+	inWindow, err := utiltidbcluster.InMaintenanceWindow(tc, time.Now())
+	if err != nil || inWindow {
+		return false, err
+	}
+
+	klog.Infof("tidbcluster: [%s/%s] is out of the maintenance windows, the upgrade of statefulset %s waits", tc.GetNamespace(), tc.GetName(), newSet.GetName())
+	if !templateEqual(newSet, oldSet) {
+		oldSpec, _, err := GetLastAppliedConfig(oldSet)
+		if err != nil {
+			return false, err
+		}
+		newSet.Spec.Template.Spec = oldSpec.Template.Spec
+		for _, key := range restartAnnotationKeys() {
+			if val, ok := oldSpec.Template.Annotations[key]; ok {
+				if newSet.Spec.Template.Annotations == nil {
+					newSet.Spec.Template.Annotations = map[string]string{}
+				}
+				newSet.Spec.Template.Annotations[key] = val
+			} else {
+				delete(newSet.Spec.Template.Annotations, key)
+			}
+		}
+	}
+	if rollingUpdate := oldSet.Spec.UpdateStrategy.RollingUpdate; rollingUpdate != nil && rollingUpdate.Partition != nil {
+		setUpgradePartition(newSet, *rollingUpdate.Partition)
+	}
+	utiltidbcluster.SetMaintenancePending(&tc.Status, fmt.Sprintf("%s upgrade", newSet.GetName()))
+	return true, nil
+}
+
 // setUpgradePartition set statefulSet's rolling update partition
 func setUpgradePartition(set *apps.StatefulSet, upgradeOrdinal int32) {
 	set.Spec.UpdateStrategy.RollingUpdate = &apps.RollingUpdateStatefulSetStrategy{Partition: &upgradeOrdinal}
 	klog.Infof("set %s/%s partition to %d", set.GetNamespace(), set.GetName(), upgradeOrdinal)
 }
 
+// restartedAtAnnotations are the annotations of the tidb cluster to restart the pods
+// of a single component, keyed by the member type
+var restartedAtAnnotations = map[v1alpha1.MemberType]string{
+	v1alpha1.PDMemberType:      label.AnnPDRestartedAt,
+	v1alpha1.TiKVMemberType:    label.AnnTiKVRestartedAt,
+	v1alpha1.TiDBMemberType:    label.AnnTiDBRestartedAt,
+	v1alpha1.TiFlashMemberType: label.AnnTiFlashRestartedAt,
+	v1alpha1.TiCDCMemberType:   label.AnnTiCDCRestartedAt,
+	v1alpha1.PumpMemberType:    label.AnnPumpRestartedAt,
+}
+
+// restartAnnotationKeys returns the pod template annotations whose changes roll the pods
+func restartAnnotationKeys() []string {
+	keys := []string{label.AnnConfigRestartRevision, label.AnnRestartedAt}
+	for _, key := range restartedAtAnnotations {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// setRestartedAtAnnotations copies the restartedAt annotations of the tidb cluster for
+// all the components and for the given component to the pod template annotations, so
+// that the pods are restarted through the upgraders whenever any of them changes
+func setRestartedAtAnnotations(tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType, podAnnotations map[string]string) {
+	for _, key := range []string{label.AnnRestartedAt, restartedAtAnnotations[memberType]} {
+		if val, ok := tc.GetAnnotations()[key]; ok && key != "" {
+			podAnnotations[key] = val
+		}
+	}
+}
+
+// recreateStatefulSetForStorage deletes the statefulset with its pods kept if the storage
+// classes or the storage requests of its volume claim templates are changed, which can not
+// be updated in place. The statefulset is recreated with the new templates in the next sync
+// and adopts the pods, then the existing volumes are resized by the PVC resizer or replaced
+// one by one by the MemberReplacer, and the new volumes are created from the new templates.
+func recreateStatefulSetForStorage(deps *controller.Dependencies, obj runtime.Object, newSet, oldSet *apps.StatefulSet) error {
+	if !volumeClaimTemplatesChanged(newSet, oldSet) {
+		return nil
+	}
+	ns := oldSet.GetNamespace()
+	if statefulSetIsUpgrading(oldSet) || *oldSet.Spec.Replicas != *newSet.Spec.Replicas {
+		klog.Infof("the storage of statefulset %s/%s is changed, wait for the scaling and upgrading to finish", ns, oldSet.GetName())
+		return nil
+	}
+	if err := deps.StatefulSetControl.DeleteStatefulSet(obj, oldSet); err != nil {
+		return err
+	}
+	return controller.RequeueErrorf("statefulset %s/%s is deleted to change the storage, waiting for it to be recreated", ns, oldSet.GetName())
+}
+
+// volumeClaimTemplatesChanged returns whether the volume claim templates are added, removed,
+// changed to another storage class or changed to another storage request
+func volumeClaimTemplatesChanged(newSet, oldSet *apps.StatefulSet) bool {
+	storageClassName := func(pvc corev1.PersistentVolumeClaim) string {
+		if pvc.Spec.StorageClassName == nil {
+			return ""
+		}
+		return *pvc.Spec.StorageClassName
+	}
+	if len(newSet.Spec.VolumeClaimTemplates) != len(oldSet.Spec.VolumeClaimTemplates) {
+		return true
+	}
+	oldTemplates := map[string]corev1.PersistentVolumeClaim{}
+	for _, tmpl := range oldSet.Spec.VolumeClaimTemplates {
+		oldTemplates[tmpl.Name] = tmpl
+	}
+	for _, tmpl := range newSet.Spec.VolumeClaimTemplates {
+		oldTmpl, ok := oldTemplates[tmpl.Name]
+		if !ok || storageClassName(oldTmpl) != storageClassName(tmpl) {
+			return true
+		}
+		oldRequest := oldTmpl.Spec.Resources.Requests[corev1.ResourceStorage]
+		newRequest := tmpl.Spec.Resources.Requests[corev1.ResourceStorage]
+		if oldRequest.Cmp(newRequest) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// replacePodWithFreshStorage deletes the pod and the PVCs created before the given time
+// to let the StatefulSet create the pod with the same ordinal on fresh storage, it
+// returns true once the pod is recreated and all the old PVCs are gone.
+func replacePodWithFreshStorage(deps *controller.Dependencies, tc *v1alpha1.TidbCluster, setName, podName string, since metav1.Time) (bool, error) {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	// The order of old PVC deleting and the new Pod creating is not guaranteed by Kubernetes.
+	// If new Pod is created before old PVC deleted, new Pod will reuse old PVC.
+	// So we must try to delete the PVCs and Pod over and over
+	pod, err := deps.PodLister.Pods(ns).Get(podName)
+	if err != nil && !errors.IsNotFound(err) {
+		return false, fmt.Errorf("failed to get pod %s for cluster %s/%s, error: %s", podName, ns, tcName, err)
+	}
+	if errors.IsNotFound(err) {
+		pod = nil
+	}
+
+	ordinal, err := util.GetOrdinalFromPodName(podName)
+	if err != nil {
+		return false, err
+	}
+	set, err := deps.StatefulSetLister.StatefulSets(ns).Get(setName)
+	if err != nil {
+		return false, fmt.Errorf("failed to get statefulset %s for cluster %s/%s, error: %s", setName, ns, tcName, err)
+	}
+
+	var oldPVCs []*corev1.PersistentVolumeClaim
+	for _, tmpl := range set.Spec.VolumeClaimTemplates {
+		pvcName := fmt.Sprintf("%s-%s-%d", tmpl.Name, setName, ordinal)
+		pvc, err := deps.PVCLister.PersistentVolumeClaims(ns).Get(pvcName)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to get pvc %s for cluster %s/%s, error: %s", pvcName, ns, tcName, err)
+		}
+		if pvc.CreationTimestamp.Before(&since) {
+			oldPVCs = append(oldPVCs, pvc)
+		}
+	}
+
+	podReplaced := pod == nil || !pod.CreationTimestamp.Before(&since)
+	if len(oldPVCs) == 0 && podReplaced {
+		return true, nil
+	}
+
+	if pod != nil {
+		unavailable, err := podNodeUnavailable(deps, pod)
+		if err != nil {
+			return false, err
+		}
+		switch {
+		case unavailable:
+			// the pod on the lost or NotReady node is stuck in terminating as the kubelet
+			// can not confirm the deletion, delete it immediately
+			if err := deps.PodControl.ForceDeletePod(tc, pod); err != nil && !errors.IsNotFound(err) {
+				return false, err
+			}
+		case pod.DeletionTimestamp == nil:
+			if err := deps.PodControl.DeletePod(tc, pod); err != nil {
+				return false, err
+			}
+		}
+	}
+	for _, pvc := range oldPVCs {
+		if pvc.DeletionTimestamp != nil {
+			continue
+		}
+		if err := deps.PVCControl.DeletePVC(tc, pvc); err != nil {
+			klog.Errorf("failed to delete pvc: %s/%s, %v", ns, pvc.Name, err)
+			return false, err
+		}
+		klog.Infof("delete pvc %s/%s successfully", ns, pvc.Name)
+	}
+	return false, nil
+}
+
+// podNodeUnavailable returns whether the node of the pod is deleted or NotReady
+func podNodeUnavailable(deps *controller.Dependencies, pod *corev1.Pod) (bool, error) {
+	if pod.Spec.NodeName == "" {
+		return false, nil
+	}
+	node, err := deps.NodeLister.Get(pod.Spec.NodeName)
+	if errors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady {
+			return cond.Status != corev1.ConditionTrue, nil
+		}
+	}
+	return false, nil
+}
+
 func MemberPodName(controllerName, controllerKind string, ordinal int32, memberType v1alpha1.MemberType) (string, error) {
 	switch controllerKind {
 	case v1alpha1.TiDBClusterKind:
@@ -137,6 +363,27 @@ func TikvPodName(tcName string, ordinal int32) string {
 	return fmt.Sprintf("%s-%d", controller.TiKVMemberName(tcName), ordinal)
 }
 
+// tikvMemberName returns the tikv member name of the TiKV group tc is synced for
+func tikvMemberName(tc *v1alpha1.TidbCluster) string {
+	return controller.TiKVGroupMemberName(tc.Name, tc.TiKVGroupName())
+}
+
+// tikvGroupPodName returns the name of the TiKV pod in the TiKV group tc is synced for
+func tikvGroupPodName(tc *v1alpha1.TidbCluster, ordinal int32) string {
+	return fmt.Sprintf("%s-%d", tikvMemberName(tc), ordinal)
+}
+
+// tikvGroupStores returns the stores of the TiKV group tc is synced for, they are
+// looked up in the group status if tc is not the group view of the TidbCluster
+func tikvGroupStores(tc *v1alpha1.TidbCluster) map[string]v1alpha1.TiKVStore {
+	if group := tc.TiKVGroupName(); group != "" {
+		if status, ok := tc.Status.TiKV.Groups[group]; ok {
+			return status.Stores
+		}
+	}
+	return tc.Status.TiKV.Stores
+}
+
 func PdPodName(tcName string, ordinal int32) string {
 	return fmt.Sprintf("%s-%d", controller.PDMemberName(tcName), ordinal)
 }
@@ -145,6 +392,20 @@ func tidbPodName(tcName string, ordinal int32) string {
 	return fmt.Sprintf("%s-%d", controller.TiDBMemberName(tcName), ordinal)
 }
 
+// tidbMemberName returns the tidb member name of the TiDB group tc is synced for
+func tidbMemberName(tc *v1alpha1.TidbCluster) string {
+	return controller.TiDBGroupMemberName(tc.Name, tc.TiDBGroupName())
+}
+
+// tidbGroupPodName returns the name of the TiDB pod in the TiDB group tc is synced for
+func tidbGroupPodName(tc *v1alpha1.TidbCluster, ordinal int32) string {
+	return fmt.Sprintf("%s-%d", tidbMemberName(tc), ordinal)
+}
+
+func ticdcPodName(tcName string, ordinal int32) string {
+	return fmt.Sprintf("%s-%d", controller.TiCDCMemberName(tcName), ordinal)
+}
+
 func DMMasterPodName(dcName string, ordinal int32) string {
 	return fmt.Sprintf("%s-%d", controller.DMMasterMemberName(dcName), ordinal)
 }
@@ -312,6 +573,36 @@ func filterContainer(sts *apps.StatefulSet, containerName string) *corev1.Contai
 	return nil
 }
 
+// storeSizeQuantity converts the size reported by PD to a quantity
+func storeSizeQuantity(size typeutil.ByteSize) resource.Quantity {
+	return *resource.NewQuantity(int64(size), resource.BinarySI)
+}
+
+// storeLabels converts the labels of a store in PD to a map
+func storeLabels(labels []*metapb.StoreLabel) map[string]string {
+	if len(labels) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(labels))
+	for _, l := range labels {
+		m[l.GetKey()] = l.GetValue()
+	}
+	return m
+}
+
+// storesCapacity sums up the capacity, used size and available size of the stores
+func storesCapacity(stores map[string]v1alpha1.TiKVStore) (total, used, available resource.Quantity) {
+	total = *resource.NewQuantity(0, resource.BinarySI)
+	used = *resource.NewQuantity(0, resource.BinarySI)
+	available = *resource.NewQuantity(0, resource.BinarySI)
+	for _, store := range stores {
+		total.Add(store.Capacity)
+		used.Add(store.UsedSize)
+		available.Add(store.Available)
+	}
+	return
+}
+
 func CopyAnnotations(src map[string]string) map[string]string {
 	if src == nil {
 		return nil
@@ -359,7 +650,7 @@ func shouldRecover(tc *v1alpha1.TidbCluster, component string, podLister corelis
 		stores = tc.Status.TiKV.Stores
 		failureStores = tc.Status.TiKV.FailureStores
 		ordinals = tc.TiKVStsDesiredOrdinals(true)
-		podPrefix = controller.TiKVMemberName(tc.Name)
+		podPrefix = tikvMemberName(tc)
 	case label.TiFlashLabelVal:
 		stores = tc.Status.TiFlash.Stores
 		failureStores = tc.Status.TiFlash.FailureStores
@@ -23,13 +23,16 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/label"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubeinformers "k8s.io/client-go/informers"
 	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
 )
 
 func TestStatefulSetIsUpgrading(t *testing.T) {
@@ -837,3 +840,192 @@ func TestMemberPodName(t *testing.T) {
 		})
 	}
 }
+
+func TestTemplateEqualConfigRestartRevision(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	old := &apps.StatefulSet{}
+	old.Spec.Template.Annotations = map[string]string{label.AnnConfigRestartRevision: "abcdefg"}
+	g.Expect(SetStatefulSetLastAppliedConfigAnnotation(old)).To(Succeed())
+
+	new := old.DeepCopy()
+	g.Expect(templateEqual(new, old)).To(BeTrue())
+
+	new.Spec.Template.Annotations[label.AnnConfigRestartRevision] = "1234567"
+	g.Expect(templateEqual(new, old)).To(BeFalse())
+}
+
+func TestSetRestartedAtAnnotations(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := &v1alpha1.TidbCluster{}
+	tc.Annotations = map[string]string{
+		label.AnnRestartedAt:     "2020-08-01T00:00:00Z",
+		label.AnnTiKVRestartedAt: "2020-08-02T00:00:00Z",
+	}
+
+	podAnnotations := map[string]string{}
+	setRestartedAtAnnotations(tc, v1alpha1.TiKVMemberType, podAnnotations)
+	g.Expect(podAnnotations).To(Equal(map[string]string{
+		label.AnnRestartedAt:     "2020-08-01T00:00:00Z",
+		label.AnnTiKVRestartedAt: "2020-08-02T00:00:00Z",
+	}))
+
+	podAnnotations = map[string]string{}
+	setRestartedAtAnnotations(tc, v1alpha1.TiDBMemberType, podAnnotations)
+	g.Expect(podAnnotations).To(Equal(map[string]string{label.AnnRestartedAt: "2020-08-01T00:00:00Z"}))
+
+	old := &apps.StatefulSet{}
+	old.Spec.Template.Annotations = podAnnotations
+	g.Expect(SetStatefulSetLastAppliedConfigAnnotation(old)).To(Succeed())
+
+	new := old.DeepCopy()
+	new.Spec.Template.Annotations[label.AnnTiDBRestartedAt] = "2020-08-03T00:00:00Z"
+	g.Expect(templateEqual(new, old)).To(BeFalse())
+}
+
+func TestRecreateStatefulSetForStorage(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deps := controller.NewFakeDependencies()
+	tc := newTidbClusterForPD()
+	setIndexer := deps.KubeInformerFactory.Apps().V1().StatefulSets().Informer().GetIndexer()
+
+	newSet := func(storageClass string) *apps.StatefulSet {
+		set := &apps.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-tikv", Namespace: tc.Namespace},
+			Spec: apps.StatefulSetSpec{
+				Replicas: pointer.Int32Ptr(3),
+				VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+					{ObjectMeta: metav1.ObjectMeta{Name: "tikv"}},
+				},
+			},
+		}
+		if storageClass != "" {
+			set.Spec.VolumeClaimTemplates[0].Spec.StorageClassName = pointer.StringPtr(storageClass)
+		}
+		return set
+	}
+	exist := func() bool {
+		_, exist, _ := setIndexer.GetByKey(tc.Namespace + "/test-tikv")
+		return exist
+	}
+
+	oldSet := newSet("gp2")
+	setIndexer.Add(oldSet)
+
+	t.Log("storage class is not changed")
+	g.Expect(recreateStatefulSetForStorage(deps, tc, newSet("gp2"), oldSet)).To(Succeed())
+	g.Expect(exist()).To(BeTrue())
+
+	t.Log("statefulset is being upgraded")
+	oldSet.Status.CurrentRevision = "1"
+	oldSet.Status.UpdateRevision = "2"
+	g.Expect(recreateStatefulSetForStorage(deps, tc, newSet("gp3"), oldSet)).To(Succeed())
+	g.Expect(exist()).To(BeTrue())
+
+	t.Log("storage class is changed")
+	oldSet.Status.UpdateRevision = "1"
+	err := recreateStatefulSetForStorage(deps, tc, newSet("gp3"), oldSet)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(exist()).To(BeFalse())
+
+	g.Expect(volumeClaimTemplatesChanged(newSet(""), newSet(""))).To(BeFalse())
+	g.Expect(volumeClaimTemplatesChanged(newSet("gp3"), newSet(""))).To(BeTrue())
+
+	t.Log("storage request is changed")
+	resized := newSet("gp2")
+	resized.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("20Gi")}
+	g.Expect(volumeClaimTemplatesChanged(resized, newSet("gp2"))).To(BeTrue())
+	g.Expect(volumeClaimTemplatesChanged(resized, resized.DeepCopy())).To(BeFalse())
+}
+
+func TestWaitForMaintenanceWindow(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name       string
+		windows    []v1alpha1.MaintenanceWindow
+		update     func(newSet *apps.StatefulSet)
+		expectWait bool
+		expectFn   func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet)
+	}
+
+	open := []v1alpha1.MaintenanceWindow{{Schedule: "* * * * *", Duration: "1m"}}
+	closed := []v1alpha1.MaintenanceWindow{{Schedule: "0 0 1 1 *", Duration: "1m"}}
+
+	testFn := func(test *testcase) {
+		t.Log(test.name)
+
+		tc := &v1alpha1.TidbCluster{Spec: v1alpha1.TidbClusterSpec{MaintenanceWindows: test.windows}}
+		oldSet := &apps.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "test-tikv"}}
+		oldSet.Spec.Template.Annotations = map[string]string{label.AnnConfigRestartRevision: "abcdefg"}
+		oldSet.Spec.Template.Spec.Containers = []corev1.Container{{Name: "tikv", Image: "tikv:v4.0.8"}}
+		g.Expect(SetStatefulSetLastAppliedConfigAnnotation(oldSet)).To(Succeed())
+		setUpgradePartition(oldSet, 2)
+
+		newSet := oldSet.DeepCopy()
+		setUpgradePartition(newSet, 3)
+		if test.update != nil {
+			test.update(newSet)
+		}
+
+		wait, err := waitForMaintenanceWindow(tc, oldSet, newSet)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(wait).To(Equal(test.expectWait))
+		if test.expectFn != nil {
+			test.expectFn(g, tc, newSet)
+		}
+	}
+
+	tests := []testcase{
+		{
+			name:    "no window",
+			windows: nil,
+			update: func(newSet *apps.StatefulSet) {
+				newSet.Spec.Template.Spec.Containers[0].Image = "tikv:v4.0.9"
+			},
+			expectWait: false,
+		},
+		{
+			name:    "in window",
+			windows: open,
+			update: func(newSet *apps.StatefulSet) {
+				newSet.Spec.Template.Spec.Containers[0].Image = "tikv:v4.0.9"
+			},
+			expectWait: false,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(newSet.Spec.Template.Spec.Containers[0].Image).To(Equal("tikv:v4.0.9"))
+			},
+		},
+		{
+			name:    "out of window with new template",
+			windows: closed,
+			update: func(newSet *apps.StatefulSet) {
+				newSet.Spec.Template.Spec.Containers[0].Image = "tikv:v4.0.9"
+				newSet.Spec.Template.Annotations[label.AnnConfigRestartRevision] = "1234567"
+			},
+			expectWait: true,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(newSet.Spec.Template.Spec.Containers[0].Image).To(Equal("tikv:v4.0.8"))
+				g.Expect(newSet.Spec.Template.Annotations[label.AnnConfigRestartRevision]).To(Equal("abcdefg"))
+				g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(2)))
+				g.Expect(tc.Status.Conditions).To(HaveLen(1))
+				g.Expect(tc.Status.Conditions[0].Type).To(Equal(v1alpha1.TidbClusterMaintenancePending))
+				g.Expect(tc.Status.Conditions[0].Message).To(ContainSubstring("test-tikv upgrade"))
+			},
+		},
+		{
+			name:       "out of window in the middle of upgrade",
+			windows:    closed,
+			expectWait: true,
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(2)))
+			},
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i])
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	namespace = "tidb_operator"
+
+	// Label values of the reconcile results.
+
+	// ReconcileSuccess is the result of a reconcile without errors.
+	ReconcileSuccess = "success"
+	// ReconcileRequeue is the result of a reconcile waiting for something and requeued.
+	ReconcileRequeue = "requeue"
+	// ReconcileError is the result of a failed reconcile.
+	ReconcileError = "error"
+
+	// Label values of the backup and restore outcomes.
+
+	// OutcomeComplete is the outcome of a complete backup or restore.
+	OutcomeComplete = "complete"
+	// OutcomeFailed is the outcome of a failed backup or restore.
+	OutcomeFailed = "failed"
+)
+
+var (
+	// ReconcileTotal is the number of reconciles by controller, object and result.
+	ReconcileTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "controller",
+			Name:      "reconcile_total",
+			Help:      "Total number of reconciles by controller, object and result.",
+		}, []string{"controller", "namespace", "name", "result"})
+
+	// ReconcileErrors is the number of failed reconciles by controller and object,
+	// the reconciles requeued to wait for something are not counted.
+	ReconcileErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "controller",
+			Name:      "reconcile_errors_total",
+			Help:      "Total number of failed reconciles by controller and object.",
+		}, []string{"controller", "namespace", "name"})
+
+	// ReconcileDuration is the duration of the reconciles by controller and object.
+	ReconcileDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "controller",
+			Name:      "reconcile_duration_seconds",
+			Help:      "Duration of the reconciles by controller and object.",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 15),
+		}, []string{"controller", "namespace", "name"})
+
+	// FailoverTotal is the number of the members or stores marked as failure
+	// by cluster and component.
+	FailoverTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "cluster",
+			Name:      "failover_total",
+			Help:      "Total number of the members or stores marked as failure by cluster and component.",
+		}, []string{"namespace", "name", "component"})
+
+	// UpgradeDuration is the duration of the upgrade phases by cluster and component.
+	UpgradeDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "cluster",
+			Name:      "upgrade_duration_seconds",
+			Help:      "Duration of the upgrade phases by cluster and component.",
+			Buckets:   prometheus.ExponentialBuckets(30, 2, 12),
+		}, []string{"namespace", "name", "component"})
+
+	// PDAPIRequestDuration is the duration of the requests to the PD API by method and API.
+	PDAPIRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "pd_client",
+			Name:      "request_duration_seconds",
+			Help:      "Duration of the requests to the PD API by method and API.",
+			Buckets:   prometheus.ExponentialBuckets(0.005, 2, 12),
+		}, []string{"method", "api"})
+
+	// PDAPIRequestErrors is the number of the requests to the PD API which fail
+	// or respond with an error status by method and API.
+	PDAPIRequestErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "pd_client",
+			Name:      "request_errors_total",
+			Help:      "Total number of the failed requests to the PD API by method and API.",
+		}, []string{"method", "api"})
+
+	// BackupOutcomeTotal is the number of the finished backups and restores by kind,
+	// namespace and outcome.
+	BackupOutcomeTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "backup",
+			Name:      "outcome_total",
+			Help:      "Total number of the finished backups and restores by kind, namespace and outcome.",
+		}, []string{"kind", "namespace", "outcome"})
+)
+
+var registerOnce sync.Once
+
+// RegisterMetrics registers the metrics of the operator and the workqueues to
+// the default prometheus registry, it must be called before the controllers
+// are created to collect the metrics of their workqueues.
+func RegisterMetrics() {
+	registerOnce.Do(func() {
+		prometheus.MustRegister(
+			ReconcileTotal,
+			ReconcileErrors,
+			ReconcileDuration,
+			FailoverTotal,
+			UpgradeDuration,
+			PDAPIRequestDuration,
+			PDAPIRequestErrors,
+			BackupOutcomeTotal,
+		)
+		registerWorkqueueMetrics()
+	})
+}
+
+// upgradeStartTimes are the time when the components are observed upgrading,
+// keyed by namespace, name and component
+var upgradeStartTimes sync.Map
+
+// clusterComponents are the components with the failover or upgrade metrics,
+// keyed by namespace, name and component, they are deleted with the cluster
+var clusterComponents sync.Map
+
+type upgradeKey struct {
+	namespace string
+	name      string
+	component string
+}
+
+// ObserveUpgradePhase observes the duration of the upgrade phase of a component
+// when it is observed not upgrading. The start time is kept in memory, so the
+// upgrade in progress when the operator restarts is timed from the restart.
+func ObserveUpgradePhase(namespace, name, component string, upgrading bool) {
+	key := upgradeKey{namespace: namespace, name: name, component: component}
+	if upgrading {
+		upgradeStartTimes.LoadOrStore(key, time.Now())
+		return
+	}
+	if start, ok := upgradeStartTimes.Load(key); ok {
+		clusterComponents.Store(key, struct{}{})
+		UpgradeDuration.WithLabelValues(namespace, name, component).Observe(time.Since(start.(time.Time)).Seconds())
+		upgradeStartTimes.Delete(key)
+	}
+}
+
+// ObserveFailover counts a member or store of the component marked as failure
+func ObserveFailover(namespace, name, component string) {
+	clusterComponents.Store(upgradeKey{namespace: namespace, name: name, component: component}, struct{}{})
+	FailoverTotal.WithLabelValues(namespace, name, component).Inc()
+}
+
+// DeleteClusterMetrics deletes the failover and upgrade metrics and the upgrade
+// start times of a deleted cluster
+func DeleteClusterMetrics(namespace, name string) {
+	deleteKeys := func(m *sync.Map, f func(key upgradeKey)) {
+		m.Range(func(k, _ interface{}) bool {
+			key := k.(upgradeKey)
+			if key.namespace == namespace && key.name == name {
+				f(key)
+				m.Delete(key)
+			}
+			return true
+		})
+	}
+	deleteKeys(&upgradeStartTimes, func(upgradeKey) {})
+	deleteKeys(&clusterComponents, func(key upgradeKey) {
+		FailoverTotal.DeleteLabelValues(namespace, name, key.component)
+		UpgradeDuration.DeleteLabelValues(namespace, name, key.component)
+	})
+}
+
+// DeleteReconcileMetrics deletes the reconcile metrics of a deleted object
+func DeleteReconcileMetrics(controllerName, namespace, name string) {
+	for _, result := range []string{ReconcileSuccess, ReconcileRequeue, ReconcileError} {
+		ReconcileTotal.DeleteLabelValues(controllerName, namespace, name, result)
+	}
+	ReconcileErrors.DeleteLabelValues(controllerName, namespace, name)
+	ReconcileDuration.DeleteLabelValues(controllerName, namespace, name)
+}
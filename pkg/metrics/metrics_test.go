@@ -0,0 +1,97 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestObserveUpgradePhase(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	ObserveUpgradePhase("ns", "demo", "tikv", false)
+	_, ok := upgradeStartTimes.Load(upgradeKey{namespace: "ns", name: "demo", component: "tikv"})
+	g.Expect(ok).To(BeFalse())
+
+	ObserveUpgradePhase("ns", "demo", "tikv", true)
+	ObserveUpgradePhase("ns", "demo", "tikv", true)
+	_, ok = upgradeStartTimes.Load(upgradeKey{namespace: "ns", name: "demo", component: "tikv"})
+	g.Expect(ok).To(BeTrue())
+
+	ObserveUpgradePhase("ns", "demo", "tikv", false)
+	_, ok = upgradeStartTimes.Load(upgradeKey{namespace: "ns", name: "demo", component: "tikv"})
+	g.Expect(ok).To(BeFalse())
+	g.Expect(collectCount(UpgradeDuration)).To(Equal(1))
+}
+
+func TestDeleteClusterMetrics(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	ObserveFailover("ns", "deleted", "pd")
+	ObserveUpgradePhase("ns", "deleted", "tikv", true)
+	ObserveUpgradePhase("ns", "deleted", "tikv", false)
+	ObserveUpgradePhase("ns", "deleted", "tidb/group", true)
+	ObserveFailover("ns", "kept", "pd")
+
+	DeleteClusterMetrics("ns", "deleted")
+	_, ok := upgradeStartTimes.Load(upgradeKey{namespace: "ns", name: "deleted", component: "tidb/group"})
+	g.Expect(ok).To(BeFalse())
+	g.Expect(FailoverTotal.DeleteLabelValues("ns", "deleted", "pd")).To(BeFalse())
+	g.Expect(UpgradeDuration.DeleteLabelValues("ns", "deleted", "tikv")).To(BeFalse())
+	g.Expect(testutil.ToFloat64(FailoverTotal.WithLabelValues("ns", "kept", "pd"))).To(Equal(float64(1)))
+}
+
+func TestDeleteReconcileMetrics(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	ReconcileTotal.WithLabelValues("test", "ns", "demo", ReconcileSuccess).Inc()
+	ReconcileErrors.WithLabelValues("test", "ns", "demo").Inc()
+	ReconcileDuration.WithLabelValues("test", "ns", "demo").Observe(1)
+
+	DeleteReconcileMetrics("test", "ns", "demo")
+	g.Expect(collectCount(ReconcileTotal)).To(Equal(0))
+	g.Expect(collectCount(ReconcileErrors)).To(Equal(0))
+	g.Expect(collectCount(ReconcileDuration)).To(Equal(0))
+}
+
+func collectCount(c prometheus.Collector) int {
+	ch := make(chan prometheus.Metric)
+	go func() {
+		c.Collect(ch)
+		close(ch)
+	}()
+	count := 0
+	for range ch {
+		count++
+	}
+	return count
+}
+
+func TestWorkqueueMetricsProvider(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	provider := workqueueMetricsProvider{}
+	provider.NewDepthMetric("tidbcluster").Inc()
+	provider.NewAddsMetric("tidbcluster").Inc()
+	provider.NewRetriesMetric("tidbcluster").Inc()
+	provider.NewRetriesMetric("tidbcluster").Inc()
+
+	g.Expect(testutil.ToFloat64(workqueueDepth.WithLabelValues("tidbcluster"))).To(Equal(float64(1)))
+	g.Expect(testutil.ToFloat64(workqueueAdds.WithLabelValues("tidbcluster"))).To(Equal(float64(1)))
+	g.Expect(testutil.ToFloat64(workqueueRetries.WithLabelValues("tidbcluster"))).To(Equal(float64(2)))
+}
@@ -42,6 +42,18 @@ const (
 	GetPDLeaderActionType              ActionType = "GetPDLeader"
 	TransferPDLeaderActionType         ActionType = "TransferPDLeader"
 	GetAutoscalingPlansActionType      ActionType = "GetAutoscalingPlans"
+	GetPlacementRulesActionType        ActionType = "GetPlacementRules"
+	SetPlacementRuleActionType         ActionType = "SetPlacementRule"
+	DeletePlacementRuleActionType      ActionType = "DeletePlacementRule"
+	GetPlacementRuleGroupsActionType   ActionType = "GetPlacementRuleGroups"
+	SetPlacementRuleGroupActionType    ActionType = "SetPlacementRuleGroup"
+	DeletePlacementRuleGroupActionType ActionType = "DeletePlacementRuleGroup"
+	GetSchedulersActionType            ActionType = "GetSchedulers"
+	AddSchedulerActionType             ActionType = "AddScheduler"
+	RemoveSchedulerActionType          ActionType = "RemoveScheduler"
+	GetSchedulerConfigActionType       ActionType = "GetSchedulerConfig"
+	SetSchedulerConfigActionType       ActionType = "SetSchedulerConfig"
+	UpdateConfigActionType             ActionType = "UpdateConfig"
 )
 
 type NotFoundReaction struct {
@@ -57,6 +69,9 @@ type Action struct {
 	Name        string
 	Labels      map[string]string
 	Replication PDReplicationConfig
+	Rule        *PlacementRule
+	RuleGroup   *PlacementRuleGroup
+	Args        map[string]interface{}
 }
 
 type Reaction func(action *Action) (interface{}, error)
@@ -260,3 +275,111 @@ func (c *FakePDClient) GetAutoscalingPlans(strategy Strategy) ([]Plan, error) {
 	}
 	return nil, nil
 }
+
+func (c *FakePDClient) GetPlacementRules(groupID string) ([]*PlacementRule, error) {
+	if reaction, ok := c.reactions[GetPlacementRulesActionType]; ok {
+		action := &Action{Name: groupID}
+		result, err := reaction(action)
+		return result.([]*PlacementRule), err
+	}
+	return nil, nil
+}
+
+func (c *FakePDClient) SetPlacementRule(rule *PlacementRule) error {
+	if reaction, ok := c.reactions[SetPlacementRuleActionType]; ok {
+		action := &Action{Rule: rule}
+		_, err := reaction(action)
+		return err
+	}
+	return nil
+}
+
+func (c *FakePDClient) DeletePlacementRule(groupID, ruleID string) error {
+	if reaction, ok := c.reactions[DeletePlacementRuleActionType]; ok {
+		action := &Action{Rule: &PlacementRule{GroupID: groupID, ID: ruleID}}
+		_, err := reaction(action)
+		return err
+	}
+	return nil
+}
+
+func (c *FakePDClient) GetPlacementRuleGroups() ([]*PlacementRuleGroup, error) {
+	if reaction, ok := c.reactions[GetPlacementRuleGroupsActionType]; ok {
+		action := &Action{}
+		result, err := reaction(action)
+		return result.([]*PlacementRuleGroup), err
+	}
+	return nil, nil
+}
+
+func (c *FakePDClient) SetPlacementRuleGroup(group *PlacementRuleGroup) error {
+	if reaction, ok := c.reactions[SetPlacementRuleGroupActionType]; ok {
+		action := &Action{RuleGroup: group}
+		_, err := reaction(action)
+		return err
+	}
+	return nil
+}
+
+func (c *FakePDClient) DeletePlacementRuleGroup(groupID string) error {
+	if reaction, ok := c.reactions[DeletePlacementRuleGroupActionType]; ok {
+		action := &Action{RuleGroup: &PlacementRuleGroup{ID: groupID}}
+		_, err := reaction(action)
+		return err
+	}
+	return nil
+}
+
+func (c *FakePDClient) GetSchedulers() ([]string, error) {
+	if reaction, ok := c.reactions[GetSchedulersActionType]; ok {
+		action := &Action{}
+		result, err := reaction(action)
+		return result.([]string), err
+	}
+	return nil, nil
+}
+
+func (c *FakePDClient) AddScheduler(name string, args map[string]interface{}) error {
+	if reaction, ok := c.reactions[AddSchedulerActionType]; ok {
+		action := &Action{Name: name, Args: args}
+		_, err := reaction(action)
+		return err
+	}
+	return nil
+}
+
+func (c *FakePDClient) RemoveScheduler(name string) error {
+	if reaction, ok := c.reactions[RemoveSchedulerActionType]; ok {
+		action := &Action{Name: name}
+		_, err := reaction(action)
+		return err
+	}
+	return nil
+}
+
+func (c *FakePDClient) GetSchedulerConfig(name string) (map[string]interface{}, error) {
+	if reaction, ok := c.reactions[GetSchedulerConfigActionType]; ok {
+		action := &Action{Name: name}
+		result, err := reaction(action)
+		return result.(map[string]interface{}), err
+	}
+	return nil, nil
+}
+
+func (c *FakePDClient) SetSchedulerConfig(name string, config map[string]interface{}) error {
+	if reaction, ok := c.reactions[SetSchedulerConfigActionType]; ok {
+		action := &Action{Name: name, Args: config}
+		_, err := reaction(action)
+		return err
+	}
+	return nil
+}
+
+func (c *FakePDClient) UpdateConfig(items map[string]interface{}) error {
+	if reaction, ok := c.reactions[UpdateConfigActionType]; ok {
+		action := &Action{Args: items}
+		_, err := reaction(action)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pdapi
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/metrics"
+)
+
+// apiPrefixes are the prefixes of the PD APIs to label the metrics of the requests,
+// the ids and names in the paths are not used as labels
+var apiPrefixes = []string{
+	healthPrefix,
+	membersPrefix,
+	storesPrefix,
+	storePrefix,
+	configPrefix,
+	clusterIDPrefix,
+	schedulersPrefix,
+	pdLeaderPrefix,
+	pdLeaderTransferPrefix,
+	pdReplicationPrefix,
+	evictLeaderSchedulerConfigPrefix,
+	schedulerConfigPrefix,
+	autoscalingPrefix,
+	placementRulePrefix,
+	placementRulesGroupPrefix,
+	placementRuleGroupPrefix,
+	placementRuleGroupsPrefix,
+}
+
+// metricsTransport records the duration and the errors of the requests to PD
+type metricsTransport struct {
+	next http.RoundTripper
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	api := apiOfPath(req.URL.Path)
+	start := time.Now()
+	res, err := t.next.RoundTrip(req)
+	metrics.PDAPIRequestDuration.WithLabelValues(req.Method, api).Observe(time.Since(start).Seconds())
+	if err != nil || res.StatusCode >= http.StatusBadRequest {
+		metrics.PDAPIRequestErrors.WithLabelValues(req.Method, api).Inc()
+	}
+	return res, err
+}
+
+// apiOfPath returns the longest API prefix of the path, or "other" if none matches
+func apiOfPath(path string) string {
+	path = strings.TrimPrefix(path, "/")
+	api := "other"
+	for _, prefix := range apiPrefixes {
+		if strings.HasPrefix(path, prefix) && (api == "other" || len(prefix) > len(api)) {
+			api = prefix
+		}
+	}
+	return api
+}
@@ -69,6 +69,9 @@ type PDClient interface {
 	SetStoreLabels(storeID uint64, labels map[string]string) (bool, error)
 	// UpdateReplicationConfig updates the replication config
 	UpdateReplicationConfig(config PDReplicationConfig) error
+	// UpdateConfig updates the online changeable config items keyed by their
+	// dotted names, e.g. schedule.leader-schedule-limit
+	UpdateConfig(items map[string]interface{}) error
 	// DeleteStore deletes a TiKV store from cluster
 	DeleteStore(storeID uint64) error
 	// SetStoreState sets store to specified state.
@@ -90,6 +93,28 @@ type PDClient interface {
 	TransferPDLeader(name string) error
 	// GetAutoscalingPlans returns the scaling plan for the cluster
 	GetAutoscalingPlans(strategy Strategy) ([]Plan, error)
+	// GetPlacementRules returns the placement rules of a rule group
+	GetPlacementRules(groupID string) ([]*PlacementRule, error)
+	// SetPlacementRule creates or updates a placement rule
+	SetPlacementRule(rule *PlacementRule) error
+	// DeletePlacementRule deletes a placement rule
+	DeletePlacementRule(groupID, ruleID string) error
+	// GetPlacementRuleGroups returns all the placement rule groups
+	GetPlacementRuleGroups() ([]*PlacementRuleGroup, error)
+	// SetPlacementRuleGroup creates or updates a placement rule group
+	SetPlacementRuleGroup(group *PlacementRuleGroup) error
+	// DeletePlacementRuleGroup deletes a placement rule group
+	DeletePlacementRuleGroup(groupID string) error
+	// GetSchedulers returns the names of all the schedulers
+	GetSchedulers() ([]string, error)
+	// AddScheduler adds a scheduler with the args, e.g. store_id of grant-leader-scheduler
+	AddScheduler(name string, args map[string]interface{}) error
+	// RemoveScheduler removes a scheduler
+	RemoveScheduler(name string) error
+	// GetSchedulerConfig returns the config of a scheduler
+	GetSchedulerConfig(name string) (map[string]interface{}, error)
+	// SetSchedulerConfig updates the config of a scheduler
+	SetSchedulerConfig(name string, config map[string]interface{}) error
 }
 
 var (
@@ -106,7 +131,12 @@ var (
 	// evictLeaderSchedulerConfigPrefix is the prefix of evict-leader-scheduler
 	// config API, available since PD v3.1.0.
 	evictLeaderSchedulerConfigPrefix = "pd/api/v1/scheduler-config/evict-leader-scheduler/list"
+	schedulerConfigPrefix            = "pd/api/v1/scheduler-config"
 	autoscalingPrefix                = "autoscaling"
+	placementRulePrefix              = "pd/api/v1/config/rule"
+	placementRulesGroupPrefix        = "pd/api/v1/config/rules/group"
+	placementRuleGroupPrefix         = "pd/api/v1/config/rule_group"
+	placementRuleGroupsPrefix        = "pd/api/v1/config/rule_groups"
 )
 
 // pdClient is default implementation of PDClient
@@ -125,7 +155,7 @@ func NewPDClient(url string, timeout time.Duration, tlsConfig *tls.Config) PDCli
 		url: url,
 		httpClient: &http.Client{
 			Timeout:   timeout,
-			Transport: &http.Transport{TLSClientConfig: tlsConfig, DisableKeepAlives: disableKeepalive},
+			Transport: &metricsTransport{next: &http.Transport{TLSClientConfig: tlsConfig, DisableKeepAlives: disableKeepalive}},
 		},
 	}
 }
@@ -156,6 +186,7 @@ type MetaStore struct {
 type StoreStatus struct {
 	Capacity           typeutil.ByteSize `json:"capacity"`
 	Available          typeutil.ByteSize `json:"available"`
+	UsedSize           typeutil.ByteSize `json:"used_size"`
 	LeaderCount        int               `json:"leader_count"`
 	RegionCount        int               `json:"region_count"`
 	SendingSnapCount   uint32            `json:"sending_snap_count"`
@@ -238,6 +269,39 @@ type Plan struct {
 	Labels       map[string]string `json:"labels"`
 }
 
+// below copied from github.com/tikv/pd/server/schedule/placement
+
+// PlacementRule is the placement rule of PD, it describes how many replicas
+// with which role should be placed on which stores for a key range.
+type PlacementRule struct {
+	GroupID          string                     `json:"group_id"`
+	ID               string                     `json:"id"`
+	Index            int                        `json:"index,omitempty"`
+	Override         bool                       `json:"override,omitempty"`
+	StartKeyHex      string                     `json:"start_key"`
+	EndKeyHex        string                     `json:"end_key"`
+	Role             string                     `json:"role"`
+	Count            int                        `json:"count"`
+	LabelConstraints []PlacementLabelConstraint `json:"label_constraints,omitempty"`
+	LocationLabels   []string                   `json:"location_labels,omitempty"`
+	IsolationLevel   string                     `json:"isolation_level,omitempty"`
+}
+
+// PlacementLabelConstraint is used to filter the stores by their labels
+type PlacementLabelConstraint struct {
+	Key    string   `json:"key"`
+	Op     string   `json:"op"`
+	Values []string `json:"values"`
+}
+
+// PlacementRuleGroup is the group of placement rules, it decides the order
+// the rules are applied and whether the rules override the previous groups.
+type PlacementRuleGroup struct {
+	ID       string `json:"id"`
+	Index    int    `json:"index,omitempty"`
+	Override bool   `json:"override,omitempty"`
+}
+
 type schedulerInfo struct {
 	Name    string `json:"name"`
 	StoreID uint64 `json:"store_id"`
@@ -498,6 +562,16 @@ func (c *pdClient) UpdateReplicationConfig(config PDReplicationConfig) error {
 	return fmt.Errorf("failed %v to update replication: %v", res.StatusCode, err)
 }
 
+func (c *pdClient) UpdateConfig(items map[string]interface{}) error {
+	apiURL := fmt.Sprintf("%s/%s", c.url, configPrefix)
+	data, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+	_, err = httputil.PostBodyOK(c.httpClient, apiURL, bytes.NewBuffer(data))
+	return err
+}
+
 func (c *pdClient) BeginEvictLeader(storeID uint64) error {
 	leaderEvictInfo := getLeaderEvictSchedulerInfo(storeID)
 	apiURL := fmt.Sprintf("%s/%s", c.url, schedulersPrefix)
@@ -691,6 +765,143 @@ func (c *pdClient) GetAutoscalingPlans(strategy Strategy) ([]Plan, error) {
 	return plans, nil
 }
 
+func (c *pdClient) GetPlacementRules(groupID string) ([]*PlacementRule, error) {
+	apiURL := fmt.Sprintf("%s/%s/%s", c.url, placementRulesGroupPrefix, groupID)
+	body, err := httputil.GetBodyOK(c.httpClient, apiURL)
+	if err != nil {
+		return nil, err
+	}
+	var rules []*PlacementRule
+	err = json.Unmarshal(body, &rules)
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (c *pdClient) SetPlacementRule(rule *PlacementRule) error {
+	apiURL := fmt.Sprintf("%s/%s", c.url, placementRulePrefix)
+	data, err := json.Marshal(rule)
+	if err != nil {
+		return err
+	}
+	_, err = httputil.PostBodyOK(c.httpClient, apiURL, bytes.NewBuffer(data))
+	return err
+}
+
+func (c *pdClient) DeletePlacementRule(groupID, ruleID string) error {
+	apiURL := fmt.Sprintf("%s/%s/%s/%s", c.url, placementRulePrefix, groupID, ruleID)
+	_, err := httputil.DeleteBodyOK(c.httpClient, apiURL)
+	return err
+}
+
+func (c *pdClient) GetPlacementRuleGroups() ([]*PlacementRuleGroup, error) {
+	apiURL := fmt.Sprintf("%s/%s", c.url, placementRuleGroupsPrefix)
+	body, err := httputil.GetBodyOK(c.httpClient, apiURL)
+	if err != nil {
+		return nil, err
+	}
+	var groups []*PlacementRuleGroup
+	err = json.Unmarshal(body, &groups)
+	if err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+func (c *pdClient) SetPlacementRuleGroup(group *PlacementRuleGroup) error {
+	apiURL := fmt.Sprintf("%s/%s", c.url, placementRuleGroupPrefix)
+	data, err := json.Marshal(group)
+	if err != nil {
+		return err
+	}
+	_, err = httputil.PostBodyOK(c.httpClient, apiURL, bytes.NewBuffer(data))
+	return err
+}
+
+func (c *pdClient) DeletePlacementRuleGroup(groupID string) error {
+	apiURL := fmt.Sprintf("%s/%s/%s", c.url, placementRuleGroupPrefix, groupID)
+	_, err := httputil.DeleteBodyOK(c.httpClient, apiURL)
+	return err
+}
+
+func (c *pdClient) GetSchedulers() ([]string, error) {
+	apiURL := fmt.Sprintf("%s/%s", c.url, schedulersPrefix)
+	body, err := httputil.GetBodyOK(c.httpClient, apiURL)
+	if err != nil {
+		return nil, err
+	}
+	var schedulers []string
+	err = json.Unmarshal(body, &schedulers)
+	if err != nil {
+		return nil, err
+	}
+	return schedulers, nil
+}
+
+func (c *pdClient) AddScheduler(name string, args map[string]interface{}) error {
+	apiURL := fmt.Sprintf("%s/%s", c.url, schedulersPrefix)
+	input := map[string]interface{}{}
+	for k, v := range args {
+		input[k] = v
+	}
+	input["name"] = name
+	data, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	_, err = httputil.PostBodyOK(c.httpClient, apiURL, bytes.NewBuffer(data))
+	return err
+}
+
+func (c *pdClient) RemoveScheduler(name string) error {
+	apiURL := fmt.Sprintf("%s/%s/%s", c.url, schedulersPrefix, name)
+	req, err := http.NewRequest("DELETE", apiURL, nil)
+	if err != nil {
+		return err
+	}
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer httputil.DeferClose(res.Body)
+	if res.StatusCode == http.StatusOK || res.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	err = httputil.ReadErrorBody(res.Body)
+	return fmt.Errorf("failed %v to remove scheduler %s: %v", res.StatusCode, name, err)
+}
+
+func (c *pdClient) GetSchedulerConfig(name string) (map[string]interface{}, error) {
+	apiURL := fmt.Sprintf("%s/%s/%s/list", c.url, schedulerConfigPrefix, name)
+	body, err := httputil.GetBodyOK(c.httpClient, apiURL)
+	if err != nil {
+		return nil, err
+	}
+	config := map[string]interface{}{}
+	err = json.Unmarshal(body, &config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func (c *pdClient) SetSchedulerConfig(name string, config map[string]interface{}) error {
+	apiURL := fmt.Sprintf("%s/%s/%s/config", c.url, schedulerConfigPrefix, name)
+	data, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	_, err = httputil.PostBodyOK(c.httpClient, apiURL, bytes.NewBuffer(data))
+	return err
+}
+
+// IsEvictLeaderScheduler returns whether the scheduler is an evict-leader
+// scheduler, they are added and removed by the operator during upgrades
+func IsEvictLeaderScheduler(name string) bool {
+	return strings.HasPrefix(name, evictSchedulerLeader)
+}
+
 func getLeaderEvictSchedulerInfo(storeID uint64) *schedulerInfo {
 	return &schedulerInfo{"evict-leader-scheduler", storeID}
 }
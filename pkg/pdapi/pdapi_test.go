@@ -774,6 +774,166 @@ func TestGeneric(t *testing.T) {
 			wantPath:    fmt.Sprintf("/%s/%s", pdLeaderTransferPrefix, "foo"),
 			checkResult: checkNoError,
 		},
+		{
+			name:   "GetSchedulers",
+			method: "GetSchedulers",
+			resp: []byte(`
+[
+	"balance-hot-region-scheduler",
+	"evict-leader-scheduler-1"
+]
+`),
+			statusCode:  http.StatusOK,
+			wantMethod:  "GET",
+			wantPath:    fmt.Sprintf("/%s", schedulersPrefix),
+			checkResult: checkNoError,
+		},
+		{
+			name:   "AddScheduler",
+			method: "AddScheduler",
+			args: []reflect.Value{
+				reflect.ValueOf("grant-leader-scheduler"),
+				reflect.ValueOf(map[string]interface{}{"store_id": 1}),
+			},
+			statusCode:  http.StatusOK,
+			wantMethod:  "POST",
+			wantPath:    fmt.Sprintf("/%s", schedulersPrefix),
+			checkResult: checkNoError,
+		},
+		{
+			name:   "RemoveScheduler",
+			method: "RemoveScheduler",
+			args: []reflect.Value{
+				reflect.ValueOf("shuffle-leader-scheduler"),
+			},
+			statusCode:  http.StatusNotFound,
+			wantMethod:  "DELETE",
+			wantPath:    fmt.Sprintf("/%s/shuffle-leader-scheduler", schedulersPrefix),
+			checkResult: checkNoError,
+		},
+		{
+			name:   "GetSchedulerConfig",
+			method: "GetSchedulerConfig",
+			args: []reflect.Value{
+				reflect.ValueOf("balance-hot-region-scheduler"),
+			},
+			resp: []byte(`
+{
+	"min-hot-byte-rate": 100
+}
+`),
+			statusCode:  http.StatusOK,
+			wantMethod:  "GET",
+			wantPath:    fmt.Sprintf("/%s/balance-hot-region-scheduler/list", schedulerConfigPrefix),
+			checkResult: checkNoError,
+		},
+		{
+			name:   "SetSchedulerConfig",
+			method: "SetSchedulerConfig",
+			args: []reflect.Value{
+				reflect.ValueOf("balance-hot-region-scheduler"),
+				reflect.ValueOf(map[string]interface{}{"min-hot-byte-rate": 200}),
+			},
+			statusCode:  http.StatusOK,
+			wantMethod:  "POST",
+			wantPath:    fmt.Sprintf("/%s/balance-hot-region-scheduler/config", schedulerConfigPrefix),
+			checkResult: checkNoError,
+		},
+		{
+			name:   "UpdateConfig",
+			method: "UpdateConfig",
+			args: []reflect.Value{
+				reflect.ValueOf(map[string]interface{}{"schedule.leader-schedule-limit": 8}),
+			},
+			statusCode:  http.StatusOK,
+			wantMethod:  "POST",
+			wantPath:    fmt.Sprintf("/%s", configPrefix),
+			checkResult: checkNoError,
+		},
+		{
+			name:   "GetPlacementRules",
+			method: "GetPlacementRules",
+			args: []reflect.Value{
+				reflect.ValueOf("foo"),
+			},
+			resp: []byte(`
+[
+	{
+		"group_id": "foo",
+		"id": "voters",
+		"start_key": "",
+		"end_key": "",
+		"role": "voter",
+		"count": 3
+	}
+]
+`),
+			statusCode:  http.StatusOK,
+			wantMethod:  "GET",
+			wantPath:    fmt.Sprintf("/%s/%s", placementRulesGroupPrefix, "foo"),
+			checkResult: checkNoError,
+		},
+		{
+			name:   "SetPlacementRule",
+			method: "SetPlacementRule",
+			args: []reflect.Value{
+				reflect.ValueOf(&PlacementRule{GroupID: "foo", ID: "voters", Role: "voter", Count: 3}),
+			},
+			statusCode:  http.StatusOK,
+			wantMethod:  "POST",
+			wantPath:    fmt.Sprintf("/%s", placementRulePrefix),
+			checkResult: checkNoError,
+		},
+		{
+			name:   "DeletePlacementRule",
+			method: "DeletePlacementRule",
+			args: []reflect.Value{
+				reflect.ValueOf("foo"),
+				reflect.ValueOf("voters"),
+			},
+			statusCode:  http.StatusOK,
+			wantMethod:  "DELETE",
+			wantPath:    fmt.Sprintf("/%s/%s/%s", placementRulePrefix, "foo", "voters"),
+			checkResult: checkNoError,
+		},
+		{
+			name:   "GetPlacementRuleGroups",
+			method: "GetPlacementRuleGroups",
+			resp: []byte(`
+[
+	{
+		"id": "foo",
+		"index": 1
+	}
+]
+`),
+			statusCode:  http.StatusOK,
+			wantMethod:  "GET",
+			wantPath:    fmt.Sprintf("/%s", placementRuleGroupsPrefix),
+			checkResult: checkNoError,
+		},
+		{
+			name:   "SetPlacementRuleGroup",
+			method: "SetPlacementRuleGroup",
+			args: []reflect.Value{
+				reflect.ValueOf(&PlacementRuleGroup{ID: "foo", Index: 1}),
+			},
+			statusCode:  http.StatusOK,
+			wantMethod:  "POST",
+			wantPath:    fmt.Sprintf("/%s", placementRuleGroupPrefix),
+			checkResult: checkNoError,
+		},
+		{
+			name:   "DeletePlacementRuleGroup",
+			method: "DeletePlacementRuleGroup",
+			args: []reflect.Value{
+				reflect.ValueOf("foo"),
+			},
+			statusCode:  http.StatusOK,
+			wantMethod:  "DELETE",
+			wantPath:    fmt.Sprintf("/%s/%s", placementRuleGroupPrefix, "foo"),
+			checkResult: checkNoError,
+		},
 	}
 
 	for _, tt := range tests {
@@ -804,3 +964,24 @@ func TestGeneric(t *testing.T) {
 		})
 	}
 }
+
+func TestAPIOfPath(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tcs := []struct {
+		path string
+		api  string
+	}{
+		{"/pd/health", healthPrefix},
+		{"/pd/api/v1/members/pd-0", membersPrefix},
+		{"/pd/api/v1/stores", storesPrefix},
+		{"/pd/api/v1/store/1/label", storePrefix},
+		{"/pd/api/v1/leader/transfer/pd-1", pdLeaderTransferPrefix},
+		{"/pd/api/v1/config/replicate", pdReplicationPrefix},
+		{"/pd/api/v1/config/rule_groups", placementRuleGroupsPrefix},
+		{"/pd/api/v1/unknown", "other"},
+	}
+	for _, tc := range tcs {
+		g.Expect(apiOfPath(tc.path)).To(Equal(tc.api), tc.path)
+	}
+}
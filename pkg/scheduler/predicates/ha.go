@@ -121,6 +121,14 @@ func (h *ha) Filter(instanceName string, pod *apiv1.Pod, nodes []apiv1.Node) ([]
 	if err != nil {
 		return nil, err
 	}
+	// the pods of a TiKV group are spread across the topologies within the group
+	if group := pod.Labels[label.TiKVGroupLabelKey]; group != "" {
+		tc, err = getTiKVGroupCluster(tc, group)
+		if err != nil {
+			return nil, err
+		}
+	}
+	podList = filterTiKVGroupPods(podList, pod.Labels[label.TiKVGroupLabelKey])
 	replicas := getReplicasFrom(tc, component)
 	klog.Infof("ha: tidbcluster %s/%s component %s replicas %d", ns, tcName, component, replicas)
 
@@ -417,9 +425,33 @@ func (h *ha) setCurrentPodScheduling(pvc *apiv1.PersistentVolumeClaim) error {
 }
 
 func getTCNameFromPod(pod *apiv1.Pod, component string) string {
+	if group := pod.Labels[label.TiKVGroupLabelKey]; group != "" {
+		return strings.TrimSuffix(pod.GenerateName, fmt.Sprintf("-%s-%s-", component, group))
+	}
 	return strings.TrimSuffix(pod.GenerateName, fmt.Sprintf("-%s-", component))
 }
 
+func getTiKVGroupCluster(tc *v1alpha1.TidbCluster, group string) (*v1alpha1.TidbCluster, error) {
+	for i := range tc.Spec.TiKVGroups {
+		if tc.Spec.TiKVGroups[i].Name == group {
+			return tc.TiKVGroupCluster(&tc.Spec.TiKVGroups[i]), nil
+		}
+	}
+	return nil, fmt.Errorf("tikv group %s not found in tidbcluster %s/%s", group, tc.GetNamespace(), tc.GetName())
+}
+
+// filterTiKVGroupPods returns the pods in the given TiKV group, the pods of
+// the default TiKV are in the group with empty name
+func filterTiKVGroupPods(podList *apiv1.PodList, group string) *apiv1.PodList {
+	filtered := &apiv1.PodList{}
+	for _, pod := range podList.Items {
+		if pod.Labels[label.TiKVGroupLabelKey] == group {
+			filtered.Items = append(filtered.Items, pod)
+		}
+	}
+	return filtered
+}
+
 func getReplicasFrom(tc *v1alpha1.TidbCluster, component string) int32 {
 	if component == v1alpha1.PDMemberType.String() {
 		return tc.PDStsDesiredReplicas()
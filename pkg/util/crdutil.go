@@ -291,6 +291,52 @@ var (
 		Description: "The minimal replicas of TiDB",
 		JSONPath:    ".spec.tidb.minReplicas",
 	}
+	ticdcChangefeedPrinterColumns []extensionsobj.CustomResourceColumnDefinition
+	ticdcChangefeedStateColumn    = extensionsobj.CustomResourceColumnDefinition{
+		Name:        "State",
+		Type:        "string",
+		Description: "The state of the changefeed",
+		JSONPath:    ".status.state",
+	}
+	ticdcChangefeedCheckpointColumn = extensionsobj.CustomResourceColumnDefinition{
+		Name:        "Checkpoint",
+		Type:        "string",
+		Description: "The checkpoint time of the changefeed",
+		JSONPath:    ".status.checkpointTime",
+	}
+	ticdcChangefeedLagColumn = extensionsobj.CustomResourceColumnDefinition{
+		Name:        "Lag",
+		Type:        "integer",
+		Description: "The replication lag of the changefeed in seconds",
+		JSONPath:    ".status.lagSeconds",
+	}
+	ticdcChangefeedSinkColumn = extensionsobj.CustomResourceColumnDefinition{
+		Name:        "Sink",
+		Type:        "string",
+		Description: "The sink URI of the changefeed",
+		JSONPath:    ".spec.sinkURI",
+		Priority:    1,
+	}
+	placementRulePrinterColumns []extensionsobj.CustomResourceColumnDefinition
+	placementRuleGroupColumn    = extensionsobj.CustomResourceColumnDefinition{
+		Name:        "Group",
+		Type:        "string",
+		Description: "The rule group in PD",
+		JSONPath:    ".status.groupID",
+	}
+	placementRuleSyncedColumn = extensionsobj.CustomResourceColumnDefinition{
+		Name:        "Synced",
+		Type:        "boolean",
+		Description: "Whether the rules in PD match the spec",
+		JSONPath:    ".status.synced",
+	}
+	placementRuleLastDriftColumn = extensionsobj.CustomResourceColumnDefinition{
+		Name:        "LastDrift",
+		Type:        "date",
+		Description: "The last time a drift was found",
+		JSONPath:    ".status.lastDriftTime",
+		Priority:    1,
+	}
 	ageColumn = extensionsobj.CustomResourceColumnDefinition{
 		Name:     "Age",
 		Type:     "date",
@@ -315,6 +361,10 @@ func init() {
 	tidbInitializerPrinterColumns = append(tidbInitializerPrinterColumns, tidbInitializerPhase, ageColumn)
 	autoScalerPrinterColumns = append(autoScalerPrinterColumns, autoScalerTiDBMaxReplicasColumn, autoScalerTiDBMinReplicasColumn,
 		autoScalerTiKVMaxReplicasColumn, autoScalerTiKVMinReplicasColumn, ageColumn)
+	ticdcChangefeedPrinterColumns = append(ticdcChangefeedPrinterColumns, ticdcChangefeedStateColumn, ticdcChangefeedCheckpointColumn,
+		ticdcChangefeedLagColumn, ticdcChangefeedSinkColumn, ageColumn)
+	placementRulePrinterColumns = append(placementRulePrinterColumns, placementRuleGroupColumn, placementRuleSyncedColumn,
+		placementRuleLastDriftColumn, ageColumn)
 }
 
 func NewCustomResourceDefinition(crdKind v1alpha1.CrdKind, group string, labels map[string]string, validation bool) *extensionsobj.CustomResourceDefinition {
@@ -352,6 +402,10 @@ func GetCrdKindFromKindName(kindName string) (v1alpha1.CrdKind, error) {
 		return v1alpha1.DefaultCrdKinds.TiDBInitializer, nil
 	case v1alpha1.TidbClusterAutoScalerKindKey:
 		return v1alpha1.DefaultCrdKinds.TidbClusterAutoScaler, nil
+	case v1alpha1.TiCDCChangefeedKindKey:
+		return v1alpha1.DefaultCrdKinds.TiCDCChangefeed, nil
+	case v1alpha1.PlacementRuleKindKey:
+		return v1alpha1.DefaultCrdKinds.PlacementRule, nil
 	default:
 		return v1alpha1.CrdKind{}, errors.New("unknown CrdKind Name")
 	}
@@ -375,6 +429,10 @@ func addAdditionalPrinterColumnsForCRD(crd *extensionsobj.CustomResourceDefiniti
 		crd.Spec.AdditionalPrinterColumns = tidbInitializerPrinterColumns
 	case v1alpha1.DefaultCrdKinds.TidbClusterAutoScaler.Kind:
 		crd.Spec.AdditionalPrinterColumns = autoScalerPrinterColumns
+	case v1alpha1.DefaultCrdKinds.TiCDCChangefeed.Kind:
+		crd.Spec.AdditionalPrinterColumns = ticdcChangefeedPrinterColumns
+	case v1alpha1.DefaultCrdKinds.PlacementRule.Kind:
+		crd.Spec.AdditionalPrinterColumns = placementRulePrinterColumns
 	default:
 	}
 }
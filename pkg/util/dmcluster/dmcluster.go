@@ -28,6 +28,49 @@ const (
 	StatfulSetNotUpToDate = "StatefulSetNotUpToDate"
 	// MasterUnhealthy is added when one of dm-master members is unhealthy.
 	MasterUnhealthy = "DMMasterUnhealthy"
+
+	// Reasons for VolumeResizing conditions.
+
+	// VolumesResizing is added when there are PVCs whose capacity is less than the storage request.
+	VolumesResizing = "VolumesResizing"
+	// NoVolumesResizing is added when all the PVCs are resized.
+	NoVolumesResizing = "NoVolumesResizing"
+
+	// Reasons for MasterAvailable conditions, MasterUnhealthy is added when the
+	// quorum is healthy but some of the dm-master members are not.
+
+	// MasterAllMembersHealthy is added when all the dm-master members are healthy.
+	MasterAllMembersHealthy = "DMMasterAllMembersHealthy"
+	// MasterQuorumUnavailable is added when the quorum of the dm-master members is not healthy.
+	MasterQuorumUnavailable = "DMMasterQuorumUnavailable"
+
+	// Reasons for WorkersHealthy conditions.
+
+	// WorkerAllMembersUp is added when none of the dm-workers is offline.
+	WorkerAllMembersUp = "DMWorkerAllMembersUp"
+	// WorkerOffline is added when one of the dm-workers is offline.
+	WorkerOffline = "DMWorkerOffline"
+
+	// Reasons for Upgrading, Scaling, FailoverActive and ConfigOutOfSync conditions.
+	// The reason of a True condition is the first component involved followed by the
+	// suffix, e.g. DMMasterUpgrading, the components are DMMaster and DMWorker in order.
+
+	// UpgradingSuffix is the suffix of the reasons of True Upgrading conditions.
+	UpgradingSuffix = "Upgrading"
+	// NoComponentsUpgrading is added when no components are being upgraded.
+	NoComponentsUpgrading = "NoComponentsUpgrading"
+	// ScalingSuffix is the suffix of the reasons of True Scaling conditions.
+	ScalingSuffix = "Scaling"
+	// NoComponentsScaling is added when no components are being scaled.
+	NoComponentsScaling = "NoComponentsScaling"
+	// FailoverActiveSuffix is the suffix of the reasons of True FailoverActive conditions.
+	FailoverActiveSuffix = "FailoverActive"
+	// NoFailoverActive is added when no components have failure members.
+	NoFailoverActive = "NoFailoverActive"
+	// ConfigOutOfSyncSuffix is the suffix of the reasons of True ConfigOutOfSync conditions.
+	ConfigOutOfSyncSuffix = "ConfigOutOfSync"
+	// ConfigInSync is added when the pods of all the components are up to date.
+	ConfigInSync = "ConfigInSync"
 )
 
 // NewDMClusterCondition creates a new dmcluster condition.
@@ -14,7 +14,12 @@
 package tidbcluster
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/robfig/cron"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -34,8 +39,204 @@ const (
 	TiDBUnhealthy = "TiDBUnhealthy"
 	// TiFlashStoreNotUp is added when one of tiflash stores is not up.
 	TiFlashStoreNotUp = "TiFlashStoreNotUp"
+	// Suspended is added when the tidb cluster is suspended or being resumed.
+	Suspended = "Suspended"
+	// Bootstrapping is added when the backup data is being restored to the tidb cluster.
+	Bootstrapping = "Bootstrapping"
+
+	// Reasons for MaintenancePending conditions.
+
+	// OutsideMaintenanceWindow is added when disruptive operations are waiting for the maintenance window.
+	OutsideMaintenanceWindow = "OutsideMaintenanceWindow"
+	// WithinMaintenanceWindow is added when disruptive operations are allowed.
+	WithinMaintenanceWindow = "WithinMaintenanceWindow"
+	// NoMaintenancePending is added when no disruptive operations are waiting for the maintenance window.
+	NoMaintenancePending = "NoMaintenancePending"
+
+	// Reasons for NodeDraining conditions.
+
+	// StoresDraining is added when there are stores on the nodes being drained.
+	StoresDraining = "StoresDraining"
+	// NoStoresDraining is added when all the stores are off the nodes being drained.
+	NoStoresDraining = "NoStoresDraining"
+
+	// Reasons for VolumeResizing conditions.
+
+	// VolumesResizing is added when there are PVCs whose capacity is less than the storage request.
+	VolumesResizing = "VolumesResizing"
+	// NoVolumesResizing is added when all the PVCs are resized.
+	NoVolumesResizing = "NoVolumesResizing"
+
+	// Reasons for PDAvailable conditions, PDUnhealthy is added when the quorum is
+	// healthy but some of the pd members are not.
+
+	// PDAllMembersHealthy is added when all the pd members are healthy.
+	PDAllMembersHealthy = "PDAllMembersHealthy"
+	// PDQuorumUnavailable is added when the quorum of the pd members is not healthy.
+	PDQuorumUnavailable = "PDQuorumUnavailable"
+
+	// Reasons for TiKVStoresHealthy conditions, TiKVStoreNotUp is added when one of
+	// tikv stores is not up.
+
+	// TiKVAllStoresUp is added when all the tikv stores are up.
+	TiKVAllStoresUp = "TiKVAllStoresUp"
+
+	// Reasons for TiDBAvailable conditions, TiDBUnhealthy is added when some of
+	// the tidb members are healthy but not all.
+
+	// TiDBAllMembersHealthy is added when all the tidb members are healthy.
+	TiDBAllMembersHealthy = "TiDBAllMembersHealthy"
+	// TiDBNoMemberHealthy is added when none of the tidb members is healthy.
+	TiDBNoMemberHealthy = "TiDBNoMemberHealthy"
+
+	// Reasons for Upgrading, Scaling, FailoverActive and ConfigOutOfSync conditions.
+	// The reason of a True condition is the first component involved followed by the
+	// suffix, e.g. TiKVUpgrading, the components are PD, TiKV, TiFlash, TiDB, TiCDC,
+	// Pump and Drainer in order.
+
+	// UpgradingSuffix is the suffix of the reasons of True Upgrading conditions.
+	UpgradingSuffix = "Upgrading"
+	// NoComponentsUpgrading is added when no components are being upgraded.
+	NoComponentsUpgrading = "NoComponentsUpgrading"
+	// ScalingSuffix is the suffix of the reasons of True Scaling conditions.
+	ScalingSuffix = "Scaling"
+	// NoComponentsScaling is added when no components are being scaled.
+	NoComponentsScaling = "NoComponentsScaling"
+	// FailoverActiveSuffix is the suffix of the reasons of True FailoverActive conditions.
+	FailoverActiveSuffix = "FailoverActive"
+	// NoFailoverActive is added when no components have failure members or stores.
+	NoFailoverActive = "NoFailoverActive"
+	// ConfigOutOfSyncSuffix is the suffix of the reasons of True ConfigOutOfSync conditions.
+	ConfigOutOfSyncSuffix = "ConfigOutOfSync"
+	// ConfigInSync is added when the pods of all the components are up to date.
+	ConfigInSync = "ConfigInSync"
+
+	maintenancePendingMessagePrefix = "Waiting for the maintenance window: "
 )
 
+// InMaintenanceWindow returns whether the disruptive operations of the tidb cluster
+// are allowed at the given time, they are always allowed if no window is set.
+func InMaintenanceWindow(tc *v1alpha1.TidbCluster, now time.Time) (bool, error) {
+	for _, window := range tc.Spec.MaintenanceWindows {
+		in, err := inMaintenanceWindow(window, now)
+		if err != nil {
+			return false, fmt.Errorf("tidbcluster %s/%s has invalid maintenance window %q: %v", tc.Namespace, tc.Name, window.Schedule, err)
+		}
+		if in {
+			return true, nil
+		}
+	}
+	return len(tc.Spec.MaintenanceWindows) == 0, nil
+}
+
+func inMaintenanceWindow(window v1alpha1.MaintenanceWindow, now time.Time) (bool, error) {
+	sched, err := cron.ParseStandard(window.Schedule)
+	if err != nil {
+		return false, err
+	}
+	duration, err := time.ParseDuration(window.Duration)
+	if err != nil {
+		return false, err
+	}
+	loc := time.UTC
+	if window.TimeZone != "" {
+		if loc, err = time.LoadLocation(window.TimeZone); err != nil {
+			return false, err
+		}
+	}
+	// the window is open if it starts in (now-duration, now]
+	start := sched.Next(now.In(loc).Add(-duration))
+	return !start.After(now), nil
+}
+
+// SetMaintenancePending adds the operation to the MaintenancePending condition
+// of the tidb cluster, the condition lists all the operations waiting for the
+// maintenance window.
+func SetMaintenancePending(status *v1alpha1.TidbClusterStatus, operation string) {
+	currentCond := GetTidbClusterCondition(*status, v1alpha1.TidbClusterMaintenancePending)
+	operations := pendingOperations(currentCond)
+	for _, op := range operations {
+		if op == operation {
+			return
+		}
+	}
+	operations = append(operations, operation)
+
+	condition := NewTidbClusterCondition(v1alpha1.TidbClusterMaintenancePending, v1.ConditionTrue,
+		OutsideMaintenanceWindow, maintenancePendingMessagePrefix+strings.Join(operations, ", "))
+	if currentCond != nil && currentCond.Status == v1.ConditionTrue {
+		condition.LastTransitionTime = currentCond.LastTransitionTime
+	}
+	status.Conditions = append(filterOutCondition(status.Conditions, condition.Type), *condition)
+}
+
+// ResetMaintenancePending removes the MaintenancePending condition before the tidb
+// cluster is synced, the operations still waiting for the maintenance window are added
+// again by SetMaintenancePending during the sync. It returns the removed condition.
+func ResetMaintenancePending(status *v1alpha1.TidbClusterStatus) *v1alpha1.TidbClusterCondition {
+	cond := GetTidbClusterCondition(*status, v1alpha1.TidbClusterMaintenancePending)
+	if cond != nil {
+		status.Conditions = filterOutCondition(status.Conditions, v1alpha1.TidbClusterMaintenancePending)
+	}
+	return cond
+}
+
+// RestoreMaintenancePending restores the MaintenancePending condition removed by
+// ResetMaintenancePending after the sync. The condition is set to False if no operation
+// is waiting any more, and the operations waiting before a failed sync are kept.
+func RestoreMaintenancePending(status *v1alpha1.TidbClusterStatus, oldCond *v1alpha1.TidbClusterCondition, synced bool) {
+	if oldCond == nil {
+		return
+	}
+	if !synced {
+		for _, op := range pendingOperations(oldCond) {
+			SetMaintenancePending(status, op)
+		}
+	}
+	cond := GetTidbClusterCondition(*status, v1alpha1.TidbClusterMaintenancePending)
+	if cond == nil {
+		status.Conditions = append(status.Conditions, *oldCond)
+		if oldCond.Status == v1.ConditionTrue {
+			SetTidbClusterCondition(status, *NewTidbClusterCondition(v1alpha1.TidbClusterMaintenancePending, v1.ConditionFalse,
+				NoMaintenancePending, "No disruptive operations are waiting for the maintenance window"))
+		}
+		return
+	}
+	if oldCond.Status != v1.ConditionTrue {
+		return
+	}
+	// keep the timestamps if the pending operations are not changed
+	cond.LastTransitionTime = oldCond.LastTransitionTime
+	if cond.Message == oldCond.Message {
+		cond.LastUpdateTime = oldCond.LastUpdateTime
+	}
+	status.Conditions = append(filterOutCondition(status.Conditions, cond.Type), *cond)
+}
+
+// MergeConditions merges the conditions set by the sync of a TiKV or TiDB group
+// into the status of the tidb cluster, the operations of the group waiting for
+// the maintenance window are added to the ones of the tidb cluster.
+func MergeConditions(status *v1alpha1.TidbClusterStatus, conditions []v1alpha1.TidbClusterCondition) {
+	for i := range conditions {
+		cond := conditions[i]
+		if cond.Type == v1alpha1.TidbClusterMaintenancePending {
+			for _, op := range pendingOperations(&cond) {
+				SetMaintenancePending(status, op)
+			}
+			continue
+		}
+		SetTidbClusterCondition(status, cond)
+	}
+}
+
+// pendingOperations returns the operations listed in the MaintenancePending condition
+func pendingOperations(cond *v1alpha1.TidbClusterCondition) []string {
+	if cond == nil || cond.Status != v1.ConditionTrue {
+		return nil
+	}
+	return strings.Split(strings.TrimPrefix(cond.Message, maintenancePendingMessagePrefix), ", ")
+}
+
 // NewTidbClusterCondition creates a new tidbcluster condition.
 func NewTidbClusterCondition(condType v1alpha1.TidbClusterConditionType, status v1.ConditionStatus, reason, message string) *v1alpha1.TidbClusterCondition {
 	return &v1alpha1.TidbClusterCondition{
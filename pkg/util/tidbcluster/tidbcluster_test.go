@@ -71,3 +71,123 @@ func TestCondition(t *testing.T) {
 	getc = GetTidbClusterReadyCondition(status)
 	g.Expect(getc).Should(Equal(c3))
 }
+
+func TestInMaintenanceWindow(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tests := []struct {
+		name    string
+		windows []v1alpha1.MaintenanceWindow
+		now     time.Time
+		expect  bool
+	}{
+		{
+			name:   "no window",
+			now:    time.Date(2020, 11, 7, 12, 0, 0, 0, time.UTC),
+			expect: true,
+		},
+		{
+			name:    "in window",
+			windows: []v1alpha1.MaintenanceWindow{{Schedule: "0 2 * * 6", Duration: "4h"}},
+			now:     time.Date(2020, 11, 7, 5, 59, 0, 0, time.UTC),
+			expect:  true,
+		},
+		{
+			name:    "at the start of window",
+			windows: []v1alpha1.MaintenanceWindow{{Schedule: "0 2 * * 6", Duration: "4h"}},
+			now:     time.Date(2020, 11, 7, 2, 0, 0, 0, time.UTC),
+			expect:  true,
+		},
+		{
+			name:    "after window",
+			windows: []v1alpha1.MaintenanceWindow{{Schedule: "0 2 * * 6", Duration: "4h"}},
+			now:     time.Date(2020, 11, 7, 6, 0, 0, 0, time.UTC),
+			expect:  false,
+		},
+		{
+			name: "in window of time zone",
+			windows: []v1alpha1.MaintenanceWindow{
+				{Schedule: "0 2 * * 6", Duration: "4h"},
+				{Schedule: "0 2 * * *", Duration: "1h", TimeZone: "Asia/Shanghai"},
+			},
+			now:    time.Date(2020, 11, 4, 18, 30, 0, 0, time.UTC),
+			expect: true,
+		},
+	}
+	for _, test := range tests {
+		t.Log(test.name)
+		tc := &v1alpha1.TidbCluster{Spec: v1alpha1.TidbClusterSpec{MaintenanceWindows: test.windows}}
+		in, err := InMaintenanceWindow(tc, test.now)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(in).To(Equal(test.expect))
+	}
+}
+
+func TestSetMaintenancePending(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	status := &v1alpha1.TidbClusterStatus{}
+	SetMaintenancePending(status, "tikv upgrade")
+	SetMaintenancePending(status, "pvc resize")
+	SetMaintenancePending(status, "tikv upgrade")
+
+	cond := GetTidbClusterCondition(*status, v1alpha1.TidbClusterMaintenancePending)
+	g.Expect(cond).NotTo(BeNil())
+	g.Expect(cond.Status).To(Equal(v1.ConditionTrue))
+	g.Expect(cond.Reason).To(Equal(OutsideMaintenanceWindow))
+	g.Expect(cond.Message).To(Equal("Waiting for the maintenance window: tikv upgrade, pvc resize"))
+}
+
+func TestResetMaintenancePending(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	pending := func() *v1alpha1.TidbClusterStatus {
+		status := &v1alpha1.TidbClusterStatus{}
+		SetMaintenancePending(status, "tikv upgrade")
+		return status
+	}
+
+	// nothing is pending after the sync
+	status := pending()
+	oldCond := ResetMaintenancePending(status)
+	g.Expect(GetTidbClusterCondition(*status, v1alpha1.TidbClusterMaintenancePending)).To(BeNil())
+	RestoreMaintenancePending(status, oldCond, true)
+	cond := GetTidbClusterCondition(*status, v1alpha1.TidbClusterMaintenancePending)
+	g.Expect(cond.Status).To(Equal(v1.ConditionFalse))
+	g.Expect(cond.Reason).To(Equal(NoMaintenancePending))
+
+	// the same operation is still pending
+	status = pending()
+	oldCond = ResetMaintenancePending(status)
+	SetMaintenancePending(status, "tikv upgrade")
+	RestoreMaintenancePending(status, oldCond, true)
+	g.Expect(*GetTidbClusterCondition(*status, v1alpha1.TidbClusterMaintenancePending)).To(Equal(*oldCond))
+
+	// the operations are kept if the sync fails
+	status = pending()
+	oldCond = ResetMaintenancePending(status)
+	SetMaintenancePending(status, "pvc resize")
+	RestoreMaintenancePending(status, oldCond, false)
+	cond = GetTidbClusterCondition(*status, v1alpha1.TidbClusterMaintenancePending)
+	g.Expect(cond.Status).To(Equal(v1.ConditionTrue))
+	g.Expect(cond.Message).To(Equal("Waiting for the maintenance window: pvc resize, tikv upgrade"))
+	g.Expect(cond.LastTransitionTime).To(Equal(oldCond.LastTransitionTime))
+}
+
+func TestMergeConditions(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	status := &v1alpha1.TidbClusterStatus{}
+	SetMaintenancePending(status, "tikv upgrade")
+	group := &v1alpha1.TidbClusterStatus{}
+	SetMaintenancePending(group, "tikv group hot upgrade")
+
+	MergeConditions(status, group.Conditions)
+	cond := GetTidbClusterCondition(*status, v1alpha1.TidbClusterMaintenancePending)
+	g.Expect(cond.Message).To(Equal("Waiting for the maintenance window: tikv upgrade, tikv group hot upgrade"))
+
+	// merging the conditions of the cluster itself changes nothing
+	conditions := append([]v1alpha1.TidbClusterCondition{}, status.Conditions...)
+	MergeConditions(status, conditions)
+	g.Expect(status.Conditions).To(Equal(conditions))
+}
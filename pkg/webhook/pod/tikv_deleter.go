@@ -163,7 +163,15 @@ func (pc *PodAdmissionControl) admitDeleteUpTiKVPod(payload *admitPayload, store
 			err := fmt.Errorf("tikv pod[%s/%s]'s controller is not tidbcluster,forbid to be deleted", namespace, name)
 			return util.ARFail(err)
 		}
-		specReplicas = tc.Spec.TiKV.Replicas
+		if group := payload.pod.Labels[label.TiKVGroupLabelKey]; group != "" {
+			for _, g := range tc.Spec.TiKVGroups {
+				if g.Name == group {
+					specReplicas = g.Replicas
+				}
+			}
+		} else {
+			specReplicas = tc.Spec.TiKV.Replicas
+		}
 	} else {
 		// unreachable
 		klog.V(4).Infof("tikv pod[%s/%s] has unknown controller[%s], admit to be deleted", namespace, name, controllerKind)
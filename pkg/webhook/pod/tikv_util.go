@@ -20,7 +20,6 @@ import (
 
 	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
-	memberUtil "github.com/pingcap/tidb-operator/pkg/manager/member"
 	"github.com/pingcap/tidb-operator/pkg/pdapi"
 	apps "k8s.io/api/apps/v1"
 	core "k8s.io/api/core/v1"
@@ -50,7 +49,8 @@ func checkFormerTiKVPodStatus(kubeCli kubernetes.Interface, controllerDesc contr
 		var podName string
 		switch controllerKind {
 		case v1alpha1.TiDBClusterKind:
-			podName = memberUtil.TikvPodName(controllerName, i)
+			// the pods of TiKV groups are named after their own StatefulSets
+			podName = fmt.Sprintf("%s-%d", set.Name, i)
 		default:
 			// unreachable
 			return fmt.Errorf("unknown controller[%s]", controllerKind)
@@ -46,6 +46,18 @@ func (p *proxiedTiDBClient) GetInfo(tc *v1alpha1.TidbCluster, ordinal int32) (*c
 	panic("implement when necessary")
 }
 
+func (p *proxiedTiDBClient) GetConnectionCount(tc *v1alpha1.TidbCluster, ordinal int32) (int, error) {
+	panic("implement when necessary")
+}
+
+func (p *proxiedTiDBClient) UpdateSettings(tc *v1alpha1.TidbCluster, ordinal int32, settings map[string]string) error {
+	panic("implement when necessary")
+}
+
+func (p *proxiedTiDBClient) ResignDDLOwner(tc *v1alpha1.TidbCluster, ordinal int32) (bool, error) {
+	panic("implement when necessary")
+}
+
 func (p *proxiedTiDBClient) GetSettings(tc *v1alpha1.TidbCluster, ordinal int32) (*config.Config, error) {
 	tcName := tc.GetName()
 	ns := tc.GetNamespace()